@@ -5,8 +5,8 @@
 package gin
 
 import (
-	"bytes"
 	"errors"
+	"io"
 	"net/http"
 	"net/url"
 	"strings"
@@ -103,9 +103,14 @@ func (gins *Gin) AddHandler(method, path string, handlers context.Handlers) {
 			c.Header(key, head[0])
 		}
 		if ctx.Response.Body != nil {
-			buf := new(bytes.Buffer)
-			_, _ = buf.ReadFrom(ctx.Response.Body)
-			c.String(ctx.Response.StatusCode, buf.String())
+			// copy the body to the writer directly, so that the streaming body
+			// of the large response is never held in the memory.
+			if c.Writer.Header().Get("Content-Type") == "" {
+				c.Header("Content-Type", "text/plain; charset=utf-8")
+			}
+			c.Status(ctx.Response.StatusCode)
+			_, _ = io.Copy(c.Writer, ctx.Response.Body)
+			_ = ctx.Response.Body.Close()
 		} else {
 			c.Status(ctx.Response.StatusCode)
 		}
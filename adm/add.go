@@ -89,6 +89,11 @@ func addUser(cfgFile string) {
 		panic(newError("user record exists"))
 	}
 
+	// the config is not initialized in the command line, use the default hasher explicitly.
+	if auth.EncryptPassAlgo == "" {
+		auth.EncryptPassAlgo = "bcrypt"
+	}
+
 	_, err = db.WithDriver(conn).Table("goadmin_users").
 		Insert(dialect.H{
 			"name":     name,
@@ -198,5 +198,48 @@ func main() {
 		})
 	})
 
+	app.Command("migrate", "migrate the goadmin tables", func(cmd *cli.Cmd) {
+
+		cmd.Command("up", "apply the pending migrations", func(cmd *cli.Cmd) {
+			var (
+				config = cmd.StringOpt("c config", "", "config ini path")
+				lang   = cmd.StringOpt("l language", "en", "language")
+				conn   = cmd.StringOpt("conn connection", "", "connection")
+			)
+
+			cmd.Action = func() {
+				setDefaultLangSet(*lang)
+				migrateUp(*config, *conn)
+			}
+		})
+
+		cmd.Command("down", "rollback the latest migrations", func(cmd *cli.Cmd) {
+			var (
+				config = cmd.StringOpt("c config", "", "config ini path")
+				lang   = cmd.StringOpt("l language", "en", "language")
+				conn   = cmd.StringOpt("conn connection", "", "connection")
+				steps  = cmd.IntOpt("s steps", 1, "number of the migrations to rollback")
+			)
+
+			cmd.Action = func() {
+				setDefaultLangSet(*lang)
+				migrateDown(*config, *conn, *steps)
+			}
+		})
+
+		cmd.Command("status", "show the applied and the pending migrations", func(cmd *cli.Cmd) {
+			var (
+				config = cmd.StringOpt("c config", "", "config ini path")
+				lang   = cmd.StringOpt("l language", "en", "language")
+				conn   = cmd.StringOpt("conn connection", "", "connection")
+			)
+
+			cmd.Action = func() {
+				setDefaultLangSet(*lang)
+				migrateStatus(*config, *conn)
+			}
+		})
+	})
+
 	_ = app.Run(os.Args)
 }
@@ -45,6 +45,10 @@ var langs = map[string]map[string]string{
 		"Add admin user success~~🍺🍺":                          "增加用户成功~~🍺🍺",
 		"Add table permissions success~~🍺🍺":                   "增加表格权限成功~~🍺🍺",
 		"Generate data table models success~~🍺🍺":              "生成数据模型文件成功~~🍺🍺",
+		"Migrate success~~🍺🍺":                                 "迁移成功~~🍺🍺",
+		"Rollback success~~🍺🍺":                                "回滚成功~~🍺🍺",
+		"Nothing to migrate":                                    "没有待执行的迁移",
+		"Nothing to rollback":                                   "没有可回滚的迁移",
 		"see the docs: ": "查看文档：",
 		"visit forum: ":  "访问论坛：",
 		"generating: ":   "生成中：",
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"fmt"
+	"runtime"
+
+	"github.com/GoAdminGroup/go-admin/modules/db"
+	"github.com/GoAdminGroup/go-admin/modules/migration"
+	"github.com/GoAdminGroup/go-admin/modules/utils"
+	// the migrations of the goadmin tables are registered by the models.
+	_ "github.com/GoAdminGroup/go-admin/plugins/admin/models"
+	"github.com/mgutz/ansi"
+	"gopkg.in/ini.v1"
+)
+
+// migrateConnection return the connection of the database to migrate, the info of
+// the database not in the config file is asked for.
+func migrateConnection(cfgFile, connName string) db.Connection {
+
+	clear(runtime.GOOS)
+	cliInfo()
+
+	// the utils are not initialized in the command line, the queries need them.
+	utils.InitUtils(100, func(s string) string { return s })
+
+	info := new(dbInfo)
+
+	if cfgFile != "" {
+		cfgModel, err := ini.Load(cfgFile)
+
+		if err != nil {
+			panic(newError("wrong config file path"))
+		}
+
+		languageCfg, err := cfgModel.GetSection("language")
+
+		if err == nil {
+			setDefaultLangSet(languageCfg.Key("language").Value())
+		}
+
+		info = getDBInfoFromINIConfig(cfgModel, connName)
+	}
+
+	return askForDBConnection(info)
+}
+
+func migrateUp(cfgFile, connName string) {
+
+	conn := migrateConnection(cfgFile, connName)
+
+	list, err := migration.Up(conn)
+
+	for _, m := range list {
+		fmt.Println(ansi.Color("applied", "green"), migrationTitle(m))
+	}
+
+	checkError(err)
+
+	if len(list) == 0 {
+		printSuccessInfo("Nothing to migrate")
+		return
+	}
+
+	printSuccessInfo("Migrate success~~🍺🍺")
+}
+
+func migrateDown(cfgFile, connName string, steps int) {
+
+	conn := migrateConnection(cfgFile, connName)
+
+	list, err := migration.Down(conn, steps)
+
+	for _, m := range list {
+		fmt.Println(ansi.Color("reverted", "yellow"), migrationTitle(m))
+	}
+
+	checkError(err)
+
+	if len(list) == 0 {
+		printSuccessInfo("Nothing to rollback")
+		return
+	}
+
+	printSuccessInfo("Rollback success~~🍺🍺")
+}
+
+func migrateStatus(cfgFile, connName string) {
+
+	conn := migrateConnection(cfgFile, connName)
+
+	list, err := migration.GetStatus(conn)
+
+	checkError(err)
+
+	for _, m := range list {
+		if m.Applied {
+			fmt.Println(ansi.Color("applied", "green"), migrationTitle(m.Migration), m.AppliedAt)
+		} else {
+			fmt.Println(ansi.Color("pending", "yellow"), migrationTitle(m.Migration))
+		}
+	}
+
+	fmt.Println()
+}
+
+func migrationTitle(m migration.Migration) string {
+	if m.Plugin == "" {
+		return fmt.Sprintf("%d %s", m.Version, m.Name)
+	}
+	return fmt.Sprintf("%d [%s] %s", m.Version, m.Plugin, m.Name)
+}
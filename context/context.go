@@ -214,6 +214,27 @@ func (ctx *Context) Data(code int, contentType string, data []byte) {
 	ctx.Response.Body = io.NopCloser(bytes.NewBuffer(data))
 }
 
+// DataStream sets the status code and the content type, and streams the data
+// written by fn into the response body. fn runs in a new goroutine, and the
+// written data is passed to the adapter through a pipe without being buffered.
+// A failed write, e.g. the client has gone, is returned to fn so that it can stop.
+func (ctx *Context) DataStream(code int, contentType string, fn func(w io.Writer) error) {
+	ctx.Response.StatusCode = code
+	ctx.SetContentType(contentType)
+	pr, pw := io.Pipe()
+	go func() {
+		var err error
+		defer func() {
+			if r := recover(); r != nil {
+				err = fmt.Errorf("data stream panic: %v", r)
+			}
+			_ = pw.CloseWithError(err)
+		}()
+		err = fn(pw)
+	}()
+	ctx.Response.Body = pr
+}
+
 // Redirect add redirect url to header.
 func (ctx *Context) Redirect(path string) {
 	ctx.Response.StatusCode = http.StatusFound
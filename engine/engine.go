@@ -14,6 +14,7 @@ import (
 	"runtime/debug"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/GoAdminGroup/go-admin/modules/language"
 	"github.com/GoAdminGroup/go-admin/template/icon"
@@ -27,8 +28,10 @@ import (
 	"github.com/GoAdminGroup/go-admin/modules/errors"
 	"github.com/GoAdminGroup/go-admin/modules/logger"
 	"github.com/GoAdminGroup/go-admin/modules/menu"
+	"github.com/GoAdminGroup/go-admin/modules/migration"
 	"github.com/GoAdminGroup/go-admin/modules/service"
 	"github.com/GoAdminGroup/go-admin/modules/system"
+	"github.com/GoAdminGroup/go-admin/modules/tenant"
 	"github.com/GoAdminGroup/go-admin/modules/ui"
 	"github.com/GoAdminGroup/go-admin/plugins"
 	"github.com/GoAdminGroup/go-admin/plugins/admin"
@@ -72,6 +75,7 @@ func (eng *Engine) Use(router interface{}) error {
 	eng.Services.Add(auth.InitCSRFTokenSrv(eng.DefaultConnection()))
 	eng.initSiteSetting()
 	eng.initJumpNavButtons()
+	eng.initMigrations()
 	eng.initPlugins()
 
 	printInitMsg(language.Get("initialize success"))
@@ -120,6 +124,80 @@ func (eng *Engine) AddConfig(cfg *config.Config) *Engine {
 	return eng.setConfig(cfg).initDatabase()
 }
 
+// AddConfigFromJSON set the global config from a JSON file.
+func (eng *Engine) AddConfigFromJSON(path string) *Engine {
+	cfg := config.ReadFromJson(path)
+	return eng.AddConfig(&cfg)
+}
+
+// AddConfigFromYAML set the global config from a YAML file.
+func (eng *Engine) AddConfigFromYAML(path string) *Engine {
+	cfg := config.ReadFromYaml(path)
+	return eng.AddConfig(&cfg)
+}
+
+// AddConfigFromINI set the global config from an INI file.
+func (eng *Engine) AddConfigFromINI(path string) *Engine {
+	cfg := config.ReadFromINI(path)
+	return eng.AddConfig(&cfg)
+}
+
+// WatchConfigFile reloads the config file when it changes. The title, logo, theme,
+// language, logger level, session life time and custom html are applied at
+// runtime, the changes of the others are shown as pending restart in the system
+// info page. It should be called after the config is added, and the interval
+// should be positive.
+func (eng *Engine) WatchConfigFile(path string, interval time.Duration) *Engine {
+	_, err := config.WatchFile(path, interval, func(c *config.Config) error {
+		if !utils.InArray(template.Themes(), c.Theme) {
+			return errors2.New("wrong config: theme " + c.Theme + " is not registered")
+		}
+		if c.Language != "" && !utils.InArray(language.Langs[:], language.FixedLanguageKey(c.Language)) {
+			return errors2.New("wrong config: language " + c.Language + " is not supported")
+		}
+		return nil
+	})
+	if err != nil {
+		logger.Error("watch config file error: ", err)
+	}
+	return eng
+}
+
+// AddTenants adds the tenants served by the engine, every tenant has its own
+// database in the Databases of the config with the driver of the default one, and
+// the goadmin tables of the tenant are in its database. It should be called after
+// the config is added.
+func (eng *Engine) AddTenants(list ...*tenant.Tenant) *Engine {
+	if eng.config == nil {
+		logger.Panic("the config should be added before the tenants")
+	}
+	driver := eng.config.Databases.GetDefault().Driver
+	for _, t := range list {
+		if t.Name == "" {
+			logger.Panic("wrong tenant: empty name")
+		}
+		if t.Connection == "" {
+			t.Connection = "default"
+		}
+		database, ok := eng.config.Databases[t.Connection]
+		if !ok {
+			logger.Panicf("wrong tenant %s: database %s not found", t.Name, t.Connection)
+		}
+		if database.Driver != driver {
+			logger.Panicf("wrong tenant %s: the driver of database %s should be %s", t.Name, t.Connection, driver)
+		}
+	}
+	tenant.Register(list...)
+	return eng
+}
+
+// SetTenantResolver replaces the resolver of the tenant of the requests, which finds
+// the tenant by the host and then by the url prefix by default.
+func (eng *Engine) SetTenantResolver(resolver tenant.Resolver) *Engine {
+	tenant.SetResolver(resolver)
+	return eng
+}
+
 // setConfig set the config of engine.
 func (eng *Engine) setConfig(cfg *config.Config) *Engine {
 	eng.config = config.Initialize(cfg)
@@ -132,6 +210,9 @@ func (eng *Engine) setConfig(cfg *config.Config) *Engine {
 		logger.Panicf(language.Get("wrong theme version, goadmin %s required version of theme %s is %s"),
 			system.Version(), eng.config.Theme, strings.Join(system.RequireThemeVersion()[eng.config.Theme], ","))
 	}
+	if err := auth.CheckSessionDriver(); err != nil {
+		logger.Panic("wrong session driver: ", err)
+	}
 	return eng
 }
 
@@ -289,13 +370,13 @@ func (eng *Engine) deferHandler(conn db.Connection) context.Handler {
 // wrapWithAuthMiddleware wrap a auth middleware to the given handler.
 func (eng *Engine) wrapWithAuthMiddleware(handler context.Handler) context.Handlers {
 	conn := db.GetConnection(eng.Services)
-	return []context.Handler{ eng.deferHandler(conn), response.OffLineHandler, auth.Middleware(conn), handler }
+	return []context.Handler{ tenant.Middleware, eng.deferHandler(conn), response.OffLineHandler, auth.Middleware(conn), handler }
 }
 
 // wrapWithAuthMiddleware wrap a auth middleware to the given handler.
 func (eng *Engine) wrap(handler context.Handler) context.Handlers {
 	conn := db.GetConnection(eng.Services)
-	return []context.Handler{ eng.deferHandler(conn), response.OffLineHandler, handler }
+	return []context.Handler{ tenant.Middleware, eng.deferHandler(conn), response.OffLineHandler, handler }
 }
 
 // ============================
@@ -396,6 +477,23 @@ func (eng *Engine) initNavJumpButtonParams() []navJumpButtonParam {
 	}
 }
 
+// initMigrations applies the pending migrations of the goadmin tables of the default
+// database and the databases of the tenants when the AutoMigrate is on.
+func (eng *Engine) initMigrations() {
+	if !eng.config.AutoMigrate {
+		return
+	}
+	conns := []db.Connection{ eng.DefaultConnection() }
+	for _, t := range tenant.All() {
+		conns = append(conns, t.Conn(conns[0]))
+	}
+	for _, conn := range conns {
+		if _, err := migration.Up(conn); err != nil {
+			logger.Panic("migrate failed: ", err)
+		}
+	}
+}
+
 func (eng *Engine) initSiteSetting() {
 	//printInitMsg(language.MustGet("initialize configuration"))
 	/*err := eng.config.Update(models.Site().
@@ -458,7 +556,7 @@ func (eng *Engine) HTML(method, url string, fn types.GetPanelInfoFn, noAuth ...b
 
 		hasError := tmpl.ExecuteTemplate(&res, tmplName, types.NewPage(&types.NewPageParam{
 			User:         user,
-			Menu:         menu.GetGlobalMenu(user, eng.Adapter.GetConnection(), ctx.Lang()).SetActiveClass(config.URLRemovePrefix(ctx.Path())),
+			Menu:         menu.GetGlobalMenu(user, tenant.Conn(ctx, eng.Adapter.GetConnection()), ctx.Lang()).SetActiveClass(config.URLRemovePrefix(ctx.Path())),
 			Panel:        panel.GetContent(eng.config.IsProductionEnvironment()),
 			Assets:       template.GetComponentAssetImportHTML(),
 			Buttons:      eng.NavButtons.CheckPermission(user),
@@ -505,7 +603,7 @@ func (eng *Engine) HTMLFile(method, url, path string, data map[string]interface{
 
 		hasError := tmpl.ExecuteTemplate(&res, tmplName, types.NewPage(&types.NewPageParam{
 			User:         user,
-			Menu:         menu.GetGlobalMenu(user, eng.Adapter.GetConnection(), ctx.Lang()).SetActiveClass(eng.config.URLRemovePrefix(ctx.Path())),
+			Menu:         menu.GetGlobalMenu(user, tenant.Conn(ctx, eng.Adapter.GetConnection()), ctx.Lang()).SetActiveClass(eng.config.URLRemovePrefix(ctx.Path())),
 			Panel:        types.Panel{ Content: template.HTML(cbuf.String()) },
 			Assets:       template.GetComponentAssetImportHTML(),
 			Buttons:      eng.NavButtons.CheckPermission(user),
@@ -564,7 +662,7 @@ func (eng *Engine) htmlFilesHandler(data map[string]interface{}, files ...string
 
 		hasError := tmpl.ExecuteTemplate(&res, tmplName, types.NewPage(&types.NewPageParam{
 			User:         user,
-			Menu:         menu.GetGlobalMenu(user, eng.Adapter.GetConnection(), ctx.Lang()).SetActiveClass(eng.config.URLRemovePrefix(ctx.Path())),
+			Menu:         menu.GetGlobalMenu(user, tenant.Conn(ctx, eng.Adapter.GetConnection()), ctx.Lang()).SetActiveClass(eng.config.URLRemovePrefix(ctx.Path())),
 			Panel:        types.Panel{ Content: template.HTML(cbuf.String()) },
 			Assets:       template.GetComponentAssetImportHTML(),
 			Buttons:      eng.NavButtons.CheckPermission(user),
@@ -588,7 +686,7 @@ func (eng *Engine) errorPanelHTML(ctx *context.Context, buf *bytes.Buffer, err e
 
 	hasError := tmpl.ExecuteTemplate(buf, tmplName, types.NewPage(&types.NewPageParam{
 		User:         user,
-		Menu:         menu.GetGlobalMenu(user, eng.Adapter.GetConnection(), ctx.Lang()).SetActiveClass(eng.config.URLRemovePrefix(ctx.Path())),
+		Menu:         menu.GetGlobalMenu(user, tenant.Conn(ctx, eng.Adapter.GetConnection()), ctx.Lang()).SetActiveClass(eng.config.URLRemovePrefix(ctx.Path())),
 		Panel:        template.WarningPanel(err.Error()).GetContent(eng.config.IsProductionEnvironment()),
 		Assets:       template.GetComponentAssetImportHTML(),
 		Buttons:      (*eng.NavButtons).CheckPermission(user),
@@ -646,6 +744,27 @@ func (eng *Engine) AddGenerator(key string, g table.Generator) *Engine {
 	return eng
 }
 
+// AddTableDefinitions add the tables defined by the yaml and json files of the
+// directory, the tables are reloaded when the files are changed. See the
+// table.Definition for the format of the files.
+func (eng *Engine) AddTableDefinitions(dir string) *Engine {
+	adm := eng.AdminPlugin()
+	list, _, err := table.WatchDefinitions(dir, table.DefaultDefinitionInterval,
+		func(changed table.GeneratorList, removed []string) {
+			for _, key := range removed {
+				adm.RemoveGenerator(key)
+			}
+			for key, g := range changed {
+				adm.AddGenerator(key, g)
+			}
+		})
+	if err != nil {
+		logger.Error("load table definitions error: ", err)
+	}
+	adm.AddGenerators(list)
+	return eng
+}
+
 // AddGlobalDisplayProcessFn call types.AddGlobalDisplayProcessFn.
 func (eng *Engine) AddGlobalDisplayProcessFn(f types.FieldFilterFn) *Engine {
 	types.AddGlobalDisplayProcessFn(f)
@@ -14,13 +14,17 @@ import (
 	"github.com/GoAdminGroup/go-admin/modules/db/dialect"
 	"github.com/GoAdminGroup/go-admin/modules/logger"
 	"github.com/GoAdminGroup/go-admin/modules/service"
+	"github.com/GoAdminGroup/go-admin/modules/tenant"
 	"github.com/GoAdminGroup/go-admin/plugins/admin/models"
 	"github.com/GoAdminGroup/go-admin/plugins/admin/modules"
 )
 
 var (
-	EncryptPass      func(algo, pass string) string
-	EncryptPassMatch func(pass, hashedPass string) bool
+	// EncryptPass hashes the password with the hasher of the algo, default the registered hashers are used.
+	EncryptPass      func(algo, pass string) string = defaultEncryptPass
+	// EncryptPassMatch verifies the password with the hasher which made the hash.
+	EncryptPassMatch func(pass, hashedPass string) bool = defaultEncryptPassMatch
+	// EncryptPassAlgo overrides the password hasher of the config when it is not empty.
 	EncryptPassAlgo  string
 )
 
@@ -35,20 +39,27 @@ func Check(username, password string, conn db.Connection) (models.UserModel, boo
 	if user.IsEmpty() || !EncryptPassMatch(password, user.Password) {
 		return user, false
 	}
-	//user.UpdatePwd(EncodePassword([]byte(password)))			// uncomment to enforce security: rewrite new hashed password at each successful access
+	// rewrite the hash with the default hasher, so that the hashes migrate to it at each successful access.
+	if NeedsRehash(user.Password) {
+		if _, err := user.UpdatePwd(EncodePassword(password)); err != nil {
+			logger.Error("rehash password error: ", err)
+		}
+	}
 	return user.WithRoles().WithPermissions().WithMenus(), true
 }
 
 // EncodePassword encode the password.
 func EncodePassword(pwd string) string {
-	return EncryptPass(EncryptPassAlgo, pwd)
+	return EncryptPass(DefaultHasherName(), pwd)
 }
 
-// SetCookie set the cookie.
+// SetCookie set the cookie. The tenant of the request is kept in the session, so
+// that the session can not be used with the other tenants.
 func SetCookie(ctx *context.Context, user models.UserModel, conn db.Connection) error {
 	ses, err := InitSession(ctx, conn)
 	if err != nil { return err }
-	return ses.Add("user_id", user.Id)
+	ses.Values[tenantSesKey] = tenant.Name(ctx)
+	return ses.Add(defaultUserIDSesKey, user.Id)
 }
 
 func DefaultCookie() *http.Cookie {
@@ -0,0 +1,278 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/GoAdminGroup/go-admin/modules/config"
+	"github.com/GoAdminGroup/go-admin/modules/logger"
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+	"golang.org/x/crypto/scrypt"
+)
+
+// Hasher hashes the passwords into a self-describing format, so that a hash tells
+// which hasher and which parameters made it.
+type Hasher interface {
+	// Name is the identifier of the hasher in the hash, like "argon2id" of
+	// "$argon2id$v=19$m=65536,t=3,p=2$salt$key".
+	Name() string
+	Hash(pass string) (string, error)
+	Verify(pass, hashedPass string) bool
+	// NeedsRehash reports whether the hash is made with weaker parameters than the
+	// current ones of the hasher.
+	NeedsRehash(hashedPass string) bool
+}
+
+var (
+	hasherLock sync.RWMutex
+	hashers    = make(map[string]Hasher)
+
+	errWrongHash = errors.New("wrong password hash")
+)
+
+func init() {
+	RegisterHasher(BcryptHasher{ Cost: bcrypt.DefaultCost })
+	RegisterHasher(Argon2idHasher{ Time: 3, Memory: 64 * 1024, Threads: 2, KeyLen: 32, SaltLen: 16 })
+	RegisterHasher(ScryptHasher{ LogN: 15, R: 8, P: 1, KeyLen: 32, SaltLen: 16 })
+}
+
+// RegisterHasher registers the hasher, a hasher of the same name is replaced.
+func RegisterHasher(h Hasher) {
+	hasherLock.Lock()
+	defer hasherLock.Unlock()
+	hashers[h.Name()] = h
+}
+
+// GetHasher return the registered hasher of the name.
+func GetHasher(name string) (Hasher, bool) {
+	hasherLock.RLock()
+	defer hasherLock.RUnlock()
+	h, ok := hashers[name]
+	return h, ok
+}
+
+// HasherOf return the registered hasher which made the hash.
+func HasherOf(hashedPass string) (Hasher, bool) {
+	return GetHasher(hashName(hashedPass))
+}
+
+// DefaultHasherName return the name of the hasher of the new passwords, the
+// EncryptPassAlgo overrides the config when it is set.
+func DefaultHasherName() string {
+	if EncryptPassAlgo != "" {
+		return EncryptPassAlgo
+	}
+	return config.GetPasswordHasher()
+}
+
+// NeedsRehash reports whether the hash should be replaced by a hash of the default
+// hasher, it is false when the hash is not made by a registered hasher.
+func NeedsRehash(hashedPass string) bool {
+	h, ok := HasherOf(hashedPass)
+	if !ok {
+		return false
+	}
+	if h.Name() != DefaultHasherName() {
+		_, ok = GetHasher(DefaultHasherName())
+		return ok
+	}
+	return h.NeedsRehash(hashedPass)
+}
+
+func hashName(hashedPass string) string {
+	if strings.HasPrefix(hashedPass, "$2a$") || strings.HasPrefix(hashedPass, "$2b$") ||
+		strings.HasPrefix(hashedPass, "$2y$") {
+		return "bcrypt"
+	}
+	parts := strings.SplitN(hashedPass, "$", 3)
+	if len(parts) < 3 || parts[0] != "" {
+		return ""
+	}
+	return parts[1]
+}
+
+func defaultEncryptPass(algo, pass string) string {
+	h, ok := GetHasher(algo)
+	if !ok {
+		logger.Error("password hasher is not registered, use bcrypt instead: ", algo)
+		h, _ = GetHasher("bcrypt")
+	}
+	hashed, err := h.Hash(pass)
+	if err != nil {
+		panic(err)
+	}
+	return hashed
+}
+
+func defaultEncryptPassMatch(pass, hashedPass string) bool {
+	h, ok := HasherOf(hashedPass)
+	if !ok {
+		return false
+	}
+	return h.Verify(pass, hashedPass)
+}
+
+func newSalt(n int) ([]byte, error) {
+	salt := make([]byte, n)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+	return salt, nil
+}
+
+var b64 = base64.RawStdEncoding
+
+// BcryptHasher hashes the passwords with bcrypt, the hash is in the standard
+// format like "$2a$10$...".
+type BcryptHasher struct {
+	Cost int
+}
+
+func (b BcryptHasher) Name() string { return "bcrypt" }
+
+func (b BcryptHasher) Hash(pass string) (string, error) {
+	hashed, err := bcrypt.GenerateFromPassword([]byte(pass), b.Cost)
+	if err != nil {
+		return "", err
+	}
+	return string(hashed), nil
+}
+
+func (b BcryptHasher) Verify(pass, hashedPass string) bool {
+	return bcrypt.CompareHashAndPassword([]byte(hashedPass), []byte(pass)) == nil
+}
+
+func (b BcryptHasher) NeedsRehash(hashedPass string) bool {
+	cost, err := bcrypt.Cost([]byte(hashedPass))
+	return err == nil && cost < b.Cost
+}
+
+// Argon2idHasher hashes the passwords with argon2id, the hash is in the PHC string
+// format like "$argon2id$v=19$m=65536,t=3,p=2$salt$key".
+type Argon2idHasher struct {
+	Time    uint32
+	Memory  uint32
+	Threads uint8
+	KeyLen  uint32
+	SaltLen int
+}
+
+func (a Argon2idHasher) Name() string { return "argon2id" }
+
+func (a Argon2idHasher) Hash(pass string) (string, error) {
+	salt, err := newSalt(a.SaltLen)
+	if err != nil {
+		return "", err
+	}
+	key := argon2.IDKey([]byte(pass), salt, a.Time, a.Memory, a.Threads, a.KeyLen)
+	return fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s", argon2.Version, a.Memory, a.Time, a.Threads,
+		b64.EncodeToString(salt), b64.EncodeToString(key)), nil
+}
+
+func (a Argon2idHasher) decode(hashedPass string) (Argon2idHasher, []byte, []byte, error) {
+	var (
+		version int
+		p       Argon2idHasher
+	)
+	parts := strings.Split(hashedPass, "$")
+	if len(parts) != 6 || parts[1] != a.Name() {
+		return p, nil, nil, errWrongHash
+	}
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return p, nil, nil, errWrongHash
+	}
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &p.Memory, &p.Time, &p.Threads); err != nil {
+		return p, nil, nil, errWrongHash
+	}
+	salt, err := b64.DecodeString(parts[4])
+	if err != nil {
+		return p, nil, nil, errWrongHash
+	}
+	key, err := b64.DecodeString(parts[5])
+	if err != nil || len(key) == 0 {
+		return p, nil, nil, errWrongHash
+	}
+	p.KeyLen = uint32(len(key))
+	return p, salt, key, nil
+}
+
+func (a Argon2idHasher) Verify(pass, hashedPass string) bool {
+	p, salt, key, err := a.decode(hashedPass)
+	if err != nil {
+		return false
+	}
+	other := argon2.IDKey([]byte(pass), salt, p.Time, p.Memory, p.Threads, p.KeyLen)
+	return subtle.ConstantTimeCompare(key, other) == 1
+}
+
+func (a Argon2idHasher) NeedsRehash(hashedPass string) bool {
+	p, _, _, err := a.decode(hashedPass)
+	return err == nil && (p.Time < a.Time || p.Memory < a.Memory || p.KeyLen < a.KeyLen)
+}
+
+// ScryptHasher hashes the passwords with scrypt, the hash is in the PHC string
+// format like "$scrypt$ln=15,r=8,p=1$salt$key".
+type ScryptHasher struct {
+	LogN    int
+	R       int
+	P       int
+	KeyLen  int
+	SaltLen int
+}
+
+func (s ScryptHasher) Name() string { return "scrypt" }
+
+func (s ScryptHasher) Hash(pass string) (string, error) {
+	salt, err := newSalt(s.SaltLen)
+	if err != nil {
+		return "", err
+	}
+	key, err := scrypt.Key([]byte(pass), salt, 1 << uint(s.LogN), s.R, s.P, s.KeyLen)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("$scrypt$ln=%d,r=%d,p=%d$%s$%s", s.LogN, s.R, s.P,
+		b64.EncodeToString(salt), b64.EncodeToString(key)), nil
+}
+
+func (s ScryptHasher) decode(hashedPass string) (ScryptHasher, []byte, []byte, error) {
+	var p ScryptHasher
+	parts := strings.Split(hashedPass, "$")
+	if len(parts) != 5 || parts[1] != s.Name() {
+		return p, nil, nil, errWrongHash
+	}
+	if _, err := fmt.Sscanf(parts[2], "ln=%d,r=%d,p=%d", &p.LogN, &p.R, &p.P); err != nil ||
+		p.LogN <= 0 || p.LogN >= 32 {
+		return p, nil, nil, errWrongHash
+	}
+	salt, err := b64.DecodeString(parts[3])
+	if err != nil {
+		return p, nil, nil, errWrongHash
+	}
+	key, err := b64.DecodeString(parts[4])
+	if err != nil || len(key) == 0 {
+		return p, nil, nil, errWrongHash
+	}
+	p.KeyLen = len(key)
+	return p, salt, key, nil
+}
+
+func (s ScryptHasher) Verify(pass, hashedPass string) bool {
+	p, salt, key, err := s.decode(hashedPass)
+	if err != nil {
+		return false
+	}
+	other, err := scrypt.Key([]byte(pass), salt, 1 << uint(p.LogN), p.R, p.P, p.KeyLen)
+	return err == nil && subtle.ConstantTimeCompare(key, other) == 1
+}
+
+func (s ScryptHasher) NeedsRehash(hashedPass string) bool {
+	p, _, _, err := s.decode(hashedPass)
+	return err == nil && (p.LogN < s.LogN || p.R < s.R || p.KeyLen < s.KeyLen)
+}
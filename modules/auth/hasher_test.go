@@ -0,0 +1,46 @@
+package auth
+
+import (
+	"testing"
+
+	"github.com/magiconair/properties/assert"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestHasher(t *testing.T) {
+	for _, name := range []string{"bcrypt", "argon2id", "scrypt"} {
+		h, ok := GetHasher(name)
+		assert.Equal(t, ok, true)
+
+		hashed, err := h.Hash("admin")
+		assert.Equal(t, err, nil)
+
+		found, ok := HasherOf(hashed)
+		assert.Equal(t, ok, true)
+		assert.Equal(t, found.Name(), name)
+
+		assert.Equal(t, defaultEncryptPassMatch("admin", hashed), true)
+		assert.Equal(t, defaultEncryptPassMatch("admin2", hashed), false)
+		assert.Equal(t, h.NeedsRehash(hashed), false)
+	}
+
+	assert.Equal(t, defaultEncryptPassMatch("admin", "admin"), false)
+	assert.Equal(t, defaultEncryptPassMatch("admin", "$argon2id$v=19$m=1,t=1,p=1$$"), false)
+}
+
+func TestNeedsRehash(t *testing.T) {
+	EncryptPassAlgo = "argon2id"
+	defer func() { EncryptPassAlgo = "" }()
+
+	weak, _ := BcryptHasher{ Cost: bcrypt.MinCost }.Hash("admin")
+	assert.Equal(t, NeedsRehash(weak), true)
+
+	hashed := defaultEncryptPass(DefaultHasherName(), "admin")
+	assert.Equal(t, NeedsRehash(hashed), false)
+
+	weak, _ = Argon2idHasher{ Time: 1, Memory: 1024, Threads: 1, KeyLen: 32, SaltLen: 16 }.Hash("admin")
+	assert.Equal(t, NeedsRehash(weak), true)
+	assert.Equal(t, defaultEncryptPassMatch("admin", weak), true)
+
+	assert.Equal(t, NeedsRehash("plain"), false)
+}
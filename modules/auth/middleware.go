@@ -12,6 +12,7 @@ import (
 	"github.com/GoAdminGroup/go-admin/modules/language"
 	"github.com/GoAdminGroup/go-admin/modules/logger"
 	"github.com/GoAdminGroup/go-admin/modules/page"
+	"github.com/GoAdminGroup/go-admin/modules/tenant"
 	"github.com/GoAdminGroup/go-admin/modules/utils"
 	"github.com/GoAdminGroup/go-admin/plugins/admin/models"
 	"github.com/GoAdminGroup/go-admin/template"
@@ -21,7 +22,10 @@ import (
 	"strings"
 )
 
-const defaultUserIDSesKey = "user_id"
+const (
+	defaultUserIDSesKey = "user_id"
+	tenantSesKey        = "tenant"
+)
 
 // Invoker contains the callback functions which are used
 // in the route middleware.
@@ -94,7 +98,7 @@ func DefaultInvoker(conn db.Connection) *Invoker {
 			} else {
 				page.SetPageContent(ctx, Auth(ctx), func(ctx interface{}) (types.Panel, error) {
 					return template.WarningPanel(errors.PermissionDenied, template.NoPermission403Page), nil
-				}, conn)
+				}, tenant.Conn(ctx, conn))
 			}
 		},
 		conn: conn,
@@ -126,7 +130,7 @@ type MiddlewareCallback func(ctx *context.Context)
 // Middleware get the auth middleware from Invoker.
 func (invoker *Invoker) Middleware() context.Handler {
 	return func(ctx *context.Context) {
-		user, authOk, permissionOk := Filter(ctx, invoker.conn)
+		user, authOk, permissionOk := Filter(ctx, tenant.Conn(ctx, invoker.conn))
 		if authOk && permissionOk {
 			ctx.SetUserValue("user", user)
 			ctx.Next()
@@ -162,6 +166,11 @@ func Filter(ctx *context.Context, conn db.Connection) (models.UserModel, bool, b
 		return user, false, false
 	}
 
+	// the ids of the users are only unique in the database of a tenant.
+	if name, _ := ses.Get(tenantSesKey).(string); name != tenant.Name(ctx) {
+		return user, false, false
+	}
+
 	user, ok = GetCurUserByID(int64(id), conn)
 	if !ok {
 		return user, false, false
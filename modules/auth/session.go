@@ -7,6 +7,7 @@ package auth
 import (
 	"net/http"
 	"strconv"
+	"sync"
 	"time"
 
 	"github.com/GoAdminGroup/go-admin/context"
@@ -20,6 +21,19 @@ import (
 
 const DefaultCookieKey = "_f_"
 
+const (
+	// SessionDriverDatabase stores the sessions in the goadmin_session table.
+	SessionDriverDatabase = "database"
+	// SessionDriverMemory stores the sessions in a sharded in-memory map.
+	SessionDriverMemory = "memory"
+	// SessionDriverFile stores the sessions as files of a local directory.
+	SessionDriverFile = "file"
+)
+
+// SessionJanitorInterval is the interval of the janitor removing the overdue
+// sessions, it should be set before the engine is used.
+var SessionJanitorInterval = 10 * time.Minute
+
 // NewDBDriver return the default PersistenceDriver.
 func newDBDriver(conn db.Connection) *DBDriver {
 	return &DBDriver{
@@ -34,9 +48,131 @@ type PersistenceDriver interface {
 	Update(sid string, values map[string]interface{}) error
 }
 
+// OverdueCleaner is implemented by the PersistenceDriver which can remove
+// the overdue sessions, it is called by the session janitor on schedule.
+type OverdueCleaner interface {
+	DeleteOverdue() error
+}
+
+// PersistenceDriverCreator creates the PersistenceDriver of given connection.
+type PersistenceDriverCreator func(conn db.Connection) PersistenceDriver
+
+var (
+	sessionDriverLock sync.RWMutex
+	sessionDrivers    = map[string]PersistenceDriverCreator{
+		SessionDriverDatabase: func(conn db.Connection) PersistenceDriver { return newDBDriver(conn) },
+		SessionDriverMemory  : func(conn db.Connection) PersistenceDriver { return defaultMemoryDriver() },
+		SessionDriverFile    : func(conn db.Connection) PersistenceDriver {
+			driver, err := defaultFileDriver()
+			if err != nil {
+				logger.Error("session file driver error: ", err)
+				return failedDriver{ err: err }
+			}
+			return driver
+		},
+	}
+	sessionJanitorLock sync.Mutex
+	sessionJanitors    = make(map[string]struct{})
+)
+
+// failedDriver is the driver of which the creation failed, it fails the loads and
+// the updates of the sessions with the error of the creation.
+type failedDriver struct {
+	err error
+}
+
+func (driver failedDriver) Load(string) (map[string]interface{}, error) { return nil, driver.err }
+
+func (driver failedDriver) Update(string, map[string]interface{}) error { return driver.err }
+
+// CheckSessionDriver checks the session driver of the config, it should be called
+// at the start so that a wrong session directory fails early.
+func CheckSessionDriver() error {
+	if config.GetSessionDriver() != SessionDriverFile {
+		return nil
+	}
+	_, err := defaultFileDriver()
+	return err
+}
+
+// RegisterSessionDriver register a PersistenceDriver with the given name, which
+// can be selected by the session_driver item of the config.
+func RegisterSessionDriver(name string, creator PersistenceDriverCreator) {
+	sessionDriverLock.Lock()
+	defer sessionDriverLock.Unlock()
+	if creator == nil {
+		panic("session driver creator is nil")
+	}
+	sessionDrivers[name] = creator
+}
+
+// GetSessionDriver return the PersistenceDriver of the configured session driver,
+// the database driver is used when the configured one is not registered.
+func GetSessionDriver(conn db.Connection) PersistenceDriver {
+	name := config.GetSessionDriver()
+
+	sessionDriverLock.RLock()
+	creator, ok := sessionDrivers[name]
+	if !ok {
+		creator = sessionDrivers[SessionDriverDatabase]
+	}
+	sessionDriverLock.RUnlock()
+
+	if !ok && name != "" {
+		logger.Warn("session driver not found: ", name)
+	}
+
+	driver := creator(conn)
+	startSessionJanitor(sessionJanitorKey(name, driver), driver)
+	return driver
+}
+
+// sessionJanitorKey return the key of the janitor of the driver, the sessions of
+// the database driver are kept in the database of every connection, such as the
+// ones of the tenants.
+func sessionJanitorKey(name string, driver PersistenceDriver) string {
+	if d, ok := driver.(*DBDriver); ok && d.conn != nil {
+		return name + ":" + db.ConnectionName(d.conn, "")
+	}
+	return name
+}
+
+// startSessionJanitor removes the overdue sessions of the driver every
+// SessionJanitorInterval, a janitor is started once for every key.
+func startSessionJanitor(key string, driver PersistenceDriver) {
+	cleaner, ok := driver.(OverdueCleaner)
+	if !ok || SessionJanitorInterval <= 0 { return }
+
+	sessionJanitorLock.Lock()
+	_, started := sessionJanitors[key]
+	sessionJanitors[key] = struct{}{}
+	sessionJanitorLock.Unlock()
+	if started { return }
+
+	clean := func() {
+		defer func() {
+			if err := recover(); err != nil {
+				logger.Error("session janitor panic: ", err)
+			}
+		}()
+		if err := cleaner.DeleteOverdue(); err != nil {
+			logger.Error("delete overdue sessions error: ", err)
+		}
+	}
+
+	go func() {
+		ticker := time.NewTicker(SessionJanitorInterval)
+		defer ticker.Stop()
+		for {
+			clean()
+			<-ticker.C
+		}
+	}()
+}
+
 // GetSessionByKey get the session value by key.
 func GetSessionByKey(sesKey, key string, conn db.Connection) (interface{}, error) {
-	m, err := newDBDriver(conn).Load(sesKey)
+	m, err := GetSessionDriver(conn).Load(sesKey)
 	return m[key], err
 }
 
@@ -128,7 +264,7 @@ func initSession(conn db.Connection) *Session {
 		Cookie:  DefaultCookieKey,
 	})
 
-	ses.UseDriver(newDBDriver(conn))
+	ses.UseDriver(GetSessionDriver(conn))
 	ses.Values = make(map[string]interface{})
 
 	return ses
@@ -168,14 +304,16 @@ func (driver *DBDriver) Load(sid string) (map[string]interface{}, error) {
 	return values, err
 }
 
-func (driver *DBDriver) deleteOverdueSession() {
-	defer func() {
-		if err := recover(); err != nil {
-			logger.Error(err)
-			panic(err)
-		}
-	}()
+// DeleteOverdue implements the OverdueCleaner.DeleteOverdue.
+func (driver *DBDriver) DeleteOverdue() error {
+	err := driver.deleteOverdueSession()
+	if db.CheckError(err, db.DELETE) {
+		return err
+	}
+	return nil
+}
 
+func (driver *DBDriver) deleteOverdueSession() error {
 	duration   := strconv.Itoa(config.GetSessionLifeTime() + 1000)
 	driverName := config.GetDatabases().GetDefault().Driver
 	raw        := ""
@@ -190,16 +328,14 @@ func (driver *DBDriver) deleteOverdueSession() {
 	case db.DriverSqlite:
 		raw = `strftime('%s', created_at) < strftime('%s', 'now') - ` + duration
 	default:
-		return
+		return nil
 	}
 
-	_ = driver.table().WhereRaw(raw).Delete()
+	return driver.table().WhereRaw(raw).Delete()
 }
 
 // Update implements the PersistenceDriver.Update.
 func (driver *DBDriver) Update(sid string, values map[string]interface{}) error {
-	go driver.deleteOverdueSession()
-
 	if sid != "" {
 		if len(values) == 0 {
 			err := driver.table().Where("sid", "=", sid).Delete()
@@ -242,5 +378,5 @@ func (driver *DBDriver) Update(sid string, values map[string]interface{}) error
 }
 
 func (driver *DBDriver) table() *db.SQL {
-	return db.Table(driver.tableName).WithDriver(driver.conn)
+	return db.Table(driver.tableName).WithDriver(driver.conn).WithPrimary()
 }
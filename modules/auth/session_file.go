@@ -0,0 +1,190 @@
+// Copyright 2019 GoAdmin Core Team. All rights reserved.
+// Use of this source code is governed by a Apache-2.0 style
+// license that can be found in the LICENSE file.
+
+package auth
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/GoAdminGroup/go-admin/modules/config"
+	"github.com/GoAdminGroup/go-admin/modules/utils"
+)
+
+const sessionFileSuffix = ".session"
+
+var (
+	fileDriver     *FileDriver
+	fileDriverLock sync.Mutex
+)
+
+// defaultFileDriver return the FileDriver of the directory of the config, it is
+// created at the first call. A failed creation is not kept, so that it is tried
+// again after the directory is fixed.
+func defaultFileDriver() (*FileDriver, error) {
+	fileDriverLock.Lock()
+	defer fileDriverLock.Unlock()
+	if fileDriver != nil {
+		return fileDriver, nil
+	}
+	dir := config.GetSessionFileDir()
+	if dir == "" {
+		dir = filepath.Join(os.TempDir(), "goadmin_session")
+	}
+	driver, err := NewFileDriver(dir)
+	if err != nil { return nil, err }
+	fileDriver = driver
+	return fileDriver, nil
+}
+
+// FileDriver is a driver which stores every session as a file of the given
+// directory, it fits the deployments of a single node. A session expires
+// when its file is not modified within the session life time.
+type FileDriver struct {
+	dir  string
+	lock sync.RWMutex
+}
+
+// NewFileDriver return a new FileDriver with the given directory, the directory
+// is created if not exists. The directory must be private to the current user,
+// since the sessions in it are as good as the logins.
+func NewFileDriver(dir string) (*FileDriver, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil { return nil, err }
+	if err := checkSessionDir(dir); err != nil { return nil, err }
+	return &FileDriver{ dir: dir }, nil
+}
+
+// checkSessionDir checks that the directory is a real directory which can
+// only be accessed by the current user. The default directory lives in the
+// shared temporary directory, where another user could have created it first.
+func checkSessionDir(dir string) error {
+	info, err := os.Lstat(dir)
+	if err != nil { return err }
+	if !info.IsDir() {
+		return errors.New("session directory " + dir + " is not a directory")
+	}
+	if !privateToCurrentUser(info) {
+		return errors.New("session directory " + dir + " must be owned by the current user with the mode 0700")
+	}
+	return nil
+}
+
+// path return the file path of the sid. The sid comes from the cookie, so it
+// is hashed rather than used as the file name directly.
+func (driver *FileDriver) path(sid string) string {
+	sum := sha256.Sum256([]byte(sid))
+	return filepath.Join(driver.dir, hex.EncodeToString(sum[:]) + sessionFileSuffix)
+}
+
+func (driver *FileDriver) lifeTime() time.Duration {
+	return time.Duration(config.GetSessionLifeTime()) * time.Second
+}
+
+// Load implements the PersistenceDriver.Load.
+func (driver *FileDriver) Load(sid string) (map[string]interface{}, error) {
+	p := driver.path(sid)
+
+	driver.lock.RLock()
+	info, err := os.Stat(p)
+	if err != nil {
+		driver.lock.RUnlock()
+		if os.IsNotExist(err) { return map[string]interface{}{}, nil }
+		return nil, err
+	}
+	if time.Since(info.ModTime()) > driver.lifeTime() {
+		driver.lock.RUnlock()
+		driver.lock.Lock()
+		_ = os.Remove(p)
+		driver.lock.Unlock()
+		return map[string]interface{}{}, nil
+	}
+	content, err := os.ReadFile(p)
+	driver.lock.RUnlock()
+
+	if err != nil {
+		if os.IsNotExist(err) { return map[string]interface{}{}, nil }
+		return nil, err
+	}
+
+	var values map[string]interface{}
+	err = utils.JsonUnmarshal(content, &values)
+	return values, err
+}
+
+// Update implements the PersistenceDriver.Update.
+func (driver *FileDriver) Update(sid string, values map[string]interface{}) error {
+	if sid == "" { return nil }
+
+	p := driver.path(sid)
+
+	driver.lock.Lock()
+	defer driver.lock.Unlock()
+
+	if len(values) == 0 {
+		if err := os.Remove(p); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return nil
+	}
+
+	valuesByte, err := utils.JsonMarshal(values)
+	if err != nil { return err }
+
+	// the same as DBDriver, a new session replaces the others with the same
+	// values when the login ip is limited.
+	if _, err := os.Stat(p); os.IsNotExist(err) && !config.GetNoLimitLoginIP() {
+		driver.walk(func(file string, info os.FileInfo) {
+			if content, err := os.ReadFile(file); err == nil && bytes.Equal(content, valuesByte) {
+				_ = os.Remove(file)
+			}
+		})
+	}
+
+	// write to a temporary file and rename it, so that a concurrent reader
+	// never sees a partial file.
+	tmp, err := os.CreateTemp(driver.dir, "tmp-*")
+	if err != nil { return err }
+	if _, err := tmp.Write(valuesByte); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), p)
+}
+
+// DeleteOverdue implements the OverdueCleaner.DeleteOverdue.
+func (driver *FileDriver) DeleteOverdue() error {
+	driver.lock.Lock()
+	defer driver.lock.Unlock()
+
+	lifeTime := driver.lifeTime()
+	driver.walk(func(file string, info os.FileInfo) {
+		if time.Since(info.ModTime()) > lifeTime {
+			_ = os.Remove(file)
+		}
+	})
+	return nil
+}
+
+func (driver *FileDriver) walk(fn func(file string, info os.FileInfo)) {
+	entries, err := os.ReadDir(driver.dir)
+	if err != nil { return }
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), sessionFileSuffix) { continue }
+		info, err := entry.Info()
+		if err != nil { continue }
+		fn(filepath.Join(driver.dir, entry.Name()), info)
+	}
+}
@@ -0,0 +1,18 @@
+// Copyright 2019 GoAdmin Core Team. All rights reserved.
+// Use of this source code is governed by a Apache-2.0 style
+// license that can be found in the LICENSE file.
+
+//go:build !windows
+
+package auth
+
+import (
+	"os"
+	"syscall"
+)
+
+func privateToCurrentUser(info os.FileInfo) bool {
+	if info.Mode().Perm() & 0077 != 0 { return false }
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	return ok && int(stat.Uid) == os.Getuid()
+}
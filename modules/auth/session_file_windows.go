@@ -0,0 +1,13 @@
+// Copyright 2019 GoAdmin Core Team. All rights reserved.
+// Use of this source code is governed by a Apache-2.0 style
+// license that can be found in the LICENSE file.
+
+package auth
+
+import "os"
+
+// privateToCurrentUser always reports true on windows, where the access is
+// controlled by the ACL of the directory rather than the file mode.
+func privateToCurrentUser(info os.FileInfo) bool {
+	return true
+}
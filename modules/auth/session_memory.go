@@ -0,0 +1,142 @@
+// Copyright 2019 GoAdmin Core Team. All rights reserved.
+// Use of this source code is governed by a Apache-2.0 style
+// license that can be found in the LICENSE file.
+
+package auth
+
+import (
+	"hash/fnv"
+	"sync"
+	"time"
+
+	"github.com/GoAdminGroup/go-admin/modules/config"
+	"github.com/GoAdminGroup/go-admin/modules/utils"
+)
+
+const memoryDriverShardCount = 32
+
+var (
+	memoryDriver     *MemoryDriver
+	memoryDriverOnce sync.Once
+)
+
+func defaultMemoryDriver() *MemoryDriver {
+	memoryDriverOnce.Do(func() { memoryDriver = NewMemoryDriver() })
+	return memoryDriver
+}
+
+// MemoryDriver is a driver which keeps the sessions in the memory of the
+// current process. The sessions are split into shards by the sid to reduce
+// the lock contention, and expire after the session life time.
+type MemoryDriver struct {
+	shards [memoryDriverShardCount]*memorySessionShard
+}
+
+type memorySessionShard struct {
+	lock  sync.RWMutex
+	items map[string]memorySession
+}
+
+type memorySession struct {
+	// values are kept marshaled, so that the loaded values have the same
+	// types as the ones of the other drivers.
+	values   []byte
+	expireAt time.Time
+}
+
+// NewMemoryDriver return a new MemoryDriver.
+func NewMemoryDriver() *MemoryDriver {
+	driver := new(MemoryDriver)
+	for i := range driver.shards {
+		driver.shards[i] = &memorySessionShard{ items: make(map[string]memorySession) }
+	}
+	return driver
+}
+
+func (driver *MemoryDriver) shard(sid string) *memorySessionShard {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(sid))
+	return driver.shards[h.Sum32() % memoryDriverShardCount]
+}
+
+// Load implements the PersistenceDriver.Load.
+func (driver *MemoryDriver) Load(sid string) (map[string]interface{}, error) {
+	shard := driver.shard(sid)
+
+	shard.lock.RLock()
+	item, ok := shard.items[sid]
+	shard.lock.RUnlock()
+
+	if !ok { return map[string]interface{}{}, nil }
+
+	if time.Now().After(item.expireAt) {
+		shard.lock.Lock()
+		delete(shard.items, sid)
+		shard.lock.Unlock()
+		return map[string]interface{}{}, nil
+	}
+
+	var values map[string]interface{}
+	err := utils.JsonUnmarshal(item.values, &values)
+	return values, err
+}
+
+// Update implements the PersistenceDriver.Update.
+func (driver *MemoryDriver) Update(sid string, values map[string]interface{}) error {
+	if sid == "" { return nil }
+
+	shard := driver.shard(sid)
+
+	if len(values) == 0 {
+		shard.lock.Lock()
+		delete(shard.items, sid)
+		shard.lock.Unlock()
+		return nil
+	}
+
+	valuesByte, err := utils.JsonMarshal(values)
+	if err != nil { return err }
+
+	shard.lock.RLock()
+	_, exist := shard.items[sid]
+	shard.lock.RUnlock()
+
+	// the same as DBDriver, a new session replaces the others with the same
+	// values when the login ip is limited.
+	if !exist && !config.GetNoLimitLoginIP() {
+		sesValue := string(valuesByte)
+		for _, s := range driver.shards {
+			s.lock.Lock()
+			for key, item := range s.items {
+				if string(item.values) == sesValue {
+					delete(s.items, key)
+				}
+			}
+			s.lock.Unlock()
+		}
+	}
+
+	shard.lock.Lock()
+	shard.items[sid] = memorySession{
+		values  : valuesByte,
+		expireAt: time.Now().Add(time.Duration(config.GetSessionLifeTime()) * time.Second),
+	}
+	shard.lock.Unlock()
+
+	return nil
+}
+
+// DeleteOverdue implements the OverdueCleaner.DeleteOverdue.
+func (driver *MemoryDriver) DeleteOverdue() error {
+	now := time.Now()
+	for _, shard := range driver.shards {
+		shard.lock.Lock()
+		for key, item := range shard.items {
+			if now.After(item.expireAt) {
+				delete(shard.items, key)
+			}
+		}
+		shard.lock.Unlock()
+	}
+	return nil
+}
@@ -0,0 +1,126 @@
+package auth
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/GoAdminGroup/go-admin/context"
+	"github.com/GoAdminGroup/go-admin/modules/config"
+	"github.com/GoAdminGroup/go-admin/modules/db"
+	"github.com/GoAdminGroup/go-admin/modules/tenant"
+	"github.com/magiconair/properties/assert"
+)
+
+func testPersistenceDriver(t *testing.T, driver PersistenceDriver) {
+	values, err := driver.Load("missing")
+	assert.Equal(t, err, nil)
+	assert.Equal(t, values, map[string]interface{}{})
+
+	assert.Equal(t, driver.Update("a", map[string]interface{}{ "user_id": 1 }), nil)
+	values, err = driver.Load("a")
+	assert.Equal(t, err, nil)
+	assert.Equal(t, values, map[string]interface{}{ "user_id": float64(1) })
+
+	// a new session with the same values replaces the old one.
+	assert.Equal(t, driver.Update("b", map[string]interface{}{ "user_id": 1 }), nil)
+	values, _ = driver.Load("a")
+	assert.Equal(t, values, map[string]interface{}{})
+	values, _ = driver.Load("b")
+	assert.Equal(t, values, map[string]interface{}{ "user_id": float64(1) })
+
+	assert.Equal(t, driver.Update("b", nil), nil)
+	values, _ = driver.Load("b")
+	assert.Equal(t, values, map[string]interface{}{})
+}
+
+func TestMemoryDriver(t *testing.T) {
+	config.Initialize(&config.Config{ SessionLifeTime: 60 })
+
+	driver := NewMemoryDriver()
+	testPersistenceDriver(t, driver)
+
+	assert.Equal(t, driver.Update("c", map[string]interface{}{ "user_id": 2 }), nil)
+	shard := driver.shard("c")
+	item  := shard.items["c"]
+	item.expireAt = time.Now().Add(-time.Second)
+	shard.items["c"] = item
+
+	assert.Equal(t, driver.DeleteOverdue(), nil)
+	_, ok := shard.items["c"]
+	assert.Equal(t, ok, false)
+}
+
+func TestFileDriver(t *testing.T) {
+	config.Initialize(&config.Config{ SessionLifeTime: 60 })
+
+	dir         := filepath.Join(t.TempDir(), "session")
+	driver, err := NewFileDriver(dir)
+	assert.Equal(t, err, nil)
+	testPersistenceDriver(t, driver)
+
+	assert.Equal(t, driver.Update("c", map[string]interface{}{ "user_id": 2 }), nil)
+	past := time.Now().Add(-time.Hour)
+	assert.Equal(t, os.Chtimes(driver.path("c"), past, past), nil)
+
+	assert.Equal(t, driver.DeleteOverdue(), nil)
+	_, err = os.Stat(driver.path("c"))
+	assert.Equal(t, os.IsNotExist(err), true)
+
+	assert.Equal(t, os.Chmod(dir, 0755), nil)
+	assert.Equal(t, checkSessionDir(dir) != nil, true)
+	assert.Equal(t, os.Chmod(dir, 0700), nil)
+	assert.Equal(t, checkSessionDir(dir), nil)
+
+	link := filepath.Join(t.TempDir(), "link")
+	assert.Equal(t, os.Symlink(dir, link), nil)
+	assert.Equal(t, checkSessionDir(link) != nil, true)
+}
+
+func TestDefaultFileDriver(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "session")
+	assert.Equal(t, os.Mkdir(dir, 0755), nil)
+	config.Initialize(&config.Config{ SessionLifeTime: 60, SessionDriver: SessionDriverFile, SessionFileDir: dir })
+
+	// a wrong directory fails the check at the start and the sessions, and it is
+	// not kept after the directory is fixed.
+	assert.Equal(t, CheckSessionDriver() != nil, true)
+	_, err := GetSessionDriver(nil).Load("sid")
+	assert.Equal(t, err != nil, true)
+
+	assert.Equal(t, os.Chmod(dir, 0700), nil)
+	assert.Equal(t, CheckSessionDriver(), nil)
+	_, ok := GetSessionDriver(nil).(*FileDriver)
+	assert.Equal(t, ok, true)
+}
+
+func TestSessionJanitorKey(t *testing.T) {
+	conn := db.GetSqliteDB()
+	assert.Equal(t, sessionJanitorKey(SessionDriverDatabase, newDBDriver(conn)), "database:default")
+	assert.Equal(t, sessionJanitorKey(SessionDriverDatabase, newDBDriver(db.WithConnectionName(conn, "a"))), "database:a")
+	assert.Equal(t, sessionJanitorKey(SessionDriverMemory, defaultMemoryDriver()), "memory")
+}
+
+func TestFilterTenant(t *testing.T) {
+	config.Initialize(&config.Config{ SessionLifeTime: 60, SessionDriver: SessionDriverMemory })
+
+	assert.Equal(t, defaultMemoryDriver().Update("sid-a", map[string]interface{}{ "user_id": 1, "tenant": "a" }), nil)
+
+	filter := func(name string) bool {
+		req := httptest.NewRequest("GET", "/admin", nil)
+		req.AddCookie(&http.Cookie{ Name: DefaultCookieKey, Value: "sid-a" })
+		ctx := context.NewContext(req)
+		if name != "" {
+			ctx.SetUserValue(tenant.ContextKey, &tenant.Tenant{ Name: name })
+		}
+		_, ok, _ := Filter(ctx, nil)
+		return ok
+	}
+
+	// the session of the tenant a is rejected by the other tenants and the globals.
+	assert.Equal(t, filter("b"), false)
+	assert.Equal(t, filter(""), false)
+}
@@ -0,0 +1,169 @@
+package auth
+
+import (
+	"net"
+	"strings"
+	"time"
+
+	"github.com/GoAdminGroup/go-admin/context"
+	"github.com/GoAdminGroup/go-admin/modules/config"
+	"github.com/GoAdminGroup/go-admin/modules/db"
+	"github.com/GoAdminGroup/go-admin/modules/logger"
+	"github.com/GoAdminGroup/go-admin/plugins/admin/models"
+)
+
+// LoginThrottle tracks the failed login attempts of the usernames and the IPs.
+// Every failure delays the next attempt with an exponential backoff, and the
+// username or the IP is locked when the failures reach the max attempts of the
+// config. The failures are forgotten after a quiet period of the lockout time.
+type LoginThrottle struct {
+	conn db.Connection
+	Now  func() time.Time
+}
+
+// NewLoginThrottle return a login throttle stored in the database.
+func NewLoginThrottle(conn db.Connection) *LoginThrottle {
+	return &LoginThrottle{ conn: conn, Now: time.Now }
+}
+
+// userAttemptKey return the attempt key of the username, which is normalized so
+// that the case and the spaces of the username do not reset its attempts.
+func userAttemptKey(username string) string {
+	return "user:" + strings.ToLower(strings.TrimSpace(username))
+}
+
+func ipAttemptKey(ip string) string { return "ip:" + ip }
+
+// keys return the attempt keys with their max attempts, the turned off ones are skipped.
+func (t *LoginThrottle) keys(username, ip string) map[string]int {
+	keys := make(map[string]int, 2)
+	if max := config.GetLoginMaxAttempts(); strings.TrimSpace(username) != "" && max > 0 {
+		keys[userAttemptKey(username)] = max
+	}
+	if max := config.GetLoginIPMaxAttempts(); ip != "" && max > 0 {
+		keys[ipAttemptKey(ip)] = max
+	}
+	return keys
+}
+
+// Check return the time to wait before the next attempt of the username from the
+// ip, and whether the username or the ip is locked.
+func (t *LoginThrottle) Check(username, ip string) (time.Duration, bool) {
+	var (
+		now    = t.Now().Unix()
+		wait   int64
+		locked bool
+		keys   = t.keys(username, ip)
+	)
+
+	list := make([]string, 0, len(keys))
+	for key := range keys {
+		list = append(list, key)
+	}
+
+	for _, attempt := range models.LoginAttempt().SetConn(t.conn).FindByKeys(list) {
+		if attempt.BlockedUntil <= now {
+			continue
+		}
+		if attempt.BlockedUntil - now > wait {
+			wait = attempt.BlockedUntil - now
+		}
+		if attempt.Failures >= int64(keys[attempt.Key]) {
+			locked = true
+		}
+	}
+
+	return time.Duration(wait) * time.Second, locked
+}
+
+// Fail records a failed attempt of the username from the ip, it return the time to
+// wait before the next attempt and whether the username or the ip is locked now.
+func (t *LoginThrottle) Fail(username, ip string) (time.Duration, bool) {
+	var (
+		now     = t.Now().Unix()
+		lockout = int64(config.GetLoginLockoutTime())
+		backoff = int64(config.GetLoginBackoffTime())
+		wait    int64
+		locked  bool
+	)
+
+	for key, max := range t.keys(username, ip) {
+		attempt := models.LoginAttempt().SetConn(t.conn).FindByKey(key)
+
+		failures := attempt.Failures
+		if now - attempt.LastFailedAt > lockout {
+			failures = 0
+		}
+		failures++
+
+		keyWait := lockout
+		if failures >= int64(max) {
+			locked = true
+		} else if shift := failures - 1; shift < 30 && backoff << uint(shift) < lockout {
+			keyWait = backoff << uint(shift)
+		}
+		if keyWait > wait {
+			wait = keyWait
+		}
+
+		if _, err := attempt.Save(failures, now, now + keyWait); err != nil {
+			logger.Error("save login attempt error: ", err)
+		}
+	}
+
+	return time.Duration(wait) * time.Second, locked
+}
+
+// Succeed forgets the failed attempts of the username. The failures of the ip are
+// kept, so that a valid account can not be used to reset them.
+func (t *LoginThrottle) Succeed(username string) {
+	if username != "" {
+		_ = models.LoginAttempt().SetConn(t.conn).Reset(userAttemptKey(username))
+	}
+}
+
+// ClientIP return the IP of the client of the request for the login throttle. The
+// forwarded headers are only read when the remote address is a trusted proxy of
+// the config, otherwise they are set by the client, which could change the IP of
+// every attempt. The IP is the last one of the X-Forwarded-For which is not of a
+// trusted proxy, or the X-Real-Ip.
+func ClientIP(ctx *context.Context) string {
+	remote, _, err := net.SplitHostPort(strings.TrimSpace(ctx.Request.RemoteAddr))
+	if err != nil {
+		remote = strings.TrimSpace(ctx.Request.RemoteAddr)
+	}
+
+	if !config.IsTrustedProxy(remote) {
+		return remote
+	}
+
+	hops := strings.Split(ctx.Request.Header.Get("X-Forwarded-For"), ",")
+	for i := len(hops) - 1; i >= 0; i-- {
+		hop := strings.TrimSpace(hops[i])
+		if hop == "" {
+			continue
+		}
+		if !config.IsTrustedProxy(hop) {
+			return hop
+		}
+	}
+	if ip := strings.TrimSpace(ctx.Request.Header.Get("X-Real-Ip")); ip != "" {
+		return ip
+	}
+	return remote
+}
+
+// UnlockLogin forgets the failed attempts of the username.
+func UnlockLogin(username string, conn db.Connection) error {
+	return models.LoginAttempt().SetConn(conn).Reset(userAttemptKey(username))
+}
+
+// IsLoginLocked reports whether the username is locked after too many failed attempts.
+func IsLoginLocked(username string, conn db.Connection) bool {
+	max := config.GetLoginMaxAttempts()
+	if max <= 0 {
+		return false
+	}
+	attempt := models.LoginAttempt().SetConn(conn).FindByKey(userAttemptKey(username))
+	return attempt.Failures >= int64(max) && attempt.BlockedUntil > time.Now().Unix()
+}
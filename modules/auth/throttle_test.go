@@ -0,0 +1,42 @@
+package auth
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/GoAdminGroup/go-admin/context"
+	"github.com/GoAdminGroup/go-admin/modules/config"
+	"github.com/magiconair/properties/assert"
+)
+
+func TestClientIP(t *testing.T) {
+	config.Initialize(&config.Config{ TrustedProxies: []string{ "10.0.0.0/8", "192.168.1.1" } })
+
+	ip := func(remote, forwarded, real string) string {
+		req, _ := http.NewRequest("POST", "/signin", nil)
+		req.RemoteAddr = remote
+		if forwarded != "" {
+			req.Header.Set("X-Forwarded-For", forwarded)
+		}
+		if real != "" {
+			req.Header.Set("X-Real-Ip", real)
+		}
+		return ClientIP(context.NewContext(req))
+	}
+
+	assert.Equal(t, ip("1.2.3.4:5000", "5.6.7.8", "5.6.7.9"), "1.2.3.4")
+	assert.Equal(t, ip("10.1.2.3:5000", "6.6.6.6, 5.6.7.8, 192.168.1.1", ""), "5.6.7.8")
+	assert.Equal(t, ip("192.168.1.1:5000", "", "5.6.7.9"), "5.6.7.9")
+	assert.Equal(t, ip("10.1.2.3:5000", "", ""), "10.1.2.3")
+}
+
+func TestUserAttemptKey(t *testing.T) {
+	config.Initialize(&config.Config{ LoginMaxAttempts: 5, LoginIPMaxAttempts: 20 })
+
+	assert.Equal(t, userAttemptKey(" Admin "), userAttemptKey("admin"))
+	assert.Equal(t, userAttemptKey("ADMIN"), "user:admin")
+
+	throttle := NewLoginThrottle(nil)
+	assert.Equal(t, throttle.keys("Admin", "1.2.3.4"), map[string]int{ "user:admin": 5, "ip:1.2.3.4": 20 })
+	assert.Equal(t, throttle.keys("  ", "1.2.3.4"), map[string]int{ "ip:1.2.3.4": 20 })
+}
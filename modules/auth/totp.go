@@ -0,0 +1,148 @@
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// TOTP generates and validates the time-based one-time passwords of RFC 6238 with
+// HMAC-SHA1, which is supported by the common authenticator apps. The Now is the
+// clock of the validation, it can be fixed in the tests.
+type TOTP struct {
+	Period int64
+	Digits int
+	Skew   int64
+	Now    func() time.Time
+}
+
+// DefaultTOTP is the TOTP of the 30 seconds period and the 6 digits, the codes of
+// the previous and the next period are accepted for the clock drift.
+var DefaultTOTP = TOTP{
+	Period: 30,
+	Digits: 6,
+	Skew  : 1,
+	Now   : time.Now,
+}
+
+var totpEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+var errWrongSecret = errors.New("wrong totp secret")
+
+// NewTOTPSecret return a random base32 encoded secret of 160 bits.
+func NewTOTPSecret() (string, error) {
+	b := make([]byte, 20)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return totpEncoding.EncodeToString(b), nil
+}
+
+func decodeTOTPSecret(secret string) ([]byte, error) {
+	secret = strings.ToUpper(strings.ReplaceAll(strings.TrimRight(secret, "="), " ", ""))
+	key, err := totpEncoding.DecodeString(secret)
+	if err != nil || len(key) == 0 {
+		return nil, errWrongSecret
+	}
+	return key, nil
+}
+
+// Step return the time step of the time.
+func (t TOTP) Step(at time.Time) int64 {
+	return at.Unix() / t.Period
+}
+
+// CodeAt return the code of the time step.
+func (t TOTP) CodeAt(secret string, step int64) (string, error) {
+	key, err := decodeTOTPSecret(secret)
+	if err != nil { return "", err }
+
+	var msg [8]byte
+	binary.BigEndian.PutUint64(msg[:], uint64(step))
+
+	mac := hmac.New(sha1.New, key)
+	mac.Write(msg[:])
+	sum := mac.Sum(nil)
+
+	offset := sum[len(sum) - 1] & 0x0f
+	value  := binary.BigEndian.Uint32(sum[offset:offset + 4]) & 0x7fffffff
+
+	mod := uint32(1)
+	for i := 0; i < t.Digits; i++ {
+		mod *= 10
+	}
+	return fmt.Sprintf("%0*d", t.Digits, value % mod), nil
+}
+
+// Code return the code of the current time.
+func (t TOTP) Code(secret string) (string, error) {
+	return t.CodeAt(secret, t.Step(t.Now()))
+}
+
+// Validate checks the code of the current time within the skew, it return the
+// matched time step which must be greater than the lastStep so that a code can
+// not be used twice.
+func (t TOTP) Validate(secret, code string, lastStep int64) (int64, bool) {
+	code = strings.ReplaceAll(strings.TrimSpace(code), " ", "")
+	if len(code) != t.Digits {
+		return 0, false
+	}
+	current := t.Step(t.Now())
+	for step := current - t.Skew; step <= current + t.Skew; step++ {
+		if step <= lastStep {
+			continue
+		}
+		expected, err := t.CodeAt(secret, step)
+		if err != nil {
+			return 0, false
+		}
+		if subtle.ConstantTimeCompare([]byte(expected), []byte(code)) == 1 {
+			return step, true
+		}
+	}
+	return 0, false
+}
+
+// URI return the otpauth uri of the key which is encoded into the QR code scanned
+// by the authenticator apps.
+func (t TOTP) URI(issuer, account, secret string) string {
+	values := url.Values{}
+	values.Set("secret", secret)
+	values.Set("issuer", issuer)
+	values.Set("algorithm", "SHA1")
+	values.Set("digits", fmt.Sprintf("%d", t.Digits))
+	values.Set("period", fmt.Sprintf("%d", t.Period))
+	return "otpauth://totp/" + url.PathEscape(issuer + ":" + account) + "?" + values.Encode()
+}
+
+// NewRecoveryCodes return n random recovery codes like "a1b2c-3d4e5".
+func NewRecoveryCodes(n int) ([]string, error) {
+	codes := make([]string, n)
+	for i := range codes {
+		b := make([]byte, 5)
+		if _, err := rand.Read(b); err != nil {
+			return nil, err
+		}
+		s := hex.EncodeToString(b)
+		codes[i] = s[:5] + "-" + s[5:]
+	}
+	return codes, nil
+}
+
+// HashRecoveryCode return the stored hash of the recovery code, the codes are
+// random enough that a slow hash is not needed.
+func HashRecoveryCode(code string) string {
+	code = strings.ToLower(strings.ReplaceAll(strings.TrimSpace(code), "-", ""))
+	sum := sha256.Sum256([]byte(code))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,52 @@
+package auth
+
+import (
+	"testing"
+	"time"
+
+	"github.com/magiconair/properties/assert"
+)
+
+// the secret "12345678901234567890" of the test vectors of RFC 6238.
+const rfcSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func fixedTOTP(unix int64) TOTP {
+	t := DefaultTOTP
+	t.Digits = 8
+	t.Now    = func() time.Time { return time.Unix(unix, 0) }
+	return t
+}
+
+func TestTOTPCode(t *testing.T) {
+	for unix, code := range map[int64]string{
+		59         : "94287082",
+		1111111109 : "07081804",
+		1111111111 : "14050471",
+		1234567890 : "89005924",
+		2000000000 : "69279037",
+		20000000000: "65353130",
+	} {
+		res, err := fixedTOTP(unix).Code(rfcSecret)
+		assert.Equal(t, err, nil)
+		assert.Equal(t, res, code)
+	}
+}
+
+func TestTOTPValidate(t *testing.T) {
+	totp := fixedTOTP(1111111111)
+
+	step, ok := totp.Validate(rfcSecret, "14050471", 0)
+	assert.Equal(t, ok, true)
+	assert.Equal(t, step, int64(1111111111 / 30))
+
+	// the code of the previous period is accepted.
+	_, ok = totp.Validate(rfcSecret, "07081804", 0)
+	assert.Equal(t, ok, true)
+
+	// a used code is rejected.
+	_, ok = totp.Validate(rfcSecret, "14050471", step)
+	assert.Equal(t, ok, false)
+
+	_, ok = fixedTOTP(1111111111 + 90).Validate(rfcSecret, "14050471", 0)
+	assert.Equal(t, ok, false)
+}
@@ -10,14 +10,24 @@ import (
 	"github.com/GoAdminGroup/go-admin/modules/utils"
 	"github.com/GoAdminGroup/go-admin/plugins/admin/modules/form"
 	"html/template"
+	"net"
 	"path/filepath"
 	"reflect"
 	"strconv"
 	"strings"
+	"sync"
 )
 
 var (
 	_global *Config
+
+	// globalLock guards the configs, the getters and the methods of the Config read
+	// them under the read lock and the Update swaps the values under the write lock.
+	globalLock sync.RWMutex
+
+	// updateLock serializes the updates and guards the update hooks.
+	updateLock  sync.Mutex
+	updateHooks []func(c *Config)
 )
 
 // Database is a type of database connection config.
@@ -43,6 +53,14 @@ type Database struct {
 	File       string            `json:"file,omitempty" yaml:"file,omitempty" ini:"file,omitempty"`
 	Dsn        string            `json:"dsn,omitempty" yaml:"dsn,omitempty" ini:"dsn,omitempty"`
 	Params     map[string]string `json:"params,omitempty" yaml:"params,omitempty" ini:"params,omitempty"`
+
+	// Replicas are the read replicas of the database, the reads out of the
+	// transactions go to the healthy replicas and the writes go to the primary.
+	Replicas []Database `json:"replicas,omitempty" yaml:"replicas,omitempty" ini:"-"`
+	// ReplicaStrategy is "round_robin" or "least_latency", the default is "round_robin".
+	ReplicaStrategy string `json:"replica_strategy,omitempty" yaml:"replica_strategy,omitempty" ini:"replica_strategy,omitempty"`
+	// ReplicaCheckInterval is the seconds between the health checks of the replicas.
+	ReplicaCheckInterval int `json:"replica_check_interval,omitempty" yaml:"replica_check_interval,omitempty" ini:"replica_check_interval,omitempty"`
 }
 
 func (d Database) GetDSN() string {
@@ -286,6 +304,14 @@ type Config struct {
 	// Session valid time duration,units are seconds. Default 7200.
 	SessionLifeTime int `json:"session_life_time,omitempty" yaml:"session_life_time,omitempty" ini:"session_life_time,omitempty"`
 
+	// Session persistence driver, which maybe database,memory,file. Default database.
+	SessionDriver string `json:"session_driver,omitempty" yaml:"session_driver,omitempty" ini:"session_driver,omitempty"`
+
+	// Directory of the session files when the session driver is file. Default
+	// goadmin_session of the temporary directory. The directory must be owned by
+	// the current user with the mode 0700.
+	SessionFileDir string `json:"session_file_dir,omitempty" yaml:"session_file_dir,omitempty" ini:"session_file_dir,omitempty"`
+
 	// Assets visit link.
 	AssetUrl string `json:"asset_url,omitempty" yaml:"asset_url,omitempty" ini:"asset_url,omitempty"`
 
@@ -319,6 +345,26 @@ type Config struct {
 	// Limit login with different IPs
 	NoLimitLoginIP bool `json:"no_limit_login_ip,omitempty" yaml:"no_limit_login_ip,omitempty" ini:"no_limit_login_ip,omitempty"`
 
+	// Hasher of the new passwords, which maybe bcrypt,argon2id,scrypt. Default bcrypt.
+	PasswordHasher string `json:"password_hasher,omitempty" yaml:"password_hasher,omitempty" ini:"password_hasher,omitempty"`
+
+	// Failed login attempts of a username before it is locked. Default 5, negative to turn off.
+	LoginMaxAttempts int `json:"login_max_attempts,omitempty" yaml:"login_max_attempts,omitempty" ini:"login_max_attempts,omitempty"`
+
+	// Failed login attempts of an IP before it is locked. Default 20, negative to turn off.
+	LoginIPMaxAttempts int `json:"login_ip_max_attempts,omitempty" yaml:"login_ip_max_attempts,omitempty" ini:"login_ip_max_attempts,omitempty"`
+
+	// Lockout duration after too many failed login attempts, units are seconds. Default 900.
+	LoginLockoutTime int `json:"login_lockout_time,omitempty" yaml:"login_lockout_time,omitempty" ini:"login_lockout_time,omitempty"`
+
+	// Initial delay after a failed login attempt, doubled for each failure, units are seconds. Default 1.
+	LoginBackoffTime int `json:"login_backoff_time,omitempty" yaml:"login_backoff_time,omitempty" ini:"login_backoff_time,omitempty"`
+
+	// IPs or CIDRs of the reverse proxies whose X-Forwarded-For, X-Real-Ip and X-Forwarded-Prefix
+	// headers are trusted for the IPs of the login attempts and the tenants. Default none, the
+	// remote addresses are used and the prefixes are ignored.
+	TrustedProxies []string `json:"trusted_proxies,omitempty" yaml:"trusted_proxies,omitempty" ini:"trusted_proxies,omitempty"`
+
 	// When site off is true, website will be closed
 	SiteOff bool `json:"site_off,omitempty" yaml:"site_off,omitempty" ini:"site_off,omitempty"`
 
@@ -353,6 +399,9 @@ type Config struct {
 
 	OperationLogOff bool `json:"operation_log_off,omitempty" yaml:"operation_log_off,omitempty" ini:"operation_log_off,omitempty"`
 
+	// Apply the pending migrations of the goadmin tables when the engine starts.
+	AutoMigrate bool `json:"auto_migrate,omitempty" yaml:"auto_migrate,omitempty" ini:"auto_migrate,omitempty"`
+
 	AssetRootPath string `json:"asset_root_path,omitempty" yaml:"asset_root_path,omitempty" ini:"asset_root_path,omitempty"`
 
 	URLFormat URLFormat `json:"url_format,omitempty" yaml:"url_format,omitempty" ini:"url_format,omitempty"`
@@ -390,6 +439,11 @@ type URLFormat struct {
 	ShowEdit   string `json:"show_edit,omitempty" yaml:"show_edit,omitempty" ini:"show_edit,omitempty"`
 	ShowCreate string `json:"show_create,omitempty" yaml:"show_create,omitempty" ini:"show_create,omitempty"`
 	Update     string `json:"update,omitempty" yaml:"update,omitempty" ini:"update,omitempty"`
+	Import     string `json:"import,omitempty" yaml:"import,omitempty" ini:"import,omitempty"`
+	ShowImport string `json:"show_import,omitempty" yaml:"show_import,omitempty" ini:"show_import,omitempty"`
+	Restore    string `json:"restore,omitempty" yaml:"restore,omitempty" ini:"restore,omitempty"`
+	Purge      string `json:"purge,omitempty" yaml:"purge,omitempty" ini:"purge,omitempty"`
+	Trash      string `json:"trash,omitempty" yaml:"trash,omitempty" ini:"trash,omitempty"`
 }
 
 func (f URLFormat) SetDefault() URLFormat {
@@ -402,6 +456,11 @@ func (f URLFormat) SetDefault() URLFormat {
 	f.Export     = utils.SetDefault(f.Export    , "", "/export/:__prefix")
 	f.Info       = utils.SetDefault(f.Info      , "", "/info/:__prefix")
 	f.Update     = utils.SetDefault(f.Update    , "", "/update/:__prefix")
+	f.Import     = utils.SetDefault(f.Import    , "", "/import/:__prefix")
+	f.ShowImport = utils.SetDefault(f.ShowImport, "", "/info/:__prefix/import")
+	f.Restore    = utils.SetDefault(f.Restore   , "", "/restore/:__prefix")
+	f.Purge      = utils.SetDefault(f.Purge     , "", "/purge/:__prefix")
+	f.Trash      = utils.SetDefault(f.Trash     , "", "/trash/:__prefix")
 	return f
 }
 
@@ -440,15 +499,38 @@ func GetFileUploadEngineFromJSON(m string) FileUploadEngine {
 	return f
 }
 
+// The methods of the Config read it under the read lock of the global config,
+// since the Update swaps the values of it, the fields of a shared config are read
+// by the Snapshot.
+
+// Snapshot return a copy of the values of the config.
+func (c *Config) Snapshot() Config {
+	globalLock.RLock()
+	defer globalLock.RUnlock()
+	return *c
+}
+
 // GetIndexURL get the index url with prefix.
 func (c *Config) GetIndexURL() string {
-	index := c.Index()
-	if index == "/" { return c.Prefix() }
-	return c.Prefix() + index
+	globalLock.RLock()
+	defer globalLock.RUnlock()
+	return c.getIndexURL()
+}
+
+func (c *Config) getIndexURL() string {
+	index := c.index()
+	if index == "/" { return c.prefix }
+	return c.prefix + index
 }
 
 // Url get url with the given suffix.
 func (c *Config) Url(suffix string) string {
+	globalLock.RLock()
+	defer globalLock.RUnlock()
+	return c.url(suffix)
+}
+
+func (c *Config) url(suffix string) string {
 	if c.prefix == "/" { return   suffix }
 	if   suffix == "/" { return c.prefix }
 	return c.prefix + suffix
@@ -467,35 +549,49 @@ func (c *Config) SetupPrefix() *Config {
 
 // IsDevEnvironment check the environment if it is development.
 func (c *Config) IsDevEnvironment() bool {
-	return c.Env == EnvDev
+	return c.env() == EnvDev
 }
 
 // IsTestEnvironment check the environment if it is test.
 func (c *Config) IsTestEnvironment() bool {
-	return c.Env == EnvTest
+	return c.env() == EnvTest
 }
 
 // IsLocalEnvironment check the environment if it is local.
 func (c *Config) IsLocalEnvironment() bool {
-	return c.Env == EnvLocal
+	return c.env() == EnvLocal
 }
 
 // IsProductionEnvironment check the environment if it is production.
 func (c *Config) IsProductionEnvironment() bool {
-	return c.Env == EnvProd
+	return c.env() == EnvProd
 }
 
 // IsNotProductionEnvironment check the environment if it is not production.
 func (c *Config) IsNotProductionEnvironment() bool {
-	return c.Env != EnvProd
+	return c.env() != EnvProd
+}
+
+func (c *Config) env() string {
+	globalLock.RLock()
+	defer globalLock.RUnlock()
+	return c.Env
 }
 
 func (c *Config) IsAllowConfigModification() bool {
+	globalLock.RLock()
+	defer globalLock.RUnlock()
 	return !c.ProhibitConfigModification
 }
 
 // URLRemovePrefix remove prefix from the given url.
 func (c *Config) URLRemovePrefix(url string) string {
+	globalLock.RLock()
+	defer globalLock.RUnlock()
+	return c.urlRemovePrefix(url)
+}
+
+func (c *Config) urlRemovePrefix(url string) string {
 	if url == c.prefix { return "/" }
 	if c.prefix == "/" { return url }
 	return strings.Replace(url, c.prefix, "", 1)
@@ -503,6 +599,12 @@ func (c *Config) URLRemovePrefix(url string) string {
 
 // Index return the index url without prefix.
 func (c *Config) Index() string {
+	globalLock.RLock()
+	defer globalLock.RUnlock()
+	return c.index()
+}
+
+func (c *Config) index() string {
 	if c.IndexUrl    == ""  { return "/" }
 	if c.IndexUrl[0] != '/' { return "/" + c.IndexUrl }
 	return c.IndexUrl
@@ -510,22 +612,38 @@ func (c *Config) Index() string {
 
 // Prefix return the prefix.
 func (c *Config) Prefix() string {
+	globalLock.RLock()
+	defer globalLock.RUnlock()
 	return c.prefix
 }
 
 // AssertPrefix return the prefix of assert.
 func (c *Config) AssertPrefix() string {
+	globalLock.RLock()
+	defer globalLock.RUnlock()
+	return c.assertPrefix()
+}
+
+func (c *Config) assertPrefix() string {
 	if c.prefix == "/" { return "" }
 	return c.prefix
 }
 
 func (c *Config) AddUpdateProcessFn(fn UpdateConfigProcessFn) *Config {
+	globalLock.Lock()
+	defer globalLock.Unlock()
 	c.UpdateProcessFn = fn
 	return c
 }
 
 // PrefixFixSlash return the prefix fix the slash error.
 func (c *Config) PrefixFixSlash() string {
+	globalLock.RLock()
+	defer globalLock.RUnlock()
+	return c.prefixFixSlash()
+}
+
+func (c *Config) prefixFixSlash() string {
 	if c.UrlPrefix == "/" {
 		return ""
 	}
@@ -536,8 +654,12 @@ func (c *Config) PrefixFixSlash() string {
 }
 
 func (c *Config) Copy() *Config {
-	//c.lock.RLock()
-	//defer c.lock.RUnlock()
+	globalLock.RLock()
+	defer globalLock.RUnlock()
+	return c.clone()
+}
+
+func (c *Config) clone() *Config {
 	var (
 		newCfg   = new(Config)
 		srcType  = reflect.TypeOf(c).Elem()
@@ -565,8 +687,12 @@ func (c *Config) Copy() *Config {
 }
 
 func (c *Config) ToMap() map[string]string {
-	//c.lock.RLock()
-	//defer c.lock.RUnlock()
+	globalLock.RLock()
+	defer globalLock.RUnlock()
+	return c.toMap()
+}
+
+func (c *Config) toMap() map[string]string {
 	var (
 		m     = make(map[string]string)
 		rType = reflect.TypeOf(c).Elem()
@@ -636,9 +762,41 @@ func (c *Config) ToMap() map[string]string {
 	return m
 }
 
+// AddUpdateHook adds a hook called after each update of the config, such as the
+// rollback of a revision, to apply the side effects of the new values.
+func AddUpdateHook(hook func(c *Config)) {
+	updateLock.Lock()
+	defer updateLock.Unlock()
+	updateHooks = append(updateHooks, hook)
+}
+
+// Update updates the config with the map of the site settings. The values are set
+// to a copy of the config first and swapped in under the write lock, so that the
+// concurrent readers never see a half updated config. The logger is initialized
+// again and the update hooks are called after the swap.
 func (c *Config) Update(m map[string]string) error {
-	//c.lock.Lock()
-	//defer c.lock.Unlock()
+	updateLock.Lock()
+	defer updateLock.Unlock()
+
+	newCfg := c.Copy()
+
+	if err := newCfg.update(m); err != nil {
+		return err
+	}
+
+	globalLock.Lock()
+	*c = *newCfg
+	globalLock.Unlock()
+
+	initLogger(c)
+	for _, hook := range updateHooks {
+		hook(c)
+	}
+
+	return nil
+}
+
+func (c *Config) update(m map[string]string) error {
 	rType := reflect.TypeOf(c).Elem()
 	rVal := reflect.ValueOf(c).Elem()
 	for i := 0; i < rType.NumField(); i++ {
@@ -710,8 +868,6 @@ func (c *Config) Update(m map[string]string) error {
 					c.Logger.Encoder.Duration = m["logger_encoder_duration"]
 					c.Logger.Encoder.Caller = m["logger_encoder_caller"]
 				}
-
-				initLogger(c)
 			case "config.FileUploadEngine":
 				c.FileUploadEngine = GetFileUploadEngineFromJSON(m["file_upload_engine"])
 			}
@@ -754,6 +910,20 @@ func SetDefault(cfg *Config) *Config {
 	if cfg.SessionLifeTime == 0 {
 		cfg.SessionLifeTime = 12 * 3600		// default twelve hours
 	}
+	cfg.SessionDriver = utils.SetDefault(cfg.SessionDriver, "", "database")
+	cfg.PasswordHasher = utils.SetDefault(cfg.PasswordHasher, "", "bcrypt")
+	if cfg.LoginMaxAttempts == 0 {
+		cfg.LoginMaxAttempts = 5
+	}
+	if cfg.LoginIPMaxAttempts == 0 {
+		cfg.LoginIPMaxAttempts = 20
+	}
+	if cfg.LoginLockoutTime == 0 {
+		cfg.LoginLockoutTime = 900		// default fifteen minutes
+	}
+	if cfg.LoginBackoffTime == 0 {
+		cfg.LoginBackoffTime = 1
+	}
 	cfg.SetupPrefix()
 	cfg.URLFormat = cfg.URLFormat.SetDefault()
 	return cfg
@@ -762,6 +932,8 @@ func SetDefault(cfg *Config) *Config {
 // Initialize initialize the config.
 func Initialize(cfg *Config) *Config {
 	initLogger(SetDefault(cfg))
+	globalLock.Lock()
+	defer globalLock.Unlock()
 	_global = cfg
 	return _global
 }
@@ -793,58 +965,78 @@ func initLogger(cfg *Config) {
 
 // AssertPrefix return the prefix of assert.
 func AssertPrefix() string {
-	return _global.AssertPrefix()
+	globalLock.RLock()
+	defer globalLock.RUnlock()
+	return _global.assertPrefix()
 }
 
 // GetIndexURL get the index url with prefix.
 func GetIndexURL() string {
-	return _global.GetIndexURL()
+	globalLock.RLock()
+	defer globalLock.RUnlock()
+	return _global.getIndexURL()
 }
 
 // IsProductionEnvironment check the environment if it is production.
 func IsProductionEnvironment() bool {
-	return _global.IsProductionEnvironment()
+	globalLock.RLock()
+	defer globalLock.RUnlock()
+	return _global.Env == EnvProd
 }
 
 // IsNotProductionEnvironment check the environment if it is not production.
 func IsNotProductionEnvironment() bool {
-	return _global.IsNotProductionEnvironment()
+	globalLock.RLock()
+	defer globalLock.RUnlock()
+	return _global.Env != EnvProd
 }
 
 // URLRemovePrefix remove prefix from the given url.
 func URLRemovePrefix(url string) string {
-	return _global.URLRemovePrefix(url)
+	globalLock.RLock()
+	defer globalLock.RUnlock()
+	return _global.urlRemovePrefix(url)
 }
 
 func Url(suffix string) string {
-	return _global.Url(suffix)
+	globalLock.RLock()
+	defer globalLock.RUnlock()
+	return _global.url(suffix)
 }
 
 func GetURLFormats() URLFormat {
+	globalLock.RLock()
+	defer globalLock.RUnlock()
 	return _global.URLFormat
 }
 
 // Prefix return the prefix.
 func Prefix() string {
+	globalLock.RLock()
+	defer globalLock.RUnlock()
 	return _global.prefix
 }
 
 // PrefixFixSlash return the prefix fix the slash error.
 func PrefixFixSlash() string {
-	return _global.PrefixFixSlash()
+	globalLock.RLock()
+	defer globalLock.RUnlock()
+	return _global.prefixFixSlash()
 }
 
 // Get gets the config.
 func Get() *Config {
-	//_global.lock.RLock()
-	//defer _global.lock.RUnlock()
-	return _global.Copy().EraseSens()
+	globalLock.RLock()
+	defer globalLock.RUnlock()
+	return _global.clone().EraseSens()
 }
 
 // Getter methods
 // ============================
 
 func GetDatabases() DatabaseList {
+	globalLock.RLock()
+	defer globalLock.RUnlock()
 	list := make(DatabaseList, len(_global.Databases))
 	for k, d := range _global.Databases {
 		list[k] = Database{
@@ -856,230 +1048,303 @@ func GetDatabases() DatabaseList {
 }
 
 func GetDomain() string {
-	//_global.lock.RLock()
-	//defer _global.lock.RUnlock()
+	globalLock.RLock()
+	defer globalLock.RUnlock()
 	return _global.Domain
 }
 
 func GetLanguage() string {
-	//_global.lock.RLock()
-	//defer _global.lock.RUnlock()
+	globalLock.RLock()
+	defer globalLock.RUnlock()
 	return _global.Language
 }
 
 func GetUrlPrefix() string {
-	//_global.lock.RLock()
-	//defer _global.lock.RUnlock()
+	globalLock.RLock()
+	defer globalLock.RUnlock()
 	return _global.UrlPrefix
 }
 
 func GetOpenAdminApi() bool {
-	//_global.lock.RLock()
-	//defer _global.lock.RUnlock()
+	globalLock.RLock()
+	defer globalLock.RUnlock()
 	return _global.OpenAdminApi
 }
 
 func GetOperationLogOff() bool {
-	//_global.lock.RLock()
-	//defer _global.lock.RUnlock()
+	globalLock.RLock()
+	defer globalLock.RUnlock()
 	return _global.OperationLogOff
 }
 
+func GetAutoMigrate() bool {
+	globalLock.RLock()
+	defer globalLock.RUnlock()
+	return _global.AutoMigrate
+}
+
 func GetCustom500HTML() template.HTML {
-	//_global.lock.RLock()
-	//defer _global.lock.RUnlock()
+	globalLock.RLock()
+	defer globalLock.RUnlock()
 	return _global.Custom500HTML
 }
 
 func GetCustom404HTML() template.HTML {
-	//_global.lock.RLock()
-	//defer _global.lock.RUnlock()
+	globalLock.RLock()
+	defer globalLock.RUnlock()
 	return _global.Custom404HTML
 }
 
 func GetCustom403HTML() template.HTML {
-	//_global.lock.RLock()
-	//defer _global.lock.RUnlock()
+	globalLock.RLock()
+	defer globalLock.RUnlock()
 	return _global.Custom403HTML
 }
 
 func GetTheme() string {
-	//_global.lock.RLock()
-	//defer _global.lock.RUnlock()
+	globalLock.RLock()
+	defer globalLock.RUnlock()
 	return _global.Theme
 }
 
 func GetStore() Store {
-	//_global.lock.RLock()
-	//defer _global.lock.RUnlock()
+	globalLock.RLock()
+	defer globalLock.RUnlock()
 	return _global.Store
 }
 
 func GetTitle() string {
-	//_global.lock.RLock()
-	//defer _global.lock.RUnlock()
+	globalLock.RLock()
+	defer globalLock.RUnlock()
 	return _global.Title
 }
 
 func GetAssetRootPath() string {
-	//_global.lock.RLock()
-	//defer _global.lock.RUnlock()
+	globalLock.RLock()
+	defer globalLock.RUnlock()
 	return _global.AssetRootPath
 }
 
 func GetLogo() template.HTML {
-	//_global.lock.RLock()
-	//defer _global.lock.RUnlock()
+	globalLock.RLock()
+	defer globalLock.RUnlock()
 	return _global.Logo
 }
 
 func GetSiteOff() bool {
-	//_global.lock.RLock()
-	//defer _global.lock.RUnlock()
+	globalLock.RLock()
+	defer globalLock.RUnlock()
 	return _global.SiteOff
 }
 
 func GetMiniLogo() template.HTML {
-	//_global.lock.RLock()
-	//defer _global.lock.RUnlock()
+	globalLock.RLock()
+	defer globalLock.RUnlock()
 	return _global.MiniLogo
 }
 
 func GetIndexUrl() string {
-	//_global.lock.RLock()
-	//defer _global.lock.RUnlock()
+	globalLock.RLock()
+	defer globalLock.RUnlock()
 	return _global.IndexUrl
 }
 
 func GetLoginUrl() string {
-	//_global.lock.RLock()
-	//defer _global.lock.RUnlock()
+	globalLock.RLock()
+	defer globalLock.RUnlock()
 	return _global.LoginUrl
 }
 
 func GetDebug() bool {
-	//_global.lock.RLock()
-	//defer _global.lock.RUnlock()
+	globalLock.RLock()
+	defer globalLock.RUnlock()
 	return _global.Debug
 }
 
 func GetEnv() string {
-	//_global.lock.RLock()
-	//defer _global.lock.RUnlock()
+	globalLock.RLock()
+	defer globalLock.RUnlock()
 	return _global.Env
 }
 
 func GetSqlLog() bool {
-	//_global.lock.RLock()
-	//defer _global.lock.RUnlock()
+	globalLock.RLock()
+	defer globalLock.RUnlock()
 	return _global.SqlLog
 }
 
 func GetAccessLogOff() bool {
-	//_global.lock.RLock()
-	//defer _global.lock.RUnlock()
+	globalLock.RLock()
+	defer globalLock.RUnlock()
 	return _global.AccessLogOff
 }
 
 func GetInfoLogOff() bool {
-	//_global.lock.RLock()
-	//defer _global.lock.RUnlock()
+	globalLock.RLock()
+	defer globalLock.RUnlock()
 	return _global.InfoLogOff
 }
 
 func GetErrorLogOff() bool {
-	//_global.lock.RLock()
-	//defer _global.lock.RUnlock()
+	globalLock.RLock()
+	defer globalLock.RUnlock()
 	return _global.ErrorLogOff
 }
 
 func GetColorScheme() string {
-	//_global.lock.RLock()
-	//defer _global.lock.RUnlock()
+	globalLock.RLock()
+	defer globalLock.RUnlock()
 	return _global.ColorScheme
 }
 
 func GetSessionLifeTime() int {
-	//_global.lock.RLock()
-	//defer _global.lock.RUnlock()
+	globalLock.RLock()
+	defer globalLock.RUnlock()
 	return _global.SessionLifeTime
 }
 
+func GetSessionDriver() string {
+	globalLock.RLock()
+	defer globalLock.RUnlock()
+	return _global.SessionDriver
+}
+
+func GetSessionFileDir() string {
+	globalLock.RLock()
+	defer globalLock.RUnlock()
+	return _global.SessionFileDir
+}
+
 func GetAssetUrl() string {
-	//_global.lock.RLock()
-	//defer _global.lock.RUnlock()
+	globalLock.RLock()
+	defer globalLock.RUnlock()
 	return _global.AssetUrl
 }
 
 func GetFileUploadEngine() FileUploadEngine {
-	//_global.lock.RLock()
-	//defer _global.lock.RUnlock()
+	globalLock.RLock()
+	defer globalLock.RUnlock()
 	return _global.FileUploadEngine
 }
 
 func GetCustomHeadHtml() template.HTML {
-	//_global.lock.RLock()
-	//defer _global.lock.RUnlock()
+	globalLock.RLock()
+	defer globalLock.RUnlock()
 	return _global.CustomHeadHtml
 }
 
 func GetCustomFootHtml() template.HTML {
-	//_global.lock.RLock()
-	//defer _global.lock.RUnlock()
+	globalLock.RLock()
+	defer globalLock.RUnlock()
 	return _global.CustomFootHtml
 }
 
 func GetFooterInfo() template.HTML {
-	//_global.lock.RLock()
-	//defer _global.lock.RUnlock()
+	globalLock.RLock()
+	defer globalLock.RUnlock()
 	return _global.FooterInfo
 }
 
 func GetLoginTitle() string {
-	//_global.lock.RLock()
-	//defer _global.lock.RUnlock()
+	globalLock.RLock()
+	defer globalLock.RUnlock()
 	return _global.LoginTitle
 }
 
 func GetLoginLogo() template.HTML {
-	//_global.lock.RLock()
-	//defer _global.lock.RUnlock()
+	globalLock.RLock()
+	defer globalLock.RUnlock()
 	return _global.LoginLogo
 }
 
 func GetAuthUserTable() string {
-	//_global.lock.RLock()
-	//defer _global.lock.RUnlock()
+	globalLock.RLock()
+	defer globalLock.RUnlock()
 	return _global.AuthUserTable
 }
 
 func GetExtra() map[string]interface{} {
-	//_global.lock.RLock()
-	//defer _global.lock.RUnlock()
+	globalLock.RLock()
+	defer globalLock.RUnlock()
 	return _global.Extra
 }
 
 func GetAnimation() PageAnimation {
-	//_global.lock.RLock()
-	//defer _global.lock.RUnlock()
+	globalLock.RLock()
+	defer globalLock.RUnlock()
 	return _global.Animation
 }
 
 func GetNoLimitLoginIP() bool {
-	//_global.lock.RLock()
-	//defer _global.lock.RUnlock()
+	globalLock.RLock()
+	defer globalLock.RUnlock()
 	return _global.NoLimitLoginIP
 }
 
+func GetPasswordHasher() string {
+	globalLock.RLock()
+	defer globalLock.RUnlock()
+	return _global.PasswordHasher
+}
+
+func GetLoginMaxAttempts() int {
+	globalLock.RLock()
+	defer globalLock.RUnlock()
+	return _global.LoginMaxAttempts
+}
+
+func GetLoginIPMaxAttempts() int {
+	globalLock.RLock()
+	defer globalLock.RUnlock()
+	return _global.LoginIPMaxAttempts
+}
+
+func GetLoginLockoutTime() int {
+	globalLock.RLock()
+	defer globalLock.RUnlock()
+	return _global.LoginLockoutTime
+}
+
+func GetLoginBackoffTime() int {
+	globalLock.RLock()
+	defer globalLock.RUnlock()
+	return _global.LoginBackoffTime
+}
+
+func GetTrustedProxies() []string {
+	globalLock.RLock()
+	defer globalLock.RUnlock()
+	return _global.TrustedProxies
+}
+
+// IsTrustedProxy reports whether the ip matches one of the IPs or CIDRs of the
+// TrustedProxies.
+func IsTrustedProxy(ip string) bool {
+	addr := net.ParseIP(ip)
+	if addr == nil {
+		return false
+	}
+	for _, proxy := range GetTrustedProxies() {
+		if _, network, err := net.ParseCIDR(proxy); err == nil {
+			if network.Contains(addr) {
+				return true
+			}
+		} else if p := net.ParseIP(proxy); p != nil && p.Equal(addr) {
+			return true
+		}
+	}
+	return false
+}
+
 func GetHideVisitorUserCenterEntrance() bool {
-	//_global.lock.RLock()
-	//defer _global.lock.RUnlock()
+	globalLock.RLock()
+	defer globalLock.RUnlock()
 	return _global.HideVisitorUserCenterEntrance
 }
 
 func GetExcludeThemeComponents() []string {
-	//_global.lock.RLock()
-	//defer _global.lock.RUnlock()
+	globalLock.RLock()
+	defer globalLock.RUnlock()
 	return _global.ExcludeThemeComponents
 }
 
@@ -0,0 +1,33 @@
+package config
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/magiconair/properties/assert"
+)
+
+func TestUpdateConcurrentReads(t *testing.T) {
+	cfg := Initialize(&Config{ UrlPrefix: "admin", Title: "foo" })
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 200; j++ {
+				_ = cfg.Url("/info/manager")
+				_ = cfg.GetIndexURL()
+				_ = cfg.Snapshot().Title
+				_ = GetTitle()
+			}
+		}()
+	}
+	for _, title := range []string{ "bar", "baz" } {
+		assert.Equal(t, cfg.Update(map[string]string{ "title": title }), nil)
+	}
+	wg.Wait()
+
+	assert.Equal(t, cfg.Snapshot().Title, "baz")
+	assert.Equal(t, cfg.Url("/info/manager"), "/admin/info/manager")
+}
@@ -0,0 +1,271 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/GoAdminGroup/go-admin/modules/logger"
+	"github.com/GoAdminGroup/go-admin/modules/utils"
+	"gopkg.in/ini.v1"
+	"gopkg.in/yaml.v3"
+)
+
+// ReadFromJson read the Config from a JSON file.
+func ReadFromJson(path string) Config {
+	cfg, err := LoadFile(path)
+	if err != nil { panic(err) }
+	return cfg
+}
+
+// ReadFromYaml read the Config from a YAML file.
+func ReadFromYaml(path string) Config {
+	cfg, err := LoadFile(path)
+	if err != nil { panic(err) }
+	return cfg
+}
+
+// ReadFromINI read the Config from an INI file.
+func ReadFromINI(path string) Config {
+	cfg, err := LoadFile(path)
+	if err != nil { panic(err) }
+	return cfg
+}
+
+// LoadFile read the Config from a file, the format is decided by the extension
+// which maybe .json, .yaml, .yml or .ini.
+func LoadFile(path string) (Config, error) {
+	var cfg Config
+
+	content, err := os.ReadFile(path)
+	if err != nil { return cfg, err }
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		err = utils.JsonUnmarshal(content, &cfg)
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(content, &cfg)
+	case ".ini":
+		err = loadINI(content, &cfg)
+	default:
+		err = errors.New("unsupported config file: " + path)
+	}
+
+	return cfg, err
+}
+
+func loadINI(content []byte, cfg *Config) error {
+	file, err := ini.Load(content)
+	if err != nil { return err }
+
+	if err := file.MapTo(cfg); err != nil { return err }
+
+	cfg.Databases = make(DatabaseList)
+	for _, section := range file.ChildSections("database") {
+		var d Database
+		if err := section.MapTo(&d); err != nil { return err }
+		cfg.Databases[strings.TrimPrefix(section.Name(), "database.")] = d
+	}
+
+	return nil
+}
+
+// Validate checks the values which would break the running application.
+func (c *Config) Validate() error {
+	if len(c.Databases) == 0 {
+		return errors.New("wrong config: no database")
+	}
+	if c.SessionLifeTime < 900 {
+		return errors.New("wrong config: session life time must bigger than 900 seconds")
+	}
+	if c.Logger.Level < -1 || c.Logger.Level > 5 {
+		return errors.New("wrong config: logger level must between -1 and 5")
+	}
+	return nil
+}
+
+// HotReloadKeys are the keys of the config applied by the FileWatcher at runtime,
+// the changes of the other keys take effect after a restart.
+var HotReloadKeys = []string{
+	"title", "login_title", "logo", "mini_logo", "login_logo", "theme", "color_scheme", "language",
+	"logger_level", "session_life_time", "custom_head_html", "custom_foot_html", "custom_404_html",
+	"custom_403_html", "custom_500_html", "footer_info",
+}
+
+// FileWatcher polls the config file and applies the changes of the HotReloadKeys
+// to the global config through the Update, the same as the site settings. The
+// changes of the other keys are kept as pending restart.
+type FileWatcher struct {
+	validate func(c *Config) error
+	path     string
+	interval time.Duration
+	modTime  time.Time
+	initial  map[string]string
+	last     map[string]string
+
+	lock    sync.RWMutex
+	pending map[string]struct{}
+	stop    chan struct{}
+}
+
+var (
+	watcherLock sync.RWMutex
+	_watcher    *FileWatcher
+)
+
+// WatchFile starts to poll the config file with the interval, the current
+// content of the file is the base of the later changes. The validate checks the
+// reloaded config in addition to the Config.Validate, such as the theme and the
+// language which are registered out of this package, it maybe nil.
+func WatchFile(path string, interval time.Duration, validate func(c *Config) error) (*FileWatcher, error) {
+	if interval <= 0 {
+		return nil, errors.New("wrong interval of the config file watcher: " + interval.String())
+	}
+
+	cfg, err := LoadFile(path)
+	if err != nil { return nil, err }
+
+	info, err := os.Stat(path)
+	if err != nil { return nil, err }
+
+	m := SetDefault(&cfg).ToMap()
+	w := &FileWatcher{
+		validate: validate,
+		path    : path,
+		interval: interval,
+		modTime : info.ModTime(),
+		initial : m,
+		last    : m,
+		pending : make(map[string]struct{}),
+		stop    : make(chan struct{}),
+	}
+
+	watcherLock.Lock()
+	if _watcher != nil {
+		_watcher.Stop()
+	}
+	_watcher = w
+	watcherLock.Unlock()
+
+	go w.run()
+
+	return w, nil
+}
+
+func (w *FileWatcher) run() {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-w.stop:
+			return
+		case <-ticker.C:
+			info, err := os.Stat(w.path)
+			if err != nil || info.ModTime().Equal(w.modTime) {
+				continue
+			}
+			w.modTime = info.ModTime()
+			if err := w.Reload(); err != nil {
+				logger.Error("reload config file error: ", err)
+			}
+		}
+	}
+}
+
+// Stop stops the polling.
+func (w *FileWatcher) Stop() {
+	select {
+	case <-w.stop:
+	default:
+		close(w.stop)
+	}
+}
+
+// Reload reads the config file and applies the changed HotReloadKeys, nothing is
+// applied when the file is invalid.
+func (w *FileWatcher) Reload() error {
+	cfg, err := LoadFile(w.path)
+	if err != nil { return err }
+
+	SetDefault(&cfg)
+	if err := cfg.Validate(); err != nil { return err }
+	if w.validate != nil {
+		if err := w.validate(&cfg); err != nil { return err }
+	}
+
+	var (
+		m       = cfg.ToMap()
+		changed = make(map[string]string)
+		pending = make(map[string]struct{})
+	)
+
+	for _, key := range HotReloadKeys {
+		if m[key] != w.last[key] {
+			changed[key] = m[key]
+		}
+	}
+	for key, value := range m {
+		if !utils.InArray(HotReloadKeys, key) && value != w.initial[key] {
+			pending[key] = struct{}{}
+		}
+	}
+
+	w.lock.Lock()
+	w.last    = m
+	w.pending = pending
+	w.lock.Unlock()
+
+	if len(changed) == 0 { return nil }
+
+	globalLock.RLock()
+	global := _global
+	var values map[string]string
+	if global != nil {
+		values = global.toMap()
+	}
+	globalLock.RUnlock()
+
+	if global == nil { return errors.New("config is not initialized") }
+
+	for key, value := range changed {
+		values[key] = value
+	}
+	if err := global.Update(values); err != nil { return err }
+
+	logger.Info("config file reloaded, changed: ", strings.Join(sortedKeys(changed), ", "))
+	return nil
+}
+
+// PendingRestart return the sorted keys changed in the file which take effect
+// after a restart.
+func (w *FileWatcher) PendingRestart() []string {
+	w.lock.RLock()
+	defer w.lock.RUnlock()
+	keys := make([]string, 0, len(w.pending))
+	for key := range w.pending {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// GetPendingRestart return the pending restart keys of the watched config file.
+func GetPendingRestart() []string {
+	watcherLock.RLock()
+	defer watcherLock.RUnlock()
+	if _watcher == nil { return nil }
+	return _watcher.PendingRestart()
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,74 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/magiconair/properties/assert"
+)
+
+func TestLoadFile(t *testing.T) {
+	for path, driver := range map[string]string{ "config.yaml": "mssql", "config.ini": "postgresql" } {
+		cfg, err := LoadFile(path)
+		assert.Equal(t, err, nil)
+		assert.Equal(t, cfg.UrlPrefix, "admin")
+		assert.Equal(t, cfg.Store.Path, "./uploads")
+		assert.Equal(t, cfg.Databases.GetDefault().Driver, driver)
+	}
+
+	file := filepath.Join(t.TempDir(), "config.json")
+	assert.Equal(t, os.WriteFile(file, []byte(`{"prefix":"admin","database":{"default":{"driver":"sqlite"}}}`), 0644), nil)
+	cfg, err := LoadFile(file)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, cfg.Databases.GetDefault().Driver, "sqlite")
+
+	_, err = LoadFile(filepath.Join(t.TempDir(), "config.toml"))
+	assert.Equal(t, err != nil, true)
+}
+
+func TestFileWatcher(t *testing.T) {
+	cfg := Initialize(&Config{ UrlPrefix: "admin", Title: "foo", Domain: "localhost",
+		Databases: DatabaseList{ "default": { Driver: "sqlite" } } })
+
+	file  := filepath.Join(t.TempDir(), "config.json")
+	write := func(content string) {
+		assert.Equal(t, os.WriteFile(file, []byte(content), 0644), nil)
+	}
+	write(`{"prefix":"admin","title":"foo","domain":"localhost","database":{"default":{"driver":"sqlite"}}}`)
+
+	_, err := WatchFile(file, 0, nil)
+	assert.Equal(t, err != nil, true)
+
+	w, err := WatchFile(file, time.Hour, func(c *Config) error {
+		if c.Theme == "missing" {
+			return errors.New("wrong theme")
+		}
+		return nil
+	})
+	assert.Equal(t, err, nil)
+	defer w.Stop()
+
+	// the hot reload keys are applied, the others are pending restart.
+	write(`{"prefix":"admin","title":"bar","domain":"example.com","database":{"default":{"driver":"sqlite"}}}`)
+	assert.Equal(t, w.Reload(), nil)
+	assert.Equal(t, cfg.Snapshot().Title, "bar")
+	assert.Equal(t, cfg.Snapshot().Domain, "localhost")
+	assert.Equal(t, w.PendingRestart(), []string{ "domain" })
+	assert.Equal(t, GetPendingRestart(), []string{ "domain" })
+
+	// nothing is applied when the file is invalid.
+	write(`{"prefix":"admin","title":"baz","theme":"missing","database":{"default":{"driver":"sqlite"}}}`)
+	assert.Equal(t, w.Reload() != nil, true)
+	write(`{"prefix":"admin","title":"baz","session_life_time":10,"database":{"default":{"driver":"sqlite"}}}`)
+	assert.Equal(t, w.Reload() != nil, true)
+	assert.Equal(t, cfg.Snapshot().Title, "bar")
+	assert.Equal(t, w.PendingRestart(), []string{ "domain" })
+
+	// the pending restart is cleared when the key is changed back.
+	write(`{"prefix":"admin","title":"bar","domain":"localhost","database":{"default":{"driver":"sqlite"}}}`)
+	assert.Equal(t, w.Reload(), nil)
+	assert.Equal(t, len(w.PendingRestart()), 0)
+}
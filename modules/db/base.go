@@ -11,9 +11,10 @@ import (
 
 // Base is a common Connection.
 type Base struct {
-	DbList  map[string]*sql.DB
-	Once    sync.Once
-	Configs config.DatabaseList
+	DbList   map[string]*sql.DB
+	Once     sync.Once
+	Configs  config.DatabaseList
+	Replicas map[string]*replicaSet
 }
 
 // Close implements the method Connection.Close.
@@ -22,6 +23,10 @@ func (db *Base) Close() []error {
 	for _, d := range db.DbList {
 		errs = append(errs, d.Close())
 	}
+	for conn, set := range db.Replicas {
+		errs = append(errs, set.close()...)
+		delete(db.Replicas, conn)
+	}
 	return errs
 }
 
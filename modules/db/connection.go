@@ -5,6 +5,7 @@
 package db
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"strings"
@@ -54,6 +55,27 @@ type Connection interface {
 	BeginTxAndConnection(conn string) *sql.Tx
 	BeginTxWithLevelAndConnection(conn string, level sql.IsolationLevel) *sql.Tx
 
+	// QueryContext is the query method of sql with the given context, the query
+	// is aborted when the context is cancelled or its deadline is exceeded.
+	QueryContext(ctx context.Context, query string, args ...interface{}) ([]map[string]interface{}, error)
+
+	// ExecContext is the exec method of sql with the given context.
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+
+	QueryWithConnectionContext(ctx context.Context, conn, query string, args ...interface{}) ([]map[string]interface{}, error)
+	QueryWithTxContext(ctx context.Context, tx *sql.Tx, query string, args ...interface{}) ([]map[string]interface{}, error)
+	QueryWithContext(ctx context.Context, tx *sql.Tx, conn, query string, args ...interface{}) ([]map[string]interface{}, error)
+
+	ExecWithConnectionContext(ctx context.Context, conn, query string, args ...interface{}) (sql.Result, error)
+	ExecWithTxContext(ctx context.Context, tx *sql.Tx, query string, args ...interface{}) (sql.Result, error)
+	ExecWithContext(ctx context.Context, tx *sql.Tx, conn, query string, args ...interface{}) (sql.Result, error)
+
+	BeginTxWithLevelAndConnectionContext(ctx context.Context, conn string, level sql.IsolationLevel) (*sql.Tx, error)
+
+	// QueryEachWithConnectionContext is the query method with given connection and
+	// context of sql, which calls fn for each row of the results like a cursor.
+	QueryEachWithConnectionContext(ctx context.Context, conn string, fn RowFn, query string, args ...interface{}) error
+
 	// InitDB initialize the database connections.
 	InitDB(cfg map[string]config.Database) Connection
 
@@ -0,0 +1,257 @@
+package dialect
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+
+	"github.com/GoAdminGroup/go-admin/modules/utils"
+)
+
+// FilterOperator is the operator of a condition of a Filter.
+type FilterOperator string
+
+const (
+	FilterEqual          FilterOperator = "eq"
+	FilterNotEqual       FilterOperator = "ne"
+	FilterGreater        FilterOperator = "gt"
+	FilterGreaterOrEqual FilterOperator = "ge"
+	FilterLess           FilterOperator = "lt"
+	FilterLessOrEqual    FilterOperator = "le"
+	FilterContains       FilterOperator = "contains"
+	FilterStartsWith     FilterOperator = "starts_with"
+	FilterEndsWith       FilterOperator = "ends_with"
+	FilterIn             FilterOperator = "in"
+	FilterNotIn          FilterOperator = "not_in"
+	FilterIsNull         FilterOperator = "is_null"
+	FilterIsNotNull      FilterOperator = "is_not_null"
+	FilterBetween        FilterOperator = "between"
+)
+
+// FilterOperators is the operators of the conditions in the order of the builder.
+var FilterOperators = []FilterOperator{
+	FilterEqual, FilterNotEqual, FilterGreater, FilterGreaterOrEqual, FilterLess, FilterLessOrEqual,
+	FilterContains, FilterStartsWith, FilterEndsWith, FilterIn, FilterNotIn, FilterIsNull, FilterIsNotNull,
+	FilterBetween,
+}
+
+const (
+	FilterLogicAnd = "and"
+	FilterLogicOr  = "or"
+)
+
+// The limits of a filter, which come from the urls.
+const (
+	maxFilterDepth      = 8
+	maxFilterConditions = 64
+	maxFilterValues     = 256
+)
+
+// likeEscape is the escape character of the patterns of the LIKE conditions, which
+// is given explicitly as sqlite and mssql have no default one.
+const likeEscape = "!"
+
+var jsonPathReg = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*(\[[0-9]+\])*(\.[A-Za-z_][A-Za-z0-9_]*(\[[0-9]+\])*)*$`)
+
+// Filter is a node of a filter tree. A group has the logic and the children,
+// which are groups or conditions. A condition has the field, the operator and the
+// values, and the path when the condition is on a value in a json column, such as
+// "address.city" or "tags[0]".
+type Filter struct {
+	Logic    string         `json:"logic,omitempty"`
+	Children []Filter       `json:"children,omitempty"`
+	Field    string         `json:"field,omitempty"`
+	Path     string         `json:"path,omitempty"`
+	Operator FilterOperator `json:"op,omitempty"`
+	Values   []string       `json:"values,omitempty"`
+}
+
+// IsGroup reports whether the filter is a group of filters.
+func (f Filter) IsGroup() bool {
+	return f.Field == ""
+}
+
+// FilterColumn return the column of the field in the statement, which is quoted
+// and qualified, and whether the field can be filtered.
+type FilterColumn func(field string) (string, bool)
+
+// CompileFilter compiles the filter to a condition of the where clause of the
+// driver. The fields are resolved by the column function and all the values are
+// the arguments, so that nothing from the filter is written in the statement.
+// The condition is empty when the filter has no conditions.
+func CompileFilter(driver string, filter Filter, column FilterColumn) (string, []interface{}, error) {
+	c := &filterCompiler{ driver: driver, column: column }
+	stmt, err := c.compile(filter, 0)
+	if err != nil {
+		return "", nil, err
+	}
+	return stmt, c.args, nil
+}
+
+type filterCompiler struct {
+	driver     string
+	column     FilterColumn
+	args       []interface{}
+	conditions int
+	values     int
+}
+
+func (c *filterCompiler) compile(f Filter, depth int) (string, error) {
+	if !f.IsGroup() {
+		return c.condition(f)
+	}
+	if depth >= maxFilterDepth {
+		return "", errors.New("filter: too deep")
+	}
+
+	sep := " AND "
+	switch strings.ToLower(f.Logic) {
+	case "", FilterLogicAnd:
+	case FilterLogicOr:
+		sep = " OR "
+	default:
+		return "", errors.New("filter: wrong logic " + f.Logic)
+	}
+
+	parts := make([]string, 0, len(f.Children))
+	for _, child := range f.Children {
+		stmt, err := c.compile(child, depth + 1)
+		if err != nil {
+			return "", err
+		}
+		if stmt != "" {
+			parts = append(parts, stmt)
+		}
+	}
+
+	switch len(parts) {
+	case 0:
+		return "", nil
+	case 1:
+		return parts[0], nil
+	default:
+		return utils.StrConcat("(", strings.Join(parts, sep), ")"), nil
+	}
+}
+
+func (c *filterCompiler) condition(f Filter) (string, error) {
+	if c.conditions++; c.conditions > maxFilterConditions {
+		return "", errors.New("filter: too many conditions")
+	}
+	if c.values += len(f.Values); c.values > maxFilterValues {
+		return "", errors.New("filter: too many values")
+	}
+
+	expr, ok := c.column(f.Field)
+	if !ok {
+		return "", errors.New("filter: wrong field " + f.Field)
+	}
+	if f.Path != "" {
+		var err error
+		if expr, err = c.jsonValue(expr, f.Path); err != nil {
+			return "", err
+		}
+	}
+
+	switch f.Operator {
+	case FilterEqual, FilterNotEqual, FilterGreater, FilterGreaterOrEqual, FilterLess, FilterLessOrEqual:
+		if err := f.checkValues(1); err != nil {
+			return "", err
+		}
+		c.args = append(c.args, f.Values[0])
+		return utils.StrConcat(expr, " ", comparisons[f.Operator], " ?"), nil
+	case FilterContains, FilterStartsWith, FilterEndsWith:
+		if err := f.checkValues(1); err != nil {
+			return "", err
+		}
+		pattern := c.escapeLike(f.Values[0])
+		switch f.Operator {
+		case FilterContains:
+			pattern = utils.StrConcat("%", pattern, "%")
+		case FilterStartsWith:
+			pattern = pattern + "%"
+		default:
+			pattern = "%" + pattern
+		}
+		c.args = append(c.args, pattern)
+		return utils.StrConcat(expr, " LIKE ? ESCAPE '", likeEscape, "'"), nil
+	case FilterIn, FilterNotIn:
+		if len(f.Values) == 0 {
+			return "", errors.New("filter: no values of " + f.Field)
+		}
+		for _, v := range f.Values {
+			c.args = append(c.args, v)
+		}
+		op := " IN ("
+		if f.Operator == FilterNotIn {
+			op = " NOT IN ("
+		}
+		return utils.StrConcat(expr, op, strings.Repeat("?,", len(f.Values) - 1), "?)"), nil
+	case FilterIsNull:
+		return expr + " IS NULL", f.checkValues(0)
+	case FilterIsNotNull:
+		return expr + " IS NOT NULL", f.checkValues(0)
+	case FilterBetween:
+		if err := f.checkValues(2); err != nil {
+			return "", err
+		}
+		c.args = append(c.args, f.Values[0], f.Values[1])
+		return expr + " BETWEEN ? AND ?", nil
+	default:
+		return "", errors.New("filter: wrong operator " + string(f.Operator))
+	}
+}
+
+var comparisons = map[FilterOperator]string{
+	FilterEqual         : "=",
+	FilterNotEqual      : "<>",
+	FilterGreater       : ">",
+	FilterGreaterOrEqual: ">=",
+	FilterLess          : "<",
+	FilterLessOrEqual   : "<=",
+}
+
+func (f Filter) checkValues(n int) error {
+	if len(f.Values) != n {
+		return errors.New("filter: wrong number of values of " + f.Field)
+	}
+	return nil
+}
+
+// jsonValue return the expression of the text value at the path of the json column,
+// the path is an argument of the expression.
+func (c *filterCompiler) jsonValue(column, path string) (string, error) {
+	path = strings.TrimPrefix(path, "$.")
+	if !jsonPathReg.MatchString(path) {
+		return "", errors.New("filter: wrong json path " + path)
+	}
+
+	switch c.driver {
+	case "postgresql":
+		// the path of the #>> operator is a text array such as {address,city}.
+		keys := strings.FieldsFunc(path, func(r rune) bool { return r == '.' || r == '[' || r == ']' })
+		c.args = append(c.args, utils.StrConcat("{", strings.Join(keys, ","), "}"))
+		return utils.StrConcat("(CAST(", column, " AS jsonb) #>> ?)"), nil
+	case "mysql":
+		c.args = append(c.args, "$." + path)
+		return utils.StrConcat("JSON_UNQUOTE(JSON_EXTRACT(", column, ", ?))"), nil
+	case "mssql":
+		c.args = append(c.args, "$." + path)
+		return utils.StrConcat("JSON_VALUE(", column, ", ?)"), nil
+	case "sqlite":
+		c.args = append(c.args, "$." + path)
+		return utils.StrConcat("json_extract(", column, ", ?)"), nil
+	default:
+		return "", errors.New("filter: json path is not supported by " + c.driver)
+	}
+}
+
+func (c *filterCompiler) escapeLike(s string) string {
+	s = strings.ReplaceAll(s, likeEscape, likeEscape + likeEscape)
+	s = strings.ReplaceAll(s, "%", likeEscape + "%")
+	s = strings.ReplaceAll(s, "_", likeEscape + "_")
+	if c.driver == "mssql" {
+		s = strings.ReplaceAll(s, "[", likeEscape + "[")
+	}
+	return s
+}
@@ -0,0 +1,53 @@
+package dialect
+
+import (
+	"testing"
+
+	"github.com/magiconair/properties/assert"
+)
+
+func TestCompileFilter(t *testing.T) {
+	column := func(field string) (string, bool) {
+		if field == "name" || field == "age" || field == "profile" {
+			return "`users`.`" + field + "`", true
+		}
+		return "", false
+	}
+
+	filter := Filter{
+		Logic: "or",
+		Children: []Filter{
+			{ Field: "name", Operator: FilterStartsWith, Values: []string{ "50%_off" } },
+			{ Logic: "and", Children: []Filter{
+				{ Field: "age", Operator: FilterBetween, Values: []string{ "18", "30" } },
+				{ Field: "age", Operator: FilterNotIn, Values: []string{ "20", "21" } },
+				{ Field: "profile", Path: "address.city", Operator: FilterIsNotNull },
+			}},
+			{ Logic: "and" },
+		},
+	}
+
+	stmt, args, err := CompileFilter("sqlite", filter, column)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, stmt, "(`users`.`name` LIKE ? ESCAPE '!' OR (`users`.`age` BETWEEN ? AND ? AND "+
+		"`users`.`age` NOT IN (?,?) AND json_extract(`users`.`profile`, ?) IS NOT NULL))")
+	assert.Equal(t, args, []interface{}{ "50!%!_off%", "18", "30", "20", "21", "$.address.city" })
+
+	stmt, args, err = CompileFilter("postgresql", Filter{ Field: "profile", Path: "tags[0]",
+		Operator: FilterEqual, Values: []string{ "go" } }, column)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, stmt, "(CAST(`users`.`profile` AS jsonb) #>> ?) = ?")
+	assert.Equal(t, args, []interface{}{ "{tags,0}", "go" })
+
+	_, _, err = CompileFilter("mysql", Filter{ Field: "password", Operator: FilterEqual, Values: []string{ "" } }, column)
+	assert.Equal(t, err != nil, true)
+
+	_, _, err = CompileFilter("mysql", Filter{ Field: "profile", Path: "a') OR 1=1 --", Operator: FilterIsNull }, column)
+	assert.Equal(t, err != nil, true)
+
+	_, _, err = CompileFilter("mysql", Filter{ Field: "age", Operator: "; DROP", Values: []string{ "" } }, column)
+	assert.Equal(t, err != nil, true)
+
+	_, _, err = CompileFilter("mysql", Filter{ Field: "age", Operator: FilterBetween, Values: []string{ "1" } }, column)
+	assert.Equal(t, err != nil, true)
+}
@@ -0,0 +1,302 @@
+package dialect
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/GoAdminGroup/go-admin/modules/utils"
+)
+
+// TableSchema is the schema of a table.
+type TableSchema struct {
+	Name        string
+	Comment     string
+	Columns     []Column
+	PrimaryKey  []string
+	ForeignKeys []ForeignKey
+	Indexes     []Index
+}
+
+// Column is a column of a table. The Default is the literal value of the default,
+// such as foo of 'foo', or the expression when the DefaultExpr is true.
+type Column struct {
+	Name          string
+	Type          string
+	Nullable      bool
+	HasDefault    bool
+	Default       string
+	DefaultExpr   bool
+	AutoIncrement bool
+	PrimaryKey    bool
+	Unique        bool
+	Comment       string
+}
+
+// ForeignKey is a foreign key of a table, the columns reference the columns of
+// the referenced table in order.
+type ForeignKey struct {
+	Name       string
+	Columns    []string
+	RefTable   string
+	RefColumns []string
+}
+
+// Index is an index of a table.
+type Index struct {
+	Name    string
+	Columns []string
+	Unique  bool
+	Primary bool
+}
+
+// Column return the column of the name.
+func (s TableSchema) Column(name string) (Column, bool) {
+	for _, col := range s.Columns {
+		if col.Name == name {
+			return col, true
+		}
+	}
+	return Column{}, false
+}
+
+// ForeignKey return the single column foreign key of the column.
+func (s TableSchema) ForeignKey(column string) (ForeignKey, bool) {
+	for _, fk := range s.ForeignKeys {
+		if len(fk.Columns) == 1 && fk.Columns[0] == column {
+			return fk, true
+		}
+	}
+	return ForeignKey{}, false
+}
+
+// Querier runs the query of the introspection with the arguments.
+type Querier func(query string, args ...interface{}) ([]map[string]interface{}, error)
+
+// introspector is implemented by the dialects which can read the schema of a table.
+type introspector interface {
+	introspect(schema, table string, query Querier) (TableSchema, error)
+}
+
+// Introspect reads the schema of the table of the driver by the query, the table
+// can be qualified by the schema, such as public.users.
+func Introspect(driver, table string, query Querier) (TableSchema, error) {
+	d, ok := GetDialectByDriver(driver).(introspector)
+	if !ok {
+		return TableSchema{}, errors.New("schema: introspection is not supported by " + driver)
+	}
+
+	schema, name := utils.StrSplitByte2(table, '.')
+	if name == "" {
+		schema, name = "", table
+	}
+
+	s, err := d.introspect(schema, name, query)
+	if err != nil {
+		return TableSchema{}, err
+	}
+	if len(s.Columns) == 0 {
+		return TableSchema{}, errors.New("schema: no columns of table " + table)
+	}
+	s.Name = name
+	s.mark()
+	return s, nil
+}
+
+// schemaStatements are the queries of the dialects having the information schema,
+// the columns of the rows are aliased to the same names:
+//
+//	columns      column_name, data_type, is_nullable, column_default, is_auto, column_comment
+//	indexes      index_name, column_name, is_unique, is_primary
+//	foreign keys constraint_name, column_name, referenced_table_name, referenced_column_name
+//	comment      table_comment
+//
+// The rows of the indexes and the foreign keys are ordered by the names and the
+// positions of the columns.
+type schemaStatements struct {
+	columns     string
+	indexes     string
+	foreignKeys string
+	comment     string
+	args        []interface{}
+	// parseDefault parses the column_default of the dialect.
+	parseDefault func(raw string, row map[string]interface{}) (string, bool)
+}
+
+func (st schemaStatements) introspect(query Querier) (TableSchema, error) {
+	var s TableSchema
+
+	rows, err := query(st.columns, st.args...)
+	if err != nil { return s, err }
+	for _, row := range rows {
+		col := Column{
+			Name:          schemaString(row["column_name"]),
+			Type:          schemaString(row["data_type"]),
+			Nullable:      schemaBool(row["is_nullable"]),
+			AutoIncrement: schemaBool(row["is_auto"]),
+			Comment:       schemaString(row["column_comment"]),
+		}
+		if raw, ok := row["column_default"]; ok && raw != nil {
+			if v := strings.TrimSpace(schemaString(raw)); v != "" && !strings.EqualFold(v, "NULL") {
+				col.HasDefault = true
+				col.Default, col.DefaultExpr = st.parseDefault(v, row)
+			}
+		}
+		// the sequences of the serial columns of postgresql are the defaults.
+		if col.AutoIncrement {
+			col.HasDefault, col.Default, col.DefaultExpr = false, "", false
+		}
+		s.Columns = append(s.Columns, col)
+	}
+
+	rows, err = query(st.indexes, st.args...)
+	if err != nil { return s, err }
+	for _, row := range rows {
+		name := schemaString(row["index_name"])
+		if n := len(s.Indexes); n > 0 && s.Indexes[n - 1].Name == name {
+			s.Indexes[n - 1].Columns = append(s.Indexes[n - 1].Columns, schemaString(row["column_name"]))
+			continue
+		}
+		s.Indexes = append(s.Indexes, Index{
+			Name:    name,
+			Columns: []string{ schemaString(row["column_name"]) },
+			Unique:  schemaBool(row["is_unique"]),
+			Primary: schemaBool(row["is_primary"]),
+		})
+	}
+
+	rows, err = query(st.foreignKeys, st.args...)
+	if err != nil { return s, err }
+	for _, row := range rows {
+		var (
+			name   = schemaString(row["constraint_name"])
+			column = schemaString(row["column_name"])
+			ref    = schemaString(row["referenced_column_name"])
+		)
+		if n := len(s.ForeignKeys); n > 0 && s.ForeignKeys[n - 1].Name == name {
+			fk := &s.ForeignKeys[n - 1]
+			// the referenced columns of postgresql are not ordered, which repeats the rows.
+			if !utils.InArray(fk.Columns, column) {
+				fk.Columns = append(fk.Columns, column)
+			}
+			if !utils.InArray(fk.RefColumns, ref) {
+				fk.RefColumns = append(fk.RefColumns, ref)
+			}
+			continue
+		}
+		s.ForeignKeys = append(s.ForeignKeys, ForeignKey{
+			Name:       name,
+			Columns:    []string{ column },
+			RefTable:   schemaString(row["referenced_table_name"]),
+			RefColumns: []string{ ref },
+		})
+	}
+
+	rows, err = query(st.comment, st.args...)
+	if err != nil { return s, err }
+	if len(rows) > 0 {
+		s.Comment = schemaString(rows[0]["table_comment"])
+	}
+
+	return s, nil
+}
+
+// mark sets the primary key and the unique flags of the columns by the indexes.
+func (s *TableSchema) mark() {
+	for _, index := range s.Indexes {
+		if index.Primary && len(s.PrimaryKey) == 0 {
+			s.PrimaryKey = index.Columns
+		}
+	}
+	for i, col := range s.Columns {
+		if utils.InArray(s.PrimaryKey, col.Name) {
+			s.Columns[i].PrimaryKey = true
+		}
+		for _, index := range s.Indexes {
+			if index.Unique && len(index.Columns) == 1 && index.Columns[0] == col.Name {
+				s.Columns[i].Unique = true
+			}
+		}
+	}
+}
+
+var (
+	numberReg = regexp.MustCompile(`^[+-]?[0-9]+(\.[0-9]+)?$`)
+	castReg   = regexp.MustCompile(`^('(?:[^']|'')*')::[a-zA-Z0-9_ ]+(\[\])?$`)
+)
+
+// parseSQLDefault parses the default of the dialects whose defaults are the sql
+// expressions, such as 'foo'::character varying of postgresql, ((0)) of mssql or
+// 'foo' of sqlite.
+func parseSQLDefault(raw string, _ map[string]interface{}) (string, bool) {
+	for len(raw) > 1 && raw[0] == '(' && raw[len(raw) - 1] == ')' {
+		raw = strings.TrimSpace(raw[1 : len(raw) - 1])
+	}
+	if m := castReg.FindStringSubmatch(raw); m != nil {
+		raw = m[1]
+	}
+	if v, ok := unquoteSQL(raw); ok {
+		return v, false
+	}
+	if numberReg.MatchString(raw) {
+		return raw, false
+	}
+	switch strings.ToLower(raw) {
+	case "true", "false":
+		return strings.ToLower(raw), false
+	}
+	return raw, true
+}
+
+// unquoteSQL unquotes the string literal of sql, such as 'it''s' and N'foo'.
+func unquoteSQL(s string) (string, bool) {
+	if len(s) > 2 && (s[0] == 'N' || s[0] == 'n') && s[1] == '\'' {
+		s = s[1:]
+	}
+	if len(s) < 2 || s[0] != '\'' || s[len(s) - 1] != '\'' {
+		return "", false
+	}
+	s = s[1 : len(s) - 1]
+	// a quote not escaped means an expression of the literals, such as 'a' || 'b'.
+	if strings.ContainsRune(strings.ReplaceAll(s, "''", ""), '\'') {
+		return "", false
+	}
+	return strings.ReplaceAll(s, "''", "'"), true
+}
+
+func schemaString(v interface{}) string {
+	switch t := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return t
+	case []byte:
+		return string(t)
+	default:
+		return fmt.Sprintf("%v", t)
+	}
+}
+
+func schemaInt(v interface{}) int64 {
+	n, _ := strconv.ParseInt(schemaString(v), 10, 64)
+	return n
+}
+
+func schemaBool(v interface{}) bool {
+	switch t := v.(type) {
+	case bool:
+		return t
+	case int64:
+		return t != 0
+	case int:
+		return t != 0
+	default:
+		s := strings.ToLower(schemaString(v))
+		if b, err := strconv.ParseBool(s); err == nil {
+			return b
+		}
+		return s == "yes" || s == "y"
+	}
+}
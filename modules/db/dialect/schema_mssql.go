@@ -0,0 +1,36 @@
+package dialect
+
+func (mssql) introspect(schema, table string, query Querier) (TableSchema, error) {
+	// OBJECT_ID resolves the name in the default schema when it is not qualified.
+	name := table
+	if schema != "" {
+		name = schema + "." + table
+	}
+	object := "OBJECT_ID(?)"
+
+	return schemaStatements{
+		columns: "SELECT c.name AS column_name, t.name AS data_type, c.is_nullable, c.is_identity AS is_auto, " +
+			"dc.definition AS column_default, CAST(ep.value AS NVARCHAR(4000)) AS column_comment " +
+			"FROM sys.columns c JOIN sys.types t ON t.user_type_id = c.user_type_id " +
+			"LEFT JOIN sys.default_constraints dc ON dc.object_id = c.default_object_id " +
+			"LEFT JOIN sys.extended_properties ep ON ep.class = 1 AND ep.major_id = c.object_id " +
+			"AND ep.minor_id = c.column_id AND ep.name = 'MS_Description' " +
+			"WHERE c.object_id = " + object + " ORDER BY c.column_id",
+		indexes: "SELECT i.name AS index_name, c.name AS column_name, i.is_unique, i.is_primary_key AS is_primary " +
+			"FROM sys.indexes i " +
+			"JOIN sys.index_columns ic ON ic.object_id = i.object_id AND ic.index_id = i.index_id " +
+			"JOIN sys.columns c ON c.object_id = ic.object_id AND c.column_id = ic.column_id " +
+			"WHERE i.object_id = " + object + " AND ic.is_included_column = 0 ORDER BY i.name, ic.key_ordinal",
+		foreignKeys: "SELECT fk.name AS constraint_name, pc.name AS column_name, rt.name AS referenced_table_name, " +
+			"rc.name AS referenced_column_name FROM sys.foreign_keys fk " +
+			"JOIN sys.foreign_key_columns fkc ON fkc.constraint_object_id = fk.object_id " +
+			"JOIN sys.columns pc ON pc.object_id = fkc.parent_object_id AND pc.column_id = fkc.parent_column_id " +
+			"JOIN sys.tables rt ON rt.object_id = fkc.referenced_object_id " +
+			"JOIN sys.columns rc ON rc.object_id = fkc.referenced_object_id AND rc.column_id = fkc.referenced_column_id " +
+			"WHERE fk.parent_object_id = " + object + " ORDER BY fk.name, fkc.constraint_column_id",
+		comment: "SELECT CAST(ep.value AS NVARCHAR(4000)) AS table_comment FROM sys.extended_properties ep " +
+			"WHERE ep.class = 1 AND ep.major_id = " + object + " AND ep.minor_id = 0 AND ep.name = 'MS_Description'",
+		args:         []interface{}{ name },
+		parseDefault: parseSQLDefault,
+	}.introspect(query)
+}
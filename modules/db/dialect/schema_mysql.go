@@ -0,0 +1,47 @@
+package dialect
+
+import (
+	"strings"
+)
+
+func (mysql) introspect(schema, table string, query Querier) (TableSchema, error) {
+	db := "DATABASE()"
+	args := []interface{}{ table }
+	if schema != "" {
+		db = "?"
+		args = []interface{}{ schema, table }
+	}
+	where := "WHERE TABLE_SCHEMA = " + db + " AND TABLE_NAME = ?"
+
+	return schemaStatements{
+		columns: "SELECT COLUMN_NAME AS column_name, COLUMN_TYPE AS data_type, IS_NULLABLE AS is_nullable, " +
+			"COLUMN_DEFAULT AS column_default, EXTRA AS extra, (EXTRA LIKE '%auto_increment%') AS is_auto, " +
+			"COLUMN_COMMENT AS column_comment FROM information_schema.COLUMNS " + where + " ORDER BY ORDINAL_POSITION",
+		indexes: "SELECT INDEX_NAME AS index_name, COLUMN_NAME AS column_name, (NON_UNIQUE = 0) AS is_unique, " +
+			"(INDEX_NAME = 'PRIMARY') AS is_primary FROM information_schema.STATISTICS " + where +
+			" ORDER BY INDEX_NAME, SEQ_IN_INDEX",
+		foreignKeys: "SELECT CONSTRAINT_NAME AS constraint_name, COLUMN_NAME AS column_name, " +
+			"REFERENCED_TABLE_NAME AS referenced_table_name, REFERENCED_COLUMN_NAME AS referenced_column_name " +
+			"FROM information_schema.KEY_COLUMN_USAGE " + where + " AND REFERENCED_TABLE_NAME IS NOT NULL " +
+			"ORDER BY CONSTRAINT_NAME, ORDINAL_POSITION",
+		comment:      "SELECT TABLE_COMMENT AS table_comment FROM information_schema.TABLES " + where,
+		args:         args,
+		parseDefault: parseMysqlDefault,
+	}.introspect(query)
+}
+
+// parseMysqlDefault parses the default of mysql, whose literals are not quoted,
+// and of mariadb, whose literals are quoted like the sql.
+func parseMysqlDefault(raw string, row map[string]interface{}) (string, bool) {
+	if v, ok := unquoteSQL(raw); ok {
+		return v, false
+	}
+	if numberReg.MatchString(raw) {
+		return raw, false
+	}
+	if strings.Contains(strings.ToUpper(schemaString(row["extra"])), "DEFAULT_GENERATED") ||
+		strings.HasPrefix(strings.ToLower(raw), "current_timestamp") || strings.ContainsRune(raw, '(') {
+		return raw, true
+	}
+	return raw, false
+}
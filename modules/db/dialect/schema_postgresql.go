@@ -0,0 +1,39 @@
+package dialect
+
+func (postgresql) introspect(schema, table string, query Querier) (TableSchema, error) {
+	ns := "current_schema()"
+	args := []interface{}{ table }
+	if schema != "" {
+		ns = "?"
+		args = []interface{}{ schema, table }
+	}
+
+	return schemaStatements{
+		columns: "SELECT c.column_name, c.udt_name AS data_type, c.is_nullable, c.column_default, " +
+			"(c.column_default LIKE 'nextval(%' OR c.is_identity = 'YES') AS is_auto, pgd.description AS column_comment " +
+			"FROM information_schema.columns c " +
+			"LEFT JOIN pg_catalog.pg_statio_all_tables st ON st.schemaname = c.table_schema AND st.relname = c.table_name " +
+			"LEFT JOIN pg_catalog.pg_description pgd ON pgd.objoid = st.relid AND pgd.objsubid = c.ordinal_position " +
+			"WHERE c.table_schema = " + ns + " AND c.table_name = ? ORDER BY c.ordinal_position",
+		indexes: "SELECT i.relname AS index_name, a.attname AS column_name, ix.indisunique AS is_unique, " +
+			"ix.indisprimary AS is_primary FROM pg_catalog.pg_class t " +
+			"JOIN pg_catalog.pg_namespace n ON n.oid = t.relnamespace " +
+			"JOIN pg_catalog.pg_index ix ON ix.indrelid = t.oid " +
+			"JOIN pg_catalog.pg_class i ON i.oid = ix.indexrelid " +
+			"JOIN pg_catalog.pg_attribute a ON a.attrelid = t.oid AND a.attnum = ANY(ix.indkey) " +
+			"WHERE n.nspname = " + ns + " AND t.relname = ? " +
+			"ORDER BY i.relname, array_position(ix.indkey::int2[], a.attnum)",
+		foreignKeys: "SELECT tc.constraint_name, kcu.column_name, ccu.table_name AS referenced_table_name, " +
+			"ccu.column_name AS referenced_column_name FROM information_schema.table_constraints tc " +
+			"JOIN information_schema.key_column_usage kcu ON kcu.constraint_name = tc.constraint_name " +
+			"AND kcu.table_schema = tc.table_schema " +
+			"JOIN information_schema.constraint_column_usage ccu ON ccu.constraint_name = tc.constraint_name " +
+			"AND ccu.table_schema = tc.table_schema " +
+			"WHERE tc.constraint_type = 'FOREIGN KEY' AND tc.table_schema = " + ns + " AND tc.table_name = ? " +
+			"ORDER BY tc.constraint_name, kcu.ordinal_position",
+		comment: "SELECT obj_description(st.relid) AS table_comment FROM pg_catalog.pg_statio_all_tables st " +
+			"WHERE st.schemaname = " + ns + " AND st.relname = ?",
+		args:         args,
+		parseDefault: parseSQLDefault,
+	}.introspect(query)
+}
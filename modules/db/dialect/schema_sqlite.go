@@ -0,0 +1,123 @@
+package dialect
+
+import (
+	"sort"
+	"strings"
+)
+
+// sqlite has no information schema, the schema is read by the pragmas, which
+// take no arguments, so the names are quoted in the statements. The columns of
+// sqlite have no comments.
+func (sqlite) introspect(schema, table string, query Querier) (TableSchema, error) {
+	var (
+		s      TableSchema
+		prefix = ""
+		pkCols = make(map[int64]string)
+	)
+	if schema != "" {
+		prefix = sqliteIdent(schema) + "."
+	}
+
+	rows, err := query("PRAGMA " + prefix + "table_info(" + sqliteString(table) + ")")
+	if err != nil { return s, err }
+	for _, row := range rows {
+		col := Column{
+			Name:     schemaString(row["name"]),
+			Type:     schemaString(row["type"]),
+			Nullable: !schemaBool(row["notnull"]),
+		}
+		if raw := strings.TrimSpace(schemaString(row["dflt_value"])); raw != "" && !strings.EqualFold(raw, "NULL") {
+			col.HasDefault = true
+			col.Default, col.DefaultExpr = parseSQLDefault(raw, row)
+		}
+		if pk := schemaInt(row["pk"]); pk > 0 {
+			pkCols[pk] = col.Name
+		}
+		s.Columns = append(s.Columns, col)
+	}
+
+	// the positions of the columns in the primary key start from 1.
+	for i := int64(1); i <= int64(len(pkCols)); i++ {
+		s.PrimaryKey = append(s.PrimaryKey, pkCols[i])
+	}
+	// an integer primary key is the alias of the rowid, which is generated.
+	if len(s.PrimaryKey) == 1 {
+		for i, col := range s.Columns {
+			if col.Name == s.PrimaryKey[0] && strings.EqualFold(col.Type, "INTEGER") {
+				s.Columns[i].AutoIncrement = true
+			}
+		}
+	}
+
+	rows, err = query("PRAGMA " + prefix + "index_list(" + sqliteString(table) + ")")
+	if err != nil { return s, err }
+	for _, row := range rows {
+		index := Index{
+			Name:    schemaString(row["name"]),
+			Unique:  schemaBool(row["unique"]),
+			Primary: schemaString(row["origin"]) == "pk",
+		}
+		cols, err := query("PRAGMA " + prefix + "index_info(" + sqliteString(index.Name) + ")")
+		if err != nil { return s, err }
+		sort.Slice(cols, func(i, j int) bool {
+			return schemaInt(cols[i]["seqno"]) < schemaInt(cols[j]["seqno"])
+		})
+		for _, col := range cols {
+			index.Columns = append(index.Columns, schemaString(col["name"]))
+		}
+		s.Indexes = append(s.Indexes, index)
+	}
+	sort.Slice(s.Indexes, func(i, j int) bool { return s.Indexes[i].Name < s.Indexes[j].Name })
+
+	rows, err = query("PRAGMA " + prefix + "foreign_key_list(" + sqliteString(table) + ")")
+	if err != nil { return s, err }
+	sort.SliceStable(rows, func(i, j int) bool {
+		if a, b := schemaInt(rows[i]["id"]), schemaInt(rows[j]["id"]); a != b {
+			return a < b
+		}
+		return schemaInt(rows[i]["seq"]) < schemaInt(rows[j]["seq"])
+	})
+	for _, row := range rows {
+		var (
+			name = "fk_" + table + "_" + schemaString(row["id"])
+			ref  = schemaString(row["to"])
+		)
+		// the referenced column is empty when the foreign key references the
+		// primary key of the referenced table.
+		if ref == "" {
+			ref = sqlitePrimaryKey(prefix, schemaString(row["table"]), query)
+		}
+		if n := len(s.ForeignKeys); n > 0 && s.ForeignKeys[n - 1].Name == name {
+			s.ForeignKeys[n - 1].Columns = append(s.ForeignKeys[n - 1].Columns, schemaString(row["from"]))
+			s.ForeignKeys[n - 1].RefColumns = append(s.ForeignKeys[n - 1].RefColumns, ref)
+			continue
+		}
+		s.ForeignKeys = append(s.ForeignKeys, ForeignKey{
+			Name:       name,
+			Columns:    []string{ schemaString(row["from"]) },
+			RefTable:   schemaString(row["table"]),
+			RefColumns: []string{ ref },
+		})
+	}
+
+	return s, nil
+}
+
+func sqlitePrimaryKey(prefix, table string, query Querier) string {
+	rows, err := query("PRAGMA " + prefix + "table_info(" + sqliteString(table) + ")")
+	if err != nil { return "" }
+	for _, row := range rows {
+		if schemaInt(row["pk"]) == 1 {
+			return schemaString(row["name"])
+		}
+	}
+	return ""
+}
+
+func sqliteString(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+func sqliteIdent(s string) string {
+	return `"` + strings.ReplaceAll(s, `"`, `""`) + `"`
+}
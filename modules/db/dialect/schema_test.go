@@ -0,0 +1,63 @@
+package dialect
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/magiconair/properties/assert"
+)
+
+func TestIntrospect(t *testing.T) {
+	query := func(query string, args ...interface{}) ([]map[string]interface{}, error) {
+		switch {
+		case strings.Contains(query, "information_schema.COLUMNS"):
+			return []map[string]interface{}{
+				{ "column_name": "id", "data_type": "int(10) unsigned", "is_nullable": "NO", "is_auto": int64(1) },
+				{ "column_name": "user_id", "data_type": "int(10)", "is_nullable": "NO", "column_comment": "author" },
+				{ "column_name": "status", "data_type": "varchar(10)", "is_nullable": "NO", "column_default": "draft" },
+				{ "column_name": "created_at", "data_type": "timestamp", "is_nullable": "YES",
+					"column_default": "CURRENT_TIMESTAMP", "extra": "DEFAULT_GENERATED" },
+			}, nil
+		case strings.Contains(query, "information_schema.STATISTICS"):
+			return []map[string]interface{}{
+				{ "index_name": "PRIMARY", "column_name": "id", "is_unique": int64(1), "is_primary": int64(1) },
+				{ "index_name": "uniq_user", "column_name": "user_id", "is_unique": int64(1), "is_primary": int64(0) },
+			}, nil
+		case strings.Contains(query, "KEY_COLUMN_USAGE"):
+			return []map[string]interface{}{
+				{ "constraint_name": "fk_user", "column_name": "user_id", "referenced_table_name": "users",
+					"referenced_column_name": "id" },
+			}, nil
+		default:
+			return []map[string]interface{}{{ "table_comment": "posts" }}, nil
+		}
+	}
+
+	s, err := Introspect("mysql", "posts", query)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, s.Comment, "posts")
+	assert.Equal(t, s.PrimaryKey, []string{ "id" })
+	assert.Equal(t, s.Columns[0].AutoIncrement, true)
+	assert.Equal(t, s.Columns[1].Unique, true)
+	assert.Equal(t, s.Columns[1].Comment, "author")
+	assert.Equal(t, s.Columns[2].Default, "draft")
+	assert.Equal(t, s.Columns[2].DefaultExpr, false)
+	assert.Equal(t, s.Columns[3].DefaultExpr, true)
+
+	fk, ok := s.ForeignKey("user_id")
+	assert.Equal(t, ok, true)
+	assert.Equal(t, fk.RefTable, "users")
+	assert.Equal(t, fk.RefColumns, []string{ "id" })
+
+	for raw, want := range map[string]string{
+		"'draft'::character varying": "draft",
+		"((0))":                      "0",
+		"(N'it''s')":                 "it's",
+	} {
+		v, expr := parseSQLDefault(raw, nil)
+		assert.Equal(t, v, want)
+		assert.Equal(t, expr, false)
+	}
+	_, expr := parseSQLDefault("nextval('posts_id_seq'::regclass)", nil)
+	assert.Equal(t, expr, true)
+}
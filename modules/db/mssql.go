@@ -5,6 +5,7 @@
 package db
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"strconv"
@@ -203,7 +204,7 @@ func (db *Mssql) parseSql(sql string) string {
 // QueryWithConnection implements the method Connection.QueryWithConnection.
 func (db *Mssql) QueryWithConnection(con string, query string, args ...interface{}) ([]map[string]interface{}, error) {
 	query = db.handleSqlBeforeExec(query)
-	return CommonQuery(db.DbList[con], query, args...)
+	return CommonQuery(db.reader(context.Background(), con), query, args...)
 }
 
 // ExecWithConnection implements the method Connection.ExecWithConnection.
@@ -215,7 +216,7 @@ func (db *Mssql) ExecWithConnection(con string, query string, args ...interface{
 // Query implements the method Connection.Query.
 func (db *Mssql) Query(query string, args ...interface{}) ([]map[string]interface{}, error) {
 	query = db.handleSqlBeforeExec(query)
-	return CommonQuery(db.DbList["default"], query, args...)
+	return CommonQuery(db.reader(context.Background(), "default"), query, args...)
 }
 
 // Exec implements the method Connection.Exec.
@@ -254,6 +255,10 @@ func (db *Mssql) InitDB(cfgs map[string]config.Database) Connection {
 			if err := sqlDB.Ping(); err != nil {
 				panic(err)
 			}
+
+			db.initReplicas(DriverMssql, conn, cfg, func(cfg config.Database) (*sql.DB, error) {
+				return sql.Open("sqlserver", cfg.GetDSN())
+			})
 		}
 	})
 	return db
@@ -320,3 +325,57 @@ func (db *Mssql) ExecWithTx(tx *sql.Tx, query string, args ...interface{}) (sql.
 	query = db.handleSqlBeforeExec(query)
 	return CommonExecWithTx(tx, query, args...)
 }
+
+// QueryContext implements the method Connection.QueryContext.
+func (db *Mssql) QueryContext(ctx context.Context, query string, args ...interface{}) ([]map[string]interface{}, error) {
+	return CommonQueryContext(ctx, db.reader(ctx, "default"), db.handleSqlBeforeExec(query), args...)
+}
+
+// ExecContext implements the method Connection.ExecContext.
+func (db *Mssql) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	return CommonExecContext(ctx, db.DbList["default"], db.handleSqlBeforeExec(query), args...)
+}
+
+// QueryWithConnectionContext implements the method Connection.QueryWithConnectionContext.
+func (db *Mssql) QueryWithConnectionContext(ctx context.Context, con string, query string, args ...interface{}) ([]map[string]interface{}, error) {
+	return CommonQueryContext(ctx, db.reader(ctx, con), db.handleSqlBeforeExec(query), args...)
+}
+
+// ExecWithConnectionContext implements the method Connection.ExecWithConnectionContext.
+func (db *Mssql) ExecWithConnectionContext(ctx context.Context, con string, query string, args ...interface{}) (sql.Result, error) {
+	return CommonExecContext(ctx, db.DbList[con], db.handleSqlBeforeExec(query), args...)
+}
+
+// QueryWithTxContext is query method within the transaction with the given context.
+func (db *Mssql) QueryWithTxContext(ctx context.Context, tx *sql.Tx, query string, args ...interface{}) ([]map[string]interface{}, error) {
+	return CommonQueryWithTxContext(ctx, tx, db.handleSqlBeforeExec(query), args...)
+}
+
+// ExecWithTxContext is exec method within the transaction with the given context.
+func (db *Mssql) ExecWithTxContext(ctx context.Context, tx *sql.Tx, query string, args ...interface{}) (sql.Result, error) {
+	return CommonExecWithTxContext(ctx, tx, db.handleSqlBeforeExec(query), args...)
+}
+
+func (db *Mssql) QueryWithContext(ctx context.Context, tx *sql.Tx, conn, query string, args ...interface{}) ([]map[string]interface{}, error) {
+	if tx != nil {
+		return db.QueryWithTxContext(ctx, tx, query, args...)
+	}
+	return db.QueryWithConnectionContext(ctx, conn, query, args...)
+}
+
+func (db *Mssql) ExecWithContext(ctx context.Context, tx *sql.Tx, conn, query string, args ...interface{}) (sql.Result, error) {
+	if tx != nil {
+		return db.ExecWithTxContext(ctx, tx, query, args...)
+	}
+	return db.ExecWithConnectionContext(ctx, conn, query, args...)
+}
+
+// BeginTxWithLevelAndConnectionContext starts a transaction with given context, transaction isolation level and connection.
+func (db *Mssql) BeginTxWithLevelAndConnectionContext(ctx context.Context, conn string, level sql.IsolationLevel) (*sql.Tx, error) {
+	return CommonBeginTxWithLevelContext(ctx, db.DbList[conn], level)
+}
+
+// QueryEachWithConnectionContext implements the method Connection.QueryEachWithConnectionContext.
+func (db *Mssql) QueryEachWithConnectionContext(ctx context.Context, con string, fn RowFn, query string, args ...interface{}) error {
+	return CommonQueryEachContext(ctx, db.reader(ctx, con), fn, db.handleSqlBeforeExec(query), args...)
+}
@@ -5,6 +5,7 @@
 package db
 
 import (
+	"context"
 	"database/sql"
 
 	"github.com/GoAdminGroup/go-admin/modules/config"
@@ -65,6 +66,10 @@ func (db *Mysql) InitDB(cfgs map[string]config.Database) Connection {
 			if err := sqlDB.Ping(); err != nil {
 				panic(err)
 			}
+
+			db.initReplicas(DriverMysql, conn, cfg, func(cfg config.Database) (*sql.DB, error) {
+				return sql.Open("mysql", cfg.GetDSN())
+			})
 		}
 	})
 	return db
@@ -72,7 +77,7 @@ func (db *Mysql) InitDB(cfgs map[string]config.Database) Connection {
 
 // QueryWithConnection implements the method Connection.QueryWithConnection.
 func (db *Mysql) QueryWithConnection(con string, query string, args ...interface{}) ([]map[string]interface{}, error) {
-	return CommonQuery(db.DbList[con], query, args...)
+	return CommonQuery(db.reader(context.Background(), con), query, args...)
 }
 
 // ExecWithConnection implements the method Connection.ExecWithConnection.
@@ -82,7 +87,7 @@ func (db *Mysql) ExecWithConnection(con string, query string, args ...interface{
 
 // Query implements the method Connection.Query.
 func (db *Mysql) Query(query string, args ...interface{}) ([]map[string]interface{}, error) {
-	return CommonQuery(db.DbList["default"], query, args...)
+	return CommonQuery(db.reader(context.Background(), "default"), query, args...)
 }
 
 // Exec implements the method Connection.Exec.
@@ -163,3 +168,57 @@ func (db *Mysql) BeginTxAndConnection(conn string) *sql.Tx {
 func (db *Mysql) BeginTxWithLevelAndConnection(conn string, level sql.IsolationLevel) *sql.Tx {
 	return CommonBeginTxWithLevel(db.DbList[conn], level)
 }
+
+// QueryContext implements the method Connection.QueryContext.
+func (db *Mysql) QueryContext(ctx context.Context, query string, args ...interface{}) ([]map[string]interface{}, error) {
+	return CommonQueryContext(ctx, db.reader(ctx, "default"), query, args...)
+}
+
+// ExecContext implements the method Connection.ExecContext.
+func (db *Mysql) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	return CommonExecContext(ctx, db.DbList["default"], query, args...)
+}
+
+// QueryWithConnectionContext implements the method Connection.QueryWithConnectionContext.
+func (db *Mysql) QueryWithConnectionContext(ctx context.Context, con string, query string, args ...interface{}) ([]map[string]interface{}, error) {
+	return CommonQueryContext(ctx, db.reader(ctx, con), query, args...)
+}
+
+// ExecWithConnectionContext implements the method Connection.ExecWithConnectionContext.
+func (db *Mysql) ExecWithConnectionContext(ctx context.Context, con string, query string, args ...interface{}) (sql.Result, error) {
+	return CommonExecContext(ctx, db.DbList[con], query, args...)
+}
+
+// QueryWithTxContext is query method within the transaction with the given context.
+func (db *Mysql) QueryWithTxContext(ctx context.Context, tx *sql.Tx, query string, args ...interface{}) ([]map[string]interface{}, error) {
+	return CommonQueryWithTxContext(ctx, tx, query, args...)
+}
+
+// ExecWithTxContext is exec method within the transaction with the given context.
+func (db *Mysql) ExecWithTxContext(ctx context.Context, tx *sql.Tx, query string, args ...interface{}) (sql.Result, error) {
+	return CommonExecWithTxContext(ctx, tx, query, args...)
+}
+
+func (db *Mysql) QueryWithContext(ctx context.Context, tx *sql.Tx, conn, query string, args ...interface{}) ([]map[string]interface{}, error) {
+	if tx != nil {
+		return db.QueryWithTxContext(ctx, tx, query, args...)
+	}
+	return db.QueryWithConnectionContext(ctx, conn, query, args...)
+}
+
+func (db *Mysql) ExecWithContext(ctx context.Context, tx *sql.Tx, conn, query string, args ...interface{}) (sql.Result, error) {
+	if tx != nil {
+		return db.ExecWithTxContext(ctx, tx, query, args...)
+	}
+	return db.ExecWithConnectionContext(ctx, conn, query, args...)
+}
+
+// BeginTxWithLevelAndConnectionContext starts a transaction with given context, transaction isolation level and connection.
+func (db *Mysql) BeginTxWithLevelAndConnectionContext(ctx context.Context, conn string, level sql.IsolationLevel) (*sql.Tx, error) {
+	return CommonBeginTxWithLevelContext(ctx, db.DbList[conn], level)
+}
+
+// QueryEachWithConnectionContext implements the method Connection.QueryEachWithConnectionContext.
+func (db *Mysql) QueryEachWithConnectionContext(ctx context.Context, con string, fn RowFn, query string, args ...interface{}) error {
+	return CommonQueryEachContext(ctx, db.reader(ctx, con), fn, query, args...)
+}
@@ -0,0 +1,153 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/GoAdminGroup/go-admin/modules/config"
+)
+
+// namedConnection is a Connection of which the default connection is replaced
+// by the named one, the other connections are kept.
+type namedConnection struct {
+	Connection
+	name string
+}
+
+// WithConnectionName return a Connection of which the queries of the default
+// connection go to the connection of the given name, which should be a database
+// of the same driver in the config. It is the way to serve a tenant by its own
+// database with the code written for the default one.
+func WithConnectionName(conn Connection, name string) Connection {
+	if name == "" || name == "default" {
+		return conn
+	}
+	if c, ok := conn.(namedConnection); ok {
+		conn = c.Connection
+	}
+	return namedConnection{ Connection: conn, name: name }
+}
+
+// ConnectionName return the name of the connection the queries of the given
+// connection name actually go to, which differs from it for the default connection
+// of a Connection returned by the WithConnectionName.
+func ConnectionName(conn Connection, name string) string {
+	if c, ok := conn.(namedConnection); ok {
+		return c.conn(name)
+	}
+	if name == "" {
+		return "default"
+	}
+	return name
+}
+
+func (c namedConnection) conn(name string) string {
+	if name == "" || name == "default" {
+		return c.name
+	}
+	return name
+}
+
+func (c namedConnection) Query(query string, args ...interface{}) ([]map[string]interface{}, error) {
+	return c.Connection.QueryWithConnection(c.name, query, args...)
+}
+
+func (c namedConnection) Exec(query string, args ...interface{}) (sql.Result, error) {
+	return c.Connection.ExecWithConnection(c.name, query, args...)
+}
+
+func (c namedConnection) QueryWithConnection(conn, query string, args ...interface{}) ([]map[string]interface{}, error) {
+	return c.Connection.QueryWithConnection(c.conn(conn), query, args...)
+}
+
+func (c namedConnection) QueryWith(tx *sql.Tx, conn, query string, args ...interface{}) ([]map[string]interface{}, error) {
+	return c.Connection.QueryWith(tx, c.conn(conn), query, args...)
+}
+
+func (c namedConnection) ExecWithConnection(conn, query string, args ...interface{}) (sql.Result, error) {
+	return c.Connection.ExecWithConnection(c.conn(conn), query, args...)
+}
+
+func (c namedConnection) ExecWith(tx *sql.Tx, conn, query string, args ...interface{}) (sql.Result, error) {
+	return c.Connection.ExecWith(tx, c.conn(conn), query, args...)
+}
+
+func (c namedConnection) BeginTxWithReadUncommitted() *sql.Tx {
+	return c.Connection.BeginTxWithReadUncommittedAndConnection(c.name)
+}
+
+func (c namedConnection) BeginTxWithReadCommitted() *sql.Tx {
+	return c.Connection.BeginTxWithReadCommittedAndConnection(c.name)
+}
+
+func (c namedConnection) BeginTxWithRepeatableRead() *sql.Tx {
+	return c.Connection.BeginTxWithRepeatableReadAndConnection(c.name)
+}
+
+func (c namedConnection) BeginTx() *sql.Tx {
+	return c.Connection.BeginTxAndConnection(c.name)
+}
+
+func (c namedConnection) BeginTxWithLevel(level sql.IsolationLevel) *sql.Tx {
+	return c.Connection.BeginTxWithLevelAndConnection(c.name, level)
+}
+
+func (c namedConnection) BeginTxWithReadUncommittedAndConnection(conn string) *sql.Tx {
+	return c.Connection.BeginTxWithReadUncommittedAndConnection(c.conn(conn))
+}
+
+func (c namedConnection) BeginTxWithReadCommittedAndConnection(conn string) *sql.Tx {
+	return c.Connection.BeginTxWithReadCommittedAndConnection(c.conn(conn))
+}
+
+func (c namedConnection) BeginTxWithRepeatableReadAndConnection(conn string) *sql.Tx {
+	return c.Connection.BeginTxWithRepeatableReadAndConnection(c.conn(conn))
+}
+
+func (c namedConnection) BeginTxAndConnection(conn string) *sql.Tx {
+	return c.Connection.BeginTxAndConnection(c.conn(conn))
+}
+
+func (c namedConnection) BeginTxWithLevelAndConnection(conn string, level sql.IsolationLevel) *sql.Tx {
+	return c.Connection.BeginTxWithLevelAndConnection(c.conn(conn), level)
+}
+
+func (c namedConnection) QueryContext(ctx context.Context, query string, args ...interface{}) ([]map[string]interface{}, error) {
+	return c.Connection.QueryWithConnectionContext(ctx, c.name, query, args...)
+}
+
+func (c namedConnection) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	return c.Connection.ExecWithConnectionContext(ctx, c.name, query, args...)
+}
+
+func (c namedConnection) QueryWithConnectionContext(ctx context.Context, conn, query string, args ...interface{}) ([]map[string]interface{}, error) {
+	return c.Connection.QueryWithConnectionContext(ctx, c.conn(conn), query, args...)
+}
+
+func (c namedConnection) QueryWithContext(ctx context.Context, tx *sql.Tx, conn, query string, args ...interface{}) ([]map[string]interface{}, error) {
+	return c.Connection.QueryWithContext(ctx, tx, c.conn(conn), query, args...)
+}
+
+func (c namedConnection) ExecWithConnectionContext(ctx context.Context, conn, query string, args ...interface{}) (sql.Result, error) {
+	return c.Connection.ExecWithConnectionContext(ctx, c.conn(conn), query, args...)
+}
+
+func (c namedConnection) ExecWithContext(ctx context.Context, tx *sql.Tx, conn, query string, args ...interface{}) (sql.Result, error) {
+	return c.Connection.ExecWithContext(ctx, tx, c.conn(conn), query, args...)
+}
+
+func (c namedConnection) BeginTxWithLevelAndConnectionContext(ctx context.Context, conn string, level sql.IsolationLevel) (*sql.Tx, error) {
+	return c.Connection.BeginTxWithLevelAndConnectionContext(ctx, c.conn(conn), level)
+}
+
+func (c namedConnection) QueryEachWithConnectionContext(ctx context.Context, conn string, fn RowFn, query string, args ...interface{}) error {
+	return c.Connection.QueryEachWithConnectionContext(ctx, c.conn(conn), fn, query, args...)
+}
+
+func (c namedConnection) GetDB(key string) *sql.DB {
+	return c.Connection.GetDB(c.conn(key))
+}
+
+func (c namedConnection) GetConfig(name string) config.Database {
+	return c.Connection.GetConfig(c.conn(name))
+}
@@ -14,10 +14,18 @@ import (
 
 // CommonQuery is a common method of query.
 func CommonQuery(db *sql.DB, query string, args ...interface{}) ([]map[string]interface{}, error) {
-	rs, err := db.Query(query, args...)
+	return CommonQueryContext(context.Background(), db, query, args...)
+}
+
+// CommonQueryContext is a common method of query with the given context. A query
+// aborted by the cancellation or the deadline of the context returns the context
+// error.
+func CommonQueryContext(ctx context.Context, db *sql.DB, query string, args ...interface{}) ([]map[string]interface{}, error) {
+	rs, err := db.QueryContext(ctx, query, args...)
 	if err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil { return nil, ctxErr }
 		logger.Errorf("error on sql query: %s\nwith args: %s", query, utils.JSON(args))
-		panic(err)
+		return nil, err
 	}
 	defer rs.Close()
 
@@ -62,17 +70,80 @@ func CommonQuery(db *sql.DB, query string, args ...interface{}) ([]map[string]in
 	return res, nil
 }
 
+// RowFn is called for every row of the query results, a non-nil error stops
+// the iteration and is returned to the caller.
+type RowFn func(row map[string]interface{}) error
+
+// CommonQueryEachContext is a common method of query which scans the results
+// row by row and calls fn for each of them instead of loading all of them into
+// the memory.
+func CommonQueryEachContext(ctx context.Context, db *sql.DB, fn RowFn, query string, args ...interface{}) error {
+	rs, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil { return ctxErr }
+		logger.Errorf("error on sql query: %s\nwith args: %s", query, utils.JSON(args))
+		return err
+	}
+	defer rs.Close()
+
+	col, err := rs.Columns()
+	if err != nil { return err }
+
+	typeVals, err := rs.ColumnTypes()
+	if err != nil { return err }
+
+	typeNames := make([]string, len(typeVals))
+	for i, tv := range typeVals {
+		typeNames[i] = strings.ToUpper(utils.RexCommonQuery.ReplaceAllString(tv.DatabaseTypeName(), ""))
+	}
+
+	nCol   := len(col)
+	colVar := make([]interface{}, nCol)
+
+	for rs.Next() {
+		for i, typeName := range typeNames {
+			colVar[i] = GetColVarType(typeName)
+		}
+		if err := rs.Scan(colVar...); err != nil {
+			return err
+		}
+		row := make(map[string]interface{}, nCol)
+		for i, c := range col {
+			row[c] = GetResultValue(colVar[i], typeNames[i])
+		}
+		if err := fn(row); err != nil {
+			return err
+		}
+	}
+
+	return rs.Err()
+}
+
 // CommonExec is a common method of exec.
 func CommonExec(db *sql.DB, query string, args ...interface{}) (sql.Result, error) {
-	rs, err := db.Exec(query, args...)
+	return CommonExecContext(context.Background(), db, query, args...)
+}
+
+// CommonExecContext is a common method of exec with the given context.
+func CommonExecContext(ctx context.Context, db *sql.DB, query string, args ...interface{}) (sql.Result, error) {
+	rs, err := db.ExecContext(ctx, query, args...)
 	if err != nil { return nil, err }
 	return rs, nil
 }
 
 // CommonQueryWithTx is a common method of query.
 func CommonQueryWithTx(tx *sql.Tx, query string, args ...interface{}) ([]map[string]interface{}, error) {
-	rs, err := tx.Query(query, args...)
-	if err != nil { panic(err) }
+	return CommonQueryWithTxContext(context.Background(), tx, query, args...)
+}
+
+// CommonQueryWithTxContext is a common method of query within the transaction
+// with the given context.
+func CommonQueryWithTxContext(ctx context.Context, tx *sql.Tx, query string, args ...interface{}) ([]map[string]interface{}, error) {
+	rs, err := tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil { return nil, ctxErr }
+		return nil, err
+	}
 	defer rs.Close()
 
 	col, err := rs.Columns()
@@ -115,14 +186,27 @@ func CommonQueryWithTx(tx *sql.Tx, query string, args ...interface{}) ([]map[str
 
 // CommonExecWithTx is a common method of exec.
 func CommonExecWithTx(tx *sql.Tx, query string, args ...interface{}) (sql.Result, error) {
-	rs, err := tx.Exec(query, args...)
+	return CommonExecWithTxContext(context.Background(), tx, query, args...)
+}
+
+// CommonExecWithTxContext is a common method of exec within the transaction
+// with the given context.
+func CommonExecWithTxContext(ctx context.Context, tx *sql.Tx, query string, args ...interface{}) (sql.Result, error) {
+	rs, err := tx.ExecContext(ctx, query, args...)
 	if err != nil { return nil, err }
 	return rs, nil
 }
 
 // CommonBeginTxWithLevel starts a transaction with given transaction isolation level and db connection.
 func CommonBeginTxWithLevel(db *sql.DB, level sql.IsolationLevel) *sql.Tx {
-	tx, err := db.BeginTx(context.Background(), &sql.TxOptions{Isolation: level})
+	tx, err := CommonBeginTxWithLevelContext(context.Background(), db, level)
 	if err != nil { panic(err) }
 	return tx
 }
+
+// CommonBeginTxWithLevelContext starts a transaction with given context, transaction
+// isolation level and db connection. The transaction is rolled back by database/sql
+// when the context is done before it is committed.
+func CommonBeginTxWithLevelContext(ctx context.Context, db *sql.DB, level sql.IsolationLevel) (*sql.Tx, error) {
+	return db.BeginTx(ctx, &sql.TxOptions{Isolation: level})
+}
@@ -0,0 +1,36 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+
+	"github.com/GoAdminGroup/go-admin/modules/config"
+	"github.com/GoAdminGroup/go-admin/modules/utils"
+	"github.com/magiconair/properties/assert"
+)
+
+func TestCommonErrors(t *testing.T) {
+	utils.InitUtils(100, func(s string) string { return s })
+
+	conn := GetSqliteDB().InitDB(map[string]config.Database{
+		"default": { Driver: DriverSqlite, File: filepath.Join(t.TempDir(), "admin.db") },
+	})
+	defer conn.Close()
+
+	_, err := conn.Query(`SELECT * FROM missing`)
+	assert.Equal(t, err != nil, true)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err = conn.BeginTxWithLevelAndConnectionContext(ctx, "default", sql.LevelDefault)
+	assert.Equal(t, err, context.Canceled)
+
+	_, err = WithDriver(conn).WithContext(ctx).WithTransaction(func(tx *sql.Tx) (map[string]interface{}, error) {
+		t.Fatal("the callback runs without a transaction")
+		return nil, nil
+	})
+	assert.Equal(t, err, context.Canceled)
+}
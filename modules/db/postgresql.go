@@ -5,6 +5,7 @@
 package db
 
 import (
+	"context"
 	"database/sql"
 	"strconv"
 	"strings"
@@ -46,7 +47,7 @@ func (db *Postgresql) GetDelimiters() []string {
 
 // QueryWithConnection implements the method Connection.QueryWithConnection.
 func (db *Postgresql) QueryWithConnection(con string, query string, args ...interface{}) ([]map[string]interface{}, error) {
-	return CommonQuery(db.DbList[con], filterQuery(query), args...)
+	return CommonQuery(db.reader(context.Background(), con), filterQuery(query), args...)
 }
 
 // ExecWithConnection implements the method Connection.ExecWithConnection.
@@ -56,7 +57,7 @@ func (db *Postgresql) ExecWithConnection(con string, query string, args ...inter
 
 // Query implements the method Connection.Query.
 func (db *Postgresql) Query(query string, args ...interface{}) ([]map[string]interface{}, error) {
-	return CommonQuery(db.DbList["default"], filterQuery(query), args...)
+	return CommonQuery(db.reader(context.Background(), "default"), filterQuery(query), args...)
 }
 
 // Exec implements the method Connection.Exec.
@@ -105,6 +106,10 @@ func (db *Postgresql) InitDB(cfgList map[string]config.Database) Connection {
 			if err := sqlDB.Ping(); err != nil {
 				panic(err)
 			}
+
+			db.initReplicas(DriverPostgresql, conn, cfg, func(cfg config.Database) (*sql.DB, error) {
+				return sql.Open("postgres", cfg.GetDSN())
+			})
 		}
 	})
 	return db
@@ -169,3 +174,57 @@ func (db *Postgresql) QueryWithTx(tx *sql.Tx, query string, args ...interface{})
 func (db *Postgresql) ExecWithTx(tx *sql.Tx, query string, args ...interface{}) (sql.Result, error) {
 	return CommonExecWithTx(tx, filterQuery(query), args...)
 }
+
+// QueryContext implements the method Connection.QueryContext.
+func (db *Postgresql) QueryContext(ctx context.Context, query string, args ...interface{}) ([]map[string]interface{}, error) {
+	return CommonQueryContext(ctx, db.reader(ctx, "default"), filterQuery(query), args...)
+}
+
+// ExecContext implements the method Connection.ExecContext.
+func (db *Postgresql) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	return CommonExecContext(ctx, db.DbList["default"], filterQuery(query), args...)
+}
+
+// QueryWithConnectionContext implements the method Connection.QueryWithConnectionContext.
+func (db *Postgresql) QueryWithConnectionContext(ctx context.Context, con string, query string, args ...interface{}) ([]map[string]interface{}, error) {
+	return CommonQueryContext(ctx, db.reader(ctx, con), filterQuery(query), args...)
+}
+
+// ExecWithConnectionContext implements the method Connection.ExecWithConnectionContext.
+func (db *Postgresql) ExecWithConnectionContext(ctx context.Context, con string, query string, args ...interface{}) (sql.Result, error) {
+	return CommonExecContext(ctx, db.DbList[con], filterQuery(query), args...)
+}
+
+// QueryWithTxContext is query method within the transaction with the given context.
+func (db *Postgresql) QueryWithTxContext(ctx context.Context, tx *sql.Tx, query string, args ...interface{}) ([]map[string]interface{}, error) {
+	return CommonQueryWithTxContext(ctx, tx, filterQuery(query), args...)
+}
+
+// ExecWithTxContext is exec method within the transaction with the given context.
+func (db *Postgresql) ExecWithTxContext(ctx context.Context, tx *sql.Tx, query string, args ...interface{}) (sql.Result, error) {
+	return CommonExecWithTxContext(ctx, tx, filterQuery(query), args...)
+}
+
+func (db *Postgresql) QueryWithContext(ctx context.Context, tx *sql.Tx, conn, query string, args ...interface{}) ([]map[string]interface{}, error) {
+	if tx != nil {
+		return db.QueryWithTxContext(ctx, tx, query, args...)
+	}
+	return db.QueryWithConnectionContext(ctx, conn, query, args...)
+}
+
+func (db *Postgresql) ExecWithContext(ctx context.Context, tx *sql.Tx, conn, query string, args ...interface{}) (sql.Result, error) {
+	if tx != nil {
+		return db.ExecWithTxContext(ctx, tx, query, args...)
+	}
+	return db.ExecWithConnectionContext(ctx, conn, query, args...)
+}
+
+// BeginTxWithLevelAndConnectionContext starts a transaction with given context, transaction isolation level and connection.
+func (db *Postgresql) BeginTxWithLevelAndConnectionContext(ctx context.Context, conn string, level sql.IsolationLevel) (*sql.Tx, error) {
+	return CommonBeginTxWithLevelContext(ctx, db.DbList[conn], level)
+}
+
+// QueryEachWithConnectionContext implements the method Connection.QueryEachWithConnectionContext.
+func (db *Postgresql) QueryEachWithConnectionContext(ctx context.Context, con string, fn RowFn, query string, args ...interface{}) error {
+	return CommonQueryEachContext(ctx, db.reader(ctx, con), fn, filterQuery(query), args...)
+}
@@ -0,0 +1,256 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/GoAdminGroup/go-admin/modules/config"
+	"github.com/GoAdminGroup/go-admin/modules/logger"
+)
+
+const (
+	// ReplicaRoundRobin spreads the reads across the healthy replicas in turn.
+	ReplicaRoundRobin = "round_robin"
+	// ReplicaLeastLatency sends the reads to the healthy replica of the lowest
+	// latency of the last health check.
+	ReplicaLeastLatency = "least_latency"
+
+	defaultReplicaCheckInterval = 10
+	replicaCheckTimeout         = 3 * time.Second
+)
+
+type primaryKey struct{}
+
+// WithPrimary return a context of which the reads go to the primary database, for
+// the reads which must see the writes just made, like the sessions.
+func WithPrimary(ctx context.Context) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return context.WithValue(ctx, primaryKey{}, true)
+}
+
+// IsPrimary reports whether the reads of the context go to the primary database.
+func IsPrimary(ctx context.Context) bool {
+	if ctx == nil {
+		return false
+	}
+	v, _ := ctx.Value(primaryKey{}).(bool)
+	return v
+}
+
+// replica is a read replica of a database.
+type replica struct {
+	host      string
+	db        *sql.DB
+	healthy   atomic.Bool
+	latency   atomic.Int64
+	lastCheck atomic.Int64
+	lastErr   atomic.Value
+}
+
+// replicaSet is the read replicas of a database of the config.
+type replicaSet struct {
+	driver   string
+	conn     string
+	strategy string
+	interval time.Duration
+	list     []*replica
+	next     atomic.Uint32
+	stop     chan struct{}
+}
+
+// ReplicaStatus is the health of a read replica.
+type ReplicaStatus struct {
+	Driver     string
+	Connection string
+	Host       string
+	Healthy    bool
+	Latency    time.Duration
+	LastCheck  time.Time
+	Error      string
+}
+
+var (
+	replicaLock sync.RWMutex
+	replicaList []*replicaSet
+)
+
+// GetReplicaStatus return the health of all the read replicas.
+func GetReplicaStatus() []ReplicaStatus {
+	replicaLock.RLock()
+	defer replicaLock.RUnlock()
+
+	res := make([]ReplicaStatus, 0)
+	for _, set := range replicaList {
+		for _, r := range set.list {
+			status := ReplicaStatus{
+				Driver    : set.driver,
+				Connection: set.conn,
+				Host      : r.host,
+				Healthy   : r.healthy.Load(),
+				Latency   : time.Duration(r.latency.Load()),
+			}
+			if t := r.lastCheck.Load(); t > 0 {
+				status.LastCheck = time.Unix(0, t)
+			}
+			if err, ok := r.lastErr.Load().(string); ok {
+				status.Error = err
+			}
+			res = append(res, status)
+		}
+	}
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].Connection != res[j].Connection {
+			return res[i].Connection < res[j].Connection
+		}
+		return res[i].Host < res[j].Host
+	})
+	return res
+}
+
+// initReplicas opens the read replicas of the database of the connection name and
+// starts the health check of them. The replicas inherit the driver and the pool
+// size of the primary when they are not set.
+func (db *Base) initReplicas(driver, conn string, cfg config.Database, open func(cfg config.Database) (*sql.DB, error)) {
+	if len(cfg.Replicas) == 0 {
+		return
+	}
+
+	set := &replicaSet{
+		driver  : driver,
+		conn    : conn,
+		strategy: cfg.ReplicaStrategy,
+		interval: time.Duration(cfg.ReplicaCheckInterval) * time.Second,
+		stop    : make(chan struct{}),
+	}
+	if set.interval <= 0 {
+		set.interval = defaultReplicaCheckInterval * time.Second
+	}
+
+	for _, replicaCfg := range cfg.Replicas {
+		if replicaCfg.Driver == "" { replicaCfg.Driver = cfg.Driver }
+		if replicaCfg.MaxIdleCon == 0 { replicaCfg.MaxIdleCon = cfg.MaxIdleCon }
+		if replicaCfg.MaxOpenCon == 0 { replicaCfg.MaxOpenCon = cfg.MaxOpenCon }
+
+		sqlDB, err := open(replicaCfg)
+		if err != nil { panic(err) }
+
+		sqlDB.SetMaxIdleConns(replicaCfg.MaxIdleCon)
+		sqlDB.SetMaxOpenConns(replicaCfg.MaxOpenCon)
+
+		host := replicaCfg.Host
+		if replicaCfg.Port != "" { host += ":" + replicaCfg.Port }
+		if host == "" { host = replicaCfg.File }
+
+		set.list = append(set.list, &replica{ host: host, db: sqlDB })
+	}
+
+	set.check()
+
+	if db.Replicas == nil {
+		db.Replicas = make(map[string]*replicaSet)
+	}
+	db.Replicas[conn] = set
+
+	replicaLock.Lock()
+	replicaList = append(replicaList, set)
+	replicaLock.Unlock()
+
+	go func() {
+		ticker := time.NewTicker(set.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				set.check()
+			case <-set.stop:
+				return
+			}
+		}
+	}()
+}
+
+// close stops the health check and closes the replicas, the set is removed from
+// the status.
+func (set *replicaSet) close() []error {
+	close(set.stop)
+
+	replicaLock.Lock()
+	for i, item := range replicaList {
+		if item == set {
+			replicaList = append(replicaList[:i:i], replicaList[i+1:]...)
+			break
+		}
+	}
+	replicaLock.Unlock()
+
+	errs := make([]error, 0, len(set.list))
+	for _, r := range set.list {
+		errs = append(errs, r.db.Close())
+	}
+	return errs
+}
+
+// check pings the replicas, a replica is used again once it answers.
+func (set *replicaSet) check() {
+	for _, r := range set.list {
+		ctx, cancel := context.WithTimeout(context.Background(), replicaCheckTimeout)
+		start := time.Now()
+		err := r.db.PingContext(ctx)
+		cancel()
+
+		r.lastCheck.Store(time.Now().UnixNano())
+		if err != nil {
+			if r.healthy.Load() {
+				logger.Error("read replica of ", set.conn, " is down: ", r.host, " ", err)
+			}
+			r.healthy.Store(false)
+			r.lastErr.Store(err.Error())
+			continue
+		}
+		r.healthy.Store(true)
+		r.latency.Store(int64(time.Since(start)))
+		r.lastErr.Store("")
+	}
+}
+
+// pick return a healthy replica by the strategy, or nil when all are down.
+func (set *replicaSet) pick() *sql.DB {
+	if set.strategy == ReplicaLeastLatency {
+		var best *replica
+		for _, r := range set.list {
+			if r.healthy.Load() && (best == nil || r.latency.Load() < best.latency.Load()) {
+				best = r
+			}
+		}
+		if best == nil {
+			return nil
+		}
+		return best.db
+	}
+
+	n     := uint32(len(set.list))
+	start := set.next.Add(1)
+	for i := uint32(0); i < n; i++ {
+		if r := set.list[(start + i) % n]; r.healthy.Load() {
+			return r.db
+		}
+	}
+	return nil
+}
+
+// reader return the database of the reads of the connection, which is a healthy
+// replica unless the context asks for the primary.
+func (db *Base) reader(ctx context.Context, conn string) *sql.DB {
+	if set, ok := db.Replicas[conn]; ok && !IsPrimary(ctx) {
+		if r := set.pick(); r != nil {
+			return r
+		}
+	}
+	return db.DbList[conn]
+}
@@ -0,0 +1,61 @@
+package db
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/GoAdminGroup/go-admin/modules/config"
+	"github.com/GoAdminGroup/go-admin/modules/db/dialect"
+	"github.com/GoAdminGroup/go-admin/modules/utils"
+	"github.com/magiconair/properties/assert"
+	_ "github.com/mattn/go-sqlite3"
+)
+
+func TestReplica(t *testing.T) {
+	utils.InitUtils(100, func(s string) string { return s })
+
+	dir := t.TempDir()
+	for _, name := range []string{"primary", "replica"} {
+		conn := GetSqliteDB().InitDB(map[string]config.Database{
+			"default": { Driver: DriverSqlite, File: filepath.Join(dir, name + ".db") },
+		})
+		_, err := conn.Exec(`CREATE TABLE t (name TEXT)`)
+		assert.Equal(t, err, nil)
+		_, err = conn.Exec(`INSERT INTO t (name) VALUES (?)`, name)
+		assert.Equal(t, err, nil)
+		conn.Close()
+	}
+
+	conn := GetSqliteDB().InitDB(map[string]config.Database{
+		"default": {
+			Driver  : DriverSqlite,
+			File    : filepath.Join(dir, "primary.db"),
+			Replicas: []config.Database{{ File: filepath.Join(dir, "replica.db") }},
+		},
+	})
+
+	item, _ := WithDriver(conn).Table("t").First()
+	assert.Equal(t, item["name"], "replica")
+
+	item, _ = WithDriver(conn).Table("t").WithPrimary().First()
+	assert.Equal(t, item["name"], "primary")
+
+	_, err := WithDriver(conn).Table("t").Insert(dialect.H{ "name": "new" })
+	assert.Equal(t, err, nil)
+	count, _ := WithDriver(conn).Table("t").WithPrimary().Count()
+	assert.Equal(t, count, int64(2))
+
+	status := GetReplicaStatus()
+	assert.Equal(t, len(status) > 0, true)
+	assert.Equal(t, status[len(status) - 1].Healthy, true)
+
+	// the close stops the health check of the replicas.
+	set := conn.(*Sqlite).Replicas["default"]
+	conn.Close()
+	select {
+	case <-set.stop:
+	default:
+		t.Fatal("the health check of the replicas is not stopped")
+	}
+	assert.Equal(t, len(GetReplicaStatus()), len(status) - 1)
+}
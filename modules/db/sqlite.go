@@ -5,6 +5,7 @@
 package db
 
 import (
+	"context"
 	"database/sql"
 
 	"github.com/GoAdminGroup/go-admin/modules/config"
@@ -46,7 +47,7 @@ func (db *Sqlite) GetDelimiters() []string {
 
 // QueryWithConnection implements the method Connection.QueryWithConnection.
 func (db *Sqlite) QueryWithConnection(con string, query string, args ...interface{}) ([]map[string]interface{}, error) {
-	return CommonQuery(db.DbList[con], query, args...)
+	return CommonQuery(db.reader(context.Background(), con), query, args...)
 }
 
 // ExecWithConnection implements the method Connection.ExecWithConnection.
@@ -56,7 +57,7 @@ func (db *Sqlite) ExecWithConnection(con string, query string, args ...interface
 
 // Query implements the method Connection.Query.
 func (db *Sqlite) Query(query string, args ...interface{}) ([]map[string]interface{}, error) {
-	return CommonQuery(db.DbList["default"], query, args...)
+	return CommonQuery(db.reader(context.Background(), "default"), query, args...)
 }
 
 // Exec implements the method Connection.Exec.
@@ -102,6 +103,14 @@ func (db *Sqlite) InitDB(cfgList map[string]config.Database) Connection {
 			if err = sqlDB.Ping(); err != nil {
 				panic(err)
 			}
+
+			db.initReplicas(DriverSqlite, conn, cfg, func(cfg config.Database) (*sql.DB, error) {
+				replicaDB, err := sql.Open("sqlite", cfg.GetDSN())
+				if err != nil {
+					return sql.Open("sqlite3", cfg.GetDSN())
+				}
+				return replicaDB, nil
+			})
 		}
 	})
 	return db
@@ -166,3 +175,57 @@ func (db *Sqlite) QueryWithTx(tx *sql.Tx, query string, args ...interface{}) ([]
 func (db *Sqlite) ExecWithTx(tx *sql.Tx, query string, args ...interface{}) (sql.Result, error) {
 	return CommonExecWithTx(tx, query, args...)
 }
+
+// QueryContext implements the method Connection.QueryContext.
+func (db *Sqlite) QueryContext(ctx context.Context, query string, args ...interface{}) ([]map[string]interface{}, error) {
+	return CommonQueryContext(ctx, db.reader(ctx, "default"), query, args...)
+}
+
+// ExecContext implements the method Connection.ExecContext.
+func (db *Sqlite) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	return CommonExecContext(ctx, db.DbList["default"], query, args...)
+}
+
+// QueryWithConnectionContext implements the method Connection.QueryWithConnectionContext.
+func (db *Sqlite) QueryWithConnectionContext(ctx context.Context, con string, query string, args ...interface{}) ([]map[string]interface{}, error) {
+	return CommonQueryContext(ctx, db.reader(ctx, con), query, args...)
+}
+
+// ExecWithConnectionContext implements the method Connection.ExecWithConnectionContext.
+func (db *Sqlite) ExecWithConnectionContext(ctx context.Context, con string, query string, args ...interface{}) (sql.Result, error) {
+	return CommonExecContext(ctx, db.DbList[con], query, args...)
+}
+
+// QueryWithTxContext is query method within the transaction with the given context.
+func (db *Sqlite) QueryWithTxContext(ctx context.Context, tx *sql.Tx, query string, args ...interface{}) ([]map[string]interface{}, error) {
+	return CommonQueryWithTxContext(ctx, tx, query, args...)
+}
+
+// ExecWithTxContext is exec method within the transaction with the given context.
+func (db *Sqlite) ExecWithTxContext(ctx context.Context, tx *sql.Tx, query string, args ...interface{}) (sql.Result, error) {
+	return CommonExecWithTxContext(ctx, tx, query, args...)
+}
+
+func (db *Sqlite) QueryWithContext(ctx context.Context, tx *sql.Tx, conn, query string, args ...interface{}) ([]map[string]interface{}, error) {
+	if tx != nil {
+		return db.QueryWithTxContext(ctx, tx, query, args...)
+	}
+	return db.QueryWithConnectionContext(ctx, conn, query, args...)
+}
+
+func (db *Sqlite) ExecWithContext(ctx context.Context, tx *sql.Tx, conn, query string, args ...interface{}) (sql.Result, error) {
+	if tx != nil {
+		return db.ExecWithTxContext(ctx, tx, query, args...)
+	}
+	return db.ExecWithConnectionContext(ctx, conn, query, args...)
+}
+
+// BeginTxWithLevelAndConnectionContext starts a transaction with given context, transaction isolation level and connection.
+func (db *Sqlite) BeginTxWithLevelAndConnectionContext(ctx context.Context, conn string, level sql.IsolationLevel) (*sql.Tx, error) {
+	return CommonBeginTxWithLevelContext(ctx, db.DbList[conn], level)
+}
+
+// QueryEachWithConnectionContext implements the method Connection.QueryEachWithConnectionContext.
+func (db *Sqlite) QueryEachWithConnectionContext(ctx context.Context, con string, fn RowFn, query string, args ...interface{}) error {
+	return CommonQueryEachContext(ctx, db.reader(ctx, con), fn, query, args...)
+}
@@ -5,8 +5,10 @@
 package db
 
 import (
+	"context"
 	dbsql "database/sql"
 	"errors"
+	"fmt"
 	"strconv"
 	"strings"
 	"sync"
@@ -23,6 +25,8 @@ type SQL struct {
 	dialect dialect.Dialect
 	conn    string
 	tx      *dbsql.Tx
+	ctx     context.Context
+	primary bool
 }
 
 var ErrNoAffectedRows = errors.New("no affected row")
@@ -97,6 +101,20 @@ func (sql *SQL) WithTx(tx *dbsql.Tx) *SQL {
 	return sql
 }
 
+// WithContext set the context of SQL, the statement executed by the terminal
+// method is aborted when the context is cancelled or its deadline is exceeded.
+func (sql *SQL) WithContext(ctx context.Context) *SQL {
+	sql.ctx = ctx
+	return sql
+}
+
+// WithPrimary sends the reads of the SQL to the primary database instead of the
+// read replicas, for the reads which must see the writes just made.
+func (sql *SQL) WithPrimary() *SQL {
+	sql.primary = true
+	return sql
+}
+
 // TableName set table of SQL.
 func (sql *SQL) Table(table string) *SQL {
 	sql.clean()
@@ -395,7 +413,8 @@ type TxFn func(tx *dbsql.Tx) (map[string]interface{}, error)
 // WithTransaction call the callback function within the transaction and
 // catch the error.
 func (sql *SQL) WithTransaction(fn TxFn) (res map[string]interface{}, err error) {
-	tx := sql.diver.BeginTxAndConnection(sql.conn)
+	tx, err := sql.diver.BeginTxWithLevelAndConnectionContext(sql.context(), sql.conn, dbsql.LevelDefault)
+	if err != nil { return nil, err }
 
 	defer func() {
 		if p := recover(); p != nil {
@@ -418,7 +437,8 @@ func (sql *SQL) WithTransaction(fn TxFn) (res map[string]interface{}, err error)
 // WithTransactionByLevel call the callback function within the transaction
 // of given transaction level and catch the error.
 func (sql *SQL) WithTransactionByLevel(level dbsql.IsolationLevel, fn TxFn) (res map[string]interface{}, err error) {
-	tx := sql.diver.BeginTxWithLevelAndConnection(sql.conn, level)
+	tx, err := sql.diver.BeginTxWithLevelAndConnectionContext(sql.context(), sql.conn, level)
+	if err != nil { return nil, err }
 
 	defer func() {
 		if p := recover(); p != nil {
@@ -451,7 +471,7 @@ func (sql *SQL) First() (map[string]interface{}, error) {
 
 	sql.dialect.Select(&sql.SQLComponent)
 
-	res, err := sql.diver.QueryWith(sql.tx, sql.conn, sql.Statement, sql.Args...)
+	res, err := sql.diver.QueryWithContext(sql.context(), sql.tx, sql.conn, sql.Statement, sql.Args...)
 	if err != nil {
 		return nil, err
 	}
@@ -466,20 +486,35 @@ func (sql *SQL) First() (map[string]interface{}, error) {
 func (sql *SQL) All() ([]map[string]interface{}, error) {
 	defer RecycleSQL(sql)
 	sql.dialect.Select(&sql.SQLComponent)
-	return sql.diver.QueryWith(sql.tx, sql.conn, sql.Statement, sql.Args...)
+	return sql.diver.QueryWithContext(sql.context(), sql.tx, sql.conn, sql.Statement, sql.Args...)
 }
 
 // ShowColumns show columns info.
 func (sql *SQL) ShowColumns() ([]map[string]interface{}, error) {
 	defer RecycleSQL(sql)
-	return sql.diver.QueryWithConnection(sql.conn, sql.dialect.ShowColumns(sql.TableName))
+	return sql.diver.QueryWithConnectionContext(sql.context(), sql.conn, sql.dialect.ShowColumns(sql.TableName))
+}
+
+// Schema return the schema of the table, which has the columns, the primary key,
+// the foreign keys, the indexes and the comments, see the dialect.Introspect.
+func (sql *SQL) Schema() (schema dialect.TableSchema, err error) {
+	defer RecycleSQL(sql)
+	// the queries of the connections panic on the errors.
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("schema: %v", r)
+		}
+	}()
+	return dialect.Introspect(sql.diver.Name(), sql.TableName, func(query string, args ...interface{}) ([]map[string]interface{}, error) {
+		return sql.diver.QueryWithConnectionContext(sql.context(), sql.conn, query, args...)
+	})
 }
 
 // ShowTables show table info.
 func (sql *SQL) ShowTables() ([]string, error) {
 	defer RecycleSQL(sql)
 
-	models, err := sql.diver.QueryWithConnection(sql.conn, sql.dialect.ShowTables())
+	models, err := sql.diver.QueryWithConnectionContext(sql.context(), sql.conn, sql.dialect.ShowTables())
 	if err != nil {
 		return nil, err
 	}
@@ -524,7 +559,7 @@ func (sql *SQL) Update(values dialect.H) (int64, error) {
 	sql.Values = values
 	sql.dialect.Update(&sql.SQLComponent)
 
-	res, err := sql.diver.ExecWith(sql.tx, sql.conn, sql.Statement, sql.Args...)
+	res, err := sql.diver.ExecWithContext(sql.context(), sql.tx, sql.conn, sql.Statement, sql.Args...)
 	if err != nil {
 		return 0, err
 	}
@@ -542,7 +577,7 @@ func (sql *SQL) Delete() error {
 
 	sql.dialect.Delete(&sql.SQLComponent)
 
-	res, err := sql.diver.ExecWith(sql.tx, sql.conn, sql.Statement, sql.Args...)
+	res, err := sql.diver.ExecWithContext(sql.context(), sql.tx, sql.conn, sql.Statement, sql.Args...)
 	if err != nil {
 		return err
 	}
@@ -560,7 +595,7 @@ func (sql *SQL) Exec() (int64, error) {
 
 	sql.dialect.Update(&sql.SQLComponent)
 
-	res, err := sql.diver.ExecWith(sql.tx, sql.conn, sql.Statement, sql.Args...)
+	res, err := sql.diver.ExecWithContext(sql.context(), sql.tx, sql.conn, sql.Statement, sql.Args...)
 	if err != nil {
 		return 0, err
 	}
@@ -572,26 +607,29 @@ func (sql *SQL) Exec() (int64, error) {
 	return res.LastInsertId()
 }
 
-const postgresInsertCheckTableName = "goadmin_menu|goadmin_permissions|goadmin_roles|goadmin_users"
+const postgresInsertCheckTableName = "goadmin_menu|goadmin_permissions|goadmin_roles|goadmin_users|goadmin_export_jobs"
 
 // Insert exec the insert method of given key/value pairs.
 func (sql *SQL) Insert(values dialect.H) (int64, error) {
 	defer RecycleSQL(sql)
 
+	// the insert of postgresql returns the id by a query
+	sql.primary = true
+
 	sql.Values = values
 	sql.dialect.Insert(&sql.SQLComponent)
 
 	if sql.diver.Name() == DriverPostgresql && (strings.Contains(postgresInsertCheckTableName, sql.TableName)) {
-		resMap, err := sql.diver.QueryWith(sql.tx, sql.conn, sql.Statement + " RETURNING id", sql.Args...)
+		resMap, err := sql.diver.QueryWithContext(sql.context(), sql.tx, sql.conn, sql.Statement + " RETURNING id", sql.Args...)
 
 		if err != nil {
 			// Fixed java h2 database postgresql mode
-			_, err := sql.diver.QueryWith(sql.tx, sql.conn, sql.Statement, sql.Args...)
+			_, err := sql.diver.QueryWithContext(sql.context(), sql.tx, sql.conn, sql.Statement, sql.Args...)
 			if err != nil {
 				return 0, err
 			}
 
-			res, err := sql.diver.QueryWithConnection(sql.conn, utils.StrConcat(`SELECT max("id") AS "id" FROM "`, sql.TableName, `"`))
+			res, err := sql.diver.QueryWithConnectionContext(sql.context(), sql.conn, utils.StrConcat(`SELECT max("id") AS "id" FROM "`, sql.TableName, `"`))
 			if err != nil {
 				return 0, err
 			}
@@ -610,7 +648,7 @@ func (sql *SQL) Insert(values dialect.H) (int64, error) {
 		return resMap[0]["id"].(int64), nil
 	}
 
-	res, err := sql.diver.ExecWith(sql.tx, sql.conn, sql.Statement, sql.Args...)
+	res, err := sql.diver.ExecWithContext(sql.context(), sql.tx, sql.conn, sql.Statement, sql.Args...)
 	if err != nil {
 		return 0, err
 	}
@@ -622,6 +660,53 @@ func (sql *SQL) Insert(values dialect.H) (int64, error) {
 	return res.LastInsertId()
 }
 
+// FirstContext is the First method with the given context.
+func (sql *SQL) FirstContext(ctx context.Context) (map[string]interface{}, error) {
+	return sql.WithContext(ctx).First()
+}
+
+// AllContext is the All method with the given context.
+func (sql *SQL) AllContext(ctx context.Context) ([]map[string]interface{}, error) {
+	return sql.WithContext(ctx).All()
+}
+
+// CountContext is the Count method with the given context.
+func (sql *SQL) CountContext(ctx context.Context) (int64, error) {
+	return sql.WithContext(ctx).Count()
+}
+
+// UpdateContext is the Update method with the given context.
+func (sql *SQL) UpdateContext(ctx context.Context, values dialect.H) (int64, error) {
+	return sql.WithContext(ctx).Update(values)
+}
+
+// DeleteContext is the Delete method with the given context.
+func (sql *SQL) DeleteContext(ctx context.Context) error {
+	return sql.WithContext(ctx).Delete()
+}
+
+// ExecContext is the Exec method with the given context.
+func (sql *SQL) ExecContext(ctx context.Context) (int64, error) {
+	return sql.WithContext(ctx).Exec()
+}
+
+// InsertContext is the Insert method with the given context.
+func (sql *SQL) InsertContext(ctx context.Context, values dialect.H) (int64, error) {
+	return sql.WithContext(ctx).Insert(values)
+}
+
+// context return the context of SQL, context.Background is used when not set.
+func (sql *SQL) context() context.Context {
+	ctx := sql.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if sql.primary {
+		return WithPrimary(ctx)
+	}
+	return ctx
+}
+
 func (sql *SQL) wrap(field string) string {
 	return utils.StrConcat(sql.diver.GetDelimiter(), field, sql.diver.GetDelimiter2())
 }
@@ -650,6 +735,8 @@ func RecycleSQL(sql *SQL) {
 	sql.conn = ""
 	sql.diver = nil
 	sql.tx = nil
+	sql.ctx = nil
+	sql.primary = false
 	sql.dialect = nil
 	SQLPool.Put(sql)
 }
@@ -22,6 +22,8 @@ const (
 	CreateFailWrongToken = "create fail, wrong token"
 	NoPermission         = "no permission"
 	SiteOff              = "site is off"
+	VersionConflict      = "the record has been modified by others since the form was loaded"
+	MissingVersion       = "missing the version of the record"
 )
 
 func WrongPK(pk string) string {
@@ -230,6 +230,11 @@ var en = LangSet{
 	"system.application run": "Applications Running Info",
 	"system.system":          "System Info",
 
+	"system.pending restart": "The changes of the config file take effect after a restart: ",
+	"system.read replicas":   "Read Replicas",
+	"system.replica healthy": "Healthy",
+	"system.replica down":    "Down",
+
 	"system.process_id":                           "Process ID",
 	"system.golang_version":                       "Golang Version",
 	"system.server_uptime":                        "Server Uptime",
@@ -0,0 +1,244 @@
+// Copyright 2019 GoAdmin Core Team. All rights reserved.
+// Use of this source code is governed by a Apache-2.0 style
+// license that can be found in the LICENSE file.
+
+// Package migration evolves the goadmin tables with versioned migrations. The
+// framework and the plugins register their migrations, and the applied ones are
+// tracked in the goadmin_migrations table of every database.
+package migration
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/GoAdminGroup/go-admin/modules/db"
+	"github.com/GoAdminGroup/go-admin/modules/db/dialect"
+	"github.com/GoAdminGroup/go-admin/modules/logger"
+)
+
+// TableName is the table of the applied migrations.
+const TableName = "goadmin_migrations"
+
+// Migration is a versioned change of the tables. The statements are keyed by the
+// driver name, a driver without statements is skipped. The statements of a
+// driver are executed at once, so the drivers which do not support multiple
+// statements in one execution, like mysql, should have one statement.
+type Migration struct {
+	// Version orders the migrations, the framework uses the date like 2024010100.
+	Version int64
+	Name    string
+	// Plugin is the name of the plugin of the migration, it is empty for the
+	// framework. The version is unique in a plugin.
+	Plugin string
+	Up     map[string]string
+	Down   map[string]string
+}
+
+func (m Migration) key() string {
+	return fmt.Sprintf("%s:%d", m.Plugin, m.Version)
+}
+
+// Status is a registered migration with whether it is applied.
+type Status struct {
+	Migration
+	Applied   bool
+	AppliedAt string
+}
+
+var (
+	lock       sync.RWMutex
+	migrations = make(map[string]Migration)
+)
+
+// Register adds the migrations, it panics when the version is registered twice
+// in a plugin.
+func Register(list ...Migration) {
+	lock.Lock()
+	defer lock.Unlock()
+	for _, m := range list {
+		if _, ok := migrations[m.key()]; ok {
+			panic(fmt.Sprintf("migration %d of plugin %q is registered twice", m.Version, m.Plugin))
+		}
+		migrations[m.key()] = m
+	}
+}
+
+// List return the registered migrations ordered by the version.
+func List() []Migration {
+	lock.RLock()
+	defer lock.RUnlock()
+	list := make([]Migration, 0, len(migrations))
+	for _, m := range migrations {
+		list = append(list, m)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Version != list[j].Version {
+			return list[i].Version < list[j].Version
+		}
+		return list[i].Plugin < list[j].Plugin
+	})
+	return list
+}
+
+var schema = map[string]string{
+	db.DriverMysql: "CREATE TABLE IF NOT EXISTS `goadmin_migrations` (" +
+		"`id` int(10) unsigned NOT NULL AUTO_INCREMENT," +
+		"`plugin` varchar(100) NOT NULL DEFAULT ''," +
+		"`version` bigint NOT NULL DEFAULT 0," +
+		"`name` varchar(255) NOT NULL DEFAULT ''," +
+		"`created_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP," +
+		"PRIMARY KEY (`id`)," +
+		"UNIQUE KEY `goadmin_migrations_version` (`plugin`,`version`)" +
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4",
+	db.DriverPostgresql: `CREATE TABLE IF NOT EXISTS goadmin_migrations (
+		id SERIAL PRIMARY KEY,
+		plugin character varying(100) NOT NULL DEFAULT '',
+		version bigint NOT NULL DEFAULT 0,
+		name character varying(255) NOT NULL DEFAULT '',
+		created_at timestamp without time zone DEFAULT now(),
+		UNIQUE (plugin, version)
+	)`,
+	db.DriverSqlite: `CREATE TABLE IF NOT EXISTS goadmin_migrations (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		plugin TEXT NOT NULL DEFAULT '',
+		version INTEGER NOT NULL DEFAULT 0,
+		name TEXT NOT NULL DEFAULT '',
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+		UNIQUE (plugin, version)
+	)`,
+	db.DriverMssql: `IF OBJECT_ID(N'goadmin_migrations', N'U') IS NULL
+	CREATE TABLE [goadmin_migrations] (
+		[id] int IDENTITY(1,1) PRIMARY KEY,
+		[plugin] nvarchar(100) NOT NULL DEFAULT '',
+		[version] bigint NOT NULL DEFAULT 0,
+		[name] nvarchar(255) NOT NULL DEFAULT '',
+		[created_at] datetime DEFAULT GETDATE(),
+		CONSTRAINT [goadmin_migrations_version] UNIQUE ([plugin], [version])
+	)`,
+}
+
+// Init creates the table of the applied migrations if not exists.
+func Init(conn db.Connection) error {
+	stmt, ok := schema[conn.Name()]
+	if !ok { return errors.New("migration: unsupported driver " + conn.Name()) }
+	_, err := conn.Exec(stmt)
+	return err
+}
+
+func table(conn db.Connection) *db.SQL {
+	return db.Table(TableName).WithDriver(conn).WithPrimary()
+}
+
+// applied return the applied time of the applied migrations keyed by the key.
+func applied(conn db.Connection) (map[string]string, error) {
+	if err := Init(conn); err != nil { return nil, err }
+
+	items, err := table(conn).All()
+	if err != nil { return nil, err }
+
+	res := make(map[string]string, len(items))
+	for _, item := range items {
+		plugin, _ := item["plugin"].(string)
+		version, _ := item["version"].(int64)
+		res[Migration{ Plugin: plugin, Version: version }.key()] = fmt.Sprintf("%v", item["created_at"])
+	}
+	return res, nil
+}
+
+// GetStatus return the registered migrations with whether they are applied.
+func GetStatus(conn db.Connection) ([]Status, error) {
+	done, err := applied(conn)
+	if err != nil { return nil, err }
+
+	list := List()
+	res  := make([]Status, len(list))
+	for i, m := range list {
+		at, ok := done[m.key()]
+		res[i] = Status{ Migration: m, Applied: ok, AppliedAt: at }
+	}
+	return res, nil
+}
+
+func run(conn db.Connection, stmts map[string]string) error {
+	stmt, ok := stmts[conn.Name()]
+	if !ok || stmt == "" { return nil }
+	_, err := conn.Exec(stmt)
+	return err
+}
+
+func up(conn db.Connection, m Migration) error {
+	if err := run(conn, m.Up); err != nil {
+		return fmt.Errorf("migration %d %s: %w", m.Version, m.Name, err)
+	}
+	_, err := table(conn).Insert(dialect.H{
+		"plugin" : m.Plugin,
+		"version": m.Version,
+		"name"   : m.Name,
+	})
+	return err
+}
+
+// Up applies the pending migrations in order and return the applied ones, it stops
+// at the first failure.
+func Up(conn db.Connection) ([]Migration, error) {
+	done, err := applied(conn)
+	if err != nil { return nil, err }
+
+	res := make([]Migration, 0)
+	for _, m := range List() {
+		if _, ok := done[m.key()]; ok {
+			continue
+		}
+		if err := up(conn, m); err != nil { return res, err }
+		logger.Info("migration applied: ", m.Version, " ", m.Name)
+		res = append(res, m)
+	}
+	return res, nil
+}
+
+// Apply applies the migration of the plugin and the version if it is pending.
+func Apply(conn db.Connection, plugin string, version int64) error {
+	lock.RLock()
+	m, ok := migrations[Migration{ Plugin: plugin, Version: version }.key()]
+	lock.RUnlock()
+	if !ok { return fmt.Errorf("migration %d of plugin %q is not registered", version, plugin) }
+
+	done, err := applied(conn)
+	if err != nil { return err }
+	if _, ok := done[m.key()]; ok { return nil }
+
+	return up(conn, m)
+}
+
+// IsPending reports whether the migration of the plugin and the version is not
+// applied, the table of the applied migrations is not created by it.
+func IsPending(conn db.Connection, plugin string, version int64) (bool, error) {
+	items, err := table(conn).Where("plugin", "=", plugin).Where("version", "=", version).All()
+	if err != nil { return true, err }
+	return len(items) == 0, nil
+}
+
+// Down reverts the latest applied migrations of the steps and return the reverted
+// ones, the applied migrations which are not registered any more are skipped.
+func Down(conn db.Connection, steps int) ([]Migration, error) {
+	status, err := GetStatus(conn)
+	if err != nil { return nil, err }
+
+	res := make([]Migration, 0)
+	for i := len(status) - 1; i >= 0 && len(res) < steps; i-- {
+		m := status[i]
+		if !m.Applied {
+			continue
+		}
+		if err := run(conn, m.Down); err != nil {
+			return res, fmt.Errorf("migration %d %s: %w", m.Version, m.Name, err)
+		}
+		err := table(conn).Where("plugin", "=", m.Plugin).Where("version", "=", m.Version).Delete()
+		if err != nil && !errors.Is(err, db.ErrNoAffectedRows) { return res, err }
+		logger.Info("migration reverted: ", m.Version, " ", m.Name)
+		res = append(res, m.Migration)
+	}
+	return res, nil
+}
@@ -0,0 +1,72 @@
+package migration
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/GoAdminGroup/go-admin/modules/config"
+	"github.com/GoAdminGroup/go-admin/modules/db"
+	"github.com/GoAdminGroup/go-admin/modules/utils"
+	"github.com/magiconair/properties/assert"
+	_ "github.com/mattn/go-sqlite3"
+)
+
+func TestMigration(t *testing.T) {
+	utils.InitUtils(100, func(s string) string { return s })
+
+	conn := db.GetSqliteDB().InitDB(map[string]config.Database{
+		"default": { Driver: db.DriverSqlite, File: filepath.Join(t.TempDir(), "admin.db") },
+	})
+	defer conn.Close()
+
+	Register(Migration{
+		Version: 2,
+		Plugin : "example",
+		Name   : "create example_posts",
+		Up     : map[string]string{ db.DriverSqlite: `CREATE TABLE example_posts (id INTEGER PRIMARY KEY)` },
+		Down   : map[string]string{ db.DriverSqlite: `DROP TABLE example_posts` },
+	}, Migration{
+		Version: 1,
+		Name   : "create example_tags",
+		Up     : map[string]string{ db.DriverSqlite: `CREATE TABLE example_tags (id INTEGER PRIMARY KEY)` },
+		Down   : map[string]string{ db.DriverSqlite: `DROP TABLE example_tags` },
+	})
+
+	list, err := Up(conn)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, len(list), 2)
+	assert.Equal(t, list[0].Version, int64(1))
+
+	list, err = Up(conn)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, len(list), 0)
+
+	list, err = Down(conn, 1)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, len(list), 1)
+	assert.Equal(t, list[0].Plugin, "example")
+
+	_, err = conn.Exec(`SELECT * FROM example_posts`)
+	assert.Equal(t, err != nil, true)
+
+	status, err := GetStatus(conn)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, status[0].Applied, true)
+	assert.Equal(t, status[1].Applied, false)
+
+	pending, err := IsPending(conn, "example", 2)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, pending, true)
+
+	assert.Equal(t, Apply(conn, "example", 2), nil)
+	pending, err = IsPending(conn, "example", 2)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, pending, false)
+	_, err = conn.Exec(`SELECT * FROM example_posts`)
+	assert.Equal(t, err, nil)
+
+	defer func() {
+		assert.Equal(t, recover() != nil, true)
+	}()
+	Register(Migration{ Version: 1, Name: "again" })
+}
@@ -0,0 +1,516 @@
+// Package qrcode encodes the text into the QR Code in the byte mode, the versions
+// from 1 to 10 are supported which are enough for the urls and the otpauth uris.
+// The images are generated locally so that it works offline.
+package qrcode
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"image"
+	"image/color"
+	"image/png"
+)
+
+// Level is the error correction level.
+type Level int
+
+const (
+	Low Level = iota
+	Medium
+	Quartile
+	High
+)
+
+// formatBits is the bits of the level in the format information.
+var formatBits = [...]int{ Low: 1, Medium: 0, Quartile: 3, High: 2 }
+
+// QuietZone is the width of the blank border around the symbol in modules.
+const QuietZone = 4
+
+const maxVersion = 10
+
+// ErrTooLong is returned when the text does not fit in the supported versions.
+var ErrTooLong = errors.New("qrcode: text too long")
+
+// blockInfo is the error correction blocks of a version and a level: the number
+// of the error correction codewords per block, and the number of the blocks and
+// the data codewords per block of the two groups.
+type blockInfo struct {
+	ecPerBlock, blocks1, data1, blocks2, data2 int
+}
+
+func (b blockInfo) dataCodewords() int {
+	return b.blocks1 * b.data1 + b.blocks2 * b.data2
+}
+
+var blockTable = [maxVersion + 1][4]blockInfo{
+	{},
+	{ { 7, 1, 19, 0, 0 }, { 10, 1, 16, 0, 0 }, { 13, 1, 13, 0, 0 }, { 17, 1, 9, 0, 0 } },
+	{ { 10, 1, 34, 0, 0 }, { 16, 1, 28, 0, 0 }, { 22, 1, 22, 0, 0 }, { 28, 1, 16, 0, 0 } },
+	{ { 15, 1, 55, 0, 0 }, { 26, 1, 44, 0, 0 }, { 18, 2, 17, 0, 0 }, { 22, 2, 13, 0, 0 } },
+	{ { 20, 1, 80, 0, 0 }, { 18, 2, 32, 0, 0 }, { 26, 2, 24, 0, 0 }, { 16, 4, 9, 0, 0 } },
+	{ { 26, 1, 108, 0, 0 }, { 24, 2, 43, 0, 0 }, { 18, 2, 15, 2, 16 }, { 22, 2, 11, 2, 12 } },
+	{ { 18, 2, 68, 0, 0 }, { 16, 4, 27, 0, 0 }, { 24, 4, 19, 0, 0 }, { 28, 4, 15, 0, 0 } },
+	{ { 20, 2, 78, 0, 0 }, { 18, 4, 31, 0, 0 }, { 18, 2, 14, 4, 15 }, { 26, 4, 13, 1, 14 } },
+	{ { 24, 2, 97, 0, 0 }, { 22, 2, 38, 2, 39 }, { 22, 4, 18, 2, 19 }, { 26, 4, 14, 2, 15 } },
+	{ { 30, 2, 116, 0, 0 }, { 22, 3, 36, 2, 37 }, { 20, 4, 16, 4, 17 }, { 24, 4, 12, 4, 13 } },
+	{ { 18, 2, 68, 2, 69 }, { 26, 4, 43, 1, 44 }, { 24, 6, 19, 2, 20 }, { 28, 6, 15, 2, 16 } },
+}
+
+var alignmentTable = [maxVersion + 1][]int{
+	{}, {},
+	{ 6, 18 }, { 6, 22 }, { 6, 26 }, { 6, 30 }, { 6, 34 },
+	{ 6, 22, 38 }, { 6, 24, 42 }, { 6, 26, 46 }, { 6, 28, 50 },
+}
+
+// Code is an encoded QR Code symbol.
+type Code struct {
+	Version int
+	Level   Level
+
+	size     int
+	modules  [][]bool
+	function [][]bool
+}
+
+// Encode encodes the text with the smallest version fitting in the given level.
+func Encode(text string, level Level) (*Code, error) {
+	data := []byte(text)
+
+	version := 0
+	for v := 1; v <= maxVersion; v++ {
+		if 4 + countBits(v) + len(data) * 8 <= blockTable[v][level].dataCodewords() * 8 {
+			version = v
+			break
+		}
+	}
+	if version == 0 {
+		return nil, ErrTooLong
+	}
+
+	c := &Code{ Version: version, Level: level, size: version * 4 + 17 }
+	c.modules  = newGrid(c.size)
+	c.function = newGrid(c.size)
+
+	c.drawFunctionPatterns()
+	c.drawCodewords(c.codewords(data))
+
+	best, bestPenalty := 0, -1
+	for mask := 0; mask < 8; mask++ {
+		c.applyMask(mask)
+		c.drawFormatBits(mask)
+		if penalty := c.penalty(); bestPenalty < 0 || penalty < bestPenalty {
+			best, bestPenalty = mask, penalty
+		}
+		c.applyMask(mask)
+	}
+	c.applyMask(best)
+	c.drawFormatBits(best)
+
+	return c, nil
+}
+
+// Size return the number of the modules of a side without the quiet zone.
+func (c *Code) Size() int {
+	return c.size
+}
+
+// Black reports whether the module at the column x and the row y is dark.
+func (c *Code) Black(x, y int) bool {
+	return x >= 0 && y >= 0 && x < c.size && y < c.size && c.modules[y][x]
+}
+
+// Image return the image of the symbol with the quiet zone, a module is drawn
+// as a square of scale pixels.
+func (c *Code) Image(scale int) image.Image {
+	if scale < 1 {
+		scale = 1
+	}
+	side := (c.size + QuietZone * 2) * scale
+	img  := image.NewGray(image.Rect(0, 0, side, side))
+	for y := 0; y < side; y++ {
+		for x := 0; x < side; x++ {
+			if c.Black(x / scale - QuietZone, y / scale - QuietZone) {
+				img.SetGray(x, y, color.Gray{ Y: 0 })
+			} else {
+				img.SetGray(x, y, color.Gray{ Y: 255 })
+			}
+		}
+	}
+	return img
+}
+
+// PNG return the png image of the symbol.
+func (c *Code) PNG(scale int) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, c.Image(scale)); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// DataURL return the png image of the symbol as a data url, which can be used
+// as the src of an img.
+func (c *Code) DataURL(scale int) (string, error) {
+	b, err := c.PNG(scale)
+	if err != nil { return "", err }
+	return "data:image/png;base64," + base64.StdEncoding.EncodeToString(b), nil
+}
+
+// DataURL encodes the text in the medium level and return the png data url.
+func DataURL(text string, scale int) (string, error) {
+	c, err := Encode(text, Medium)
+	if err != nil { return "", err }
+	return c.DataURL(scale)
+}
+
+func newGrid(size int) [][]bool {
+	grid := make([][]bool, size)
+	for i := range grid {
+		grid[i] = make([]bool, size)
+	}
+	return grid
+}
+
+// countBits return the bits of the character count of the byte mode.
+func countBits(version int) int {
+	if version < 10 {
+		return 8
+	}
+	return 16
+}
+
+// codewords return the interleaved data and error correction codewords.
+func (c *Code) codewords(data []byte) []byte {
+	info := blockTable[c.Version][c.Level]
+
+	var bb bitBuffer
+	bb.append(4, 4)
+	bb.append(len(data), countBits(c.Version))
+	for _, b := range data {
+		bb.append(int(b), 8)
+	}
+
+	capacity := info.dataCodewords() * 8
+	terminator := capacity - len(bb)
+	if terminator > 4 {
+		terminator = 4
+	}
+	bb.append(0, terminator)
+	bb.append(0, (8 - len(bb) % 8) % 8)
+	for pad := 0xEC; len(bb) < capacity; pad ^= 0xEC ^ 0x11 {
+		bb.append(pad, 8)
+	}
+
+	all := bb.bytes()
+
+	var (
+		blocks    = make([][]byte, 0, info.blocks1 + info.blocks2)
+		ecBlocks  = make([][]byte, 0, info.blocks1 + info.blocks2)
+		generator = rsGenerator(info.ecPerBlock)
+		offset    = 0
+	)
+	for i := 0; i < info.blocks1 + info.blocks2; i++ {
+		n := info.data1
+		if i >= info.blocks1 {
+			n = info.data2
+		}
+		block := all[offset:offset + n]
+		offset += n
+		blocks   = append(blocks, block)
+		ecBlocks = append(ecBlocks, rsRemainder(block, generator))
+	}
+
+	res := make([]byte, 0, len(all) + info.ecPerBlock * len(blocks))
+	for i := 0; i < info.data2 || i < info.data1; i++ {
+		for _, block := range blocks {
+			if i < len(block) {
+				res = append(res, block[i])
+			}
+		}
+	}
+	for i := 0; i < info.ecPerBlock; i++ {
+		for _, block := range ecBlocks {
+			res = append(res, block[i])
+		}
+	}
+	return res
+}
+
+type bitBuffer []bool
+
+func (bb *bitBuffer) append(value, length int) {
+	for i := length - 1; i >= 0; i-- {
+		*bb = append(*bb, (value >> uint(i)) & 1 == 1)
+	}
+}
+
+func (bb bitBuffer) bytes() []byte {
+	res := make([]byte, len(bb) / 8)
+	for i, bit := range bb {
+		if bit {
+			res[i / 8] |= 1 << uint(7 - i % 8)
+		}
+	}
+	return res
+}
+
+// rsMultiply multiplies in the GF(2^8) of the polynomial 0x11D.
+func rsMultiply(x, y byte) byte {
+	z := 0
+	for i := 7; i >= 0; i-- {
+		z = (z << 1) ^ ((z >> 7) * 0x11D)
+		z ^= int((y >> uint(i)) & 1) * int(x)
+	}
+	return byte(z)
+}
+
+// rsGenerator return the coefficients of the generator polynomial of the degree,
+// the leading coefficient is omitted.
+func rsGenerator(degree int) []byte {
+	res := make([]byte, degree)
+	res[degree - 1] = 1
+	root := byte(1)
+	for i := 0; i < degree; i++ {
+		for j := range res {
+			res[j] = rsMultiply(res[j], root)
+			if j + 1 < len(res) {
+				res[j] ^= res[j + 1]
+			}
+		}
+		root = rsMultiply(root, 0x02)
+	}
+	return res
+}
+
+func rsRemainder(data, generator []byte) []byte {
+	res := make([]byte, len(generator))
+	for _, b := range data {
+		factor := b ^ res[0]
+		copy(res, res[1:])
+		res[len(res) - 1] = 0
+		for i := range res {
+			res[i] ^= rsMultiply(generator[i], factor)
+		}
+	}
+	return res
+}
+
+func (c *Code) setFunction(x, y int, dark bool) {
+	c.modules[y][x]  = dark
+	c.function[y][x] = true
+}
+
+func (c *Code) drawFunctionPatterns() {
+	for i := 0; i < c.size; i++ {
+		c.setFunction(6, i, i % 2 == 0)
+		c.setFunction(i, 6, i % 2 == 0)
+	}
+
+	c.drawFinder(3, 3)
+	c.drawFinder(c.size - 4, 3)
+	c.drawFinder(3, c.size - 4)
+
+	positions := alignmentTable[c.Version]
+	last := len(positions) - 1
+	for i, y := range positions {
+		for j, x := range positions {
+			if i == 0 && j == 0 || i == 0 && j == last || i == last && j == 0 {
+				continue
+			}
+			c.drawAlignment(x, y)
+		}
+	}
+
+	// reserve the areas of the format bits which are drawn with the mask.
+	c.drawFormatBits(0)
+	c.drawVersion()
+}
+
+func (c *Code) drawFinder(x, y int) {
+	for dy := -4; dy <= 4; dy++ {
+		for dx := -4; dx <= 4; dx++ {
+			xx, yy := x + dx, y + dy
+			if xx < 0 || yy < 0 || xx >= c.size || yy >= c.size {
+				continue
+			}
+			dist := max(abs(dx), abs(dy))
+			c.setFunction(xx, yy, dist != 2 && dist != 4)
+		}
+	}
+}
+
+func (c *Code) drawAlignment(x, y int) {
+	for dy := -2; dy <= 2; dy++ {
+		for dx := -2; dx <= 2; dx++ {
+			c.setFunction(x + dx, y + dy, max(abs(dx), abs(dy)) != 1)
+		}
+	}
+}
+
+// formatInfo return the 15 bits of the format information of the mask.
+func (c *Code) formatInfo(mask int) int {
+	data := formatBits[c.Level] << 3 | mask
+	rem  := data
+	for i := 0; i < 10; i++ {
+		rem = (rem << 1) ^ ((rem >> 9) * 0x537)
+	}
+	return (data << 10 | rem) ^ 0x5412
+}
+
+func (c *Code) drawFormatBits(mask int) {
+	bits := c.formatInfo(mask)
+	bit  := func(i int) bool { return (bits >> uint(i)) & 1 == 1 }
+
+	for i := 0; i <= 5; i++ {
+		c.setFunction(8, i, bit(i))
+	}
+	c.setFunction(8, 7, bit(6))
+	c.setFunction(8, 8, bit(7))
+	c.setFunction(7, 8, bit(8))
+	for i := 9; i < 15; i++ {
+		c.setFunction(14 - i, 8, bit(i))
+	}
+
+	for i := 0; i < 8; i++ {
+		c.setFunction(c.size - 1 - i, 8, bit(i))
+	}
+	for i := 8; i < 15; i++ {
+		c.setFunction(8, c.size - 15 + i, bit(i))
+	}
+	// the dark module.
+	c.setFunction(8, c.size - 8, true)
+}
+
+func (c *Code) drawVersion() {
+	if c.Version < 7 {
+		return
+	}
+	rem := c.Version
+	for i := 0; i < 12; i++ {
+		rem = (rem << 1) ^ ((rem >> 11) * 0x1F25)
+	}
+	bits := c.Version << 12 | rem
+	for i := 0; i < 18; i++ {
+		dark := (bits >> uint(i)) & 1 == 1
+		a, b := c.size - 11 + i % 3, i / 3
+		c.setFunction(a, b, dark)
+		c.setFunction(b, a, dark)
+	}
+}
+
+// drawCodewords places the bits of the codewords in the zigzag order.
+func (c *Code) drawCodewords(data []byte) {
+	i := 0
+	for right := c.size - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			right = 5
+		}
+		for vert := 0; vert < c.size; vert++ {
+			for j := 0; j < 2; j++ {
+				x := right - j
+				y := vert
+				if (right + 1) & 2 == 0 {
+					y = c.size - 1 - vert
+				}
+				if c.function[y][x] || i >= len(data) * 8 {
+					continue
+				}
+				c.modules[y][x] = (data[i >> 3] >> uint(7 - i & 7)) & 1 == 1
+				i++
+			}
+		}
+	}
+}
+
+// applyMask flips the data modules by the mask, applying it twice undoes it.
+func (c *Code) applyMask(mask int) {
+	for y := 0; y < c.size; y++ {
+		for x := 0; x < c.size; x++ {
+			if c.function[y][x] {
+				continue
+			}
+			var flip bool
+			switch mask {
+			case 0: flip = (x + y) % 2 == 0
+			case 1: flip = y % 2 == 0
+			case 2: flip = x % 3 == 0
+			case 3: flip = (x + y) % 3 == 0
+			case 4: flip = (x / 3 + y / 2) % 2 == 0
+			case 5: flip = x * y % 2 + x * y % 3 == 0
+			case 6: flip = (x * y % 2 + x * y % 3) % 2 == 0
+			case 7: flip = ((x + y) % 2 + x * y % 3) % 2 == 0
+			}
+			if flip {
+				c.modules[y][x] = !c.modules[y][x]
+			}
+		}
+	}
+}
+
+var finderLike = [...]bool{ true, false, true, true, true, false, true, false, false, false, false }
+
+// penalty scores the symbol by the rules of the mask evaluation, the lower the better.
+func (c *Code) penalty() int {
+	var (
+		res  = 0
+		dark = 0
+		at   = func(x, y int, horizontal bool) bool {
+			if horizontal { return c.modules[y][x] }
+			return c.modules[x][y]
+		}
+	)
+
+	for _, horizontal := range []bool{ true, false } {
+		for y := 0; y < c.size; y++ {
+			run := 1
+			for x := 1; x < c.size; x++ {
+				if at(x, y, horizontal) == at(x - 1, y, horizontal) {
+					run++
+					if run == 5 {
+						res += 3
+					} else if run > 5 {
+						res++
+					}
+				} else {
+					run = 1
+				}
+			}
+			for x := 0; x + len(finderLike) <= c.size; x++ {
+				forward, backward := true, true
+				for k, v := range finderLike {
+					if at(x + k, y, horizontal) != v { forward = false }
+					if at(x + k, y, horizontal) != finderLike[len(finderLike) - 1 - k] { backward = false }
+				}
+				if forward { res += 40 }
+				if backward { res += 40 }
+			}
+		}
+	}
+
+	for y := 0; y < c.size; y++ {
+		for x := 0; x < c.size; x++ {
+			if c.modules[y][x] {
+				dark++
+			}
+			if x + 1 < c.size && y + 1 < c.size {
+				v := c.modules[y][x]
+				if v == c.modules[y][x + 1] && v == c.modules[y + 1][x] && v == c.modules[y + 1][x + 1] {
+					res += 3
+				}
+			}
+		}
+	}
+
+	total := c.size * c.size
+	k := (abs(dark * 20 - total * 10) + total - 1) / total - 1
+	res += k * 10
+
+	return res
+}
+
+func abs(x int) int {
+	if x < 0 { return -x }
+	return x
+}
@@ -0,0 +1,189 @@
+package qrcode
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/magiconair/properties/assert"
+)
+
+func TestReedSolomon(t *testing.T) {
+	// the data codewords of "HELLO WORLD" of the 1-M in the alphanumeric mode and
+	// their error correction codewords.
+	data := []byte{ 32, 91, 11, 120, 209, 114, 220, 77, 67, 64, 236, 17, 236, 17, 236, 17 }
+	assert.Equal(t, rsRemainder(data, rsGenerator(10)), []byte{ 196, 35, 39, 119, 235, 215, 231, 226, 93, 23 })
+}
+
+func TestFormatAndVersionInfo(t *testing.T) {
+	assert.Equal(t, (&Code{ Level: Medium }).formatInfo(0), 0x5412)
+	assert.Equal(t, (&Code{ Level: Low }).formatInfo(4), 0x662F)
+	assert.Equal(t, (&Code{ Level: High }).formatInfo(0), 0x1689)
+
+	// the version information of the version 7 is 000111110010010100.
+	c := &Code{ Version: 7, size: 45 }
+	c.modules, c.function = newGrid(c.size), newGrid(c.size)
+	c.drawVersion()
+	bits := 0
+	for i := 17; i >= 0; i-- {
+		bits <<= 1
+		if c.modules[i / 3][c.size - 11 + i % 3] { bits |= 1 }
+	}
+	assert.Equal(t, bits, 0x07C94)
+}
+
+func TestRoundTrip(t *testing.T) {
+	for _, text := range []string{
+		"HELLO WORLD",
+		"otpauth://totp/GoAdmin:admin?secret=JBSWY3DPEHPK3PXP&issuer=GoAdmin",
+		"https://example.com/admin/info/manager?__page=1&__pageSize=10&__sort=id&__sort_type=desc",
+		strings.Repeat("0123456789abcdef", 13),
+	} {
+		for _, level := range []Level{ Low, Medium, Quartile, High } {
+			c, err := Encode(text, level)
+			if err == ErrTooLong {
+				continue
+			}
+			assert.Equal(t, err, nil)
+			assert.Equal(t, decode(t, c), text)
+		}
+	}
+
+	_, err := Encode(strings.Repeat("a", 300), Medium)
+	assert.Equal(t, err, ErrTooLong)
+}
+
+// decode reads the text back from the modules of the symbol, the function
+// patterns and the error correction are checked on the way.
+func decode(t *testing.T, c *Code) string {
+	size    := c.Size()
+	version := (size - 17) / 4
+	assert.Equal(t, c.Version, version)
+
+	// the three finder patterns.
+	for _, corner := range [][2]int{ { 0, 0 }, { size - 7, 0 }, { 0, size - 7 } } {
+		for dy := 0; dy < 7; dy++ {
+			for dx := 0; dx < 7; dx++ {
+				// the light ring is the second one from the outside.
+				assert.Equal(t, c.Black(corner[0] + dx, corner[1] + dy), max(abs(dx - 3), abs(dy - 3)) != 2)
+			}
+		}
+	}
+	assert.Equal(t, c.Black(8, size - 8), true)
+
+	// the two copies of the format information.
+	var first, second int
+	for _, p := range [][2]int{ { 8, 0 }, { 8, 1 }, { 8, 2 }, { 8, 3 }, { 8, 4 }, { 8, 5 }, { 8, 7 }, { 8, 8 },
+		{ 7, 8 }, { 5, 8 }, { 4, 8 }, { 3, 8 }, { 2, 8 }, { 1, 8 }, { 0, 8 } } {
+		first >>= 1
+		if c.Black(p[0], p[1]) { first |= 1 << 14 }
+	}
+	for i := 0; i < 15; i++ {
+		second >>= 1
+		x, y := size - 1 - i, 8
+		if i >= 8 { x, y = 8, size - 15 + i }
+		if c.Black(x, y) { second |= 1 << 14 }
+	}
+	assert.Equal(t, first, second)
+	format := first ^ 0x5412
+	mask   := (format >> 10) & 7
+	level  := map[int]Level{ 1: Low, 0: Medium, 3: Quartile, 2: High }[format >> 13]
+	assert.Equal(t, level, c.Level)
+
+	// the modules of the function patterns are not data.
+	reserved := newGrid(size)
+	fill := func(x0, y0, w, h int) {
+		for y := y0; y < y0 + h; y++ {
+			for x := x0; x < x0 + w; x++ {
+				reserved[y][x] = true
+			}
+		}
+	}
+	fill(0, 0, 9, 9)
+	fill(size - 8, 0, 8, 9)
+	fill(0, size - 8, 9, 8)
+	fill(6, 0, 1, size)
+	fill(0, 6, size, 1)
+	if version >= 7 {
+		fill(size - 11, 0, 3, 6)
+		fill(0, size - 11, 6, 3)
+	}
+	// the alignment patterns overlapping the finders are left out.
+	centers := alignmentTable[version]
+	for _, y := range centers {
+		for _, x := range centers {
+			if x < 9 && y < 9 || x > size - 9 && y < 9 || x < 9 && y > size - 9 { continue }
+			fill(x - 2, y - 2, 5, 5)
+		}
+	}
+
+	// the bits in the zigzag order without the mask.
+	var bits []bool
+	for right := size - 1; right >= 1; right -= 2 {
+		if right == 6 { right-- }
+		for i := 0; i < size; i++ {
+			y := size - 1 - i
+			if (size - 1 - right) / 2 % 2 == 1 { y = i }
+			for _, x := range []int{ right, right - 1 } {
+				if reserved[y][x] { continue }
+				flip := [8]bool{
+					(x + y) % 2 == 0, y % 2 == 0, x % 3 == 0, (x + y) % 3 == 0,
+					(x / 3 + y / 2) % 2 == 0, x * y % 2 + x * y % 3 == 0,
+					(x * y % 2 + x * y % 3) % 2 == 0, ((x + y) % 2 + x * y % 3) % 2 == 0,
+				}[mask]
+				bits = append(bits, c.Black(x, y) != flip)
+			}
+		}
+	}
+	codewords := bitBuffer(bits[:len(bits) / 8 * 8]).bytes()
+
+	// split the interleaved codewords into the blocks and check the syndromes.
+	info   := blockTable[version][level]
+	blocks := make([][]byte, info.blocks1 + info.blocks2)
+	n      := 0
+	for i := 0; i < info.data2 || i < info.data1; i++ {
+		for b := range blocks {
+			if b < info.blocks1 && i >= info.data1 { continue }
+			blocks[b] = append(blocks[b], codewords[n])
+			n++
+		}
+	}
+	for i := 0; i < info.ecPerBlock; i++ {
+		for b := range blocks {
+			blocks[b] = append(blocks[b], codewords[n])
+			n++
+		}
+	}
+	var data []byte
+	for _, block := range blocks {
+		root := byte(1)
+		for i := 0; i < info.ecPerBlock; i++ {
+			var syndrome byte
+			for _, v := range block {
+				syndrome = rsMultiply(syndrome, root) ^ v
+			}
+			assert.Equal(t, syndrome, byte(0))
+			root = rsMultiply(root, 2)
+		}
+		data = append(data, block[:len(block) - info.ecPerBlock]...)
+	}
+
+	// the byte mode segment.
+	var (
+		pos  = 0
+		read = func(length int) int {
+			v := 0
+			for i := 0; i < length; i++ {
+				v <<= 1
+				if data[(pos + i) / 8] >> uint(7 - (pos + i) % 8) & 1 == 1 { v |= 1 }
+			}
+			pos += length
+			return v
+		}
+	)
+	assert.Equal(t, read(4), 4)
+	text := make([]byte, read(countBits(version)))
+	for i := range text {
+		text[i] = byte(read(8))
+	}
+	return string(text)
+}
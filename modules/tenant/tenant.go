@@ -0,0 +1,205 @@
+// Copyright 2019 GoAdmin Core Team. All rights reserved.
+// Use of this source code is governed by a Apache-2.0 style
+// license that can be found in the LICENSE file.
+
+// Package tenant serves several admin panels from one engine. A tenant is resolved
+// from the host or the url prefix of every request, and the connection, the menus,
+// the permissions and the config of the request are read from the tenant instead
+// of the globals.
+package tenant
+
+import (
+	"net"
+	"strings"
+	"sync"
+
+	"github.com/GoAdminGroup/go-admin/context"
+	"github.com/GoAdminGroup/go-admin/modules/config"
+	"github.com/GoAdminGroup/go-admin/modules/db"
+)
+
+// ContextKey is the key of the resolved tenant in the user values of the context.
+const ContextKey = "tenant"
+
+// PrefixHeader is the header of the url prefix of the tenant, which is set by the
+// reverse proxy stripping the prefix before the request reaches the engine. It is
+// only read from the trusted proxies of the config, see config.TrustedProxies.
+const PrefixHeader = "X-Forwarded-Prefix"
+
+// Tenant is an admin panel served by the engine. The menus, the roles and the
+// permissions of a tenant are the goadmin tables of its own database.
+type Tenant struct {
+	Name string
+
+	// Hosts and Prefixes resolve the tenant of a request, see the ByHost and the
+	// ByPrefix.
+	Hosts    []string
+	Prefixes []string
+
+	// Connection is the name of the database of the tenant in the Databases of the
+	// global config, the driver of which is the same as the default one.
+	Connection string
+
+	// Config is the config of the tenant, like the title, the logo and the custom
+	// html, the global config is used when it is nil.
+	Config *config.Config
+}
+
+// Resolver finds the tenant of the request.
+type Resolver func(ctx *context.Context) (*Tenant, bool)
+
+var (
+	lock     sync.RWMutex
+	tenants  = make(map[string]*Tenant)
+	resolver Resolver = Default
+)
+
+// Register adds the tenants, a tenant of the same name is replaced.
+func Register(list ...*Tenant) {
+	lock.Lock()
+	defer lock.Unlock()
+	for _, t := range list {
+		tenants[t.Name] = t
+	}
+}
+
+// Get return the registered tenant of the name.
+func Get(name string) (*Tenant, bool) {
+	lock.RLock()
+	defer lock.RUnlock()
+	t, ok := tenants[name]
+	return t, ok
+}
+
+// All return the registered tenants.
+func All() []*Tenant {
+	lock.RLock()
+	defer lock.RUnlock()
+	list := make([]*Tenant, 0, len(tenants))
+	for _, t := range tenants {
+		list = append(list, t)
+	}
+	return list
+}
+
+// SetResolver replaces the Default resolver.
+func SetResolver(r Resolver) {
+	lock.Lock()
+	defer lock.Unlock()
+	resolver = r
+}
+
+// ByHost finds the tenant of the host of the request, the port is ignored.
+func ByHost(ctx *context.Context) (*Tenant, bool) {
+	host := ctx.Request.Host
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	return find(func(t *Tenant) bool {
+		for _, item := range t.Hosts {
+			if strings.EqualFold(item, host) {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// ByPrefix finds the tenant of the url prefix in the PrefixHeader of the request,
+// the header of a client other than a trusted proxy is ignored.
+func ByPrefix(ctx *context.Context) (*Tenant, bool) {
+	remote, _, err := net.SplitHostPort(strings.TrimSpace(ctx.Request.RemoteAddr))
+	if err != nil {
+		remote = strings.TrimSpace(ctx.Request.RemoteAddr)
+	}
+	if !config.IsTrustedProxy(remote) {
+		return nil, false
+	}
+
+	prefix := "/" + strings.Trim(ctx.Headers(PrefixHeader), "/")
+	if prefix == "/" {
+		return nil, false
+	}
+	return find(func(t *Tenant) bool {
+		for _, item := range t.Prefixes {
+			if "/" + strings.Trim(item, "/") == prefix {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// Default finds the tenant by the host first and then by the url prefix.
+func Default(ctx *context.Context) (*Tenant, bool) {
+	if t, ok := ByHost(ctx); ok {
+		return t, true
+	}
+	return ByPrefix(ctx)
+}
+
+func find(match func(t *Tenant) bool) (*Tenant, bool) {
+	lock.RLock()
+	defer lock.RUnlock()
+	for _, t := range tenants {
+		if match(t) {
+			return t, true
+		}
+	}
+	return nil, false
+}
+
+// Middleware resolves the tenant of the request and keeps it in the context, the
+// requests without a tenant are served by the globals.
+func Middleware(ctx *context.Context) {
+	lock.RLock()
+	r, empty := resolver, len(tenants) == 0
+	lock.RUnlock()
+
+	if empty || r == nil {
+		return
+	}
+	if t, ok := r(ctx); ok {
+		ctx.SetUserValue(ContextKey, t)
+	}
+}
+
+// FromContext return the tenant of the request.
+func FromContext(ctx *context.Context) (*Tenant, bool) {
+	if ctx == nil {
+		return nil, false
+	}
+	t, ok := ctx.UserValue[ContextKey].(*Tenant)
+	return t, ok
+}
+
+// Conn return the connection of the tenant of the request, which is the given
+// connection of which the default database is replaced by the one of the tenant.
+func Conn(ctx *context.Context, conn db.Connection) db.Connection {
+	if t, ok := FromContext(ctx); ok {
+		return t.Conn(conn)
+	}
+	return conn
+}
+
+// Config return the config of the tenant of the request, or the given one.
+func Config(ctx *context.Context, cfg *config.Config) *config.Config {
+	if t, ok := FromContext(ctx); ok && t.Config != nil {
+		return t.Config
+	}
+	return cfg
+}
+
+// Name return the name of the tenant of the request, empty when the request is
+// served by the globals.
+func Name(ctx *context.Context) string {
+	if t, ok := FromContext(ctx); ok {
+		return t.Name
+	}
+	return ""
+}
+
+// Conn return the connection of the tenant based on the given connection.
+func (t *Tenant) Conn(conn db.Connection) db.Connection {
+	return db.WithConnectionName(conn, t.Connection)
+}
@@ -0,0 +1,49 @@
+package tenant
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/GoAdminGroup/go-admin/context"
+	"github.com/GoAdminGroup/go-admin/modules/config"
+	"github.com/magiconair/properties/assert"
+)
+
+func TestResolve(t *testing.T) {
+	config.Initialize(&config.Config{ TrustedProxies: []string{ "192.0.2.1" } })
+
+	Register(&Tenant{ Name: "a", Hosts: []string{"a.example.com"}, Connection: "a" },
+		&Tenant{ Name: "b", Prefixes: []string{"/b/"}, Connection: "b" })
+
+	req := httptest.NewRequest("GET", "http://A.example.com:8080/admin", nil)
+	ctx := context.NewContext(req)
+	Middleware(ctx)
+	tn, ok := FromContext(ctx)
+	assert.Equal(t, ok, true)
+	assert.Equal(t, tn.Name, "a")
+
+	req = httptest.NewRequest("GET", "http://example.com/admin", nil)
+	req.Header.Set(PrefixHeader, "/b")
+	ctx = context.NewContext(req)
+	Middleware(ctx)
+	tn, ok = FromContext(ctx)
+	assert.Equal(t, ok, true)
+	assert.Equal(t, tn.Name, "b")
+	assert.Equal(t, Name(ctx), "b")
+
+	// the prefix of a client other than the trusted proxies is ignored.
+	req = httptest.NewRequest("GET", "http://example.com/admin", nil)
+	req.RemoteAddr = "203.0.113.9:1234"
+	req.Header.Set(PrefixHeader, "/b")
+	ctx = context.NewContext(req)
+	Middleware(ctx)
+	_, ok = FromContext(ctx)
+	assert.Equal(t, ok, false)
+	assert.Equal(t, Name(ctx), "")
+
+	ctx = context.NewContext(httptest.NewRequest("GET", "http://example.com/admin", nil))
+	Middleware(ctx)
+	_, ok = FromContext(ctx)
+	assert.Equal(t, ok, false)
+	assert.Equal(t, Conn(ctx, nil), nil)
+}
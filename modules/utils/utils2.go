@@ -20,7 +20,8 @@ var (
 
 	PkReplacer, TableFormReplacer, JsonTmplReplacer, JumpTmplReplacer, XssJsReplacer *strings.Replacer
 
-	logoutUrl string
+	logoutUrl    string
+	twoFactorUrl string
 
 	DefaultExceptMap map[string]struct{}
 )
@@ -29,6 +30,10 @@ func IsLogoutUrl(s string) bool {
 	return s == logoutUrl
 }
 
+func IsTwoFactorUrl(s string) bool {
+	return s == twoFactorUrl
+}
+
 func IsInfoUrl(s string) bool {
 	sub := rexInfoUrl.FindStringSubmatch(s)
 	return len(sub) > 2 && !strings.Contains(sub[2], "/")
@@ -89,10 +94,11 @@ func InitUtils(cacheSize int, urler func(string) string) {
 	XssJsReplacer      = strings.NewReplacer("<script>", "&lt;script&gt;", "</script>", "&lt;/script&gt;")
 
 	DefaultExceptMap = map[string]struct{}{
-		form.PreviousKey: {}, form.MethodKey: {}, form.TokenKey: {}, constant.IframeKey: {}, constant.IframeIDKey: {},
+		form.PreviousKey: {}, form.MethodKey: {}, form.TokenKey: {}, form.VersionKey: {}, constant.IframeKey: {}, constant.IframeIDKey: {},
 	}
 
-	logoutUrl = urler("/logout")
+	logoutUrl    = urler("/logout")
+	twoFactorUrl = urler("/two_factor")
 }
 
 func CachedRex(rexStr string) (*regexp.Regexp, error) {
@@ -29,17 +29,21 @@ func (admin *Admin) InitPlugin(services service.List) {
 	admin.InitBase(services, "")
 
 	c := config.GetService(services.MustGet("config"))
-	st := table.NewSystemTable(admin.Conn, c)
+	st := table.NewSystemTable(admin.Conn, c).SetGenerators(admin.tableList)
 	genList := table.GeneratorList{
 		"manager":        st.GetManagerTable,
 		"permission":     st.GetPermissionTable,
 		"roles":          st.GetRolesTable,
 		"op":             st.GetOpTable,
+		"export_jobs":    st.GetExportJobTable,
+		"audit_trail":    st.GetAuditTrailTable,
 		"menu":           st.GetMenuTable,
 		"normal_manager": st.GetNormalManagerTable,
+		"table_defs":     st.GetTableDefTable,
 	}
 	if c.IsAllowConfigModification() {
 		genList.Add("site", st.GetSiteTable)
+		genList.Add("config_revisions", st.GetConfigRevisionTable)
 	}
 	//if c.IsNotProductionEnvironment() {
 	//	genList.Add("generate", st.GetGenerateForm)
@@ -53,6 +57,13 @@ func (admin *Admin) InitPlugin(services service.List) {
 		Connection: admin.Conn,
 	}
 	admin.handler.UpdateCfg(handlerCfg)
+	admin.handler.InitExportJobs()
+	admin.handler.InitAuditTrail()
+	admin.handler.InitTwoFactor()
+	admin.handler.InitLoginThrottle()
+	admin.handler.InitConfigRevisions()
+	admin.handler.InitSavedViews()
+	admin.handler.InitTableDefs()
 	admin.initRouter()
 	admin.handler.SetRoutes(admin.App.Routers)
 	admin.handler.AddNavButton(admin.UI.NavButtons)
@@ -100,6 +111,12 @@ func (admin *Admin) AddGenerator(key string, g table.Generator) *Admin {
 	return admin
 }
 
+// RemoveGenerator removes the table model generator of the key.
+func (admin *Admin) RemoveGenerator(key string) *Admin {
+	admin.tableList.Remove(key)
+	return admin
+}
+
 // AddGenerators add table model generators.
 func (admin *Admin) AddGenerators(gen ...table.GeneratorList) *Admin {
 	admin.tableList.CombineAll(gen)
@@ -1,7 +1,10 @@
 package controller
 
 import (
+	"strings"
+
 	"github.com/GoAdminGroup/go-admin/context"
+	"github.com/GoAdminGroup/go-admin/plugins/admin/modules/form"
 	"github.com/GoAdminGroup/go-admin/plugins/admin/modules/guard"
 	"github.com/GoAdminGroup/go-admin/plugins/admin/modules/response"
 )
@@ -13,7 +16,14 @@ func (h *Handler) Update(ctx *context.Context) {
 	err := param.Panel.UpdateData(param.Value)
 
 	if err != nil {
-		response.Error(ctx, err.Error())
+		updateError(ctx, err, nil)
+		return
+	}
+
+	if version, ok := param.Value[form.VersionKey]; ok {
+		response.OkWithData(ctx, map[string]interface{}{
+			"version": strings.Join(version, ""),
+		})
 		return
 	}
 
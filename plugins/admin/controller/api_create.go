@@ -13,7 +13,7 @@ func (h *Handler) ApiCreate(ctx *context.Context) {
 	param := guard.GetNewFormParam(ctx)
 
 	if len(param.MultiForm.File) > 0 {
-		err := file.GetFileEngine(h.config.FileUploadEngine.Name).Upload(param.MultiForm)
+		err := file.GetFileEngine(h.config.Snapshot().FileUploadEngine.Name).Upload(param.MultiForm)
 		if err != nil {
 			response.Error(ctx, err.Error())
 			return
@@ -30,15 +30,20 @@ func (h *Handler) ApiCreate(ctx *context.Context) {
 }
 
 func (h *Handler) ApiCreateForm(ctx *context.Context) {
+	params := guard.GetShowNewFormParam(ctx)
+	prefix, paramStr := params.Prefix, params.Param.GetRouteParamStr()
+
+	panel, ok := h.table(prefix, ctx)
+	if !ok {
+		return
+	}
+
 	var (
-		params           = guard.GetShowNewFormParam(ctx)
-		prefix, paramStr = params.Prefix, params.Param.GetRouteParamStr()
-		panel            = h.table(prefix, ctx)
-		formInfo         = panel.GetNewFormInfo()
-		infoUrl          = h.routePathWithPrefix("api_info", prefix) + paramStr
-		newUrl           = h.routePathWithPrefix("api_new", prefix)
-		referer          = ctx.Referer()
-		f                = panel.GetActualNewForm()
+		formInfo = panel.GetNewFormInfo()
+		infoUrl  = h.routePathWithPrefix("api_info", prefix) + paramStr
+		newUrl   = h.routePathWithPrefix("api_new", prefix)
+		referer  = ctx.Referer()
+		f        = panel.GetActualNewForm()
 	)
 
 	if referer != "" && !utils.IsInfoUrl(referer) && !utils.IsNewUrl(referer, ctx.Query(constant.PrefixKey)) {
@@ -17,7 +17,10 @@ import (
 func (h *Handler) ApiDetail(ctx *context.Context) {
 	prefix := ctx.Query(constant.PrefixKey)
 	id := ctx.Query(constant.DetailPKKey)
-	panel := h.table(prefix, ctx)
+	panel, ok := h.table(prefix, ctx)
+	if !ok {
+		return
+	}
 	user := auth.Auth(ctx)
 
 	newPanel := panel.Copy()
@@ -110,7 +113,7 @@ $('.delete-btn').on('click', function (event) {
 		desc = language.Get("Detail")
 	}
 
-	formInfo, err := newPanel.GetDataWithId(param.WithPKs(id))
+	formInfo, err := newPanel.GetDataWithId(param.WithPKs(id).WithContext(ctx.Request.Context()))
 
 	if err != nil {
 		response.Error(ctx, err.Error())
@@ -10,7 +10,10 @@ import (
 func (h *Handler) ApiList(ctx *context.Context) {
 	prefix := ctx.Query(constant.PrefixKey)
 
-	panel := h.table(prefix, ctx)
+	panel, ok := h.table(prefix, ctx)
+	if !ok {
+		return
+	}
 
 	params := parameter.GetParam(ctx.Request.URL, panel.GetInfo().DefaultPageSize, panel.GetInfo().SortField,
 		panel.GetInfo().GetSort())
@@ -15,7 +15,7 @@ func (h *Handler) ApiUpdate(ctx *context.Context) {
 	param := guard.GetEditFormParam(ctx)
 
 	if len(param.MultiForm.File) > 0 {
-		err := file.GetFileEngine(h.config.FileUploadEngine.Name).Upload(param.MultiForm)
+		err := file.GetFileEngine(h.config.Snapshot().FileUploadEngine.Name).Upload(param.MultiForm)
 		if err != nil {
 			response.Error(ctx, err.Error())
 			return
@@ -32,7 +32,7 @@ func (h *Handler) ApiUpdate(ctx *context.Context) {
 
 	err := param.Panel.UpdateData(param.Value())
 	if err != nil {
-		response.Error(ctx, err.Error())
+		updateError(ctx, err, nil)
 		return
 	}
 
@@ -44,7 +44,10 @@ func (h *Handler) ApiUpdateForm(ctx *context.Context) {
 
 	prefix, param := params.Prefix, params.Param
 
-	panel := h.table(prefix, ctx)
+	panel, ok := h.table(prefix, ctx)
+	if !ok {
+		return
+	}
 
 	user := auth.Auth(ctx)
 
@@ -56,7 +59,7 @@ func (h *Handler) ApiUpdateForm(ctx *context.Context) {
 		footerKind = "edit_only"
 	}
 
-	formInfo, err := panel.GetDataWithId(param)
+	formInfo, err := panel.GetDataWithId(param.WithContext(ctx.Request.Context()))
 
 	if err != nil {
 		response.Error(ctx, err.Error())
@@ -0,0 +1,96 @@
+package controller
+
+import (
+	"fmt"
+	"html"
+	"strings"
+
+	"github.com/GoAdminGroup/go-admin/modules/config"
+	"github.com/GoAdminGroup/go-admin/modules/db"
+	"github.com/GoAdminGroup/go-admin/modules/language"
+	"github.com/GoAdminGroup/go-admin/modules/logger"
+	"github.com/GoAdminGroup/go-admin/plugins/admin/models"
+	"github.com/GoAdminGroup/go-admin/plugins/admin/modules/table"
+	"github.com/GoAdminGroup/go-admin/template"
+	"github.com/GoAdminGroup/go-admin/template/types"
+)
+
+// auditHistoryLimit is the max number of the audit records shown in the history of a row.
+const auditHistoryLimit = 50
+
+// InitAuditTrail creates the table of the audit records.
+func (h *Handler) InitAuditTrail() {
+	for _, conn := range h.connections() {
+		if err := models.Audit().SetConn(conn).Init(); err != nil {
+			logger.Error("init audit trail error: ", err)
+		}
+	}
+}
+
+// auditHistory return the table of the audit records of the row, only the changes
+// of the visible fields of the detail are shown, and the values of the fields the
+// data policies of the user mask are masked.
+func (h *Handler) auditHistory(conn db.Connection, panel table.Table, tableName, id string, fieldList types.FieldList) template.HTML {
+	if tableName == "" || id == "" {
+		return ""
+	}
+
+	records := models.Audit().SetConn(conn).GetByRow(tableName, id, auditHistoryLimit)
+	if len(records) == 0 {
+		return ""
+	}
+
+	heads := make(map[string]string, len(fieldList))
+	for _, field := range fieldList {
+		if !field.Hide {
+			heads[field.Field] = field.Head
+		}
+	}
+
+	userIds := make([]interface{}, 0, len(records))
+	for _, record := range records {
+		userIds = append(userIds, record.UserId)
+	}
+	userNames := make(map[string]string, len(userIds))
+	users, _ := db.WithDriver(conn).Table(config.GetAuthUserTable()).Select("id", "name").WhereIn("id", userIds).All()
+	for _, user := range users {
+		userNames[fmt.Sprintf("%v", user["id"])] = fmt.Sprintf("%v", user["name"])
+	}
+
+	infoList := make([]map[string]types.InfoItem, 0, len(records))
+	for _, record := range records {
+		var (
+			diff    = record.GetDiff()
+			changes strings.Builder
+		)
+		for _, field := range diff.Fields() {
+			head, ok := heads[field]
+			if !ok { continue }
+			change := diff[field]
+			before, after := models.FormatAuditValue(change.Before), models.FormatAuditValue(change.After)
+			if panel.ColumnRule(field) >= table.ColumnMask {
+				before, after = table.MaskedValue, table.MaskedValue
+			}
+			changes.WriteString(fmt.Sprintf("<p><b>%s</b>: %s &rarr; %s</p>", html.EscapeString(head),
+				html.EscapeString(before), html.EscapeString(after)))
+		}
+		infoList = append(infoList, map[string]types.InfoItem{
+			"created_at": { Content: template.HTML(html.EscapeString(record.CreatedAt)) },
+			"user"      : { Content: template.HTML(html.EscapeString(userNames[fmt.Sprintf("%d", record.UserId)])) },
+			"action"    : { Content: template.HTML(language.Get(record.Action)) },
+			"changes"   : { Content: template.HTML(changes.String()) },
+		})
+	}
+
+	return aBox().
+		SetBody(aTable().
+			SetThead(types.Thead{
+				{ Head: language.Get("Created At"), Field: "created_at" },
+				{ Head: language.Get("User")      , Field: "user"       },
+				{ Head: language.Get("Action")    , Field: "action"     },
+				{ Head: language.Get("Changes")   , Field: "changes"    },
+			}).
+			SetInfoList(infoList).
+			GetContent()).
+		GetContent()
+}
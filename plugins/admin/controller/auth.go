@@ -4,12 +4,14 @@ import (
 	"net/http"
 	"net/url"
 	"strings"
+	"time"
 
 	"github.com/GoAdminGroup/go-admin/context"
 	"github.com/GoAdminGroup/go-admin/modules/auth"
 	"github.com/GoAdminGroup/go-admin/modules/config"
 	"github.com/GoAdminGroup/go-admin/modules/db"
 	"github.com/GoAdminGroup/go-admin/modules/logger"
+	"github.com/GoAdminGroup/go-admin/modules/utils"
 	"github.com/GoAdminGroup/go-admin/plugins/admin/models"
 	"github.com/GoAdminGroup/go-admin/plugins/admin/modules/captcha"
 	"github.com/GoAdminGroup/go-admin/plugins/admin/modules/response"
@@ -19,12 +21,25 @@ import (
 // Auth check the input password and username for authentication.
 func (h *Handler) Auth(ctx *context.Context) {
 	var (
-		user   models.UserModel
-		ok     bool
-		errMsg = "fail"
-		s      = h.services.Get(auth.ServiceKey)
+		user     models.UserModel
+		ok       bool
+		errMsg   = "fail"
+		s        = h.services.Get(auth.ServiceKey)
+		username = ctx.FormValue("username")
+		ip       = auth.ClientIP(ctx)
+		throttle = auth.NewLoginThrottle(h.connection(ctx))
 	)
 
+	if wait, locked := throttle.Check(username, ip); wait > 0 {
+		h.recordLoginAttempt(ctx, username, "locked", wait)
+		if locked {
+			response.BadRequest(ctx, "too many failed login attempts, the account is locked temporarily")
+		} else {
+			response.BadRequest(ctx, "too many failed login attempts, please try again later")
+		}
+		return
+	}
+
 	if capDriver, ok := h.captchaConfig["driver"]; ok {
 		if capt, ok := captcha.Get(capDriver); ok {
 			if !capt.Validate(ctx.FormValue("token")) {
@@ -35,18 +50,23 @@ func (h *Handler) Auth(ctx *context.Context) {
 	}
 
 	if s == nil {
-		username := ctx.FormValue("username")
 		password := ctx.FormValue("password")
 		if username == "" || password == "" {
 			response.BadRequest(ctx, "wrong username or password")
 			return
 		}
-		user, ok = auth.Check(username, password, h.conn)
+		user, ok = auth.Check(username, password, h.connection(ctx))
 	} else {
 		user, ok, errMsg = auth.GetService(s).P(ctx)
 	}
 
 	if !ok {
+		wait, locked := throttle.Fail(username, ip)
+		if locked {
+			h.recordLoginAttempt(ctx, username, "locked", wait)
+		} else {
+			h.recordLoginAttempt(ctx, username, "failed", wait)
+		}
 		response.BadRequest(ctx, errMsg)
 		return
 	}
@@ -55,28 +75,57 @@ func (h *Handler) Auth(ctx *context.Context) {
 		return
 	}
 
-	err := auth.SetCookie(ctx, user, h.conn)
-	if err != nil {
-		response.Error(ctx, err.Error())
-		return
-	}
-
+	redirect := h.config.GetIndexURL()
 	if ref := ctx.Referer(); ref != "" {
 		if u, err := url.Parse(ref); err == nil {
 			if r := u.Query().Get("ref"); r != "" {
-				rr, _ := url.QueryUnescape(r)
-				response.OkWithData(ctx, map[string]interface{}{ "url": rr })
-				return
+				redirect, _ = url.QueryUnescape(r)
 			}
 		}
 	}
 
-	response.OkWithData(ctx, map[string]interface{}{ "url": h.config.GetIndexURL() })
+	// the failures are kept until the second factor passes too.
+	if twoFactorUrl, ok := h.twoFactorLogin(ctx, user, username, redirect); ok {
+		response.OkWithData(ctx, map[string]interface{}{ "url": twoFactorUrl })
+		return
+	}
+
+	throttle.Succeed(username)
+
+	err := auth.SetCookie(ctx, user, h.connection(ctx))
+	if err != nil {
+		response.Error(ctx, err.Error())
+		return
+	}
+
+	response.OkWithData(ctx, map[string]interface{}{ "url": redirect })
+}
+
+// recordLoginAttempt records a failed or locked login attempt into the operation log.
+func (h *Handler) recordLoginAttempt(ctx *context.Context, username, result string, wait time.Duration) {
+	var userId int64
+	if username != "" {
+		userId = models.User().SetConn(h.connection(ctx)).FindByUserName(username).Id
+	}
+	models.OperationLog().SetConn(h.connection(ctx)).New(userId, ctx.Path(), ctx.Method(), auth.ClientIP(ctx), utils.JSON(map[string]interface{}{
+		"username": username,
+		"result"  : result,
+		"wait"    : int64(wait / time.Second),
+	}))
+}
+
+// InitLoginThrottle creates the table of the failed login attempts.
+func (h *Handler) InitLoginThrottle() {
+	for _, conn := range h.connections() {
+		if err := models.LoginAttempt().SetConn(conn).Init(); err != nil {
+			logger.Error("init login throttle error: ", err)
+		}
+	}
 }
 
 // Logout delete the cookie.
 func (h *Handler) Logout(ctx *context.Context) {
-	err := auth.DelCookie(ctx, db.GetConnection(h.services))
+	err := auth.DelCookie(ctx, h.connection(ctx))
 	if err != nil {
 		logger.Error("user logout error:", err)
 	}
@@ -94,6 +143,7 @@ func (h *Handler) ShowLogin(ctx *context.Context) {
 	}
 
 	tmpl, name := template.GetComp("login").GetTemplate()
+	cfg := h.config.Snapshot()
 	var sb strings.Builder
 
 	err := tmpl.ExecuteTemplate(&sb, name, struct {
@@ -102,10 +152,10 @@ func (h *Handler) ShowLogin(ctx *context.Context) {
 		Logo      template.HTML
 		CdnUrl    string
 	}{
-		UrlPrefix: h.config.AssertPrefix(),
-		Title:     h.config.LoginTitle,
-		Logo:      h.config.LoginLogo,
-		CdnUrl:    h.config.AssetUrl,
+		UrlPrefix: cfg.AssertPrefix(),
+		Title:     cfg.LoginTitle,
+		Logo:      cfg.LoginLogo,
+		CdnUrl:    cfg.AssetUrl,
 	})
 
 	if err == nil {
@@ -9,12 +9,14 @@ import (
 	"github.com/GoAdminGroup/go-admin/modules/auth"
 	c "github.com/GoAdminGroup/go-admin/modules/config"
 	"github.com/GoAdminGroup/go-admin/modules/db"
+	"github.com/GoAdminGroup/go-admin/modules/tenant"
 	"github.com/GoAdminGroup/go-admin/modules/language"
 	"github.com/GoAdminGroup/go-admin/modules/menu"
 	"github.com/GoAdminGroup/go-admin/modules/service"
 	"github.com/GoAdminGroup/go-admin/modules/utils"
 	"github.com/GoAdminGroup/go-admin/plugins/admin/models"
 	"github.com/GoAdminGroup/go-admin/plugins/admin/modules/constant"
+	"github.com/GoAdminGroup/go-admin/plugins/admin/modules/guard"
 	"github.com/GoAdminGroup/go-admin/plugins/admin/modules/form"
 	"github.com/GoAdminGroup/go-admin/plugins/admin/modules/table"
 	"github.com/GoAdminGroup/go-admin/template"
@@ -66,6 +68,21 @@ func (h *Handler) UpdateCfg(cfg Config) {
 	h.generators = cfg.Generators
 }
 
+// connection return the connection of the tenant of the request.
+func (h *Handler) connection(ctx *context.Context) db.Connection {
+	return tenant.Conn(ctx, h.conn)
+}
+
+// connections return the connections of the default database and the databases of
+// the tenants.
+func (h *Handler) connections() []db.Connection {
+	list := []db.Connection{ h.conn }
+	for _, t := range tenant.All() {
+		list = append(list, t.Conn(h.conn))
+	}
+	return list
+}
+
 func (h *Handler) SetCaptcha(captcha map[string]string) {
 	h.captchaConfig = captcha
 }
@@ -74,8 +91,23 @@ func (h *Handler) SetRoutes(r context.RouterMap) {
 	h.routes = r
 }
 
-func (h *Handler) table(prefix string, ctx *context.Context) table.Table {
-	t := h.generators[prefix](ctx)
+// table return the table of the prefix, the ok is false and the 404 is responded
+// when the prefix is not found.
+func (h *Handler) table(prefix string, ctx *context.Context) (table.Table, bool) {
+	gen, ok := h.generators.Get(prefix)
+	if !ok {
+		guard.TableNotFound(ctx, h.conn, h.navButtons)
+		return nil, false
+	}
+	t := gen(ctx)
+	t.SetContext(ctx.Request.Context())
+	if tn, ok := tenant.FromContext(ctx); ok {
+		t.SetTenantConnection(tn.Connection)
+	}
+	if user, ok := ctx.User().(models.UserModel); ok {
+		t.SetOperator(user.Id)
+		t.SetUser(user)
+	}
 	authHandler := auth.Middleware(db.GetConnection(h.services))
 	for _, cb := range t.GetInfo().Callbacks {
 		if cb.Value[constant.ContextNodeNeedAuth] == 1 {
@@ -99,7 +131,7 @@ func (h *Handler) table(prefix string, ctx *context.Context) table.Table {
 			h.AddOperation(context.Node{ Path: cb.Path, Method: cb.Method, Handlers: cb.Handlers })
 		}
 	}
-	return t
+	return t, true
 }
 
 func (h *Handler) route(name string) context.Router {
@@ -190,8 +222,8 @@ func (h *Handler) ExecuteWithBtns(ctx *context.Context, user models.UserModel, p
 		TmplName:   tmplName,
 		Tmpl:       tmpl,
 		Panel:      panel,
-		Config:     h.config,
-		Menu:       menu.GetGlobalMenu(user, h.conn, ctx.Lang(), plugName).SetActiveClass(h.config.URLRemovePrefix(ctx.Path())),
+		Config:     tenant.Config(ctx, h.config),
+		Menu:       menu.GetGlobalMenu(user, h.connection(ctx), ctx.Lang(), plugName).SetActiveClass(h.config.URLRemovePrefix(ctx.Path())),
 		Animation:  option.Animation,
 		Buttons:    btns,
 		Iframe:     ctx.IsIframe(),
@@ -209,8 +241,8 @@ func (h *Handler) Execute(ctx *context.Context, user models.UserModel, panel typ
 		TmplName:   tmplName,
 		Tmpl:       tmpl,
 		Panel:      panel,
-		Config:     h.config,
-		Menu:       menu.GetGlobalMenu(user, h.conn, ctx.Lang(), plugName).SetActiveClass(h.config.URLRemovePrefix(ctx.Path())),
+		Config:     tenant.Config(ctx, h.config),
+		Menu:       menu.GetGlobalMenu(user, h.connection(ctx), ctx.Lang(), plugName).SetActiveClass(h.config.URLRemovePrefix(ctx.Path())),
 		Animation:  option.Animation,
 		Buttons:    (*h.navButtons).CheckPermission(user),
 		Iframe:     ctx.IsIframe(),
@@ -0,0 +1,35 @@
+package controller
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/GoAdminGroup/go-admin/context"
+	"github.com/GoAdminGroup/go-admin/modules/config"
+	"github.com/GoAdminGroup/go-admin/plugins/admin/modules/table"
+	"github.com/magiconair/properties/assert"
+)
+
+func TestTableNotFound(t *testing.T) {
+	initExportTest(&config.Config{})
+
+	h := New(Config{ Generators: table.GeneratorList{
+		"posts": func(ctx *context.Context) table.Table { return table.NewDefaultTable(table.DefaultConfig()) },
+	} })
+
+	ctx := context.NewContext(httptest.NewRequest("POST", "/admin/delete/missing", nil))
+	panel, ok := h.table("missing", ctx)
+	assert.Equal(t, ok, false)
+	assert.Equal(t, panel, nil)
+	assert.Equal(t, ctx.Response.StatusCode, http.StatusNotFound)
+
+	var res struct {
+		Code int `json:"code"`
+	}
+	body, _ := io.ReadAll(ctx.Response.Body)
+	assert.Equal(t, json.Unmarshal(body, &res), nil)
+	assert.Equal(t, res.Code, http.StatusNotFound)
+}
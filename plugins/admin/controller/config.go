@@ -0,0 +1,15 @@
+package controller
+
+import (
+	"github.com/GoAdminGroup/go-admin/modules/logger"
+	"github.com/GoAdminGroup/go-admin/plugins/admin/models"
+)
+
+// InitConfigRevisions creates the table of the revisions of the site config.
+func (h *Handler) InitConfigRevisions() {
+	for _, conn := range h.connections() {
+		if err := models.ConfigRevision().SetConn(conn).Init(); err != nil {
+			logger.Error("init config revisions error: ", err)
+		}
+	}
+}
@@ -19,7 +19,12 @@ func (h *Handler) Delete(ctx *context.Context) {
 	//	return
 	//}
 
-	if err := h.table(param.Prefix, ctx).DeleteData(param.Id); err != nil {
+	panel, ok := h.table(param.Prefix, ctx)
+	if !ok {
+		return
+	}
+
+	if err := panel.DeleteData(param.Id); err != nil {
 		logger.Error(err)
 		response.Error(ctx, "delete fail")
 		return
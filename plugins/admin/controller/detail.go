@@ -16,10 +16,14 @@ import (
 )
 
 func (h *Handler) ShowDetail(ctx *context.Context) {
+	prefix := ctx.Query(constant.PrefixKey)
+	panel, ok := h.table(prefix, ctx)
+	if !ok {
+		return
+	}
+
 	var (
-		prefix    = ctx.Query(constant.PrefixKey)
 		id        = ctx.Query(constant.DetailPKKey)
-		panel     = h.table(prefix, ctx)
 		user      = auth.Auth(ctx)
 		newPanel  = panel.Copy()
 		detail    = panel.GetDetail()
@@ -136,7 +140,7 @@ $('.delete-btn').on('click', function(event) { DeletePost(%s) });
 		if title == desc { desc = "" }
 	}
 
-	formInfo, err := newPanel.GetDataWithId(param.WithPKs(id))
+	formInfo, err := newPanel.GetDataWithId(param.WithPKs(id).WithContext(ctx.Request.Context()))
 
 	if err != nil {
 		h.HTML(ctx, user, template.WarningPanelWithDescAndTitle(err.Error(), desc, title),
@@ -144,16 +148,25 @@ $('.delete-btn').on('click', function(event) { DeletePost(%s) });
 		return
 	}
 
+	content := detailContent(aForm().
+		SetTitle(template.HTML(title)).
+		SetContent(formInfo.FieldList).
+		SetHeader(detail.HeaderHtml).
+		SetFooter(template.HTML(deleteJs)+detail.FooterHtml).
+		SetHiddenFields(map[string]string{
+			form2.PreviousKey: infoUrl,
+		}).
+		SetPrefix(h.config.PrefixFixSlash()), editUrl, deleteUrl, !isNotIframe)
+
+	if history := h.auditHistory(h.connection(ctx), panel, formModel.Table, id, fieldList); history != "" {
+		content = aTab().SetData([]map[string]template.HTML{
+			{ "title": template.HTML(language.Get("Detail")), "content": content },
+			{ "title": template.HTML(language.Get("History")), "content": history },
+		}).GetContent()
+	}
+
 	h.HTML(ctx, user, types.Panel{
-		Content: detailContent(aForm().
-			SetTitle(template.HTML(title)).
-			SetContent(formInfo.FieldList).
-			SetHeader(detail.HeaderHtml).
-			SetFooter(template.HTML(deleteJs)+detail.FooterHtml).
-			SetHiddenFields(map[string]string{
-				form2.PreviousKey: infoUrl,
-			}).
-			SetPrefix(h.config.PrefixFixSlash()), editUrl, deleteUrl, !isNotIframe),
+		Content:     content,
 		Description: template.HTML(desc),
 		Title:       template.HTML(title),
 	}, template.ExecuteOptions{Animation: param.Animation})
@@ -26,8 +26,12 @@ func (h *Handler) ShowForm(ctx *context.Context) {
 }
 
 func (h *Handler) showForm(ctx *context.Context, alert template.HTML, prefix string, param parameter.Parameters, isEdit bool, animation ...bool) {
-	panel := h.table(prefix, ctx)
-	f     := panel.GetForm()
+	panel, ok := h.table(prefix, ctx)
+	if !ok {
+		return
+	}
+
+	f := panel.GetForm()
 
 	if f.HasError() {
 		if f.PageErrorHTML != "" {
@@ -56,7 +60,7 @@ func (h *Handler) showForm(ctx *context.Context, alert template.HTML, prefix str
 		footerKind = "edit_only"
 	}
 
-	formInfo, err := panel.GetDataWithId(param)
+	formInfo, err := panel.GetDataWithId(param.WithContext(ctx.Request.Context()))
 
 	if err != nil {
 		logger.Error("receive data error: ", err)
@@ -86,6 +90,10 @@ func (h *Handler) showForm(ctx *context.Context, alert template.HTML, prefix str
 		form2.PreviousKey: infoUrl,
 	}
 
+	if f.VersionField != "" {
+		hiddenFields[form2.VersionKey] = formInfo.Version
+	}
+
 	if ctx.Query(constant.IframeKey) != "" {
 		hiddenFields[constant.IframeKey] = ctx.Query(constant.IframeKey)
 	}
@@ -139,7 +147,7 @@ func (h *Handler) EditForm(ctx *context.Context) {
 	pmf   := param.MultiForm.File
 
 	if len(pmf) > 0 {
-		err := file.GetFileEngine(h.config.FileUploadEngine.Name).Upload(param.MultiForm)
+		err := file.GetFileEngine(h.config.Snapshot().FileUploadEngine.Name).Upload(param.MultiForm)
 		if err != nil {
 			logger.Error("get file engine error: ", err)
 			if ctx.WantJSON() {
@@ -186,11 +194,11 @@ func (h *Handler) EditForm(ctx *context.Context) {
 	if err != nil {
 		logger.Error("update data error: ", err)
 		if ctx.WantJSON() {
-			response.Error(ctx, err.Error(), map[string]interface{}{
+			updateError(ctx, err, map[string]interface{}{
 				"token": h.authSrv().AddToken(),
 			})
 		} else {
-			h.showForm(ctx, aAlert().Warning(err.Error()), param.Prefix, param.Param, true)
+			h.showForm(ctx, updateErrorAlert(err), param.Prefix, param.Param, true)
 		}
 		return
 	}
@@ -0,0 +1,248 @@
+package controller
+
+import (
+	"bufio"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/360EntSecGroup-Skylar/excelize"
+	"github.com/GoAdminGroup/go-admin/context"
+	"github.com/GoAdminGroup/go-admin/modules/logger"
+	"github.com/GoAdminGroup/go-admin/modules/utils"
+	"github.com/GoAdminGroup/go-admin/plugins/admin/modules/guard"
+	"github.com/GoAdminGroup/go-admin/plugins/admin/modules/parameter"
+	"github.com/GoAdminGroup/go-admin/plugins/admin/modules/response"
+	"github.com/GoAdminGroup/go-admin/plugins/admin/modules/table"
+	"github.com/GoAdminGroup/go-admin/template/types"
+)
+
+// exportEachFn iterates over the exported rows.
+type exportEachFn func(theadFn table.TheadFn, rowFn table.InfoRowFn) error
+
+// exportRowWriter writes the exported rows of a format.
+type exportRowWriter interface {
+	WriteHead(thead types.Thead) error
+	WriteRow(thead types.Thead, values []string) error
+	Flush() error
+}
+
+// exportSource return the iteration over the exported rows of the export parameter
+// and the file name without the extension. The data processed by ExportProcessFn is
+// ready when returned, so that the error of it can still be responded.
+func exportSource(panel table.Table, param *guard.ExportParam, params parameter.Parameters) (exportEachFn, string, error) {
+	tableInfo := panel.GetInfo()
+	params     = params.WithIsAll(param.IsAll)
+
+	if fn := tableInfo.ExportProcessFn; fn != nil {
+		p, err := fn(params)
+		if err != nil { return nil, "", err }
+		return func(theadFn table.TheadFn, rowFn table.InfoRowFn) error {
+			if err := theadFn(p.Thead); err != nil { return err }
+			for _, row := range p.InfoList {
+				if err := rowFn(row); err != nil { return err }
+			}
+			return nil
+		}, fmt.Sprintf("%s-%d", tableInfo.Title, time.Now().Unix()), nil
+	}
+
+	fileName := ""
+	if len(param.Id) == 0 {
+		fileName = fmt.Sprintf("%s-%d-page-%s-pageSize-%s", tableInfo.Title, time.Now().Unix(), params.Page, params.PageSize)
+	} else {
+		params   = params.WithPKs(param.Id...)
+		fileName = fmt.Sprintf("%s-%d-id-%s", tableInfo.Title, time.Now().Unix(), strings.Join(param.Id, "_"))
+	}
+	return func(theadFn table.TheadFn, rowFn table.InfoRowFn) error {
+		return panel.EachData(params, theadFn, rowFn)
+	}, fileName, nil
+}
+
+// exportContentType return the content type of the export format.
+func exportContentType(format string) string {
+	switch format {
+	case guard.ExportFormatCSV:
+		return "text/csv; charset=utf-8"
+	case guard.ExportFormatNDJSON:
+		return "application/x-ndjson"
+	}
+	return "application/vnd.ms-excel"
+}
+
+// writeExport writes the rows of each into w in the given format, onRow is called
+// with the count of the written rows after each row if not nil.
+func writeExport(w io.Writer, format string, isExportValue bool, each exportEachFn, onRow func(count int64)) error {
+	var writer exportRowWriter
+	switch format {
+	case guard.ExportFormatNDJSON:
+		writer = newNDJSONRowWriter(w)
+	case guard.ExportFormatCSV:
+		writer = newCSVRowWriter(w)
+	default:
+		writer = newXlsxRowWriter(w)
+	}
+
+	var (
+		heads  types.Thead
+		values []string
+		count  int64
+	)
+
+	err := each(func(thead types.Thead) error {
+		heads = make(types.Thead, 0, len(thead))
+		for _, head := range thead {
+			if !head.Hide { heads = append(heads, head) }
+		}
+		values = make([]string, len(heads))
+		return writer.WriteHead(heads)
+	}, func(row map[string]types.InfoItem) error {
+		for i, head := range heads {
+			if isExportValue {
+				values[i] = row[head.Field].Value
+			} else {
+				values[i] = string(row[head.Field].Content)
+			}
+		}
+		if err := writer.WriteRow(heads, values); err != nil { return err }
+		count++
+		if onRow != nil { onRow(count) }
+		return nil
+	})
+	if err != nil { return err }
+	return writer.Flush()
+}
+
+// exportStream streams the rows of the table as csv or ndjson. The rows of the
+// database are written one by one from a cursor, so that exporting a large table
+// does not hold all the rows in the memory.
+func (h *Handler) exportStream(ctx *context.Context, panel table.Table, param *guard.ExportParam) {
+	tableInfo := panel.GetInfo()
+	params    := parameter.GetParam(ctx.Request.URL, tableInfo.DefaultPageSize, tableInfo.SortField,
+		tableInfo.GetSort()).WithContext(ctx.Request.Context())
+
+	each, fileName, err := exportSource(panel, param, params)
+	if err != nil {
+		response.Error(ctx, "export error")
+		return
+	}
+
+	isExportValue := tableInfo.IsExportValue()
+
+	ctx.AddHeader("content-disposition", utils.StrConcat(`attachment; filename=`, fileName, ".", param.Format))
+	ctx.DataStream(http.StatusOK, exportContentType(param.Format), func(w io.Writer) error {
+		err := writeExport(w, param.Format, isExportValue, each, nil)
+		if err != nil {
+			logger.Error("export stream error: ", err)
+		}
+		return err
+	})
+}
+
+type csvRowWriter struct {
+	w      *csv.Writer
+	record []string
+}
+
+func newCSVRowWriter(w io.Writer) *csvRowWriter {
+	// the utf-8 bom makes the spreadsheet applications recognize the encoding.
+	_, _ = w.Write([]byte("\xEF\xBB\xBF"))
+	return &csvRowWriter{ w: csv.NewWriter(w) }
+}
+
+func (c *csvRowWriter) WriteHead(thead types.Thead) error {
+	c.record = make([]string, len(thead))
+	for i, head := range thead {
+		c.record[i] = csvCell(head.Head)
+	}
+	return c.w.Write(c.record)
+}
+
+func (c *csvRowWriter) WriteRow(_ types.Thead, values []string) error {
+	for i, v := range values {
+		c.record[i] = csvCell(v)
+	}
+	return c.w.Write(c.record)
+}
+
+// csvCell escapes the cell which the spreadsheet applications take as a formula,
+// the quote keeps the value as a text.
+func csvCell(v string) string {
+	if v != "" && strings.ContainsRune("=+-@\t\r", rune(v[0])) {
+		return "'" + v
+	}
+	return v
+}
+
+func (c *csvRowWriter) Flush() error {
+	c.w.Flush()
+	return c.w.Error()
+}
+
+type ndjsonRowWriter struct {
+	w *bufio.Writer
+}
+
+func newNDJSONRowWriter(w io.Writer) *ndjsonRowWriter {
+	return &ndjsonRowWriter{ w: bufio.NewWriter(w) }
+}
+
+func (n *ndjsonRowWriter) WriteHead(_ types.Thead) error {
+	return nil
+}
+
+func (n *ndjsonRowWriter) WriteRow(thead types.Thead, values []string) error {
+	record := make(map[string]string, len(thead))
+	for i, head := range thead {
+		record[head.Field] = values[i]
+	}
+	line, err := utils.JsonMarshal(record)
+	if err != nil { return err }
+	if _, err := n.w.Write(line); err != nil { return err }
+	return n.w.WriteByte('\n')
+}
+
+func (n *ndjsonRowWriter) Flush() error {
+	return n.w.Flush()
+}
+
+// xlsxRowWriter builds the workbook in the memory and writes it when flushed,
+// it is used by the export jobs which run in the background.
+type xlsxRowWriter struct {
+	w     io.Writer
+	f     *excelize.File
+	sheet string
+	row   int
+}
+
+func newXlsxRowWriter(w io.Writer) *xlsxRowWriter {
+	const sheet = "Sheet1"
+	f := excelize.NewFile()
+	f.SetActiveSheet(f.NewSheet(sheet))
+	return &xlsxRowWriter{ w: w, f: f, sheet: sheet }
+}
+
+func (x *xlsxRowWriter) WriteHead(thead types.Thead) error {
+	record := make([]string, len(thead))
+	for i, head := range thead {
+		record[i] = head.Head
+	}
+	return x.WriteRow(thead, record)
+}
+
+func (x *xlsxRowWriter) WriteRow(_ types.Thead, values []string) error {
+	x.row++
+	record := make([]interface{}, len(values))
+	for i, v := range values {
+		record[i] = v
+	}
+	x.f.SetSheetRow(x.sheet, "A" + strconv.Itoa(x.row), &record)
+	return nil
+}
+
+func (x *xlsxRowWriter) Flush() error {
+	return x.f.Write(x.w)
+}
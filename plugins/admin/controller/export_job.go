@@ -0,0 +1,260 @@
+package controller
+
+import (
+	ctx2 "context"
+	"fmt"
+	"html/template"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/GoAdminGroup/go-admin/context"
+	"github.com/GoAdminGroup/go-admin/modules/auth"
+	"github.com/GoAdminGroup/go-admin/modules/config"
+	"github.com/GoAdminGroup/go-admin/modules/db"
+	"github.com/GoAdminGroup/go-admin/modules/errors"
+	"github.com/GoAdminGroup/go-admin/modules/language"
+	"github.com/GoAdminGroup/go-admin/modules/logger"
+	"github.com/GoAdminGroup/go-admin/modules/utils"
+	"github.com/GoAdminGroup/go-admin/plugins/admin/models"
+	"github.com/GoAdminGroup/go-admin/plugins/admin/modules"
+	"github.com/GoAdminGroup/go-admin/plugins/admin/modules/guard"
+	"github.com/GoAdminGroup/go-admin/plugins/admin/modules/parameter"
+	"github.com/GoAdminGroup/go-admin/plugins/admin/modules/response"
+	"github.com/GoAdminGroup/go-admin/plugins/admin/modules/table"
+	"github.com/GoAdminGroup/go-admin/template/icon"
+	"github.com/GoAdminGroup/go-admin/template/types/action"
+)
+
+var (
+	// ExportJobWorkers is the number of the workers generating the export files.
+	ExportJobWorkers = 2
+	// ExportJobQueueSize is the max number of the export jobs waiting for a worker.
+	ExportJobQueueSize = 128
+	// ExportJobExpiration is the duration the generated file can be downloaded.
+	ExportJobExpiration = 24 * time.Hour
+	// ExportJobTimeout is the max running duration of an export job.
+	ExportJobTimeout = time.Hour
+	// ExportJobRows is the number of the rows over which the export of all the
+	// rows runs as an export job, zero disables it.
+	ExportJobRows = 10000
+)
+
+// exportJobDir is the directory of the export files under the store path.
+const exportJobDir = "export"
+
+// exportProgressStep is the number of the rows between two progress updates.
+const exportProgressStep = 1000
+
+type exportJob struct {
+	model  models.ExportJobModel
+	panel  table.Table
+	param  *guard.ExportParam
+	params parameter.Parameters
+}
+
+var (
+	exportJobQueue chan *exportJob
+	exportJobOnce  sync.Once
+)
+
+// InitExportJobs creates the table of the export jobs and starts the workers and
+// the janitor removing the expired files.
+func (h *Handler) InitExportJobs() {
+	exportJobOnce.Do(func() {
+		conns := h.connections()
+		for _, conn := range conns {
+			if err := models.ExportJob().SetConn(conn).Init(); err != nil {
+				logger.Error("init export jobs error: ", err)
+				return
+			}
+		}
+		exportJobQueue = make(chan *exportJob, ExportJobQueueSize)
+		for i := 0; i < ExportJobWorkers; i++ {
+			go func() {
+				for job := range exportJobQueue {
+					runExportJob(job)
+				}
+			}()
+		}
+		go func() {
+			ticker := time.NewTicker(10 * time.Minute)
+			defer ticker.Stop()
+			for range ticker.C {
+				for _, conn := range conns {
+					removeExpiredExportJobs(conn)
+				}
+			}
+		}()
+	})
+}
+
+// enqueueExportJob adds an export job of the current user into the queue, the
+// guard.Export has checked the export permission of the table.
+func (h *Handler) enqueueExportJob(ctx *context.Context, panel table.Table, param *guard.ExportParam) {
+	if exportJobQueue == nil {
+		response.Error(ctx, "export job is not available")
+		return
+	}
+
+	user := auth.Auth(ctx)
+	job, err := models.ExportJob().SetConn(h.connection(ctx)).New(user.Id, param.Prefix, param.Format, ctx.Request.URL.String())
+	if err != nil {
+		logger.Error("create export job error: ", err)
+		response.Error(ctx, "export error")
+		return
+	}
+
+	tableInfo := panel.GetInfo()
+	params    := parameter.GetParam(ctx.Request.URL, tableInfo.DefaultPageSize, tableInfo.SortField, tableInfo.GetSort())
+
+	select {
+	case exportJobQueue <- &exportJob{ model: job, panel: panel, param: param, params: params }:
+	default:
+		job.Fail("too many export jobs")
+		response.Error(ctx, language.Get("too many export jobs"))
+		return
+	}
+
+	response.OkWithData(ctx, map[string]interface{}{
+		"id" : job.Id,
+		"url": h.routePathWithPrefix("info", "export_jobs"),
+	})
+}
+
+// isLargeExport reports whether the export of all the rows has more rows than
+// ExportJobRows, the rows are counted with the filters of the request.
+func isLargeExport(ctx *context.Context, panel table.Table, param *guard.ExportParam) bool {
+	tableInfo := panel.GetInfo()
+	if exportJobQueue == nil || ExportJobRows <= 0 || !param.IsAll || len(param.Id) > 0 || tableInfo.ExportProcessFn != nil {
+		return false
+	}
+	params := parameter.GetParam(ctx.Request.URL, tableInfo.DefaultPageSize, tableInfo.SortField, tableInfo.GetSort())
+	info, err := panel.GetData(params.WithIsAll(false).WithContext(ctx.Request.Context()))
+	return err == nil && info.Size > ExportJobRows
+}
+
+// addExportJobButton adds the button exporting all the rows of the current query
+// as an export job, the success jumps to the export jobs page.
+func (h *Handler) addExportJobButton(ctx *context.Context, prefix string, panel table.Table, params parameter.Parameters) {
+	info := panel.GetInfo()
+	if exportJobQueue == nil || !panel.GetExportable() || info.IsHideExportButton || params.IsTrashed() {
+		return
+	}
+
+	user := auth.Auth(ctx)
+	u    := user.GetCheckPermissionByUrlMethod(h.routePathWithPrefix("export", prefix) + params.DeleteIsAll().GetRouteParamStr(),
+		h.route("export").Method())
+	if u == "" {
+		return
+	}
+
+	info.AddButton(template.HTML(language.Get("export in background")), icon.CloudDownload,
+		action.Ajax("export_job_" + prefix, nil).
+			SetUrl(u).
+			AddData(map[string]interface{}{ "async": "true", "is_all": "true" }).
+			SetSuccessJS(`if (data.code === 200) {
+		$.pjax({ url: data.data.url, container: '#pjax-container' });
+	} else {
+		swal(data.msg, '', 'error');
+	}`))
+}
+
+func runExportJob(job *exportJob) {
+	model := job.model.Start()
+
+	var file *os.File
+	err := func() (err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				err = fmt.Errorf("%v", r)
+			}
+		}()
+
+		c, cancel := ctx2.WithTimeout(ctx2.Background(), ExportJobTimeout)
+		defer cancel()
+
+		each, _, err := exportSource(job.panel, job.param, job.params.WithContext(c))
+		if err != nil { return err }
+
+		dir := filepath.Join(config.GetStore().Path, exportJobDir)
+		if err := os.MkdirAll(dir, os.ModePerm); err != nil { return err }
+
+		file, err = os.Create(filepath.Join(dir, utils.StrConcat(modules.Uuid(), ".", job.param.Format)))
+		if err != nil { return err }
+
+		var processed int64
+		err = writeExport(file, job.param.Format, job.panel.GetInfo().IsExportValue(), each, func(count int64) {
+			processed = count
+			if count % exportProgressStep == 0 {
+				model = model.Progress(count)
+			}
+		})
+		if closeErr := file.Close(); err == nil { err = closeErr }
+		if err != nil { return err }
+
+		model = model.Finish(filepath.ToSlash(filepath.Join(exportJobDir, filepath.Base(file.Name()))),
+			processed, time.Now().Add(ExportJobExpiration))
+		return nil
+	}()
+
+	if err != nil {
+		logger.Error("export job error: ", err)
+		if file != nil { _ = os.Remove(file.Name()) }
+		model.Fail(err.Error())
+	}
+}
+
+func removeExpiredExportJobs(conn db.Connection) {
+	defer func() {
+		if r := recover(); r != nil {
+			logger.Error("remove expired export jobs error: ", r)
+		}
+	}()
+	for _, job := range models.ExportJob().SetConn(conn).GetExpired() {
+		if job.FilePath != "" {
+			if err := os.Remove(filepath.Join(config.GetStore().Path, job.FilePath)); err != nil && !os.IsNotExist(err) {
+				logger.Error("remove export file error: ", err)
+				continue
+			}
+		}
+		job.Expire()
+	}
+}
+
+// DownloadExportJob responds the file of the export job. Only the owner of the job,
+// who still has the permission of the export url, can download it.
+func (h *Handler) DownloadExportJob(ctx *context.Context) {
+	user := auth.Auth(ctx)
+	job  := models.ExportJob().SetConn(h.connection(ctx)).Find(ctx.Query("id"))
+
+	if job.IsEmpty() || job.UserId != user.Id {
+		response.BadRequest(ctx, "export job not found")
+		return
+	}
+	if !user.CheckPermissionByUrlMethod(job.Url, http.MethodPost, nil) {
+		response.Denied(ctx, errors.PermissionDenied)
+		return
+	}
+	if job.Status != models.ExportJobStatusDone || job.IsExpired() {
+		response.BadRequest(ctx, "export file is not available")
+		return
+	}
+
+	file, err := os.Open(filepath.Join(config.GetStore().Path, filepath.FromSlash(job.FilePath)))
+	if err != nil {
+		response.BadRequest(ctx, "export file is not available")
+		return
+	}
+
+	ctx.AddHeader("content-disposition", utils.StrConcat(`attachment; filename=`, job.Prefix, "-",
+		fmt.Sprintf("%d", job.Id), ".", job.Format))
+	ctx.DataStream(http.StatusOK, exportContentType(job.Format), func(w io.Writer) error {
+		defer func() { _ = file.Close() }()
+		_, err := io.Copy(w, file)
+		return err
+	})
+}
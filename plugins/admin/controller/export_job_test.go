@@ -0,0 +1,171 @@
+package controller
+
+import (
+	"encoding/json"
+	"io"
+	"net/http/httptest"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/GoAdminGroup/go-admin/context"
+	"github.com/GoAdminGroup/go-admin/modules/config"
+	"github.com/GoAdminGroup/go-admin/modules/db"
+	"github.com/GoAdminGroup/go-admin/modules/utils"
+	"github.com/GoAdminGroup/go-admin/plugins/admin/models"
+	"github.com/GoAdminGroup/go-admin/plugins/admin/modules/guard"
+	"github.com/GoAdminGroup/go-admin/plugins/admin/modules/parameter"
+	"github.com/GoAdminGroup/go-admin/plugins/admin/modules/table"
+	"github.com/GoAdminGroup/go-admin/template"
+	"github.com/GoAdminGroup/go-admin/template/components"
+	"github.com/GoAdminGroup/go-admin/template/types"
+	"github.com/magiconair/properties/assert"
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// exportTheme is the theme of the paginator of the panels of the tests.
+type exportTheme struct{ template.Template }
+
+func (exportTheme) Name() string { return "export_test" }
+
+func (exportTheme) Paginator() types.PaginatorAttribute { return components.Base{}.Paginator() }
+
+var exportThemeOnce sync.Once
+
+func initExportTest(cfg *config.Config) {
+	exportThemeOnce.Do(func() { template.Add("export_test", exportTheme{}) })
+	utils.InitUtils(100, func(s string) string { return s })
+	cfg.Language, cfg.Theme = "en", "export_test"
+	config.Initialize(cfg)
+}
+
+func TestExportJob(t *testing.T) {
+	initExportTest(&config.Config{ Store: config.Store{ Path: t.TempDir() }, AutoMigrate: true })
+
+	conn := db.GetSqliteDB().InitDB(map[string]config.Database{
+		"default": { Driver: db.DriverSqlite, File: filepath.Join(t.TempDir(), "admin.db") },
+	})
+	defer conn.Close()
+
+	_, err := conn.Exec(`CREATE TABLE goadmin_menu (id INTEGER PRIMARY KEY AUTOINCREMENT, parent_id INTEGER NOT NULL DEFAULT 0,
+		type INTEGER NOT NULL DEFAULT 0, "order" INTEGER NOT NULL DEFAULT 0, title TEXT NOT NULL, icon TEXT NOT NULL,
+		uri TEXT NOT NULL DEFAULT '', header TEXT, plugin_name TEXT NOT NULL DEFAULT '', uuid TEXT,
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP, updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP)`)
+	assert.Equal(t, err, nil)
+	_, err = conn.Exec(`INSERT INTO goadmin_menu (id, parent_id, type, "order", title, icon, uri) VALUES (2, 0, 1, 2, 'Admin', 'fa-tasks', '')`)
+	assert.Equal(t, err, nil)
+
+	h := New(Config{ Connection: conn })
+	h.SetRoutes(context.RouterMap{ "info": { Methods: []string{ "GET" }, Patten: "/admin/info/:__prefix" } })
+	h.InitExportJobs()
+
+	// the menu item of the export jobs is added under the admin menu once.
+	menus, err := conn.Query(`SELECT parent_id FROM goadmin_menu WHERE uri = '/info/export_jobs'`)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, len(menus), 1)
+	assert.Equal(t, menus[0]["parent_id"], int64(2))
+
+	var (
+		release = make(chan struct{})
+		rows    = 2500
+		panel   = table.NewDefaultTable(table.DefaultConfig())
+	)
+	panel.GetInfo().AddField("ID", "id", db.Int)
+	panel.GetInfo().SetGetDataFn(func(param parameter.Parameters) ([]map[string]interface{}, int) {
+		<-release
+		data := make([]map[string]interface{}, rows)
+		for i := range data {
+			data[i] = map[string]interface{}{ "id": i + 1 }
+		}
+		return data, rows
+	})
+
+	owner := models.UserModel{ Id: 1, Permissions: []models.PermissionModel{ { HttpMethod: []string{ "" }, HttpPath: []string{ "*" } } } }
+	newCtx := func(url string, user models.UserModel) *context.Context {
+		ctx := context.NewContext(httptest.NewRequest("POST", url, nil))
+		ctx.SetUserValue("user", user)
+		return ctx
+	}
+
+	ctx := newCtx("/admin/export/users?is_all=true&async=true", owner)
+	h.enqueueExportJob(ctx, panel, &guard.ExportParam{ Prefix: "users", IsAll: true, Format: guard.ExportFormatCSV, Async: true })
+
+	var res struct {
+		Code int `json:"code"`
+		Data struct {
+			Id  int64  `json:"id"`
+			Url string `json:"url"`
+		} `json:"data"`
+	}
+	body, _ := io.ReadAll(ctx.Response.Body)
+	assert.Equal(t, json.Unmarshal(body, &res), nil)
+	assert.Equal(t, res.Code, 200)
+	assert.Equal(t, res.Data.Url, "/admin/info/export_jobs")
+
+	find := func() models.ExportJobModel {
+		return models.ExportJob().SetConn(conn).Find(res.Data.Id)
+	}
+	wait := func(status string) models.ExportJobModel {
+		for i := 0; i < 500; i++ {
+			if job := find(); job.Status == status {
+				return job
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+		t.Fatalf("export job is not %s: %s", status, find().Status)
+		return models.ExportJobModel{}
+	}
+
+	wait(models.ExportJobStatusRunning)
+	close(release)
+	job := wait(models.ExportJobStatusDone)
+	assert.Equal(t, job.Processed, int64(rows))
+	assert.Equal(t, job.ExpiredAt > time.Now().Unix(), true)
+
+	// only the owner downloads the file.
+	ctx = newCtx("/admin/export_jobs/download?id=" + strconv.FormatInt(job.Id, 10), models.UserModel{ Id: 2, Permissions: owner.Permissions })
+	h.DownloadExportJob(ctx)
+	assert.Equal(t, ctx.Response.StatusCode != 200, true)
+
+	ctx = newCtx("/admin/export_jobs/download?id=" + strconv.FormatInt(job.Id, 10), owner)
+	h.DownloadExportJob(ctx)
+	assert.Equal(t, ctx.Response.StatusCode, 200)
+	body, _ = io.ReadAll(ctx.Response.Body)
+	lines := strings.Split(strings.TrimSpace(strings.TrimPrefix(string(body), "\xEF\xBB\xBF")), "\n")
+	assert.Equal(t, len(lines), rows + 1)
+	assert.Equal(t, strings.TrimSpace(lines[1]), "1")
+}
+
+func TestIsLargeExport(t *testing.T) {
+	initExportTest(&config.Config{})
+
+	defer func(max int) { ExportJobRows = max }(ExportJobRows)
+	ExportJobRows = 10
+
+	if exportJobQueue == nil {
+		exportJobQueue = make(chan *exportJob)
+		defer func() { exportJobQueue = nil }()
+	}
+
+	size  := 0
+	panel := table.NewDefaultTable(table.DefaultConfig())
+	panel.GetInfo().AddField("ID", "id", db.Int)
+	panel.GetInfo().SetGetDataFn(func(param parameter.Parameters) ([]map[string]interface{}, int) {
+		return nil, size
+	})
+
+	ctx := context.NewContext(httptest.NewRequest("POST", "/admin/export/users?is_all=true", nil))
+	all := &guard.ExportParam{ Prefix: "users", IsAll: true, Format: guard.ExportFormatCSV }
+
+	size = 10
+	assert.Equal(t, isLargeExport(ctx, panel, all), false)
+	size = 11
+	assert.Equal(t, isLargeExport(ctx, panel, all), true)
+
+	// the exports of the current page or the selected rows stay synchronous.
+	assert.Equal(t, isLargeExport(ctx, panel, &guard.ExportParam{ Prefix: "users", Format: guard.ExportFormatCSV }), false)
+	assert.Equal(t, isLargeExport(ctx, panel, &guard.ExportParam{ Prefix: "users", IsAll: true, Id: []string{ "1" } }), false)
+}
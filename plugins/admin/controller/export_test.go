@@ -0,0 +1,78 @@
+package controller
+
+import (
+	"encoding/json"
+	"io"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/GoAdminGroup/go-admin/context"
+	"github.com/GoAdminGroup/go-admin/modules/config"
+	"github.com/GoAdminGroup/go-admin/modules/db"
+	"github.com/GoAdminGroup/go-admin/modules/service"
+	"github.com/GoAdminGroup/go-admin/plugins/admin/modules/guard"
+	"github.com/GoAdminGroup/go-admin/plugins/admin/modules/table"
+	"github.com/magiconair/properties/assert"
+)
+
+func TestCSVCell(t *testing.T) {
+	for v, want := range map[string]string{
+		"=1+1"      : "'=1+1",
+		"+1"        : "'+1",
+		"-1"        : "'-1",
+		"@SUM(A1)"  : "'@SUM(A1)",
+		"\tcmd"     : "'\tcmd",
+		"\rcmd"     : "'\rcmd",
+		"a=1"       : "a=1",
+		""          : "",
+	} {
+		assert.Equal(t, csvCell(v), want)
+	}
+}
+
+func TestExportStream(t *testing.T) {
+	initExportTest(&config.Config{})
+
+	conn := db.GetSqliteDB().InitDB(map[string]config.Database{
+		"default": { Driver: db.DriverSqlite, File: filepath.Join(t.TempDir(), "admin.db") },
+	})
+	defer conn.Close()
+	table.SetServices(service.List{ db.DriverSqlite: conn })
+
+	_, err := conn.Exec(`CREATE TABLE posts (id INTEGER PRIMARY KEY AUTOINCREMENT, title TEXT, author TEXT, deleted_at TIMESTAMP)`)
+	assert.Equal(t, err, nil)
+	_, err = conn.Exec(`INSERT INTO posts (id, title, author, deleted_at) VALUES
+		(1, 'hello', 'sam', NULL), (2, '=HYPERLINK("http://example.com")', 'sam', NULL),
+		(3, 'bye', 'tom', NULL), (4, 'gone', 'sam', '2024-01-01 00:00:00')`)
+	assert.Equal(t, err, nil)
+
+	panel := table.NewDefaultTable(table.DefaultConfigWithDriver(db.DriverSqlite).SetSoftDelete())
+	info  := panel.GetInfo().SetTable("posts")
+	info.AddField("ID", "id", db.Int)
+	info.AddField("Title", "title", db.Varchar)
+	info.AddField("Author", "author", db.Varchar).FieldFilterable()
+	panel.GetForm().SetTable("posts")
+
+	export := func(format, url string) string {
+		ctx := context.NewContext(httptest.NewRequest("POST", url, nil))
+		New().exportStream(ctx, panel, &guard.ExportParam{ Prefix: "posts", IsAll: true, Format: format })
+		body, err := io.ReadAll(ctx.Response.Body)
+		assert.Equal(t, err, nil)
+		return string(body)
+	}
+
+	// the filter of the author is applied, and the soft deleted row is left out.
+	assert.Equal(t, export(guard.ExportFormatCSV, "/admin/export/posts?author=sam&__sort=id&__sort_type=asc"),
+		"\xEF\xBB\xBFID,Title,Author\n1,hello,sam\n2,\"'=HYPERLINK(\"\"http://example.com\"\")\",sam\n")
+
+	lines := strings.Split(strings.TrimSpace(export(guard.ExportFormatNDJSON, "/admin/export/posts?author=tom")), "\n")
+	assert.Equal(t, len(lines), 1)
+	var row map[string]string
+	assert.Equal(t, json.Unmarshal([]byte(lines[0]), &row), nil)
+	assert.Equal(t, row, map[string]string{ "id": "3", "title": "bye", "author": "tom" })
+
+	lines = strings.Split(strings.TrimSpace(export(guard.ExportFormatNDJSON, "/admin/export/posts?__sort=id&__sort_type=asc")), "\n")
+	assert.Equal(t, len(lines), 3)
+}
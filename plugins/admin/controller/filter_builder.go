@@ -0,0 +1,44 @@
+package controller
+
+import (
+	"html/template"
+
+	"github.com/GoAdminGroup/go-admin/modules/language"
+	"github.com/GoAdminGroup/go-admin/plugins/admin/modules/parameter"
+	"github.com/GoAdminGroup/go-admin/plugins/admin/modules/table"
+	"github.com/GoAdminGroup/go-admin/template/icon"
+	"github.com/GoAdminGroup/go-admin/template/types"
+	"github.com/GoAdminGroup/go-admin/template/types/action"
+)
+
+// addFilterBuilderButton adds the button of the filter builder of the filterable fields of the
+// info panel when it is enabled.
+func (h *Handler) addFilterBuilderButton(prefix string, panel table.Table, params parameter.Parameters) {
+	info := panel.GetInfo()
+	if !info.FilterBuilder {
+		return
+	}
+
+	fields := make(types.FieldOptions, 0, len(info.FieldList))
+	for _, field := range info.FieldList {
+		if field.Field == "" || !field.Filterable {
+			continue
+		}
+		value := field.Field
+		if field.Joins.Valid() {
+			value = types.JoinField(field.Joins.Last().GetTableName(), field.Field)
+		}
+		fields = append(fields, types.FieldOption{ Value: value, Text: field.Head })
+	}
+
+	// the builder jumps to the first page of the list with the other parameters.
+	p := params
+	p.Filter = nil
+	u := h.routePathWithPrefix("info", prefix) + "?" + p.GetFixedParamStr().Encode()
+
+	title := language.Get("filter builder")
+	if params.Filter != nil {
+		title += " *"
+	}
+	info.AddButton(template.HTML(title), icon.Filter, action.FilterBuilder(u, parameter.Filter, fields, params.Filter))
+}
@@ -23,7 +23,9 @@ import (
 func (h *Handler) GlobalDeferHandler(ctx *context.Context) {
 	logger.Access(ctx)
 
-	if !h.config.OperationLogOff {
+	cfg := h.config.Snapshot()
+
+	if !cfg.OperationLogOff {
 		h.RecordOperationLog(ctx)
 	}
 
@@ -37,7 +39,7 @@ func (h *Handler) GlobalDeferHandler(ctx *context.Context) {
 			return
 		}
 
-		ctxPath := utils.UrlWithoutQuery(ctx.Path()[len(h.config.UrlPrefix):])
+		ctxPath := utils.UrlWithoutQuery(ctx.Path()[len(cfg.UrlPrefix):])
 		for _, action := range [...]string{ "/edit", "/new" } {
 			if strings.HasPrefix(ctxPath, action) || strings.HasSuffix(ctxPath, action) {
 				h.setFormWithReturnErrMessage(ctx, errMsg, action[1:])
@@ -50,12 +52,16 @@ func (h *Handler) GlobalDeferHandler(ctx *context.Context) {
 }
 
 func (h *Handler) setFormWithReturnErrMessage(ctx *context.Context, errMsg, kind string) {
+	prefix := ctx.Query(constant.PrefixKey)
+	panel, ok := h.table(prefix, ctx)
+	if !ok {
+		return
+	}
+
 	var (
 		formInfo table.FormInfo
 		f        *types.FormPanel
 		btnWord  template2.HTML
-		prefix   = ctx.Query(constant.PrefixKey)
-		panel    = h.table(prefix, ctx)
 		info     = panel.GetInfo()
 	)
 
@@ -68,7 +74,7 @@ func (h *Handler) setFormWithReturnErrMessage(ctx *context.Context, errMsg, kind
 			fallthrough
 		default:
 			f = panel.GetForm()
-			formInfo, _ = panel.GetDataWithId(parameter.GetParam(ctx.Request.URL, info.DefaultPageSize, info.SortField, info.GetSort()).WithPKs(id))
+			formInfo, _ = panel.GetDataWithId(parameter.GetParam(ctx.Request.URL, info.DefaultPageSize, info.SortField, info.GetSort()).WithPKs(id).WithContext(ctx.Request.Context()))
 			btnWord = f.FormEditBtnWord
 		}
 	}
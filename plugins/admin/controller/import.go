@@ -0,0 +1,383 @@
+package controller
+
+import (
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/xml"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/360EntSecGroup-Skylar/excelize"
+	"github.com/GoAdminGroup/go-admin/context"
+	"github.com/GoAdminGroup/go-admin/modules/auth"
+	"github.com/GoAdminGroup/go-admin/modules/config"
+	"github.com/GoAdminGroup/go-admin/modules/language"
+	"github.com/GoAdminGroup/go-admin/modules/logger"
+	"github.com/GoAdminGroup/go-admin/plugins/admin/modules"
+	form2 "github.com/GoAdminGroup/go-admin/plugins/admin/modules/form"
+	"github.com/GoAdminGroup/go-admin/plugins/admin/modules/guard"
+	"github.com/GoAdminGroup/go-admin/plugins/admin/modules/response"
+	"github.com/GoAdminGroup/go-admin/plugins/admin/modules/table"
+	"github.com/GoAdminGroup/go-admin/template"
+	"github.com/GoAdminGroup/go-admin/template/types"
+)
+
+var (
+	// ImportMaxRows is the max number of the rows of an imported file.
+	ImportMaxRows = 10000
+	// ImportMaxFileSize is the max size in bytes of an uploaded file.
+	ImportMaxFileSize int64 = 10 << 20
+	// ImportMaxUnzipSize is the max uncompressed size in bytes of the entries of
+	// an xlsx file, for each of them and for all of them.
+	ImportMaxUnzipSize uint64 = 100 << 20
+	// ImportFileExpiration is the duration an uploaded file is kept for the mapping.
+	ImportFileExpiration = time.Hour
+)
+
+// importDir is the directory of the uploaded files under the store path.
+const importDir = "import"
+
+// importPreviewRows is the number of the rows shown in the mapping step.
+const importPreviewRows = 3
+
+// ShowImport show the page uploading the file to import.
+func (h *Handler) ShowImport(ctx *context.Context) {
+	param := guard.GetImportParam(ctx)
+	h.showImport(ctx, param.Panel, param.Prefix, importPageData{}, "")
+}
+
+// Import processes the steps of importing a file into the table:
+// the upload step stores the file and shows the mapping of the columns to the
+// form fields, the dry run step validates the rows and the run step inserts them.
+func (h *Handler) Import(ctx *context.Context) {
+	var (
+		param = guard.GetImportParam(ctx)
+		data  importPageData
+		err   error
+	)
+
+	if param.Step == guard.ImportStepUpload {
+		data.File, err = h.saveImportFile(ctx)
+	} else {
+		data.File = ctx.FormValue("__goadmin_import_file")
+	}
+	if err != nil {
+		h.importError(ctx, param, data, err)
+		return
+	}
+
+	header, records, err := readImportFile(data.File)
+	if err != nil {
+		h.importError(ctx, param, data, err)
+		return
+	}
+
+	f := param.Panel.GetActualNewForm()
+	for _, field := range f.FieldList {
+		if field.NotAllowAdd { continue }
+		data.Fields = append(data.Fields, importField{ Field: field.Field, Head: field.Head })
+	}
+
+	for i, name := range header {
+		col := importColumn{ Index: i, Name: name }
+		if param.Step == guard.ImportStepUpload {
+			col.Field = matchImportField(name, data.Fields)
+		} else {
+			col.Field = ctx.FormValue("__goadmin_import_map_" + strconv.Itoa(i))
+		}
+		data.Columns = append(data.Columns, col)
+	}
+
+	if param.Step == guard.ImportStepUpload {
+		data.Preview = records
+		if len(data.Preview) > importPreviewRows {
+			data.Preview = data.Preview[:importPreviewRows]
+		}
+		if ctx.WantJSON() {
+			response.OkWithData(ctx, map[string]interface{}{
+				"file"   : data.File,
+				"columns": data.Columns,
+				"fields" : data.Fields,
+				"token"  : h.authSrv().AddToken(),
+			})
+			return
+		}
+		h.showImport(ctx, param.Panel, param.Prefix, data, "")
+		return
+	}
+
+	rows   := importValues(f, data.Columns, records)
+	result := param.Panel.ImportData(rows, param.Step == guard.ImportStepDryRun)
+	data.Result = &result
+
+	if !result.DryRun {
+		removeImportFile(data.File)
+	}
+
+	if ctx.WantJSON() {
+		response.OkWithData(ctx, map[string]interface{}{
+			"result": result,
+			"token" : h.authSrv().AddToken(),
+		})
+		return
+	}
+
+	data.Preview = records
+	if len(data.Preview) > importPreviewRows {
+		data.Preview = data.Preview[:importPreviewRows]
+	}
+	h.showImport(ctx, param.Panel, param.Prefix, data, "")
+}
+
+func (h *Handler) importError(ctx *context.Context, param *guard.ImportParam, data importPageData, err error) {
+	logger.Error("import error: ", err)
+	if ctx.WantJSON() {
+		response.Error(ctx, err.Error(), map[string]interface{}{
+			"token": h.authSrv().AddToken(),
+		})
+		return
+	}
+	h.showImport(ctx, param.Panel, param.Prefix, importPageData{}, aAlert().Warning(err.Error()))
+}
+
+func (h *Handler) showImport(ctx *context.Context, panel table.Table, prefix string, data importPageData, alert template.HTML) {
+	f := panel.GetActualNewForm()
+
+	data.Url      = h.routePathWithPrefix("import", prefix)
+	data.InfoUrl  = h.routePathWithPrefix("info", prefix)
+	data.Token    = h.authSrv().AddToken()
+	data.TokenKey = form2.TokenKey
+	data.MaxRows  = ImportMaxRows
+
+	h.HTML(ctx, auth.Auth(ctx), types.Panel{
+		Content: alert + aBox().
+			SetHeader(template.HTML(language.Get("import"))).
+			WithHeadBorder().
+			SetBody(getImportPageContent(data)).
+			GetContent(),
+		Description: template.HTML(f.Description),
+		Title:       template.HTML(f.Title),
+	}, template.ExecuteOptions{ Animation: alert == "" })
+}
+
+// saveImportFile stores the uploaded file and return the name of the stored file.
+func (h *Handler) saveImportFile(ctx *context.Context) (string, error) {
+	upload, header, err := ctx.Request.FormFile("file")
+	if err != nil { return "", errors.New("file is required") }
+	defer func() { _ = upload.Close() }()
+
+	ext := strings.ToLower(filepath.Ext(header.Filename))
+	if ext != ".csv" && ext != ".xlsx" {
+		return "", errors.New("wrong file type, only csv and xlsx are supported")
+	}
+	if header.Size > ImportMaxFileSize { return "", errImportFileTooLarge() }
+
+	dir := filepath.Join(config.GetStore().Path, importDir)
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil { return "", err }
+
+	removeExpiredImportFiles(dir)
+
+	name := modules.Uuid() + ext
+	file, err := os.Create(filepath.Join(dir, name))
+	if err != nil { return "", err }
+
+	// the size of the header comes from the client.
+	n, err := io.Copy(file, io.LimitReader(upload, ImportMaxFileSize + 1))
+	if err == nil && n > ImportMaxFileSize { err = errImportFileTooLarge() }
+	if closeErr := file.Close(); err == nil { err = closeErr }
+	if err != nil {
+		_ = os.Remove(file.Name())
+		return "", err
+	}
+
+	return name, nil
+}
+
+// importFilePath return the path of the stored file, the name comes from the
+// form so that it must be a plain file name.
+func importFilePath(name string) (string, error) {
+	ext := filepath.Ext(name)
+	if name == "" || filepath.Base(name) != name || (ext != ".csv" && ext != ".xlsx") {
+		return "", errors.New("wrong import file")
+	}
+	return filepath.Join(config.GetStore().Path, importDir, name), nil
+}
+
+func removeImportFile(name string) {
+	if p, err := importFilePath(name); err == nil {
+		_ = os.Remove(p)
+	}
+}
+
+func removeExpiredImportFiles(dir string) {
+	entries, err := os.ReadDir(dir)
+	if err != nil { return }
+	for _, entry := range entries {
+		info, err := entry.Info()
+		if err != nil || entry.IsDir() { continue }
+		if time.Since(info.ModTime()) > ImportFileExpiration {
+			_ = os.Remove(filepath.Join(dir, entry.Name()))
+		}
+	}
+}
+
+// readImportFile return the header and the records of the stored file.
+func readImportFile(name string) ([]string, [][]string, error) {
+	p, err := importFilePath(name)
+	if err != nil { return nil, nil, err }
+
+	file, err := os.Open(p)
+	if err != nil { return nil, nil, errors.New("import file not found, please upload again") }
+	defer func() { _ = file.Close() }()
+
+	var rows [][]string
+	if filepath.Ext(name) == ".xlsx" {
+		var info os.FileInfo
+		if info, err = file.Stat(); err == nil {
+			rows, err = readImportXlsx(file, info.Size())
+		}
+	} else {
+		rows, err = readImportCsv(file)
+	}
+	if err != nil { return nil, nil, err }
+
+	if len(rows) == 0 {
+		return nil, nil, errors.New("import file is empty")
+	}
+
+	header := make([]string, len(rows[0]))
+	for i, name := range rows[0] {
+		header[i] = strings.TrimSpace(name)
+	}
+
+	return header, rows[1:], nil
+}
+
+func errTooManyImportRows() error {
+	return errors.New("too many rows, the max is " + strconv.Itoa(ImportMaxRows))
+}
+
+func errImportFileTooLarge() error {
+	return errors.New("file is too large, the max is " + strconv.FormatInt(ImportMaxFileSize >> 20, 10) + "MB")
+}
+
+// readImportCsv return the rows of the csv, the reading stops once the rows
+// are more than ImportMaxRows.
+func readImportCsv(file io.Reader) ([][]string, error) {
+	reader := bufio.NewReader(file)
+	// skip the utf-8 bom written by the csv export and the excel.
+	if bom, err := reader.Peek(3); err == nil && bytes.Equal(bom, []byte("\xEF\xBB\xBF")) {
+		_, _ = reader.Discard(3)
+	}
+	r := csv.NewReader(reader)
+	r.FieldsPerRecord = -1
+	r.LazyQuotes      = true
+
+	var rows [][]string
+	for {
+		record, err := r.Read()
+		if err == io.EOF { return rows, nil }
+		if err != nil { return nil, err }
+		// the first row is the header.
+		if len(rows) > ImportMaxRows { return nil, errTooManyImportRows() }
+		rows = append(rows, record)
+	}
+}
+
+// readImportXlsx return the rows of the first sheet. The sizes of the entries
+// are checked before the file is unzipped, and the rows of the sheet are counted
+// before they are read, since the reading allocates all the rows up to the last
+// one at once.
+func readImportXlsx(file io.ReaderAt, size int64) ([][]string, error) {
+	zr, err := zip.NewReader(file, size)
+	if err != nil { return nil, err }
+
+	// the zip reader fails the entries longer than their size in the header.
+	var total uint64
+	for _, entry := range zr.File {
+		total += entry.UncompressedSize64
+		if entry.UncompressedSize64 > ImportMaxUnzipSize || total > ImportMaxUnzipSize {
+			return nil, errors.New("wrong xlsx file, the uncompressed file is too large")
+		}
+	}
+
+	f, err := excelize.OpenReader(io.NewSectionReader(file, 0, size))
+	if err != nil { return nil, err }
+
+	// the first sheet of the workbook, the rows are counted and read from the
+	// same worksheet file.
+	sheets := f.GetSheetMap()
+	if f.WorkBook == nil || len(f.WorkBook.Sheets.Sheet) == 0 {
+		return nil, errors.New("wrong xlsx file, no sheet found")
+	}
+	var (
+		sheet = f.WorkBook.Sheets.Sheet[0].Name
+		path  string
+	)
+	for index, name := range sheets {
+		if name == sheet { path = "xl/worksheets/sheet" + strconv.Itoa(index) + ".xml" }
+	}
+	content, ok := f.XLSX[path]
+	if !ok { return nil, errors.New("wrong xlsx file, no sheet found") }
+
+	var (
+		decoder = xml.NewDecoder(bytes.NewReader(content))
+		row     int
+	)
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF { break }
+		if err != nil { return nil, err }
+		el, ok := token.(xml.StartElement)
+		if !ok || el.Name.Local != "row" { continue }
+		// the number of the row is the r attribute, rows can be skipped.
+		row++
+		for _, attr := range el.Attr {
+			if attr.Name.Local == "r" {
+				if n, err := strconv.Atoi(attr.Value); err == nil { row = n }
+			}
+		}
+		if row - 1 > ImportMaxRows { return nil, errTooManyImportRows() }
+	}
+
+	return f.GetRows(sheet), nil
+}
+
+// matchImportField return the field whose name or head equals to the column name.
+func matchImportField(name string, fields []importField) string {
+	for _, field := range fields {
+		if strings.EqualFold(name, field.Field) || strings.EqualFold(name, field.Head) {
+			return field.Field
+		}
+	}
+	return ""
+}
+
+// importValues converts the records to the form values by the mapping of the columns.
+func importValues(f *types.FormPanel, columns []importColumn, records [][]string) []form2.Values {
+	rows := make([]form2.Values, len(records))
+	for i, record := range records {
+		values := make(form2.Values)
+		for _, col := range columns {
+			if col.Field == "" || col.Index >= len(record) { continue }
+			field := f.FieldList.FindByFieldName(col.Field)
+			if field == nil || field.NotAllowAdd { continue }
+			value := strings.TrimSpace(record[col.Index])
+			if field.FormType.IsMultiSelect() {
+				delim := modules.SetDefault(field.DefaultOptionDelimiter, ",")
+				values[col.Field + "[]"] = strings.Split(value, delim)
+			} else {
+				values[col.Field] = []string{ value }
+			}
+		}
+		rows[i] = values
+	}
+	return rows
+}
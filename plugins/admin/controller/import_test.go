@@ -0,0 +1,61 @@
+package controller
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/360EntSecGroup-Skylar/excelize"
+	"github.com/magiconair/properties/assert"
+)
+
+func TestReadImportRows(t *testing.T) {
+	defer func(max int) { ImportMaxRows = max }(ImportMaxRows)
+	ImportMaxRows = 2
+
+	rows, err := readImportCsv(strings.NewReader("\xEF\xBB\xBFname,age\nfoo,1\nbar,2\n"))
+	assert.Equal(t, err, nil)
+	assert.Equal(t, rows, [][]string{ { "name", "age" }, { "foo", "1" }, { "bar", "2" } })
+
+	_, err = readImportCsv(strings.NewReader("name\nfoo\nbar\nbaz\n\"broken"))
+	assert.Equal(t, err, errTooManyImportRows())
+
+	xlsx := func(axis ...string) *excelize.File {
+		f := excelize.NewFile()
+		for _, a := range axis {
+			f.SetCellStr("Sheet1", a, a)
+		}
+		return f
+	}
+
+	read := func(f *excelize.File) ([][]string, error) {
+		buf, _ := f.WriteToBuffer()
+		return readImportXlsx(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	}
+
+	rows, err = read(xlsx("A1", "A3"))
+	assert.Equal(t, err, nil)
+	assert.Equal(t, len(rows), 3)
+	assert.Equal(t, rows[2][0], "A3")
+
+	// the last row counts rather than the number of the rows.
+	_, err = read(xlsx("A1", "A1000000"))
+	assert.Equal(t, err, errTooManyImportRows())
+
+	// the first sheet of the workbook is not always the sheet1.xml, the rows
+	// are counted and read from the same sheet.
+	f := xlsx("A1", "A1000000")
+	f.NewSheet("First")
+	f.SetCellStr("First", "A1", "first")
+	f.GetSheetMap()
+	f.WorkBook.Sheets.Sheet[0], f.WorkBook.Sheets.Sheet[1] = f.WorkBook.Sheets.Sheet[1], f.WorkBook.Sheets.Sheet[0]
+	rows, err = read(f)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, rows, [][]string{ { "first" } })
+
+	// the entries are not unzipped over the size.
+	defer func(max uint64) { ImportMaxUnzipSize = max }(ImportMaxUnzipSize)
+	ImportMaxUnzipSize = 1024
+	_, err = read(xlsx("A1"))
+	assert.Equal(t, err != nil, true)
+}
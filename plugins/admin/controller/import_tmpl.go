@@ -0,0 +1,121 @@
+package controller
+
+import (
+	"html/template"
+	"strings"
+
+	"github.com/GoAdminGroup/go-admin/modules/language"
+	"github.com/GoAdminGroup/go-admin/modules/logger"
+	"github.com/GoAdminGroup/go-admin/plugins/admin/modules/table"
+)
+
+func getImportPageContent(data importPageData) template.HTML {
+	t := template.New("import_page").Funcs(map[string]interface{}{
+		"lang": language.Get,
+		"add" : func(a, b int) int { return a + b },
+	})
+	t, err := t.Parse(importPageTmpl)
+	if err != nil {
+		logger.Error(err)
+		return ""
+	}
+	var sb strings.Builder
+	err = t.Execute(&sb, data)
+	if err != nil {
+		logger.Error(err)
+		return ""
+	}
+	return template.HTML(sb.String())
+}
+
+type importPageData struct {
+	Url      string
+	InfoUrl  string
+	Token    string
+	TokenKey string
+	File     string
+	Columns  []importColumn
+	Fields   []importField
+	Preview  [][]string
+	Result   *table.ImportResult
+	MaxRows  int
+}
+
+type importColumn struct {
+	Index int
+	Name  string
+	Field string
+}
+
+type importField struct {
+	Field string
+	Head  string
+}
+
+func (d importPageData) Valid() int {
+	if d.Result == nil { return 0 }
+	return d.Result.Total - len(d.Result.Errors)
+}
+
+var importPageTmpl = `
+<form method="post" action="{{.Url}}" enctype="multipart/form-data" class="form-horizontal">
+	<input type="hidden" name="{{.TokenKey}}" value="{{.Token}}">
+{{if eq .File ""}}
+	<input type="hidden" name="__goadmin_import_step" value="upload">
+	<div class="form-group">
+		<label class="col-sm-2 control-label">{{lang "file"}}</label>
+		<div class="col-sm-8">
+			<input type="file" name="file" accept=".csv,.xlsx" required>
+			<p class="help-block">{{lang "csv or xlsx file, the first row is the header"}}, {{lang "max rows"}}: {{.MaxRows}}</p>
+		</div>
+	</div>
+	<div class="form-group">
+		<div class="col-sm-offset-2 col-sm-8">
+			<button type="submit" class="btn btn-primary">{{lang "upload"}}</button>
+			<a href="{{.InfoUrl}}" class="btn btn-default">{{lang "back"}}</a>
+		</div>
+	</div>
+{{else}}
+	<input type="hidden" name="__goadmin_import_file" value="{{.File}}">
+	{{with .Result}}
+	<div class="alert {{if .Errors}}alert-warning{{else}}alert-success{{end}}">
+		{{if .DryRun}}
+			{{lang "dry run"}}: {{lang "total"}} {{.Total}}, {{lang "valid"}} {{$.Valid}}, {{lang "error"}} {{len .Errors}}
+		{{else}}
+			{{lang "total"}} {{.Total}}, {{lang "inserted"}} {{.Inserted}}, {{lang "error"}} {{len .Errors}}
+		{{end}}
+	</div>
+	{{if .Errors}}
+	<table class="table table-bordered table-condensed">
+		<thead><tr><th style="width: 100px;">{{lang "row"}}</th><th>{{lang "error"}}</th></tr></thead>
+		<tbody>
+		{{range .Errors}}<tr><td>{{.Row}}</td><td>{{.Error}}</td></tr>{{end}}
+		</tbody>
+	</table>
+	{{end}}
+	{{end}}
+	{{if or (not .Result) .Result.DryRun}}
+	<table class="table table-bordered table-condensed">
+		<thead><tr><th>{{lang "column"}}</th><th>{{lang "field"}}</th>{{range $i, $row := .Preview}}<th>{{lang "row"}} {{add $i 1}}</th>{{end}}</tr></thead>
+		<tbody>
+		{{range $col := .Columns}}
+		<tr>
+			<td>{{$col.Name}}</td>
+			<td>
+				<select name="__goadmin_import_map_{{$col.Index}}" class="form-control input-sm">
+					<option value="">-- {{lang "skip"}} --</option>
+					{{range $.Fields}}<option value="{{.Field}}" {{if eq .Field $col.Field}}selected{{end}}>{{.Head}} ({{.Field}})</option>{{end}}
+				</select>
+			</td>
+			{{range $row := $.Preview}}<td>{{if lt $col.Index (len $row)}}{{index $row $col.Index}}{{end}}</td>{{end}}
+		</tr>
+		{{end}}
+		</tbody>
+	</table>
+	<button type="submit" name="__goadmin_import_step" value="dry_run" class="btn btn-default">{{lang "dry run"}}</button>
+	<button type="submit" name="__goadmin_import_step" value="run" class="btn btn-primary">{{lang "import"}}</button>
+	{{end}}
+	<a href="{{.InfoUrl}}" class="btn btn-default">{{lang "back"}}</a>
+{{end}}
+</form>
+`
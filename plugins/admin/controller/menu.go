@@ -46,10 +46,14 @@ func getMenuPlugNameParams(plugName string) string {
 }
 
 func (h *Handler) showNewMenu(ctx *context.Context, err error) {
+	panel, ok := h.table("menu", ctx)
+	if !ok {
+		return
+	}
+
 	var (
 		alert template.HTML
 
-		panel    = h.table("menu", ctx)
 		formInfo = panel.GetNewFormInfo()
 		user     = auth.Auth(ctx)
 		plugName = getPlugNameFromReferer(ctx)
@@ -84,15 +88,18 @@ func (h *Handler) ShowEditMenu(ctx *context.Context) {
 	plugName := getPlugNameFromReferer(ctx)
 
 	if ctx.Query("id") == "" {
-		h.getMenuInfoPanel(ctx, "", template.Get(h.config.Theme).Alert().Warning(errors.WrongID))
+		h.getMenuInfoPanel(ctx, "", template.Get(h.config.Snapshot().Theme).Alert().Warning(errors.WrongID))
 
 		ctx.AddHeader("Content-Type", "text/html; charset=utf-8")
 		ctx.AddHeader(constant.PjaxUrlHeader, h.routePath("menu")+getMenuPlugNameParams(plugName))
 		return
 	}
 
-	model := h.table("menu", ctx)
-	formInfo, err := model.GetDataWithId(parameter.BaseParam().WithPKs(ctx.Query("id")))
+	model, ok := h.table("menu", ctx)
+	if !ok {
+		return
+	}
+	formInfo, err := model.GetDataWithId(parameter.BaseParam().WithPKs(ctx.Query("id")).WithContext(ctx.Request.Context()))
 
 	user := auth.Auth(ctx)
 
@@ -111,8 +118,12 @@ func (h *Handler) showEditMenu(ctx *context.Context, plugName string, formInfo t
 		alert = aAlert().Warning(err.Error())
 	}
 
+	panel, ok := h.table("menu", ctx)
+	if !ok {
+		return
+	}
+
 	params := getMenuPlugNameParams(plugName)
-	panel  := h.table("menu", ctx)
 
 	h.HTMLPlug(ctx, auth.Auth(ctx), types.Panel{
 		Content: alert + formContent(aForm().
@@ -135,7 +146,7 @@ func (h *Handler) showEditMenu(ctx *context.Context, plugName string, formInfo t
 
 // DeleteMenu delete the menu of given id.
 func (h *Handler) DeleteMenu(ctx *context.Context) {
-	models.MenuWithId(guard.GetMenuDeleteParam(ctx).Id).SetConn(h.conn).Delete()
+	models.MenuWithId(guard.GetMenuDeleteParam(ctx).Id).SetConn(h.connection(ctx)).Delete()
 	response.OkWithMsg(ctx, language.Get("delete succeed"))
 }
 
@@ -151,12 +162,17 @@ func (h *Handler) EditMenu(ctx *context.Context) {
 		return
 	}
 
-	menuModel := models.MenuWithId(param.Id).SetConn(h.conn)
+	panel, ok := h.table("menu", ctx)
+	if !ok {
+		return
+	}
+
+	menuModel := models.MenuWithId(param.Id).SetConn(h.connection(ctx))
 
 	// TODO: use transaction
 	deleteRolesErr := menuModel.DeleteRoles()
 	if db.CheckError(deleteRolesErr, db.DELETE) {
-		formInfo, _ := h.table("menu", ctx).GetDataWithId(parameter.BaseParam().WithPKs(param.Id))
+		formInfo, _ := panel.GetDataWithId(parameter.BaseParam().WithPKs(param.Id).WithContext(ctx.Request.Context()))
 		h.showEditMenu(ctx, param.PluginName, formInfo, deleteRolesErr)
 		ctx.AddHeader(constant.PjaxUrlHeader, h.routePath("menu")+params)
 		return
@@ -164,7 +180,7 @@ func (h *Handler) EditMenu(ctx *context.Context) {
 	for _, roleId := range param.Roles {
 		_, addRoleErr := menuModel.AddRole(roleId)
 		if db.CheckError(addRoleErr, db.INSERT) {
-			formInfo, _ := h.table("menu", ctx).GetDataWithId(parameter.BaseParam().WithPKs(param.Id))
+			formInfo, _ := panel.GetDataWithId(parameter.BaseParam().WithPKs(param.Id).WithContext(ctx.Request.Context()))
 			h.showEditMenu(ctx, param.PluginName, formInfo, addRoleErr)
 			ctx.AddHeader(constant.PjaxUrlHeader, h.routePath("menu")+params)
 			return
@@ -174,7 +190,7 @@ func (h *Handler) EditMenu(ctx *context.Context) {
 	_, updateErr := menuModel.Update(param.Title, param.Icon, param.Uri, param.Header, param.PluginName, param.ParentId)
 
 	if db.CheckError(updateErr, db.UPDATE) {
-		formInfo, _ := h.table("menu", ctx).GetDataWithId(parameter.BaseParam().WithPKs(param.Id))
+		formInfo, _ := panel.GetDataWithId(parameter.BaseParam().WithPKs(param.Id).WithContext(ctx.Request.Context()))
 		h.showEditMenu(ctx, param.PluginName, formInfo, updateErr)
 		ctx.AddHeader(constant.PjaxUrlHeader, h.routePath("menu")+params)
 		return
@@ -200,9 +216,9 @@ func (h *Handler) NewMenu(ctx *context.Context) {
 	user := auth.Auth(ctx)
 
 	// TODO: use transaction
-	menuModel, createErr := models.Menu().SetConn(h.conn).
+	menuModel, createErr := models.Menu().SetConn(h.connection(ctx)).
 		New(param.Title, param.Icon, param.Uri, param.Header, param.PluginName, param.ParentId,
-			(menu.GetGlobalMenu(user, h.conn, ctx.Lang(), param.PluginName)).MaxOrder+1)
+			(menu.GetGlobalMenu(user, h.connection(ctx), ctx.Lang(), param.PluginName)).MaxOrder+1)
 
 	if db.CheckError(createErr, db.INSERT) {
 		h.showNewMenu(ctx, createErr)
@@ -217,7 +233,7 @@ func (h *Handler) NewMenu(ctx *context.Context) {
 		}
 	}
 
-	menu.GetGlobalMenu(user, h.conn, ctx.Lang(), param.PluginName).AddMaxOrder()
+	menu.GetGlobalMenu(user, h.connection(ctx), ctx.Lang(), param.PluginName).AddMaxOrder()
 
 	h.getMenuInfoPanel(ctx, param.PluginName, "")
 	ctx.AddHeader("Content-Type", "text/html; charset=utf-8")
@@ -229,7 +245,7 @@ func (h *Handler) MenuOrder(ctx *context.Context) {
 	var data []map[string]interface{}
 	_ = utils.JsonUnmarshal([]byte(ctx.FormValue("_order")), &data)
 
-	models.Menu().SetConn(h.conn).ResetOrder([]byte(ctx.FormValue("_order")))
+	models.Menu().SetConn(h.connection(ctx)).ResetOrder([]byte(ctx.FormValue("_order")))
 
 	response.Ok(ctx)
 }
@@ -244,7 +260,7 @@ func (h *Handler) getMenuInfoPanel(ctx *context.Context, plugName string, alert
 	}
 
 	tree := aTree().
-		SetTree((menu.GetGlobalMenu(user, h.conn, ctx.Lang(), plugName)).List).
+		SetTree((menu.GetGlobalMenu(user, h.connection(ctx), ctx.Lang(), plugName)).List).
 		SetUrlPrefix(h.config.Prefix()).
 		SetOrderUrl(h.routePath("menu_order"))
 	if allowEdit {
@@ -254,12 +270,16 @@ func (h *Handler) getMenuInfoPanel(ctx *context.Context, plugName string, alert
 		tree.SetDeleteUrl(h.routePath("menu_delete"))
 	}
 
+	panel, ok := h.table("menu", ctx)
+	if !ok {
+		return
+	}
+
 	var (
 		header   = aTree().GetTreeHeader()
 		box      = aBox().SetHeader(header).SetBody(tree.GetContent()).GetContent()
 		col1     = aCol().SetSize(types.SizeMD(6)).SetContent(box).GetContent()
 		col2     template.HTML
-		panel    = h.table("menu", ctx)
 		formInfo = panel.GetNewFormInfo()
 	)
 
@@ -24,9 +24,13 @@ func (h *Handler) ShowNewForm(ctx *context.Context) {
 }
 
 func (h *Handler) showNewForm(ctx *context.Context, alert template.HTML, prefix, paramStr string, isNew bool) {
+	panel, ok := h.table(prefix, ctx)
+	if !ok {
+		return
+	}
+
 	var (
 		user        = auth.Auth(ctx)
-		panel       = h.table(prefix, ctx)
 		formInfo    = panel.GetNewFormInfo()
 		infoUrl     = h.routePathWithPrefix("info", prefix) + paramStr
 		newUrl      = h.routePathWithPrefix("new", prefix)
@@ -97,7 +101,7 @@ func (h *Handler) NewForm(ctx *context.Context) {
 
 	// process uploading files, only support local storage
 	if len(param.MultiForm.File) > 0 {
-		err := file.GetFileEngine(h.config.FileUploadEngine.Name).Upload(param.MultiForm)
+		err := file.GetFileEngine(h.config.Snapshot().FileUploadEngine.Name).Upload(param.MultiForm)
 		if err != nil {
 			logger.Error("get file engine error: ", err)
 			if ctx.WantJSON() {
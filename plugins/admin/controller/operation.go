@@ -6,6 +6,7 @@ import (
 	"github.com/GoAdminGroup/go-admin/context"
 	"github.com/GoAdminGroup/go-admin/modules/config"
 	"github.com/GoAdminGroup/go-admin/modules/db"
+	"github.com/GoAdminGroup/go-admin/modules/tenant"
 	"github.com/GoAdminGroup/go-admin/plugins/admin/models"
 	"github.com/GoAdminGroup/go-admin/plugins/admin/modules/response"
 )
@@ -17,7 +18,7 @@ func (h *Handler) Operation(ctx *context.Context) {
 		if ctx.IsDataRequest() {
 			response.BadRequest(ctx, errMsg)
 		} else {
-			response.Alert(ctx, errMsg, errMsg, errMsg, h.conn, h.navButtons)
+			response.Alert(ctx, errMsg, errMsg, errMsg, h.connection(ctx), h.navButtons)
 		}
 		return
 	}
@@ -35,6 +36,6 @@ func RecordOperationLog(ctx *context.Context, conn db.Connection) {
 		if form != nil && len(form.Value) > 0 {
 			input, _ = utils.JsonMarshal(form.Value)
 		}
-		models.OperationLog().SetConn(conn).New(user.Id, ctx.Path(), ctx.Method(), ctx.LocalIP(), string(input))
+		models.OperationLog().SetConn(tenant.Conn(ctx, conn)).New(user.Id, ctx.Path(), ctx.Method(), ctx.LocalIP(), string(input))
 	}
 }
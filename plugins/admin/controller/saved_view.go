@@ -0,0 +1,216 @@
+package controller
+
+import (
+	"html/template"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/GoAdminGroup/go-admin/context"
+	"github.com/GoAdminGroup/go-admin/modules/auth"
+	"github.com/GoAdminGroup/go-admin/modules/db"
+	"github.com/GoAdminGroup/go-admin/modules/language"
+	"github.com/GoAdminGroup/go-admin/modules/logger"
+	"github.com/GoAdminGroup/go-admin/plugins/admin/models"
+	"github.com/GoAdminGroup/go-admin/plugins/admin/modules/constant"
+	"github.com/GoAdminGroup/go-admin/plugins/admin/modules/form"
+	"github.com/GoAdminGroup/go-admin/plugins/admin/modules/guard"
+	"github.com/GoAdminGroup/go-admin/plugins/admin/modules/parameter"
+	"github.com/GoAdminGroup/go-admin/plugins/admin/modules/response"
+	"github.com/GoAdminGroup/go-admin/plugins/admin/modules/table"
+	template2 "github.com/GoAdminGroup/go-admin/template"
+	"github.com/GoAdminGroup/go-admin/template/icon"
+	"github.com/GoAdminGroup/go-admin/template/types"
+	form2 "github.com/GoAdminGroup/go-admin/template/types/form"
+	"github.com/GoAdminGroup/go-admin/template/types/action"
+)
+
+// InitSavedViews creates the table of the saved views of the info tables.
+func (h *Handler) InitSavedViews() {
+	for _, conn := range h.connections() {
+		if err := models.SavedView().SetConn(conn).Init(); err != nil {
+			logger.Error("init saved views error: ", err)
+		}
+	}
+}
+
+// SaveView saves the query of the list as a view of the user, which is shared to
+// the users of the role when the role is set. The table of the prefix must exist.
+func (h *Handler) SaveView(ctx *context.Context) {
+	var (
+		prefix = ctx.Query(constant.PrefixKey)
+		user   = auth.Auth(ctx)
+		name   = strings.TrimSpace(ctx.FormValue("name"))
+	)
+
+	if _, ok := h.generators.Get(prefix); !ok {
+		guard.TableNotFound(ctx, h.conn, h.navButtons)
+		return
+	}
+
+	if name == "" {
+		response.BadRequest(ctx, "view name is empty")
+		return
+	}
+
+	roleId, _ := strconv.ParseInt(ctx.FormValue("role_id"), 10, 64)
+	if roleId != 0 && !hasRole(user, roleId) {
+		response.BadRequest(ctx, "wrong role")
+		return
+	}
+
+	_, err := models.SavedView().SetConn(h.connection(ctx)).
+		New(user.Id, roleId, prefix, name, savedViewParams(ctx.FormValue("params")))
+	if err != nil {
+		logger.Error(err)
+		response.Error(ctx, "save view fail")
+		return
+	}
+
+	response.Ok(ctx)
+}
+
+// DeleteView deletes a saved view of the user.
+func (h *Handler) DeleteView(ctx *context.Context) {
+	var (
+		prefix = ctx.Query(constant.PrefixKey)
+		user   = auth.Auth(ctx)
+		view   = models.SavedView().SetConn(h.connection(ctx)).Find(ctx.FormValue("view_id"))
+	)
+
+	if view.IsEmpty() || view.Prefix != prefix || view.UserId != user.Id {
+		response.BadRequest(ctx, "wrong view")
+		return
+	}
+
+	if err := view.Delete(); err != nil {
+		logger.Error(err)
+		response.Error(ctx, "delete view fail")
+		return
+	}
+
+	response.Ok(ctx)
+}
+
+// showSavedView redirects the stable url of a saved view, which is the url of the
+// list with the id of the view, to the list with the query of the view. It
+// reports whether the request is handled.
+func (h *Handler) showSavedView(ctx *context.Context, prefix string) bool {
+	id := ctx.Query(parameter.View)
+	if id == "" {
+		return false
+	}
+
+	view := models.SavedView().SetConn(h.connection(ctx)).Find(id)
+	if view.IsEmpty() || view.Prefix != prefix || !view.VisibleTo(auth.Auth(ctx)) {
+		h.HTML(ctx, auth.Auth(ctx), template2.WarningPanel(language.Get("view not found"), template2.Missing404Page))
+		return true
+	}
+
+	u := h.routePathWithPrefix("info", prefix)
+	if view.Params != "" {
+		u += "?" + view.Params
+	}
+	ctx.Redirect(u)
+	return true
+}
+
+// addSavedViewButtons adds the select box of the saved views visible to the user,
+// and the buttons saving the current query as a view and deleting the selected one.
+func (h *Handler) addSavedViewButtons(ctx *context.Context, prefix string, panel table.Table, params parameter.Parameters) {
+	if params.IsTrashed() {
+		return
+	}
+
+	var (
+		user    = auth.Auth(ctx)
+		info    = panel.GetInfo()
+		infoUrl = h.routePathWithPrefix("info", prefix)
+		views   = models.SavedView().SetConn(h.connection(ctx)).GetVisible(prefix, user)
+	)
+
+	if len(views) > 0 {
+		var (
+			options = make(types.FieldOptions, len(views))
+			jumps   = make(action.JumpOptions, len(views))
+		)
+		for i, view := range views {
+			options[i] = types.FieldOption{ Value: strconv.FormatInt(view.Id, 10), Text: view.Name }
+			jumps[i]   = action.JumpOption{ Value: view.Name, Url: infoUrl + "?" + parameter.View + "=" + options[i].Value }
+		}
+		selectBox := action.SelectBoxJump(jumps)
+		info.AddSelectBox(language.Get("saved views"), options, selectBox)
+
+		if u := user.GetCheckPermissionByUrlMethod(h.routePathWithPrefix("delete_view", prefix), h.route("delete_view").Method()); u != "" {
+			info.AddButton(template.HTML(language.Get("delete view")), icon.Trash,
+				action.Ajax("delete_view_" + prefix, nil).
+					SetUrl(u).
+					SetParameterJS(template.JS(`data["view_id"] = $("select` + selectBox.BtnId + `").val();
+		if (!data["view_id"] || data["view_id"] === "__go_admin_all__") { return; }`)).
+					SetSuccessJS(`if (data.code === 200) {
+		$.pjax.reload('#pjax-container');
+	} else {
+		swal(data.msg, '', 'error');
+	}`).
+					WithAlert())
+		}
+	}
+
+	u := user.GetCheckPermissionByUrlMethod(h.routePathWithPrefix("save_view", prefix), h.route("save_view").Method())
+	if u == "" {
+		return
+	}
+
+	popup := action.PopUpWithCtxForm(action.PopUpData{
+		Id:     "/saved_view/form/" + prefix,
+		Title:  language.Get("save view"),
+		Width:  "600px",
+		Height: "280px",
+	}, func(ctx *context.Context, panel *types.FormPanel) *types.FormPanel {
+		roles := types.FieldOptions{{ Value: "0", Text: language.Get("only me") }}
+		for _, role := range auth.Auth(ctx).Roles {
+			roles = append(roles, types.FieldOption{ Value: strconv.FormatInt(role.Id, 10), Text: role.Name })
+		}
+		params := savedViewParams(ctx.FormValue("params"))
+
+		panel.AddField(language.Get("name"), "name", db.Varchar, form2.Text).FieldMust()
+		panel.AddField(language.Get("share to role"), "role_id", db.Int, form2.SelectSingle).
+			FieldOptions(roles).FieldDefault("0")
+		panel.AddField("params", "params", db.Text, form2.Text).FieldDefault(params).FieldHide()
+		panel.EnableAjax(language.Get("save succeed"), language.Get("save fail"), infoUrl + "?" + params)
+		return panel
+	}, u).SetParameterJS(`data["params"] = window.location.search.replace(/^\?/, "");`)
+
+	info.AddButton(template.HTML(language.Get("save view")), icon.Save, popup)
+
+	// the buttons are added after the callbacks of the table are registered.
+	cb := popup.GetCallbacks()
+	h.AddOperation(context.Node{
+		Path:     cb.Path,
+		Method:   cb.Method,
+		Handlers: append([]context.Handler{ auth.Middleware(db.GetConnection(h.services)) }, cb.Handlers...),
+	})
+}
+
+// savedViewParams return the query of a view from the query of the list without
+// the page, the cursors and the keys of the requests.
+func savedViewParams(raw string) string {
+	values, err := url.ParseQuery(strings.TrimPrefix(raw, "?"))
+	if err != nil {
+		return ""
+	}
+	for _, key := range []string{ parameter.Page, parameter.After, parameter.Before, parameter.Pjax,
+		parameter.View, parameter.Prefix, form.NoAnimationKey } {
+		values.Del(key)
+	}
+	return values.Encode()
+}
+
+func hasRole(user models.UserModel, roleId int64) bool {
+	for _, role := range user.Roles {
+		if role.Id == roleId {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,85 @@
+package controller
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/GoAdminGroup/go-admin/context"
+	"github.com/GoAdminGroup/go-admin/modules/config"
+	"github.com/GoAdminGroup/go-admin/modules/db"
+	"github.com/GoAdminGroup/go-admin/plugins/admin/models"
+	"github.com/GoAdminGroup/go-admin/plugins/admin/modules/parameter"
+	"github.com/GoAdminGroup/go-admin/plugins/admin/modules/table"
+	"github.com/magiconair/properties/assert"
+)
+
+func TestSavedView(t *testing.T) {
+	initExportTest(&config.Config{ UrlPrefix: "admin", AutoMigrate: true })
+
+	conn := db.GetSqliteDB().InitDB(map[string]config.Database{
+		"default": { Driver: db.DriverSqlite, File: filepath.Join(t.TempDir(), "admin.db") },
+	})
+	defer conn.Close()
+
+	h := New(Config{ Connection: conn, Generators: table.GeneratorList{
+		"posts": func(ctx *context.Context) table.Table { return table.NewDefaultTable(table.DefaultConfig()) },
+	} })
+	h.SetRoutes(context.RouterMap{ "info": { Methods: []string{ "GET" }, Patten: "/admin/info/:__prefix" } })
+	h.InitSavedViews()
+
+	var (
+		editor = models.RoleModel{ Id: 2, Slug: "editor" }
+		owner  = models.UserModel{ Id: 1, Roles: []models.RoleModel{ editor } }
+		member = models.UserModel{ Id: 2, Roles: []models.RoleModel{ editor } }
+		other  = models.UserModel{ Id: 3 }
+	)
+
+	save := func(user models.UserModel, prefix string, values url.Values) int {
+		req := httptest.NewRequest("POST", "/admin/saved_view/new/" + prefix + "?__prefix=" + prefix,
+			strings.NewReader(values.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		ctx := context.NewContext(req)
+		ctx.SetUserValue("user", user)
+		h.SaveView(ctx)
+		return ctx.Response.StatusCode
+	}
+
+	params := "__page=3&author=sam&_pjax=%23pjax-container"
+	assert.Equal(t, save(owner, "posts", url.Values{ "name": { "mine" }, "params": { params } }), http.StatusOK)
+	assert.Equal(t, save(owner, "posts", url.Values{ "name": { "shared" }, "role_id": { "2" }, "params": { params } }), http.StatusOK)
+
+	// the unknown tables, the empty names and the roles of the others are rejected.
+	assert.Equal(t, save(owner, "missing", url.Values{ "name": { "mine" } }), http.StatusNotFound)
+	assert.Equal(t, save(owner, "posts", url.Values{ "name": { " " } }), http.StatusBadRequest)
+	assert.Equal(t, save(other, "posts", url.Values{ "name": { "shared" }, "role_id": { "2" } }), http.StatusBadRequest)
+	assert.Equal(t, len(models.SavedView().SetConn(conn).GetVisible("missing", owner)), 0)
+
+	names := func(user models.UserModel) []string {
+		var res []string
+		for _, view := range models.SavedView().SetConn(conn).GetVisible("posts", user) {
+			res = append(res, view.Name)
+		}
+		return res
+	}
+	assert.Equal(t, names(owner), []string{ "mine", "shared" })
+	assert.Equal(t, names(member), []string{ "shared" })
+	assert.Equal(t, len(names(other)), 0)
+
+	// the stable url of the view redirects to the list with the query of the view
+	// without the page and the keys of the requests.
+	shared := models.SavedView().SetConn(conn).GetVisible("posts", member)[0]
+	ctx := context.NewContext(httptest.NewRequest("GET", "/admin/info/posts?" + parameter.View + "=" +
+		strconv.FormatInt(shared.Id, 10), nil))
+	ctx.SetUserValue("user", member)
+	assert.Equal(t, h.showSavedView(ctx, "posts"), true)
+	assert.Equal(t, ctx.Response.StatusCode, http.StatusFound)
+	assert.Equal(t, ctx.Response.Header.Get("Location"), "/admin/info/posts?author=sam")
+
+	ctx = context.NewContext(httptest.NewRequest("GET", "/admin/info/posts", nil))
+	assert.Equal(t, h.showSavedView(ctx, "posts"), false)
+}
@@ -35,7 +35,10 @@ import (
 // ShowInfo show info page.
 func (h *Handler) ShowInfo(ctx *context.Context) {
 	prefix := ctx.Query(constant.PrefixKey)
-	panel  := h.table(prefix, ctx)
+	panel, ok := h.table(prefix, ctx)
+	if !ok {
+		return
+	}
 
 	if panel.GetOnlyUpdateForm() {
 		ctx.Redirect(h.routePathWithPrefix("show_edit", prefix))
@@ -52,19 +55,31 @@ func (h *Handler) ShowInfo(ctx *context.Context) {
 		return
 	}
 
+	if h.showSavedView(ctx, prefix) {
+		return
+	}
+
 	info   := panel.GetInfo()
 	params := parameter.GetParam(ctx.Request.URL, info.DefaultPageSize, info.SortField, info.GetSort())
 
+	h.addTrashButtons(ctx, prefix, panel, params)
+	h.addSavedViewButtons(ctx, prefix, panel, params)
+	h.addFilterBuilderButton(prefix, panel, params)
+	h.addExportJobButton(ctx, prefix, panel, params)
+
 	buf := h.showTable(ctx, prefix, params, panel)
 	ctx.HTML(http.StatusOK, buf.String())
 }
 
 func (h *Handler) showTableData(ctx *context.Context, prefix string, params parameter.Parameters, panel table.Table, urlNamePrefix string) (table.Table, table.PanelInfo, []string, error) {
 	if panel == nil {
-		panel = h.table(prefix, ctx)
+		var ok bool
+		if panel, ok = h.table(prefix, ctx); !ok {
+			return nil, table.PanelInfo{}, nil, fmt.Errorf("table model not found: %s", prefix)
+		}
 	}
 
-	panelInfo, err := panel.GetData(params.WithIsAll(false))
+	panelInfo, err := panel.GetData(params.WithIsAll(false).WithContext(ctx.Request.Context()))
 	if err != nil {
 		return panel, panelInfo, nil, err
 	}
@@ -270,7 +285,7 @@ func (h *Handler) showTable(ctx *context.Context, prefix string, params paramete
 				GetContent())
 	}
 
-	content := boxModel.GetContent()
+	content := boxModel.GetContent() + versionScript(updateUrl, panelInfo.Versions)
 
 	if info.Wrapper != nil {
 		content = info.Wrapper(content)
@@ -336,7 +351,20 @@ func (h *Handler) Export(ctx *context.Context) {
 
 	tableName := "Sheet1"
 	prefix := ctx.Query(constant.PrefixKey)
-	panel := h.table(prefix, ctx)
+	panel, ok := h.table(prefix, ctx)
+	if !ok {
+		return
+	}
+
+	if param.Async || isLargeExport(ctx, panel, param) {
+		h.enqueueExportJob(ctx, panel, param)
+		return
+	}
+
+	if param.Format == guard.ExportFormatCSV || param.Format == guard.ExportFormatNDJSON {
+		h.exportStream(ctx, panel, param)
+		return
+	}
 
 	f := excelize.NewFile()
 	index := f.NewSheet(tableName)
@@ -353,7 +381,7 @@ func (h *Handler) Export(ctx *context.Context) {
 	if fn := panel.GetInfo().ExportProcessFn; fn != nil {
 		params = parameter.GetParam(ctx.Request.URL, tableInfo.DefaultPageSize, tableInfo.SortField,
 			tableInfo.GetSort())
-		p, err := fn(params.WithIsAll(param.IsAll))
+		p, err := fn(params.WithIsAll(param.IsAll).WithContext(ctx.Request.Context()))
 		if err != nil {
 			response.Error(ctx, "export error")
 			return
@@ -363,11 +391,11 @@ func (h *Handler) Export(ctx *context.Context) {
 	} else {
 		if len(param.Id) == 0 {
 			params = parameter.GetParam(ctx.Request.URL, tableInfo.DefaultPageSize, tableInfo.SortField, tableInfo.GetSort())
-			infoData, err = panel.GetData(params.WithIsAll(param.IsAll))
+			infoData, err = panel.GetData(params.WithIsAll(param.IsAll).WithContext(ctx.Request.Context()))
 			fileName = fmt.Sprintf("%s-%d-page-%s-pageSize-%s.xlsx", tableInfo.Title, time.Now().Unix(), params.Page, params.PageSize)
 		} else {
 			infoData, err = panel.GetDataWithIds(parameter.GetParam(ctx.Request.URL,
-				tableInfo.DefaultPageSize, tableInfo.SortField, tableInfo.GetSort()).WithPKs(param.Id...))
+				tableInfo.DefaultPageSize, tableInfo.SortField, tableInfo.GetSort()).WithPKs(param.Id...).WithContext(ctx.Request.Context()))
 			fileName = fmt.Sprintf("%s-%d-id-%s.xlsx", tableInfo.Title, time.Now().Unix(), strings.Join(param.Id, "_"))
 		}
 		if err != nil {
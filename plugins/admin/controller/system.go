@@ -5,9 +5,13 @@ import (
 	"html/template"
 	"os"
 	"runtime"
+	"strings"
+	"time"
 
 	"github.com/GoAdminGroup/go-admin/context"
 	"github.com/GoAdminGroup/go-admin/modules/auth"
+	"github.com/GoAdminGroup/go-admin/modules/config"
+	"github.com/GoAdminGroup/go-admin/modules/db"
 	"github.com/GoAdminGroup/go-admin/modules/language"
 	"github.com/GoAdminGroup/go-admin/modules/system"
 	"github.com/GoAdminGroup/go-admin/template/types"
@@ -148,6 +152,30 @@ func (h *Handler) SystemInfo(ctx *context.Context) {
 
 	row := aRow().SetContent(col1 + col2).GetContent()
 
+	if replicas := db.GetReplicaStatus(); len(replicas) > 0 {
+		list := make([]map[string]types.InfoItem, len(replicas))
+		for i, r := range replicas {
+			status := lg("replica healthy") + template.HTML(" " + r.Latency.Round(time.Millisecond).String())
+			if !r.Healthy {
+				status = lg("replica down") + template.HTML(" " + template.HTMLEscapeString(r.Error))
+			}
+			list[i] = map[string]types.InfoItem{
+				"key"  : { Content: template.HTML(template.HTMLEscapeString(r.Connection + " / " + r.Host)) },
+				"value": { Content: status },
+			}
+		}
+		box5 := aBox().
+			WithHeadBorder().
+			SetHeader("<b>" + lg("read replicas") + "</b>").
+			SetBody(stripedTable(list)).
+			GetContent()
+		row += aRow().SetContent(aCol().SetSize(types.Size(12, 12, 12)).SetContent(box5).GetContent()).GetContent()
+	}
+
+	if keys := config.GetPendingRestart(); len(keys) > 0 {
+		row = aAlert().Warning(string(lg("pending restart")) + strings.Join(keys, ", ")) + row
+	}
+
 	h.HTML(ctx, auth.Auth(ctx), types.Panel{
 		Content:     row,
 		Title:       language.GetFromHtml("system info", "system"),
@@ -0,0 +1,18 @@
+package controller
+
+import (
+	"github.com/GoAdminGroup/go-admin/modules/logger"
+	"github.com/GoAdminGroup/go-admin/plugins/admin/models"
+	"github.com/GoAdminGroup/go-admin/plugins/admin/modules/table"
+)
+
+// InitTableDefs creates the table of the table definitions of the designer and
+// serves the live versions of them.
+func (h *Handler) InitTableDefs() {
+	for _, conn := range h.connections() {
+		if err := models.TableDef().SetConn(conn).Init(); err != nil {
+			logger.Error("init table definitions error: ", err)
+		}
+	}
+	table.LoadTableDefs(h.conn, h.generators)
+}
@@ -0,0 +1,92 @@
+package controller
+
+import (
+	"html/template"
+
+	"github.com/GoAdminGroup/go-admin/context"
+	"github.com/GoAdminGroup/go-admin/modules/auth"
+	"github.com/GoAdminGroup/go-admin/modules/language"
+	"github.com/GoAdminGroup/go-admin/modules/logger"
+	"github.com/GoAdminGroup/go-admin/plugins/admin/modules/guard"
+	"github.com/GoAdminGroup/go-admin/plugins/admin/modules/parameter"
+	"github.com/GoAdminGroup/go-admin/plugins/admin/modules/response"
+	"github.com/GoAdminGroup/go-admin/plugins/admin/modules/table"
+	"github.com/GoAdminGroup/go-admin/template/icon"
+	"github.com/GoAdminGroup/go-admin/template/types/action"
+)
+
+// Restore restores the soft deleted rows.
+func (h *Handler) Restore(ctx *context.Context) {
+	param := guard.GetDeleteParam(ctx)
+
+	if err := param.Panel.RestoreData(param.Id); err != nil {
+		logger.Error(err)
+		response.Error(ctx, "restore fail")
+		return
+	}
+
+	response.Ok(ctx)
+}
+
+// Purge deletes the soft deleted rows physically.
+func (h *Handler) Purge(ctx *context.Context) {
+	param := guard.GetDeleteParam(ctx)
+
+	if err := param.Panel.PurgeData(param.Id); err != nil {
+		logger.Error(err)
+		response.Error(ctx, "purge fail")
+		return
+	}
+
+	response.Ok(ctx)
+}
+
+// ShowTrash shows the soft deleted rows of the table, the trash has its own route
+// and so its own permission.
+func (h *Handler) ShowTrash(ctx *context.Context) {
+	query := ctx.Request.URL.Query()
+	query.Set(parameter.Trashed, parameter.True)
+	ctx.Request.URL.RawQuery = query.Encode()
+	h.ShowInfo(ctx)
+}
+
+// addTrashButtons adds the button switching between the list and the trash of a
+// soft deleted table. In the trash, the rows can only be restored or purged when
+// the user has the permissions of the restore and the purge routes.
+func (h *Handler) addTrashButtons(ctx *context.Context, prefix string, panel table.Table, params parameter.Parameters) {
+	if panel.GetSoftDeleteField() == "" || !panel.GetDeletable() {
+		return
+	}
+
+	var (
+		user    = auth.Auth(ctx)
+		info    = panel.GetInfo()
+		infoUrl = h.routePathWithPrefix("info", prefix)
+	)
+
+	if !params.IsTrashed() {
+		if url := user.GetCheckPermissionByUrlMethod(h.routePathWithPrefix("trash", prefix), h.route("trash").Method()); url != "" {
+			info.AddButton(template.HTML(language.Get("trash")), icon.Trash, action.Jump(url))
+		}
+		return
+	}
+
+	info.HideNewButton().HideEditButton().HideDeleteButton().HideDetailButton()
+	info.AddButton(template.HTML(language.Get("back")), icon.Reply, action.Jump(infoUrl))
+
+	reload := template.JS(`if (data.code === 200) {
+		$.pjax.reload('#pjax-container');
+	} else {
+		swal(data.msg, '', 'error');
+	}`)
+
+	if url := user.GetCheckPermissionByUrlMethod(h.routePathWithPrefix("restore", prefix), h.route("restore").Method()); url != "" {
+		info.AddActionButton(template.HTML(language.Get("restore")),
+			action.Ajax("restore_" + prefix, nil).SetUrl(url).SetSuccessJS(reload))
+	}
+
+	if url := user.GetCheckPermissionByUrlMethod(h.routePathWithPrefix("purge", prefix), h.route("purge").Method()); url != "" {
+		info.AddActionButton(template.HTML(language.Get("purge")),
+			action.Ajax("purge_" + prefix, nil).SetUrl(url).SetSuccessJS(reload).WithAlert())
+	}
+}
@@ -0,0 +1,392 @@
+package controller
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/GoAdminGroup/go-admin/context"
+	"github.com/GoAdminGroup/go-admin/modules/auth"
+	"github.com/GoAdminGroup/go-admin/modules/config"
+	"github.com/GoAdminGroup/go-admin/modules/language"
+	"github.com/GoAdminGroup/go-admin/modules/logger"
+	"github.com/GoAdminGroup/go-admin/modules/qrcode"
+	"github.com/GoAdminGroup/go-admin/plugins/admin/models"
+	"github.com/GoAdminGroup/go-admin/plugins/admin/modules"
+	form2 "github.com/GoAdminGroup/go-admin/plugins/admin/modules/form"
+	"github.com/GoAdminGroup/go-admin/template"
+	"github.com/GoAdminGroup/go-admin/template/types"
+)
+
+var (
+	// TwoFactorTOTP validates the codes of the two-factor authentication, the clock
+	// can be fixed in the tests.
+	TwoFactorTOTP = auth.DefaultTOTP
+	// TwoFactorLoginExpiration is the duration to finish the second step of a login.
+	TwoFactorLoginExpiration = 5 * time.Minute
+	// TwoFactorMaxAttempts is the max number of the wrong codes of a login.
+	TwoFactorMaxAttempts = 5
+	// TwoFactorRecoveryCodes is the number of the generated recovery codes.
+	TwoFactorRecoveryCodes = 10
+)
+
+// pendingLogin is a login whose password has been checked and which waits for
+// the second step, the secret is set when the user must enroll first.
+type pendingLogin struct {
+	userId   int64
+	username string
+	secret   string
+	redirect string
+	expireAt time.Time
+	attempts int
+}
+
+// twoFactorLogins is the pending logins keyed by the random token, they are kept
+// in the memory since they live only a few minutes.
+var twoFactorLogins = struct {
+	sync.Mutex
+	m map[string]*pendingLogin
+}{ m: make(map[string]*pendingLogin) }
+
+func addPendingLogin(login *pendingLogin) string {
+	twoFactorLogins.Lock()
+	defer twoFactorLogins.Unlock()
+	now := time.Now()
+	for token, l := range twoFactorLogins.m {
+		if now.After(l.expireAt) {
+			delete(twoFactorLogins.m, token)
+		}
+	}
+	token := modules.Uuid()
+	login.expireAt = now.Add(TwoFactorLoginExpiration)
+	twoFactorLogins.m[token] = login
+	return token
+}
+
+func getPendingLogin(token string) *pendingLogin {
+	twoFactorLogins.Lock()
+	defer twoFactorLogins.Unlock()
+	login, ok := twoFactorLogins.m[token]
+	if !ok { return nil }
+	if time.Now().After(login.expireAt) {
+		delete(twoFactorLogins.m, token)
+		return nil
+	}
+	return login
+}
+
+// failPendingLogin counts a wrong code, the login is dropped after too many attempts.
+func failPendingLogin(token string) bool {
+	twoFactorLogins.Lock()
+	defer twoFactorLogins.Unlock()
+	login, ok := twoFactorLogins.m[token]
+	if !ok { return false }
+	login.attempts++
+	if login.attempts >= TwoFactorMaxAttempts {
+		delete(twoFactorLogins.m, token)
+		return false
+	}
+	return true
+}
+
+func removePendingLogin(token string) {
+	twoFactorLogins.Lock()
+	defer twoFactorLogins.Unlock()
+	delete(twoFactorLogins.m, token)
+}
+
+// InitTwoFactor creates the tables of the two-factor authentication.
+func (h *Handler) InitTwoFactor() {
+	for _, conn := range h.connections() {
+		if err := models.TwoFactor().SetConn(conn).Init(); err != nil {
+			logger.Error("init two-factor authentication error: ", err)
+		}
+	}
+}
+
+// twoFactorLogin starts the second step of the login when the user has enabled
+// the two-factor authentication or any role of the user requires it, it return
+// the url of the second step.
+func (h *Handler) twoFactorLogin(ctx *context.Context, user models.UserModel, username, redirect string) (string, bool) {
+	tf := models.TwoFactor().SetConn(h.connection(ctx))
+	login := &pendingLogin{ userId: user.Id, username: username, redirect: redirect }
+
+	if tf.FindByUserId(user.Id).IsEmpty() {
+		if !tf.IsRequired(user.SetConn(h.connection(ctx)).WithRoles().Roles) {
+			return "", false
+		}
+		secret, err := auth.NewTOTPSecret()
+		if err != nil {
+			logger.Error("generate totp secret error: ", err)
+			return "", false
+		}
+		login.secret = secret
+	}
+
+	return h.config.Url("/login/two_factor?token=" + addPendingLogin(login)), true
+}
+
+// ShowTwoFactorLogin show the page of the second step of the login.
+func (h *Handler) ShowTwoFactorLogin(ctx *context.Context) {
+	token := ctx.Query("token")
+	login := getPendingLogin(token)
+	if login == nil {
+		ctx.Redirect(h.config.Url(config.GetLoginUrl()))
+		return
+	}
+	h.showTwoFactorLogin(ctx, token, login, "")
+}
+
+func (h *Handler) showTwoFactorLogin(ctx *context.Context, token string, login *pendingLogin, errMsg string) {
+	cfg  := h.config.Snapshot()
+	data := twoFactorPageData{
+		Title:    cfg.LoginTitle,
+		AssetUrl: modules.SetDefault(cfg.AssetUrl, cfg.AssertPrefix()),
+		Url:      h.config.Url("/login/two_factor"),
+		Token:    token,
+		Error:    errMsg,
+	}
+	if login.secret != "" {
+		user := models.User().SetConn(h.connection(ctx)).Find(login.userId)
+		h.setEnrollData(&data, user, login.secret)
+	}
+	ctx.HTML(http.StatusOK, string(executeTwoFactorTmpl("two_factor_login", twoFactorLoginTmpl, data)))
+}
+
+func (h *Handler) setEnrollData(data *twoFactorPageData, user models.UserModel, secret string) {
+	data.Enroll = true
+	data.Secret = secret
+	uri := TwoFactorTOTP.URI(h.config.Snapshot().Title, user.UserName, secret)
+	if qr, err := qrcode.DataURL(uri, 4); err == nil {
+		data.QRCode = qr
+	} else {
+		logger.Error("generate qrcode error: ", err)
+	}
+}
+
+// TwoFactorLogin verifies the code of the second step and sets the cookie of the login.
+func (h *Handler) TwoFactorLogin(ctx *context.Context) {
+	var (
+		token = ctx.FormValue("token")
+		code  = ctx.FormValue("code")
+		login = getPendingLogin(token)
+	)
+
+	if login == nil {
+		ctx.Redirect(h.config.Url(config.GetLoginUrl()))
+		return
+	}
+
+	user := models.User().SetConn(h.connection(ctx)).Find(login.userId)
+	if user.IsEmpty() || user.IsDisabled() {
+		removePendingLogin(token)
+		ctx.Redirect(h.config.Url(config.GetLoginUrl()))
+		return
+	}
+
+	var (
+		recoveryCodes []string
+		ok            bool
+		tf            = models.TwoFactor().SetConn(h.connection(ctx))
+		ip            = auth.ClientIP(ctx)
+		throttle      = auth.NewLoginThrottle(h.connection(ctx))
+	)
+
+	// the wrong codes are throttled as the wrong passwords, so that the code can
+	// not be guessed by starting the logins again and again.
+	if wait, _ := throttle.Check(login.username, ip); wait > 0 {
+		h.recordLoginAttempt(ctx, login.username, "locked", wait)
+		h.showTwoFactorLogin(ctx, token, login, language.Get("too many failed login attempts, please try again later"))
+		return
+	}
+
+	if login.secret != "" {
+		recoveryCodes, ok = h.enableTwoFactor(ctx, user, login.secret, code)
+	} else {
+		ok = checkTwoFactorCode(tf.FindByUserId(user.Id), code)
+	}
+
+	if !ok {
+		wait, locked := throttle.Fail(login.username, ip)
+		if locked {
+			h.recordLoginAttempt(ctx, login.username, "locked", wait)
+		} else {
+			h.recordLoginAttempt(ctx, login.username, "failed", wait)
+		}
+		if locked || !failPendingLogin(token) {
+			removePendingLogin(token)
+			ctx.Redirect(h.config.Url(config.GetLoginUrl()))
+			return
+		}
+		h.showTwoFactorLogin(ctx, token, login, language.Get("wrong code"))
+		return
+	}
+
+	removePendingLogin(token)
+	throttle.Succeed(login.username)
+
+	if err := auth.SetCookie(ctx, user, h.connection(ctx)); err != nil {
+		logger.Error("set cookie error: ", err)
+		ctx.Redirect(h.config.Url(config.GetLoginUrl()))
+		return
+	}
+
+	if len(recoveryCodes) > 0 {
+		cfg := h.config.Snapshot()
+		ctx.HTML(http.StatusOK, string(executeTwoFactorTmpl("two_factor_login", twoFactorLoginTmpl, twoFactorPageData{
+			Title:         cfg.LoginTitle,
+			AssetUrl:      modules.SetDefault(cfg.AssetUrl, cfg.AssertPrefix()),
+			RecoveryCodes: recoveryCodes,
+			ContinueUrl:   login.redirect,
+		})))
+		return
+	}
+
+	ctx.Redirect(login.redirect)
+}
+
+// checkTwoFactorCode checks the TOTP code or a recovery code of the user.
+func checkTwoFactorCode(tf models.TwoFactorModel, code string) bool {
+	if tf.IsEmpty() || code == "" {
+		return false
+	}
+	if step, ok := TwoFactorTOTP.Validate(tf.Secret, code, tf.LastStep); ok {
+		tf.UpdateLastStep(step)
+		return true
+	}
+	return tf.UseRecoveryCode(auth.HashRecoveryCode(code))
+}
+
+// enableTwoFactor verifies the code of the new secret and enables the two-factor
+// authentication, it return the new recovery codes.
+func (h *Handler) enableTwoFactor(ctx *context.Context, user models.UserModel, secret, code string) ([]string, bool) {
+	step, ok := TwoFactorTOTP.Validate(secret, code, 0)
+	if !ok {
+		return nil, false
+	}
+	codes, hashed, err := newRecoveryCodes()
+	if err != nil {
+		logger.Error("generate recovery codes error: ", err)
+		return nil, false
+	}
+	if _, err := models.TwoFactor().SetConn(h.connection(ctx)).Enable(user.Id, secret, step, hashed); err != nil {
+		logger.Error("enable two-factor authentication error: ", err)
+		return nil, false
+	}
+	return codes, true
+}
+
+func newRecoveryCodes() ([]string, []string, error) {
+	codes, err := auth.NewRecoveryCodes(TwoFactorRecoveryCodes)
+	if err != nil { return nil, nil, err }
+	hashed := make([]string, len(codes))
+	for i, code := range codes {
+		hashed[i] = auth.HashRecoveryCode(code)
+	}
+	return codes, hashed, nil
+}
+
+// ShowTwoFactor show the page of the two-factor authentication of the current user.
+func (h *Handler) ShowTwoFactor(ctx *context.Context) {
+	h.showTwoFactor(ctx, twoFactorPageData{})
+}
+
+func (h *Handler) showTwoFactor(ctx *context.Context, data twoFactorPageData) {
+	var (
+		user = auth.Auth(ctx)
+		tf   = models.TwoFactor().SetConn(h.connection(ctx))
+		item = tf.FindByUserId(user.Id)
+	)
+
+	data.Url      = h.config.Url("/two_factor")
+	data.Token    = h.authSrv().AddToken()
+	data.TokenKey = form2.TokenKey
+	data.Enabled  = !item.IsEmpty()
+	data.Required = tf.IsRequired(user.Roles)
+
+	if data.Enabled {
+		data.RecoveryLeft = len(item.RecoveryCodes)
+	} else {
+		secret, err := auth.NewTOTPSecret()
+		if err != nil {
+			logger.Error("generate totp secret error: ", err)
+		}
+		h.setEnrollData(&data, user, secret)
+	}
+
+	h.HTML(ctx, user, types.Panel{
+		Content: aBox().
+			SetHeader(template.HTML(language.Get("two-factor authentication"))).
+			WithHeadBorder().
+			SetBody(executeTwoFactorTmpl("two_factor", twoFactorSettingTmpl, data)).
+			GetContent(),
+		Title:       template.HTML(language.Get("two-factor authentication")),
+		Description: template.HTML(user.Name),
+	}, template.ExecuteOptions{ Animation: data.Error == "" && data.Message == "" })
+}
+
+// TwoFactor enables or disables the two-factor authentication of the current user,
+// and regenerates the recovery codes. A valid code is required for all actions,
+// re-enrolling an enabled user also requires the current code as current_code.
+func (h *Handler) TwoFactor(ctx *context.Context) {
+	var (
+		user = auth.Auth(ctx)
+		code = ctx.FormValue("code")
+		tf   = models.TwoFactor().SetConn(h.connection(ctx))
+		item = tf.FindByUserId(user.Id)
+		data twoFactorPageData
+	)
+
+	if !h.authSrv().CheckToken(ctx.FormValue(form2.TokenKey)) {
+		data.Error = language.Get("wrong token")
+		h.showTwoFactor(ctx, data)
+		return
+	}
+
+	switch ctx.FormValue("action") {
+	case "enable":
+		// replacing the secret of an enabled user requires the current code, or a
+		// stolen session could swap the second factor.
+		if !item.IsEmpty() && !checkTwoFactorCode(item, ctx.FormValue("current_code")) {
+			data.Error = language.Get("wrong code")
+			break
+		}
+		codes, ok := h.enableTwoFactor(ctx, user, ctx.FormValue("secret"), code)
+		if !ok {
+			data.Error = language.Get("wrong code")
+			break
+		}
+		data.Message       = language.Get("the two-factor authentication is enabled")
+		data.RecoveryCodes = codes
+	case "disable":
+		if tf.IsRequired(user.Roles) {
+			data.Error = language.Get("your role requires the two-factor authentication")
+			break
+		}
+		if !checkTwoFactorCode(item, code) {
+			data.Error = language.Get("wrong code")
+			break
+		}
+		if err := tf.Disable(user.Id); err != nil {
+			data.Error = err.Error()
+			break
+		}
+		data.Message = language.Get("the two-factor authentication is disabled")
+	case "recovery":
+		if !checkTwoFactorCode(item, code) {
+			data.Error = language.Get("wrong code")
+			break
+		}
+		codes, hashed, err := newRecoveryCodes()
+		if err != nil {
+			data.Error = err.Error()
+			break
+		}
+		tf.FindByUserId(user.Id).UpdateRecoveryCodes(hashed)
+		data.RecoveryCodes = codes
+	default:
+		data.Error = language.Get("wrong action")
+	}
+
+	h.showTwoFactor(ctx, data)
+}
@@ -0,0 +1,130 @@
+package controller
+
+import (
+	"html/template"
+	"strings"
+
+	"github.com/GoAdminGroup/go-admin/modules/language"
+	"github.com/GoAdminGroup/go-admin/modules/logger"
+)
+
+func executeTwoFactorTmpl(name, tmpl string, data twoFactorPageData) template.HTML {
+	t := template.New(name).Funcs(map[string]interface{}{
+		"lang": language.Get,
+		// the data url of the generated QR code is not escaped by the html/template.
+		"safeURL": func(s string) template.URL { return template.URL(s) },
+	})
+	t, err := t.Parse(tmpl)
+	if err != nil {
+		logger.Error(err)
+		return ""
+	}
+	var sb strings.Builder
+	err = t.Execute(&sb, data)
+	if err != nil {
+		logger.Error(err)
+		return ""
+	}
+	return template.HTML(sb.String())
+}
+
+type twoFactorPageData struct {
+	Title         string
+	AssetUrl      string
+	Url           string
+	Token         string
+	TokenKey      string
+	Enroll        bool
+	Enabled       bool
+	Required      bool
+	QRCode        string
+	Secret        string
+	Error         string
+	Message       string
+	RecoveryLeft  int
+	RecoveryCodes []string
+	ContinueUrl   string
+}
+
+var twoFactorEnrollTmpl = `
+{{define "enroll"}}
+<p>{{lang "scan the QR code with the authenticator app, then enter the code to verify"}}</p>
+{{if .QRCode}}<p><img src="{{safeURL .QRCode}}" alt="QR code" style="width: 200px; height: 200px;"></p>{{end}}
+<p>{{lang "or enter the key manually"}}: <code>{{.Secret}}</code></p>
+{{end}}
+{{define "recovery"}}
+<p>{{lang "save the recovery codes in a safe place, each code can be used once when the authenticator app is not available"}}</p>
+<pre>{{range .RecoveryCodes}}{{.}}
+{{end}}</pre>
+{{end}}
+`
+
+var twoFactorLoginTmpl = `<!DOCTYPE html>
+<html>
+<head>
+	<meta charset="utf-8">
+	<meta http-equiv="X-UA-Compatible" content="IE=edge">
+	<title>{{.Title}}</title>
+	<meta name="viewport" content="width=device-width, initial-scale=1">
+	<link rel="stylesheet" href="{{.AssetUrl}}/assets/login/dist/all.min.css">
+</head>
+<body>
+<div class="container">
+	<div class="row" style="margin-top: 80px;">
+		<div class="col-md-4 col-md-offset-4">
+			<div class="fh5co-form">
+				<h2>{{lang "two-factor authentication"}}</h2>
+				{{if .RecoveryCodes}}
+					{{template "recovery" .}}
+					<a href="{{.ContinueUrl}}" class="btn btn-primary">{{lang "continue"}}</a>
+				{{else}}
+				<form method="post" action="{{.Url}}">
+					<input type="hidden" name="token" value="{{.Token}}">
+					{{if .Enroll}}
+						<p>{{lang "your role requires the two-factor authentication"}}</p>
+						{{template "enroll" .}}
+					{{else}}
+						<p>{{lang "enter the code of the authenticator app or a recovery code"}}</p>
+					{{end}}
+					{{if .Error}}<p class="text-danger">{{.Error}}</p>{{end}}
+					<div class="form-group">
+						<input type="text" class="form-control" name="code" placeholder="{{lang "code"}}"
+							autocomplete="one-time-code" autofocus required>
+					</div>
+					<div class="form-group">
+						<button type="submit" class="btn btn-primary">{{lang "verify"}}</button>
+					</div>
+				</form>
+				{{end}}
+			</div>
+		</div>
+	</div>
+</div>
+</body>
+</html>
+` + twoFactorEnrollTmpl
+
+var twoFactorSettingTmpl = `
+{{if .Error}}<div class="alert alert-warning">{{.Error}}</div>{{end}}
+{{if .Message}}<div class="alert alert-success">{{.Message}}</div>{{end}}
+{{if .RecoveryCodes}}{{template "recovery" .}}{{end}}
+<form method="post" action="{{.Url}}" class="form-inline">
+	<input type="hidden" name="{{.TokenKey}}" value="{{.Token}}">
+	{{if .Enabled}}
+		<p>{{lang "the two-factor authentication is enabled"}}, {{lang "recovery codes left"}}: {{.RecoveryLeft}}</p>
+		{{if .Required}}<p class="text-muted">{{lang "your role requires the two-factor authentication"}}</p>{{end}}
+	{{else}}
+		<input type="hidden" name="secret" value="{{.Secret}}">
+		{{template "enroll" .}}
+	{{end}}
+	<div class="form-group">
+		<input type="text" class="form-control" name="code" placeholder="{{lang "code"}}" autocomplete="one-time-code" required>
+	</div>
+	{{if .Enabled}}
+		<button type="submit" name="action" value="recovery" class="btn btn-default">{{lang "regenerate recovery codes"}}</button>
+		{{if not .Required}}<button type="submit" name="action" value="disable" class="btn btn-danger">{{lang "disable"}}</button>{{end}}
+	{{else}}
+		<button type="submit" name="action" value="enable" class="btn btn-primary">{{lang "enable"}}</button>
+	{{end}}
+</form>
+` + twoFactorEnrollTmpl
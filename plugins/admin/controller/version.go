@@ -0,0 +1,92 @@
+package controller
+
+import (
+	"fmt"
+	"html"
+	"strings"
+
+	"github.com/GoAdminGroup/go-admin/context"
+	"github.com/GoAdminGroup/go-admin/modules/errors"
+	"github.com/GoAdminGroup/go-admin/modules/language"
+	"github.com/GoAdminGroup/go-admin/modules/utils"
+	form2 "github.com/GoAdminGroup/go-admin/plugins/admin/modules/form"
+	"github.com/GoAdminGroup/go-admin/plugins/admin/modules/response"
+	"github.com/GoAdminGroup/go-admin/plugins/admin/modules/table"
+	"github.com/GoAdminGroup/go-admin/template"
+)
+
+// updateErrorAlert return the alert of the error of the update, the conflicting
+// fields are listed for a version conflict.
+func updateErrorAlert(err error) template.HTML {
+	conflict, ok := err.(*table.VersionConflictError)
+	if !ok || len(conflict.Fields) == 0 {
+		return aAlert().Warning(err.Error())
+	}
+
+	var sb strings.Builder
+	sb.WriteString(html.EscapeString(language.Get(err.Error())))
+	sb.WriteString(`<table class="table table-condensed" style="margin: 10px 0 0;"><thead><tr><th>`)
+	sb.WriteString(language.Get("field"))
+	sb.WriteString("</th><th>")
+	sb.WriteString(language.Get("current value"))
+	sb.WriteString("</th><th>")
+	sb.WriteString(language.Get("your value"))
+	sb.WriteString("</th></tr></thead><tbody>")
+	for _, field := range conflict.Fields {
+		sb.WriteString(fmt.Sprintf("<tr><td>%s</td><td>%s</td><td>%s</td></tr>", html.EscapeString(field.Head),
+			html.EscapeString(field.Current), html.EscapeString(field.Submitted)))
+	}
+	sb.WriteString("</tbody></table>")
+
+	return aAlert().SetTitle(errors.MsgWithIcon).
+		SetTheme("warning").
+		SetContent(template.HTML(sb.String())).
+		GetContent()
+}
+
+// updateError responds the error of the update in json, the conflicting fields
+// of a version conflict are added into the data.
+func updateError(ctx *context.Context, err error, data map[string]interface{}) {
+	if conflict, ok := err.(*table.VersionConflictError); ok {
+		if data == nil { data = make(map[string]interface{}) }
+		data["conflict"] = conflict.Fields
+	}
+	if data == nil {
+		response.Error(ctx, err.Error())
+		return
+	}
+	response.Error(ctx, err.Error(), data)
+}
+
+// versionScript attaches the versions of the rows to the inline updates of the
+// table, and keeps the versions of the updated rows.
+func versionScript(updateUrl string, versions map[string]string) template.HTML {
+	if len(versions) == 0 {
+		return ""
+	}
+	url, _         := utils.JsonMarshal(updateUrl)
+	versionJSON, _ := utils.JsonMarshal(versions)
+	return template.HTML(fmt.Sprintf(`<script>
+(function () {
+	window.goAdminVersions = window.goAdminVersions || {};
+	window.goAdminVersions[%s] = %s;
+	if (window.goAdminVersionFilter) return;
+	window.goAdminVersionFilter = true;
+	$.ajaxPrefilter(function (options, originalOptions, jqXHR) {
+		let versions = window.goAdminVersions[options.url];
+		if (!versions || typeof options.data !== "string") return;
+		let pk = new URLSearchParams(options.data).get("pk");
+		if (pk === null || versions[pk] === undefined) return;
+		options.data += "&%s=" + encodeURIComponent(versions[pk]);
+		jqXHR.done(function (data) {
+			if (typeof data === "string") {
+				try { data = JSON.parse(data) } catch (e) { return }
+			}
+			if (data && data.code === 200 && data.data && data.data.version !== undefined) {
+				versions[pk] = data.data.version;
+			}
+		});
+	});
+})();
+</script>`, url, versionJSON, form2.VersionKey))
+}
@@ -0,0 +1,204 @@
+package models
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/GoAdminGroup/go-admin/modules/db"
+	"github.com/GoAdminGroup/go-admin/modules/db/dialect"
+	"github.com/GoAdminGroup/go-admin/modules/utils"
+)
+
+const (
+	AuditActionInsert  = "insert"
+	AuditActionUpdate  = "update"
+	AuditActionDelete  = "delete"
+	AuditActionRestore = "restore"
+	AuditActionPurge   = "purge"
+)
+
+// AuditTableName is the table of the audit records.
+const AuditTableName = "goadmin_audit_trail"
+
+var auditSchema = tableSchema{
+	db.DriverMysql: "CREATE TABLE IF NOT EXISTS `goadmin_audit_trail` (" +
+		"`id` int(10) unsigned NOT NULL AUTO_INCREMENT," +
+		"`user_id` int(10) unsigned NOT NULL DEFAULT 0," +
+		"`table_name` varchar(100) NOT NULL DEFAULT ''," +
+		"`pk` varchar(100) NOT NULL DEFAULT ''," +
+		"`action` varchar(20) NOT NULL DEFAULT ''," +
+		"`diff` text," +
+		"`created_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP," +
+		"PRIMARY KEY (`id`)," +
+		"KEY `goadmin_audit_trail_row` (`table_name`,`pk`)" +
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4",
+	db.DriverPostgresql: `CREATE TABLE IF NOT EXISTS goadmin_audit_trail (
+		id SERIAL PRIMARY KEY,
+		user_id integer NOT NULL DEFAULT 0,
+		table_name character varying(100) NOT NULL DEFAULT '',
+		pk character varying(100) NOT NULL DEFAULT '',
+		action character varying(20) NOT NULL DEFAULT '',
+		diff text,
+		created_at timestamp without time zone DEFAULT now()
+	);
+	CREATE INDEX IF NOT EXISTS goadmin_audit_trail_row ON goadmin_audit_trail (table_name, pk)`,
+	db.DriverSqlite: `CREATE TABLE IF NOT EXISTS goadmin_audit_trail (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		user_id INTEGER NOT NULL DEFAULT 0,
+		table_name TEXT NOT NULL DEFAULT '',
+		pk TEXT NOT NULL DEFAULT '',
+		action TEXT NOT NULL DEFAULT '',
+		diff TEXT,
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+	);
+	CREATE INDEX IF NOT EXISTS goadmin_audit_trail_row ON goadmin_audit_trail (table_name, pk)`,
+	db.DriverMssql: `IF OBJECT_ID(N'goadmin_audit_trail', N'U') IS NULL
+	CREATE TABLE [goadmin_audit_trail] (
+		[id] int IDENTITY(1,1) PRIMARY KEY,
+		[user_id] int NOT NULL DEFAULT 0,
+		[table_name] nvarchar(100) NOT NULL DEFAULT '',
+		[pk] nvarchar(100) NOT NULL DEFAULT '',
+		[action] nvarchar(20) NOT NULL DEFAULT '',
+		[diff] nvarchar(max),
+		[created_at] datetime DEFAULT GETDATE(),
+		INDEX [goadmin_audit_trail_row] ([table_name], [pk])
+	)`,
+}
+
+// AuditChange is the values of a field before and after a write.
+type AuditChange struct {
+	Before interface{} `json:"before"`
+	After  interface{} `json:"after"`
+}
+
+// AuditDiff is the changes of a row keyed by the field.
+type AuditDiff map[string]AuditChange
+
+// NewAuditDiff compares the values of a row before and after a write, the before
+// is nil for an insertion and the after is nil for a deletion. Only the fields of
+// the after are compared when both are given, and the same values are omitted.
+func NewAuditDiff(before, after map[string]interface{}) AuditDiff {
+	diff := make(AuditDiff)
+	if after == nil {
+		for field, value := range before {
+			diff[field] = AuditChange{ Before: value }
+		}
+		return diff
+	}
+	for field, value := range after {
+		old, ok := before[field]
+		if ok && auditValueString(old) == auditValueString(value) {
+			continue
+		}
+		diff[field] = AuditChange{ Before: old, After: value }
+	}
+	return diff
+}
+
+// Fields return the sorted fields of the diff.
+func (d AuditDiff) Fields() []string {
+	fields := make([]string, 0, len(d))
+	for field := range d {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	return fields
+}
+
+// FormatAuditValue return the displayed text of a value of the diff.
+func FormatAuditValue(value interface{}) string {
+	if value == nil { return "-" }
+	return auditValueString(value)
+}
+
+func auditValueString(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case []byte:
+		return string(v)
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
+
+// AuditModel is audit model structure.
+type AuditModel struct {
+	Base
+
+	Id        int64
+	UserId    int64
+	DataTable string
+	Pk        string
+	Action    string
+	Diff      string
+	CreatedAt string
+}
+
+// Audit return a default audit model.
+func Audit() AuditModel {
+	return AuditModel{Base: Base{TableName: AuditTableName}}
+}
+
+func (t AuditModel) SetConn(con db.Connection) AuditModel {
+	t.Conn = con
+	return t
+}
+
+// Init applies the migration of the table of the audit records if it is pending.
+func (t AuditModel) Init() error {
+	return migrate(t.Conn, migrationAuditTrail)
+}
+
+// Record add an audit record of the row of the table.
+func (t AuditModel) Record(userId int64, table, pk, action string, diff AuditDiff) error {
+	diffByte, err := utils.JsonMarshal(diff)
+	if err != nil { return err }
+
+	_, err = t.Table(t.TableName).Insert(dialect.H{
+		"user_id"   : userId,
+		"table_name": table,
+		"pk"        : pk,
+		"action"    : action,
+		"diff"      : string(diffByte),
+	})
+	if db.CheckError(err, db.INSERT) {
+		return err
+	}
+	return nil
+}
+
+// GetByRow return the latest audit records of the row of the table.
+func (t AuditModel) GetByRow(table, pk string, limit int) []AuditModel {
+	items, _ := t.Table(t.TableName).
+		Where("table_name", "=", table).
+		Where("pk", "=", pk).
+		OrderBy("id", "desc").
+		Take(limit).
+		All()
+
+	records := make([]AuditModel, len(items))
+	for i, item := range items {
+		records[i] = t.MapToModel(item)
+	}
+	return records
+}
+
+// GetDiff return the unmarshalled diff of the record.
+func (t AuditModel) GetDiff() AuditDiff {
+	var diff AuditDiff
+	_ = utils.JsonUnmarshal([]byte(t.Diff), &diff)
+	return diff
+}
+
+// MapToModel get the audit model from given map.
+func (t AuditModel) MapToModel(m map[string]interface{}) AuditModel {
+	t.Id, _ = m["id"].(int64)
+	t.UserId, _ = m["user_id"].(int64)
+	t.DataTable, _ = m["table_name"].(string)
+	t.Pk, _ = m["pk"].(string)
+	t.Action, _ = m["action"].(string)
+	t.Diff, _ = m["diff"].(string)
+	t.CreatedAt, _ = m["created_at"].(string)
+	return t
+}
@@ -0,0 +1,460 @@
+package models
+
+import (
+	"github.com/GoAdminGroup/go-admin/modules/db"
+)
+
+// The schemas of the core tables, which were created by the install sql before
+// the migrations. The baseline migrations create them in the new databases and
+// are recorded without changes in the existing ones.
+
+var usersSchema = tableSchema{
+	db.DriverMysql: "CREATE TABLE IF NOT EXISTS `goadmin_users` (" +
+		"`id` int(10) unsigned NOT NULL AUTO_INCREMENT," +
+		"`username` varchar(100) NOT NULL," +
+		"`password` varchar(100) NOT NULL DEFAULT ''," +
+		"`name` varchar(100) NOT NULL," +
+		"`avatar` varchar(255) DEFAULT NULL," +
+		"`remember_token` varchar(100) DEFAULT NULL," +
+		"`created_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP," +
+		"`updated_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP," +
+		"PRIMARY KEY (`id`)," +
+		"UNIQUE KEY `goadmin_users_username_unique` (`username`)" +
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4",
+	db.DriverPostgresql: `CREATE TABLE IF NOT EXISTS goadmin_users (
+		id SERIAL PRIMARY KEY,
+		username character varying(100) NOT NULL,
+		password character varying(100) NOT NULL DEFAULT '',
+		name character varying(100) NOT NULL,
+		avatar character varying(255),
+		remember_token character varying(100),
+		created_at timestamp without time zone DEFAULT now(),
+		updated_at timestamp without time zone DEFAULT now(),
+		CONSTRAINT goadmin_users_username_unique UNIQUE (username)
+	)`,
+	db.DriverSqlite: `CREATE TABLE IF NOT EXISTS goadmin_users (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		username TEXT NOT NULL,
+		password TEXT NOT NULL DEFAULT '',
+		name TEXT NOT NULL,
+		avatar TEXT,
+		remember_token TEXT,
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+		updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+		CONSTRAINT goadmin_users_username_unique UNIQUE (username)
+	)`,
+	db.DriverMssql: `IF OBJECT_ID(N'goadmin_users', N'U') IS NULL
+	CREATE TABLE [goadmin_users] (
+		[id] int IDENTITY(1,1) PRIMARY KEY,
+		[username] nvarchar(100) NOT NULL,
+		[password] nvarchar(100) NOT NULL DEFAULT '',
+		[name] nvarchar(100) NOT NULL,
+		[avatar] nvarchar(255),
+		[remember_token] nvarchar(100),
+		[created_at] datetime DEFAULT GETDATE(),
+		[updated_at] datetime DEFAULT GETDATE(),
+		CONSTRAINT [goadmin_users_username_unique] UNIQUE ([username])
+	)`,
+}
+
+var rolesSchema = tableSchema{
+	db.DriverMysql: "CREATE TABLE IF NOT EXISTS `goadmin_roles` (" +
+		"`id` int(10) unsigned NOT NULL AUTO_INCREMENT," +
+		"`name` varchar(50) NOT NULL," +
+		"`slug` varchar(50) NOT NULL," +
+		"`created_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP," +
+		"`updated_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP," +
+		"PRIMARY KEY (`id`)," +
+		"UNIQUE KEY `goadmin_roles_name_unique` (`name`)" +
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4",
+	db.DriverPostgresql: `CREATE TABLE IF NOT EXISTS goadmin_roles (
+		id SERIAL PRIMARY KEY,
+		name character varying(50) NOT NULL,
+		slug character varying(50) NOT NULL,
+		created_at timestamp without time zone DEFAULT now(),
+		updated_at timestamp without time zone DEFAULT now(),
+		CONSTRAINT goadmin_roles_name_unique UNIQUE (name)
+	)`,
+	db.DriverSqlite: `CREATE TABLE IF NOT EXISTS goadmin_roles (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		name TEXT NOT NULL,
+		slug TEXT NOT NULL,
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+		updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+		CONSTRAINT goadmin_roles_name_unique UNIQUE (name)
+	)`,
+	db.DriverMssql: `IF OBJECT_ID(N'goadmin_roles', N'U') IS NULL
+	CREATE TABLE [goadmin_roles] (
+		[id] int IDENTITY(1,1) PRIMARY KEY,
+		[name] nvarchar(50) NOT NULL,
+		[slug] nvarchar(50) NOT NULL,
+		[created_at] datetime DEFAULT GETDATE(),
+		[updated_at] datetime DEFAULT GETDATE(),
+		CONSTRAINT [goadmin_roles_name_unique] UNIQUE ([name])
+	)`,
+}
+
+var permissionsSchema = tableSchema{
+	db.DriverMysql: "CREATE TABLE IF NOT EXISTS `goadmin_permissions` (" +
+		"`id` int(10) unsigned NOT NULL AUTO_INCREMENT," +
+		"`name` varchar(50) NOT NULL," +
+		"`slug` varchar(50) NOT NULL," +
+		"`http_method` varchar(255) DEFAULT NULL," +
+		"`http_path` text NOT NULL," +
+		"`created_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP," +
+		"`updated_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP," +
+		"PRIMARY KEY (`id`)," +
+		"UNIQUE KEY `goadmin_permissions_name_unique` (`name`)" +
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4",
+	db.DriverPostgresql: `CREATE TABLE IF NOT EXISTS goadmin_permissions (
+		id SERIAL PRIMARY KEY,
+		name character varying(50) NOT NULL,
+		slug character varying(50) NOT NULL,
+		http_method character varying(255),
+		http_path text NOT NULL,
+		created_at timestamp without time zone DEFAULT now(),
+		updated_at timestamp without time zone DEFAULT now(),
+		CONSTRAINT goadmin_permissions_name_unique UNIQUE (name)
+	)`,
+	db.DriverSqlite: `CREATE TABLE IF NOT EXISTS goadmin_permissions (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		name TEXT NOT NULL,
+		slug TEXT NOT NULL,
+		http_method TEXT,
+		http_path TEXT NOT NULL,
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+		updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+		CONSTRAINT goadmin_permissions_name_unique UNIQUE (name)
+	)`,
+	db.DriverMssql: `IF OBJECT_ID(N'goadmin_permissions', N'U') IS NULL
+	CREATE TABLE [goadmin_permissions] (
+		[id] int IDENTITY(1,1) PRIMARY KEY,
+		[name] nvarchar(50) NOT NULL,
+		[slug] nvarchar(50) NOT NULL,
+		[http_method] nvarchar(255),
+		[http_path] nvarchar(max) NOT NULL,
+		[created_at] datetime DEFAULT GETDATE(),
+		[updated_at] datetime DEFAULT GETDATE(),
+		CONSTRAINT [goadmin_permissions_name_unique] UNIQUE ([name])
+	)`,
+}
+
+var menuSchema = tableSchema{
+	db.DriverMysql: "CREATE TABLE IF NOT EXISTS `goadmin_menu` (" +
+		"`id` int(10) unsigned NOT NULL AUTO_INCREMENT," +
+		"`parent_id` int(11) unsigned NOT NULL DEFAULT 0," +
+		"`type` int(11) unsigned NOT NULL DEFAULT 0," +
+		"`order` int(11) unsigned NOT NULL DEFAULT 0," +
+		"`title` varchar(50) NOT NULL," +
+		"`icon` varchar(50) NOT NULL," +
+		"`uri` varchar(3000) NOT NULL DEFAULT ''," +
+		"`header` varchar(150) DEFAULT NULL," +
+		"`plugin_name` varchar(150) NOT NULL DEFAULT ''," +
+		"`uuid` varchar(150) DEFAULT NULL," +
+		"`created_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP," +
+		"`updated_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP," +
+		"PRIMARY KEY (`id`)" +
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4",
+	db.DriverPostgresql: `CREATE TABLE IF NOT EXISTS goadmin_menu (
+		id SERIAL PRIMARY KEY,
+		parent_id integer NOT NULL DEFAULT 0,
+		type integer NOT NULL DEFAULT 0,
+		"order" integer NOT NULL DEFAULT 0,
+		title character varying(50) NOT NULL,
+		icon character varying(50) NOT NULL,
+		uri character varying(3000) NOT NULL DEFAULT '',
+		header character varying(150),
+		plugin_name character varying(150) NOT NULL DEFAULT '',
+		uuid character varying(150),
+		created_at timestamp without time zone DEFAULT now(),
+		updated_at timestamp without time zone DEFAULT now()
+	)`,
+	db.DriverSqlite: `CREATE TABLE IF NOT EXISTS goadmin_menu (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		parent_id INTEGER NOT NULL DEFAULT 0,
+		type INTEGER NOT NULL DEFAULT 0,
+		"order" INTEGER NOT NULL DEFAULT 0,
+		title TEXT NOT NULL,
+		icon TEXT NOT NULL,
+		uri TEXT NOT NULL DEFAULT '',
+		header TEXT,
+		plugin_name TEXT NOT NULL DEFAULT '',
+		uuid TEXT,
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+		updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+	)`,
+	db.DriverMssql: `IF OBJECT_ID(N'goadmin_menu', N'U') IS NULL
+	CREATE TABLE [goadmin_menu] (
+		[id] int IDENTITY(1,1) PRIMARY KEY,
+		[parent_id] int NOT NULL DEFAULT 0,
+		[type] int NOT NULL DEFAULT 0,
+		[order] int NOT NULL DEFAULT 0,
+		[title] nvarchar(50) NOT NULL,
+		[icon] nvarchar(50) NOT NULL,
+		[uri] nvarchar(3000) NOT NULL DEFAULT '',
+		[header] nvarchar(150),
+		[plugin_name] nvarchar(150) NOT NULL DEFAULT '',
+		[uuid] nvarchar(150),
+		[created_at] datetime DEFAULT GETDATE(),
+		[updated_at] datetime DEFAULT GETDATE()
+	)`,
+}
+
+var roleMenuSchema = tableSchema{
+	db.DriverMysql: "CREATE TABLE IF NOT EXISTS `goadmin_role_menu` (" +
+		"`role_id` int(11) unsigned NOT NULL," +
+		"`menu_id` int(11) unsigned NOT NULL," +
+		"`created_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP," +
+		"`updated_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP," +
+		"KEY `goadmin_role_menu_role_id_menu_id_index` (`role_id`,`menu_id`)" +
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4",
+	db.DriverPostgresql: `CREATE TABLE IF NOT EXISTS goadmin_role_menu (
+		role_id integer NOT NULL,
+		menu_id integer NOT NULL,
+		created_at timestamp without time zone DEFAULT now(),
+		updated_at timestamp without time zone DEFAULT now()
+	);
+	CREATE INDEX IF NOT EXISTS goadmin_role_menu_role_id_menu_id_index ON goadmin_role_menu (role_id, menu_id)`,
+	db.DriverSqlite: `CREATE TABLE IF NOT EXISTS goadmin_role_menu (
+		role_id INTEGER NOT NULL,
+		menu_id INTEGER NOT NULL,
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+		updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+	);
+	CREATE INDEX IF NOT EXISTS goadmin_role_menu_role_id_menu_id_index ON goadmin_role_menu (role_id, menu_id)`,
+	db.DriverMssql: `IF OBJECT_ID(N'goadmin_role_menu', N'U') IS NULL
+	BEGIN
+	CREATE TABLE [goadmin_role_menu] (
+		[role_id] int NOT NULL,
+		[menu_id] int NOT NULL,
+		[created_at] datetime DEFAULT GETDATE(),
+		[updated_at] datetime DEFAULT GETDATE()
+	);
+	CREATE INDEX [goadmin_role_menu_role_id_menu_id_index] ON [goadmin_role_menu] ([role_id], [menu_id]);
+	END`,
+}
+
+var rolePermissionsSchema = tableSchema{
+	db.DriverMysql: "CREATE TABLE IF NOT EXISTS `goadmin_role_permissions` (" +
+		"`role_id` int(11) unsigned NOT NULL," +
+		"`permission_id` int(11) unsigned NOT NULL," +
+		"`created_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP," +
+		"`updated_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP," +
+		"UNIQUE KEY `goadmin_role_permissions_unique` (`role_id`,`permission_id`)" +
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4",
+	db.DriverPostgresql: `CREATE TABLE IF NOT EXISTS goadmin_role_permissions (
+		role_id integer NOT NULL,
+		permission_id integer NOT NULL,
+		created_at timestamp without time zone DEFAULT now(),
+		updated_at timestamp without time zone DEFAULT now(),
+		CONSTRAINT goadmin_role_permissions_unique UNIQUE (role_id, permission_id)
+	)`,
+	db.DriverSqlite: `CREATE TABLE IF NOT EXISTS goadmin_role_permissions (
+		role_id INTEGER NOT NULL,
+		permission_id INTEGER NOT NULL,
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+		updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+		CONSTRAINT goadmin_role_permissions_unique UNIQUE (role_id, permission_id)
+	)`,
+	db.DriverMssql: `IF OBJECT_ID(N'goadmin_role_permissions', N'U') IS NULL
+	CREATE TABLE [goadmin_role_permissions] (
+		[role_id] int NOT NULL,
+		[permission_id] int NOT NULL,
+		[created_at] datetime DEFAULT GETDATE(),
+		[updated_at] datetime DEFAULT GETDATE(),
+		CONSTRAINT [goadmin_role_permissions_unique] UNIQUE ([role_id], [permission_id])
+	)`,
+}
+
+var roleUsersSchema = tableSchema{
+	db.DriverMysql: "CREATE TABLE IF NOT EXISTS `goadmin_role_users` (" +
+		"`role_id` int(11) unsigned NOT NULL," +
+		"`user_id` int(11) unsigned NOT NULL," +
+		"`created_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP," +
+		"`updated_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP," +
+		"UNIQUE KEY `goadmin_role_users_unique` (`role_id`,`user_id`)" +
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4",
+	db.DriverPostgresql: `CREATE TABLE IF NOT EXISTS goadmin_role_users (
+		role_id integer NOT NULL,
+		user_id integer NOT NULL,
+		created_at timestamp without time zone DEFAULT now(),
+		updated_at timestamp without time zone DEFAULT now(),
+		CONSTRAINT goadmin_role_users_unique UNIQUE (role_id, user_id)
+	)`,
+	db.DriverSqlite: `CREATE TABLE IF NOT EXISTS goadmin_role_users (
+		role_id INTEGER NOT NULL,
+		user_id INTEGER NOT NULL,
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+		updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+		CONSTRAINT goadmin_role_users_unique UNIQUE (role_id, user_id)
+	)`,
+	db.DriverMssql: `IF OBJECT_ID(N'goadmin_role_users', N'U') IS NULL
+	CREATE TABLE [goadmin_role_users] (
+		[role_id] int NOT NULL,
+		[user_id] int NOT NULL,
+		[created_at] datetime DEFAULT GETDATE(),
+		[updated_at] datetime DEFAULT GETDATE(),
+		CONSTRAINT [goadmin_role_users_unique] UNIQUE ([role_id], [user_id])
+	)`,
+}
+
+var userPermissionsSchema = tableSchema{
+	db.DriverMysql: "CREATE TABLE IF NOT EXISTS `goadmin_user_permissions` (" +
+		"`user_id` int(11) unsigned NOT NULL," +
+		"`permission_id` int(11) unsigned NOT NULL," +
+		"`created_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP," +
+		"`updated_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP," +
+		"UNIQUE KEY `goadmin_user_permissions_unique` (`user_id`,`permission_id`)" +
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4",
+	db.DriverPostgresql: `CREATE TABLE IF NOT EXISTS goadmin_user_permissions (
+		user_id integer NOT NULL,
+		permission_id integer NOT NULL,
+		created_at timestamp without time zone DEFAULT now(),
+		updated_at timestamp without time zone DEFAULT now(),
+		CONSTRAINT goadmin_user_permissions_unique UNIQUE (user_id, permission_id)
+	)`,
+	db.DriverSqlite: `CREATE TABLE IF NOT EXISTS goadmin_user_permissions (
+		user_id INTEGER NOT NULL,
+		permission_id INTEGER NOT NULL,
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+		updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+		CONSTRAINT goadmin_user_permissions_unique UNIQUE (user_id, permission_id)
+	)`,
+	db.DriverMssql: `IF OBJECT_ID(N'goadmin_user_permissions', N'U') IS NULL
+	CREATE TABLE [goadmin_user_permissions] (
+		[user_id] int NOT NULL,
+		[permission_id] int NOT NULL,
+		[created_at] datetime DEFAULT GETDATE(),
+		[updated_at] datetime DEFAULT GETDATE(),
+		CONSTRAINT [goadmin_user_permissions_unique] UNIQUE ([user_id], [permission_id])
+	)`,
+}
+
+var operationLogSchema = tableSchema{
+	db.DriverMysql: "CREATE TABLE IF NOT EXISTS `goadmin_operation_log` (" +
+		"`id` int(10) unsigned NOT NULL AUTO_INCREMENT," +
+		"`user_id` int(11) unsigned NOT NULL," +
+		"`path` varchar(255) NOT NULL," +
+		"`method` varchar(10) NOT NULL," +
+		"`ip` varchar(255) NOT NULL," +
+		"`input` text NOT NULL," +
+		"`created_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP," +
+		"`updated_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP," +
+		"PRIMARY KEY (`id`)," +
+		"KEY `goadmin_operation_log_user_id_index` (`user_id`)" +
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4",
+	db.DriverPostgresql: `CREATE TABLE IF NOT EXISTS goadmin_operation_log (
+		id SERIAL PRIMARY KEY,
+		user_id integer NOT NULL,
+		path character varying(255) NOT NULL,
+		method character varying(10) NOT NULL,
+		ip character varying(255) NOT NULL,
+		input text NOT NULL,
+		created_at timestamp without time zone DEFAULT now(),
+		updated_at timestamp without time zone DEFAULT now()
+	);
+	CREATE INDEX IF NOT EXISTS goadmin_operation_log_user_id_index ON goadmin_operation_log (user_id)`,
+	db.DriverSqlite: `CREATE TABLE IF NOT EXISTS goadmin_operation_log (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		user_id INTEGER NOT NULL,
+		path TEXT NOT NULL,
+		method TEXT NOT NULL,
+		ip TEXT NOT NULL,
+		input TEXT NOT NULL,
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+		updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+	);
+	CREATE INDEX IF NOT EXISTS goadmin_operation_log_user_id_index ON goadmin_operation_log (user_id)`,
+	db.DriverMssql: `IF OBJECT_ID(N'goadmin_operation_log', N'U') IS NULL
+	BEGIN
+	CREATE TABLE [goadmin_operation_log] (
+		[id] int IDENTITY(1,1) PRIMARY KEY,
+		[user_id] int NOT NULL,
+		[path] nvarchar(255) NOT NULL,
+		[method] nvarchar(10) NOT NULL,
+		[ip] nvarchar(255) NOT NULL,
+		[input] nvarchar(max) NOT NULL,
+		[created_at] datetime DEFAULT GETDATE(),
+		[updated_at] datetime DEFAULT GETDATE()
+	);
+	CREATE INDEX [goadmin_operation_log_user_id_index] ON [goadmin_operation_log] ([user_id]);
+	END`,
+}
+
+var sessionSchema = tableSchema{
+	db.DriverMysql: "CREATE TABLE IF NOT EXISTS `goadmin_session` (" +
+		"`id` int(10) unsigned NOT NULL AUTO_INCREMENT," +
+		"`sid` varchar(50) NOT NULL DEFAULT ''," +
+		"`values` varchar(3000) NOT NULL DEFAULT ''," +
+		"`created_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP," +
+		"`updated_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP," +
+		"PRIMARY KEY (`id`)" +
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4",
+	db.DriverPostgresql: `CREATE TABLE IF NOT EXISTS goadmin_session (
+		id SERIAL PRIMARY KEY,
+		sid character varying(50) NOT NULL DEFAULT '',
+		"values" character varying(3000) NOT NULL DEFAULT '',
+		created_at timestamp without time zone DEFAULT now(),
+		updated_at timestamp without time zone DEFAULT now()
+	)`,
+	db.DriverSqlite: `CREATE TABLE IF NOT EXISTS goadmin_session (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		sid TEXT NOT NULL DEFAULT '',
+		"values" TEXT NOT NULL DEFAULT '',
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+		updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+	)`,
+	db.DriverMssql: `IF OBJECT_ID(N'goadmin_session', N'U') IS NULL
+	CREATE TABLE [goadmin_session] (
+		[id] int IDENTITY(1,1) PRIMARY KEY,
+		[sid] nvarchar(50) NOT NULL DEFAULT '',
+		[values] nvarchar(3000) NOT NULL DEFAULT '',
+		[created_at] datetime DEFAULT GETDATE(),
+		[updated_at] datetime DEFAULT GETDATE()
+	)`,
+}
+
+var siteSchema = tableSchema{
+	db.DriverMysql: "CREATE TABLE IF NOT EXISTS `goadmin_site` (" +
+		"`id` int(10) unsigned NOT NULL AUTO_INCREMENT," +
+		"`key` varchar(100) DEFAULT NULL," +
+		"`value` longtext," +
+		"`type` int(11) unsigned NOT NULL DEFAULT 0," +
+		"`description` varchar(3000) DEFAULT NULL," +
+		"`state` int(11) unsigned NOT NULL DEFAULT 0," +
+		"`created_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP," +
+		"`updated_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP," +
+		"PRIMARY KEY (`id`)" +
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4",
+	db.DriverPostgresql: `CREATE TABLE IF NOT EXISTS goadmin_site (
+		id SERIAL PRIMARY KEY,
+		"key" character varying(100),
+		"value" text,
+		type integer NOT NULL DEFAULT 0,
+		description character varying(3000),
+		state integer NOT NULL DEFAULT 0,
+		created_at timestamp without time zone DEFAULT now(),
+		updated_at timestamp without time zone DEFAULT now()
+	)`,
+	db.DriverSqlite: `CREATE TABLE IF NOT EXISTS goadmin_site (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		"key" TEXT,
+		"value" TEXT,
+		type INTEGER NOT NULL DEFAULT 0,
+		description TEXT,
+		state INTEGER NOT NULL DEFAULT 0,
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+		updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+	)`,
+	db.DriverMssql: `IF OBJECT_ID(N'goadmin_site', N'U') IS NULL
+	CREATE TABLE [goadmin_site] (
+		[id] int IDENTITY(1,1) PRIMARY KEY,
+		[key] nvarchar(100),
+		[value] nvarchar(max),
+		[type] int NOT NULL DEFAULT 0,
+		[description] nvarchar(3000),
+		[state] int NOT NULL DEFAULT 0,
+		[created_at] datetime DEFAULT GETDATE(),
+		[updated_at] datetime DEFAULT GETDATE()
+	)`,
+}
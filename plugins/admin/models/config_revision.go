@@ -0,0 +1,141 @@
+package models
+
+import (
+	"github.com/GoAdminGroup/go-admin/modules/db"
+	"github.com/GoAdminGroup/go-admin/modules/db/dialect"
+	"github.com/GoAdminGroup/go-admin/modules/utils"
+)
+
+const (
+	ConfigRevisionActionUpdate   = "update"
+	ConfigRevisionActionRollback = "rollback"
+)
+
+// ConfigRevisionTableName is the table of the revisions of the site config.
+const ConfigRevisionTableName = "goadmin_config_revisions"
+
+var configRevisionSchema = tableSchema{
+	db.DriverMysql: "CREATE TABLE IF NOT EXISTS `goadmin_config_revisions` (" +
+		"`id` int(10) unsigned NOT NULL AUTO_INCREMENT," +
+		"`user_id` int(10) unsigned NOT NULL DEFAULT 0," +
+		"`action` varchar(20) NOT NULL DEFAULT ''," +
+		"`target_id` int(10) unsigned NOT NULL DEFAULT 0," +
+		"`diff` text," +
+		"`created_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP," +
+		"PRIMARY KEY (`id`)" +
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4",
+	db.DriverPostgresql: `CREATE TABLE IF NOT EXISTS goadmin_config_revisions (
+		id SERIAL PRIMARY KEY,
+		user_id integer NOT NULL DEFAULT 0,
+		action character varying(20) NOT NULL DEFAULT '',
+		target_id integer NOT NULL DEFAULT 0,
+		diff text,
+		created_at timestamp without time zone DEFAULT now()
+	)`,
+	db.DriverSqlite: `CREATE TABLE IF NOT EXISTS goadmin_config_revisions (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		user_id INTEGER NOT NULL DEFAULT 0,
+		action TEXT NOT NULL DEFAULT '',
+		target_id INTEGER NOT NULL DEFAULT 0,
+		diff TEXT,
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+	)`,
+	db.DriverMssql: `IF OBJECT_ID(N'goadmin_config_revisions', N'U') IS NULL
+	CREATE TABLE [goadmin_config_revisions] (
+		[id] int IDENTITY(1,1) PRIMARY KEY,
+		[user_id] int NOT NULL DEFAULT 0,
+		[action] nvarchar(20) NOT NULL DEFAULT '',
+		[target_id] int NOT NULL DEFAULT 0,
+		[diff] nvarchar(max),
+		[created_at] datetime DEFAULT GETDATE()
+	)`,
+}
+
+// ConfigRevisionModel is a revision of the site config, the diff keeps the values
+// of the changed keys before and after the revision. The target is the rolled
+// back revision of a rollback.
+type ConfigRevisionModel struct {
+	Base
+
+	Id        int64
+	UserId    int64
+	Action    string
+	TargetId  int64
+	Diff      string
+	CreatedAt string
+}
+
+// ConfigRevision return a default config revision model.
+func ConfigRevision() ConfigRevisionModel {
+	return ConfigRevisionModel{Base: Base{TableName: ConfigRevisionTableName}}
+}
+
+func (t ConfigRevisionModel) SetConn(con db.Connection) ConfigRevisionModel {
+	t.Conn = con
+	return t
+}
+
+// Init applies the migration of the table of the config revisions if it is pending.
+func (t ConfigRevisionModel) Init() error {
+	return migrate(t.Conn, migrationConfigRevisions)
+}
+
+// Record add a revision of the changed site config.
+func (t ConfigRevisionModel) Record(userId int64, action string, targetId int64, diff AuditDiff) error {
+	diffByte, err := utils.JsonMarshal(diff)
+	if err != nil { return err }
+
+	_, err = t.Table(t.TableName).Insert(dialect.H{
+		"user_id"  : userId,
+		"action"   : action,
+		"target_id": targetId,
+		"diff"     : string(diffByte),
+	})
+	if db.CheckError(err, db.INSERT) {
+		return err
+	}
+	return nil
+}
+
+// Find return the revision of the id.
+func (t ConfigRevisionModel) Find(id interface{}) ConfigRevisionModel {
+	item, _ := t.Table(t.TableName).Find(id)
+	return t.MapToModel(item)
+}
+
+// IsEmpty check the revision model is empty or not.
+func (t ConfigRevisionModel) IsEmpty() bool {
+	return t.Id == int64(0)
+}
+
+// GetAfter return the revisions after the id, the latest first.
+func (t ConfigRevisionModel) GetAfter(id int64) []ConfigRevisionModel {
+	items, _ := t.Table(t.TableName).
+		Where("id", ">", id).
+		OrderBy("id", "desc").
+		All()
+
+	revisions := make([]ConfigRevisionModel, len(items))
+	for i, item := range items {
+		revisions[i] = t.MapToModel(item)
+	}
+	return revisions
+}
+
+// GetDiff return the unmarshalled diff of the revision.
+func (t ConfigRevisionModel) GetDiff() AuditDiff {
+	var diff AuditDiff
+	_ = utils.JsonUnmarshal([]byte(t.Diff), &diff)
+	return diff
+}
+
+// MapToModel get the config revision model from given map.
+func (t ConfigRevisionModel) MapToModel(m map[string]interface{}) ConfigRevisionModel {
+	t.Id, _ = m["id"].(int64)
+	t.UserId, _ = m["user_id"].(int64)
+	t.Action, _ = m["action"].(string)
+	t.TargetId, _ = m["target_id"].(int64)
+	t.Diff, _ = m["diff"].(string)
+	t.CreatedAt, _ = m["created_at"].(string)
+	return t
+}
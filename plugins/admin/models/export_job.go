@@ -0,0 +1,290 @@
+package models
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/GoAdminGroup/go-admin/modules/db"
+	"github.com/GoAdminGroup/go-admin/modules/db/dialect"
+	"github.com/GoAdminGroup/go-admin/modules/migration"
+	"github.com/GoAdminGroup/go-admin/modules/utils"
+)
+
+const (
+	ExportJobStatusPending = "pending"
+	ExportJobStatusRunning = "running"
+	ExportJobStatusDone    = "done"
+	ExportJobStatusFailed  = "failed"
+	ExportJobStatusExpired = "expired"
+)
+
+var exportJobSchema = tableSchema{
+	db.DriverMysql: "CREATE TABLE IF NOT EXISTS `goadmin_export_jobs` (" +
+		"`id` int(10) unsigned NOT NULL AUTO_INCREMENT," +
+		"`user_id` int(10) unsigned NOT NULL," +
+		"`prefix` varchar(100) NOT NULL DEFAULT ''," +
+		"`format` varchar(20) NOT NULL DEFAULT ''," +
+		"`url` varchar(255) NOT NULL DEFAULT ''," +
+		"`status` varchar(20) NOT NULL DEFAULT 'pending'," +
+		"`processed` int(10) unsigned NOT NULL DEFAULT 0," +
+		"`file_path` varchar(255) NOT NULL DEFAULT ''," +
+		"`message` text," +
+		"`expired_at` bigint(20) NOT NULL DEFAULT 0," +
+		"`created_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP," +
+		"`updated_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP," +
+		"PRIMARY KEY (`id`)," +
+		"KEY `goadmin_export_jobs_user_id` (`user_id`)" +
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4",
+	db.DriverPostgresql: `CREATE TABLE IF NOT EXISTS goadmin_export_jobs (
+		id SERIAL PRIMARY KEY,
+		user_id integer NOT NULL,
+		prefix character varying(100) NOT NULL DEFAULT '',
+		format character varying(20) NOT NULL DEFAULT '',
+		url character varying(255) NOT NULL DEFAULT '',
+		status character varying(20) NOT NULL DEFAULT 'pending',
+		processed integer NOT NULL DEFAULT 0,
+		file_path character varying(255) NOT NULL DEFAULT '',
+		message text,
+		expired_at bigint NOT NULL DEFAULT 0,
+		created_at timestamp without time zone DEFAULT now(),
+		updated_at timestamp without time zone DEFAULT now()
+	)`,
+	db.DriverSqlite: `CREATE TABLE IF NOT EXISTS goadmin_export_jobs (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		user_id INTEGER NOT NULL,
+		prefix TEXT NOT NULL DEFAULT '',
+		format TEXT NOT NULL DEFAULT '',
+		url TEXT NOT NULL DEFAULT '',
+		status TEXT NOT NULL DEFAULT 'pending',
+		processed INTEGER NOT NULL DEFAULT 0,
+		file_path TEXT NOT NULL DEFAULT '',
+		message TEXT,
+		expired_at INTEGER NOT NULL DEFAULT 0,
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+		updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+	)`,
+	db.DriverMssql: `IF OBJECT_ID(N'goadmin_export_jobs', N'U') IS NULL
+	CREATE TABLE [goadmin_export_jobs] (
+		[id] int IDENTITY(1,1) PRIMARY KEY,
+		[user_id] int NOT NULL,
+		[prefix] nvarchar(100) NOT NULL DEFAULT '',
+		[format] nvarchar(20) NOT NULL DEFAULT '',
+		[url] nvarchar(255) NOT NULL DEFAULT '',
+		[status] nvarchar(20) NOT NULL DEFAULT 'pending',
+		[processed] int NOT NULL DEFAULT 0,
+		[file_path] nvarchar(255) NOT NULL DEFAULT '',
+		[message] nvarchar(max),
+		[expired_at] bigint NOT NULL DEFAULT 0,
+		[created_at] datetime DEFAULT GETDATE(),
+		[updated_at] datetime DEFAULT GETDATE()
+	)`,
+}
+
+// exportJobsMenuMigration adds the menu item of the export jobs under the admin
+// menu, the item is not added twice.
+var exportJobsMenuMigration = migration.Migration{
+	Version: migrationExportJobsMenu,
+	Name   : "add the export jobs menu",
+	Up     : map[string]string{
+		db.DriverMysql: "INSERT INTO `goadmin_menu` (`parent_id`, `type`, `order`, `title`, `icon`, `uri`, `header`, `plugin_name`) " +
+			"SELECT COALESCE((SELECT MIN(`id`) FROM `goadmin_menu` WHERE `parent_id` = 0 AND `title` = 'Admin'), 0), " +
+			"1, 8, 'Export Jobs', 'fa-download', '/info/export_jobs', '', '' FROM DUAL " +
+			"WHERE NOT EXISTS (SELECT 1 FROM `goadmin_menu` WHERE `uri` = '/info/export_jobs')",
+		db.DriverPostgresql: `INSERT INTO goadmin_menu (parent_id, type, "order", title, icon, uri, header, plugin_name)
+	SELECT COALESCE((SELECT MIN(id) FROM goadmin_menu WHERE parent_id = 0 AND title = 'Admin'), 0),
+		1, 8, 'Export Jobs', 'fa-download', '/info/export_jobs', '', ''
+	WHERE NOT EXISTS (SELECT 1 FROM goadmin_menu WHERE uri = '/info/export_jobs')`,
+		db.DriverSqlite: `INSERT INTO goadmin_menu (parent_id, type, "order", title, icon, uri, header, plugin_name)
+	SELECT COALESCE((SELECT MIN(id) FROM goadmin_menu WHERE parent_id = 0 AND title = 'Admin'), 0),
+		1, 8, 'Export Jobs', 'fa-download', '/info/export_jobs', '', ''
+	WHERE NOT EXISTS (SELECT 1 FROM goadmin_menu WHERE uri = '/info/export_jobs')`,
+		db.DriverMssql: `INSERT INTO [goadmin_menu] ([parent_id], [type], [order], [title], [icon], [uri], [header], [plugin_name])
+	SELECT COALESCE((SELECT MIN([id]) FROM [goadmin_menu] WHERE [parent_id] = 0 AND [title] = 'Admin'), 0),
+		1, 8, 'Export Jobs', 'fa-download', '/info/export_jobs', '', ''
+	WHERE NOT EXISTS (SELECT 1 FROM [goadmin_menu] WHERE [uri] = '/info/export_jobs')`,
+	},
+	Down   : map[string]string{
+		db.DriverMysql     : "DELETE FROM `goadmin_menu` WHERE `uri` = '/info/export_jobs'",
+		db.DriverPostgresql: "DELETE FROM goadmin_menu WHERE uri = '/info/export_jobs'",
+		db.DriverSqlite    : "DELETE FROM goadmin_menu WHERE uri = '/info/export_jobs'",
+		db.DriverMssql     : "DELETE FROM [goadmin_menu] WHERE [uri] = '/info/export_jobs'",
+	},
+}
+
+// ExportJobModel is export job model structure.
+type ExportJobModel struct {
+	Base
+
+	Id        int64
+	UserId    int64
+	Prefix    string
+	Format    string
+	Url       string
+	Status    string
+	Processed int64
+	FilePath  string
+	Message   string
+	ExpiredAt int64
+	CreatedAt string
+	UpdatedAt string
+}
+
+// ExportJob return a default export job model.
+func ExportJob() ExportJobModel {
+	return ExportJobModel{Base: Base{TableName: "goadmin_export_jobs"}}
+}
+
+func (t ExportJobModel) SetConn(con db.Connection) ExportJobModel {
+	t.Conn = con
+	return t
+}
+
+func (t ExportJobModel) WithTx(tx *sql.Tx) ExportJobModel {
+	t.Tx = tx
+	return t
+}
+
+// Init applies the migration of the table of the export jobs if it is pending, and
+// fails the jobs which were interrupted by the last shutdown since the queue is in
+// the memory.
+func (t ExportJobModel) Init() error {
+	if err := migrate(t.Conn, migrationExportJobs); err != nil {
+		return err
+	}
+	if err := migrate(t.Conn, migrationExportJobsMenu); err != nil {
+		return err
+	}
+	_, err := t.Table(t.TableName).
+		WhereIn("status", []interface{}{ ExportJobStatusPending, ExportJobStatusRunning }).
+		Update(dialect.H{
+			"status"    : ExportJobStatusFailed,
+			"message"   : "interrupted by the restart",
+			"updated_at": utils.NowStr(),
+		})
+	if db.CheckError(err, db.UPDATE) {
+		return err
+	}
+	return nil
+}
+
+// Find return the export job model of given id.
+func (t ExportJobModel) Find(id interface{}) ExportJobModel {
+	item, _ := t.Table(t.TableName).Find(id)
+	return t.MapToModel(item)
+}
+
+// IsEmpty check the export job model is empty or not.
+func (t ExportJobModel) IsEmpty() bool {
+	return t.Id == int64(0)
+}
+
+// IsExpired check the export job is expired or not.
+func (t ExportJobModel) IsExpired() bool {
+	return t.Status == ExportJobStatusExpired || (t.ExpiredAt > 0 && time.Now().Unix() > t.ExpiredAt)
+}
+
+// New create a new pending export job model.
+func (t ExportJobModel) New(userId int64, prefix, format, url string) (ExportJobModel, error) {
+	id, err := t.Table(t.TableName).Insert(dialect.H{
+		"user_id"   : userId,
+		"prefix"    : prefix,
+		"format"    : format,
+		"url"       : url,
+		"status"    : ExportJobStatusPending,
+		"processed" : 0,
+		"file_path" : "",
+		"message"   : "",
+		"expired_at": 0,
+	})
+	if db.CheckError(err, db.INSERT) {
+		return t, err
+	}
+
+	t.Id = id
+	t.UserId = userId
+	t.Prefix = prefix
+	t.Format = format
+	t.Url = url
+	t.Status = ExportJobStatusPending
+
+	return t, nil
+}
+
+// Start set the status of the export job to running.
+func (t ExportJobModel) Start() ExportJobModel {
+	t.Status = ExportJobStatusRunning
+	t.update(dialect.H{ "status": t.Status })
+	return t
+}
+
+// Progress update the processed rows of the export job.
+func (t ExportJobModel) Progress(processed int64) ExportJobModel {
+	t.Processed = processed
+	t.update(dialect.H{ "processed": processed })
+	return t
+}
+
+// Finish set the export job to done with the generated file.
+func (t ExportJobModel) Finish(filePath string, processed int64, expiredAt time.Time) ExportJobModel {
+	t.Status = ExportJobStatusDone
+	t.FilePath = filePath
+	t.Processed = processed
+	t.ExpiredAt = expiredAt.Unix()
+	t.update(dialect.H{
+		"status"    : t.Status,
+		"file_path" : filePath,
+		"processed" : processed,
+		"expired_at": t.ExpiredAt,
+	})
+	return t
+}
+
+// Fail set the export job to failed with the error message.
+func (t ExportJobModel) Fail(msg string) ExportJobModel {
+	t.Status = ExportJobStatusFailed
+	t.Message = msg
+	t.update(dialect.H{ "status": t.Status, "message": msg })
+	return t
+}
+
+// Expire set the export job to expired.
+func (t ExportJobModel) Expire() ExportJobModel {
+	t.Status = ExportJobStatusExpired
+	t.update(dialect.H{ "status": t.Status })
+	return t
+}
+
+// GetExpired return the done export jobs which are expired.
+func (t ExportJobModel) GetExpired() []ExportJobModel {
+	items, _ := t.Table(t.TableName).
+		Where("status", "=", ExportJobStatusDone).
+		Where("expired_at", "<", time.Now().Unix()).
+		All()
+	jobs := make([]ExportJobModel, len(items))
+	for i, item := range items {
+		jobs[i] = t.MapToModel(item)
+	}
+	return jobs
+}
+
+func (t ExportJobModel) update(values dialect.H) {
+	values["updated_at"] = utils.NowStr()
+	_, _ = t.Table(t.TableName).Where("id", "=", t.Id).Update(values)
+}
+
+// MapToModel get the export job model from given map.
+func (t ExportJobModel) MapToModel(m map[string]interface{}) ExportJobModel {
+	t.Id, _ = m["id"].(int64)
+	t.UserId, _ = m["user_id"].(int64)
+	t.Prefix, _ = m["prefix"].(string)
+	t.Format, _ = m["format"].(string)
+	t.Url, _ = m["url"].(string)
+	t.Status, _ = m["status"].(string)
+	t.Processed, _ = m["processed"].(int64)
+	t.FilePath, _ = m["file_path"].(string)
+	t.Message, _ = m["message"].(string)
+	t.ExpiredAt, _ = m["expired_at"].(int64)
+	t.CreatedAt, _ = m["created_at"].(string)
+	t.UpdatedAt, _ = m["updated_at"].(string)
+	return t
+}
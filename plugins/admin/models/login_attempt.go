@@ -0,0 +1,161 @@
+package models
+
+import (
+	"github.com/GoAdminGroup/go-admin/modules/db"
+	"github.com/GoAdminGroup/go-admin/modules/db/dialect"
+	"github.com/GoAdminGroup/go-admin/modules/utils"
+)
+
+var loginAttemptSchema = tableSchema{
+	db.DriverMysql: "CREATE TABLE IF NOT EXISTS `goadmin_login_attempts` (" +
+		"`id` int(10) unsigned NOT NULL AUTO_INCREMENT," +
+		"`attempt_key` varchar(191) NOT NULL," +
+		"`failures` int(10) NOT NULL DEFAULT 0," +
+		"`last_failed_at` bigint NOT NULL DEFAULT 0," +
+		"`blocked_until` bigint NOT NULL DEFAULT 0," +
+		"`created_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP," +
+		"`updated_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP," +
+		"PRIMARY KEY (`id`)," +
+		"UNIQUE KEY `goadmin_login_attempts_key` (`attempt_key`)" +
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4",
+	db.DriverPostgresql: `CREATE TABLE IF NOT EXISTS goadmin_login_attempts (
+		id SERIAL PRIMARY KEY,
+		attempt_key character varying(191) NOT NULL UNIQUE,
+		failures integer NOT NULL DEFAULT 0,
+		last_failed_at bigint NOT NULL DEFAULT 0,
+		blocked_until bigint NOT NULL DEFAULT 0,
+		created_at timestamp without time zone DEFAULT now(),
+		updated_at timestamp without time zone DEFAULT now()
+	)`,
+	db.DriverSqlite: `CREATE TABLE IF NOT EXISTS goadmin_login_attempts (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		attempt_key TEXT NOT NULL UNIQUE,
+		failures INTEGER NOT NULL DEFAULT 0,
+		last_failed_at INTEGER NOT NULL DEFAULT 0,
+		blocked_until INTEGER NOT NULL DEFAULT 0,
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+		updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+	)`,
+	db.DriverMssql: `IF OBJECT_ID(N'goadmin_login_attempts', N'U') IS NULL
+	CREATE TABLE [goadmin_login_attempts] (
+		[id] int IDENTITY(1,1) PRIMARY KEY,
+		[attempt_key] nvarchar(191) NOT NULL UNIQUE,
+		[failures] int NOT NULL DEFAULT 0,
+		[last_failed_at] bigint NOT NULL DEFAULT 0,
+		[blocked_until] bigint NOT NULL DEFAULT 0,
+		[created_at] datetime DEFAULT GETDATE(),
+		[updated_at] datetime DEFAULT GETDATE()
+	)`,
+}
+
+// LoginAttemptModel is the failed login attempts of a username or an IP, the
+// times are unix seconds.
+type LoginAttemptModel struct {
+	Base
+
+	Id           int64
+	Key          string
+	Failures     int64
+	LastFailedAt int64
+	BlockedUntil int64
+	CreatedAt    string
+	UpdatedAt    string
+}
+
+// LoginAttempt return a default login attempt model.
+func LoginAttempt() LoginAttemptModel {
+	return LoginAttemptModel{Base: Base{TableName: "goadmin_login_attempts"}}
+}
+
+func (t LoginAttemptModel) SetConn(con db.Connection) LoginAttemptModel {
+	t.Conn = con
+	return t
+}
+
+// Init applies the migration of the table of the login attempts if it is pending.
+func (t LoginAttemptModel) Init() error {
+	return migrate(t.Conn, migrationLoginAttempts)
+}
+
+// FindByKey return the login attempt model of the key, the key is kept when not found.
+func (t LoginAttemptModel) FindByKey(key string) LoginAttemptModel {
+	item, _ := t.Table(t.TableName).WithPrimary().Where("attempt_key", "=", key).First()
+	t = t.MapToModel(item)
+	t.Key = key
+	return t
+}
+
+// FindByKeys return the login attempt models of the keys.
+func (t LoginAttemptModel) FindByKeys(keys []string) []LoginAttemptModel {
+	if len(keys) == 0 {
+		return nil
+	}
+	ids := make([]interface{}, len(keys))
+	for i, key := range keys {
+		ids[i] = key
+	}
+	items, _ := t.Table(t.TableName).WithPrimary().WhereIn("attempt_key", ids).All()
+	res := make([]LoginAttemptModel, len(items))
+	for i, item := range items {
+		res[i] = t.MapToModel(item)
+	}
+	return res
+}
+
+// IsEmpty check the login attempt model is empty or not.
+func (t LoginAttemptModel) IsEmpty() bool {
+	return t.Id == int64(0)
+}
+
+// Save inserts or updates the failures and the blocked time of the key.
+func (t LoginAttemptModel) Save(failures, lastFailedAt, blockedUntil int64) (LoginAttemptModel, error) {
+	t.Failures = failures
+	t.LastFailedAt = lastFailedAt
+	t.BlockedUntil = blockedUntil
+
+	if t.IsEmpty() {
+		id, err := t.Table(t.TableName).Insert(dialect.H{
+			"attempt_key"   : t.Key,
+			"failures"      : failures,
+			"last_failed_at": lastFailedAt,
+			"blocked_until" : blockedUntil,
+		})
+		if db.CheckError(err, db.INSERT) {
+			return t, err
+		}
+		t.Id = id
+		return t, nil
+	}
+
+	_, err := t.Table(t.TableName).Where("id", "=", t.Id).Update(dialect.H{
+		"failures"      : failures,
+		"last_failed_at": lastFailedAt,
+		"blocked_until" : blockedUntil,
+		"updated_at"    : utils.NowStr(),
+	})
+	if db.CheckError(err, db.UPDATE) {
+		return t, err
+	}
+	return t, nil
+}
+
+// Reset deletes the failed attempts of the key.
+func (t LoginAttemptModel) Reset(key string) error {
+	err := t.Table(t.TableName).Where("attempt_key", "=", key).Delete()
+	if db.CheckError(err, db.DELETE) {
+		return err
+	}
+	return nil
+}
+
+// MapToModel get the login attempt model from given map.
+func (t LoginAttemptModel) MapToModel(m map[string]interface{}) LoginAttemptModel {
+	t.Id, _ = m["id"].(int64)
+	t.Key, _ = m["attempt_key"].(string)
+	t.Failures, _ = m["failures"].(int64)
+	t.LastFailedAt, _ = m["last_failed_at"].(int64)
+	t.BlockedUntil, _ = m["blocked_until"].(int64)
+	t.CreatedAt, _ = m["created_at"].(string)
+	t.UpdatedAt, _ = m["updated_at"].(string)
+	return t
+}
@@ -0,0 +1,193 @@
+package models
+
+import (
+	"github.com/GoAdminGroup/go-admin/modules/db"
+	"github.com/GoAdminGroup/go-admin/modules/db/dialect"
+	"github.com/GoAdminGroup/go-admin/modules/utils"
+)
+
+var savedViewSchema = tableSchema{
+	db.DriverMysql: "CREATE TABLE IF NOT EXISTS `goadmin_saved_views` (" +
+		"`id` int(10) unsigned NOT NULL AUTO_INCREMENT," +
+		"`user_id` int(10) unsigned NOT NULL DEFAULT 0," +
+		"`role_id` int(10) unsigned NOT NULL DEFAULT 0," +
+		"`prefix` varchar(100) NOT NULL DEFAULT ''," +
+		"`name` varchar(100) NOT NULL DEFAULT ''," +
+		"`params` text," +
+		"`created_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP," +
+		"`updated_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP," +
+		"PRIMARY KEY (`id`)," +
+		"KEY `goadmin_saved_views_prefix` (`prefix`)" +
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4",
+	db.DriverPostgresql: `CREATE TABLE IF NOT EXISTS goadmin_saved_views (
+		id SERIAL PRIMARY KEY,
+		user_id integer NOT NULL DEFAULT 0,
+		role_id integer NOT NULL DEFAULT 0,
+		prefix character varying(100) NOT NULL DEFAULT '',
+		name character varying(100) NOT NULL DEFAULT '',
+		params text,
+		created_at timestamp without time zone DEFAULT now(),
+		updated_at timestamp without time zone DEFAULT now()
+	);
+	CREATE INDEX IF NOT EXISTS goadmin_saved_views_prefix ON goadmin_saved_views (prefix)`,
+	db.DriverSqlite: `CREATE TABLE IF NOT EXISTS goadmin_saved_views (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		user_id INTEGER NOT NULL DEFAULT 0,
+		role_id INTEGER NOT NULL DEFAULT 0,
+		prefix TEXT NOT NULL DEFAULT '',
+		name TEXT NOT NULL DEFAULT '',
+		params TEXT,
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+		updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+	);
+	CREATE INDEX IF NOT EXISTS goadmin_saved_views_prefix ON goadmin_saved_views (prefix)`,
+	db.DriverMssql: `IF OBJECT_ID(N'goadmin_saved_views', N'U') IS NULL
+	CREATE TABLE [goadmin_saved_views] (
+		[id] int IDENTITY(1,1) PRIMARY KEY,
+		[user_id] int NOT NULL DEFAULT 0,
+		[role_id] int NOT NULL DEFAULT 0,
+		[prefix] nvarchar(100) NOT NULL DEFAULT '',
+		[name] nvarchar(100) NOT NULL DEFAULT '',
+		[params] nvarchar(max),
+		[created_at] datetime DEFAULT GETDATE(),
+		[updated_at] datetime DEFAULT GETDATE()
+	)`,
+}
+
+// SavedViewModel is a named view of an info table, which keeps the query string of
+// the list, that is the filters, the sort, the page size and the visible columns.
+// A view belongs to its user, and it is shared to the users of the role when the
+// role is set.
+type SavedViewModel struct {
+	Base
+
+	Id        int64
+	UserId    int64
+	RoleId    int64
+	Prefix    string
+	Name      string
+	Params    string
+	CreatedAt string
+	UpdatedAt string
+}
+
+// SavedView return a default saved view model.
+func SavedView() SavedViewModel {
+	return SavedViewModel{Base: Base{TableName: "goadmin_saved_views"}}
+}
+
+func (t SavedViewModel) SetConn(con db.Connection) SavedViewModel {
+	t.Conn = con
+	return t
+}
+
+// Init applies the migration of the table of the saved views if it is pending.
+func (t SavedViewModel) Init() error {
+	return migrate(t.Conn, migrationSavedViews)
+}
+
+// Find return the saved view of the id.
+func (t SavedViewModel) Find(id interface{}) SavedViewModel {
+	item, _ := t.Table(t.TableName).Find(id)
+	return t.MapToModel(item)
+}
+
+// IsEmpty check the saved view model is empty or not.
+func (t SavedViewModel) IsEmpty() bool {
+	return t.Id == int64(0)
+}
+
+// VisibleTo reports whether the user owns the view or has the role it is shared to.
+func (t SavedViewModel) VisibleTo(user UserModel) bool {
+	if t.UserId == user.Id {
+		return true
+	}
+	for _, role := range user.Roles {
+		if t.RoleId != 0 && role.Id == t.RoleId {
+			return true
+		}
+	}
+	return false
+}
+
+// GetVisible return the saved views of the table of the prefix which are visible
+// to the user, ordered by the name.
+func (t SavedViewModel) GetVisible(prefix string, user UserModel) []SavedViewModel {
+	items, _ := t.Table(t.TableName).
+		Where("prefix", "=", prefix).
+		OrderBy("name", "asc").
+		All()
+
+	views := make([]SavedViewModel, 0, len(items))
+	for _, item := range items {
+		if view := t.MapToModel(item); view.VisibleTo(user) {
+			views = append(views, view)
+		}
+	}
+	return views
+}
+
+// New create a saved view, the view of the same name of the user and the table is
+// replaced.
+func (t SavedViewModel) New(userId, roleId int64, prefix, name, params string) (SavedViewModel, error) {
+	item, _ := t.Table(t.TableName).
+		Where("user_id", "=", userId).
+		Where("prefix", "=", prefix).
+		Where("name", "=", name).
+		First()
+
+	t.UserId = userId
+	t.RoleId = roleId
+	t.Prefix = prefix
+	t.Name = name
+	t.Params = params
+
+	if item != nil {
+		t.Id, _ = item["id"].(int64)
+		_, err := t.Table(t.TableName).Where("id", "=", t.Id).Update(dialect.H{
+			"role_id"   : roleId,
+			"params"    : params,
+			"updated_at": utils.NowStr(),
+		})
+		if db.CheckError(err, db.UPDATE) {
+			return t, err
+		}
+		return t, nil
+	}
+
+	id, err := t.Table(t.TableName).Insert(dialect.H{
+		"user_id": userId,
+		"role_id": roleId,
+		"prefix" : prefix,
+		"name"   : name,
+		"params" : params,
+	})
+	if db.CheckError(err, db.INSERT) {
+		return t, err
+	}
+
+	t.Id = id
+	return t, nil
+}
+
+// Delete delete the saved view.
+func (t SavedViewModel) Delete() error {
+	err := t.Table(t.TableName).Where("id", "=", t.Id).Delete()
+	if db.CheckError(err, db.DELETE) {
+		return err
+	}
+	return nil
+}
+
+// MapToModel get the saved view model from given map.
+func (t SavedViewModel) MapToModel(m map[string]interface{}) SavedViewModel {
+	t.Id, _ = m["id"].(int64)
+	t.UserId, _ = m["user_id"].(int64)
+	t.RoleId, _ = m["role_id"].(int64)
+	t.Prefix, _ = m["prefix"].(string)
+	t.Name, _ = m["name"].(string)
+	t.Params, _ = m["params"].(string)
+	t.CreatedAt, _ = m["created_at"].(string)
+	t.UpdatedAt, _ = m["updated_at"].(string)
+	return t
+}
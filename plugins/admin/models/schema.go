@@ -0,0 +1,93 @@
+package models
+
+import (
+	"github.com/GoAdminGroup/go-admin/modules/config"
+	"github.com/GoAdminGroup/go-admin/modules/db"
+	"github.com/GoAdminGroup/go-admin/modules/logger"
+	"github.com/GoAdminGroup/go-admin/modules/migration"
+)
+
+// tableSchema is the statements of a built-in table keyed by the driver name.
+type tableSchema map[string]string
+
+// The versions of the migrations of the built-in tables, which are applied in
+// order by the migration runner. A new change of a table is a new version, the
+// released ones must not be edited.
+const (
+	migrationUsers           int64 = 2023123101
+	migrationRoles           int64 = 2023123102
+	migrationPermissions     int64 = 2023123103
+	migrationMenu            int64 = 2023123104
+	migrationRoleMenu        int64 = 2023123105
+	migrationRolePermissions int64 = 2023123106
+	migrationRoleUsers       int64 = 2023123107
+	migrationUserPermissions int64 = 2023123108
+	migrationOperationLog    int64 = 2023123109
+	migrationSession         int64 = 2023123110
+	migrationSite            int64 = 2023123111
+
+	migrationExportJobs      int64 = 2024010101
+	migrationAuditTrail      int64 = 2024010102
+	migrationUserTwoFactor   int64 = 2024010103
+	migrationRoleTwoFactor   int64 = 2024010104
+	migrationLoginAttempts   int64 = 2024010105
+	migrationConfigRevisions int64 = 2024010106
+	migrationSavedViews      int64 = 2024010107
+	migrationTableDefs       int64 = 2024010108
+	migrationExportJobsMenu  int64 = 2024010109
+)
+
+func init() {
+	migration.Register(
+		tableMigration(migrationUsers, "goadmin_users", usersSchema),
+		tableMigration(migrationRoles, "goadmin_roles", rolesSchema),
+		tableMigration(migrationPermissions, "goadmin_permissions", permissionsSchema),
+		tableMigration(migrationMenu, "goadmin_menu", menuSchema),
+		tableMigration(migrationRoleMenu, "goadmin_role_menu", roleMenuSchema),
+		tableMigration(migrationRolePermissions, "goadmin_role_permissions", rolePermissionsSchema),
+		tableMigration(migrationRoleUsers, "goadmin_role_users", roleUsersSchema),
+		tableMigration(migrationUserPermissions, "goadmin_user_permissions", userPermissionsSchema),
+		tableMigration(migrationOperationLog, "goadmin_operation_log", operationLogSchema),
+		tableMigration(migrationSession, "goadmin_session", sessionSchema),
+		tableMigration(migrationSite, "goadmin_site", siteSchema),
+		tableMigration(migrationExportJobs, "goadmin_export_jobs", exportJobSchema),
+		tableMigration(migrationAuditTrail, "goadmin_audit_trail", auditSchema),
+		tableMigration(migrationUserTwoFactor, "goadmin_user_two_factor", twoFactorSchema),
+		tableMigration(migrationRoleTwoFactor, "goadmin_role_two_factor", twoFactorRoleSchema),
+		tableMigration(migrationLoginAttempts, "goadmin_login_attempts", loginAttemptSchema),
+		tableMigration(migrationConfigRevisions, "goadmin_config_revisions", configRevisionSchema),
+		tableMigration(migrationSavedViews, "goadmin_saved_views", savedViewSchema),
+		tableMigration(migrationTableDefs, TableDefTableName, tableDefSchema),
+		exportJobsMenuMigration,
+	)
+}
+
+// tableMigration return the migration creating the table of the schema. The create
+// statements are idempotent, so that the databases of which the table was created
+// before the migrations are recorded without changes.
+func tableMigration(version int64, table string, schema tableSchema) migration.Migration {
+	return migration.Migration{
+		Version: version,
+		Name   : "create " + table,
+		Up     : schema,
+		Down   : map[string]string{
+			db.DriverMysql     : "DROP TABLE IF EXISTS `" + table + "`",
+			db.DriverPostgresql: "DROP TABLE IF EXISTS " + table,
+			db.DriverSqlite    : "DROP TABLE IF EXISTS " + table,
+			db.DriverMssql     : "IF OBJECT_ID(N'" + table + "', N'U') IS NOT NULL DROP TABLE [" + table + "]",
+		},
+	}
+}
+
+// migrate applies the built-in migration of the version with the given connection
+// if it is pending and the AutoMigrate is on. Otherwise the migrations are applied
+// by the adm migrate command, and a pending one is only warned.
+func migrate(conn db.Connection, version int64) error {
+	if config.GetAutoMigrate() {
+		return migration.Apply(conn, "", version)
+	}
+	if pending, err := migration.IsPending(conn, "", version); err != nil || pending {
+		logger.Warn("migration ", version, " is pending, run adm migrate or turn on the auto_migrate")
+	}
+	return nil
+}
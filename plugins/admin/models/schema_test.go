@@ -0,0 +1,62 @@
+package models
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/GoAdminGroup/go-admin/modules/config"
+	"github.com/GoAdminGroup/go-admin/modules/db"
+	"github.com/GoAdminGroup/go-admin/modules/migration"
+	"github.com/GoAdminGroup/go-admin/modules/utils"
+	"github.com/magiconair/properties/assert"
+	_ "github.com/mattn/go-sqlite3"
+)
+
+func TestMigrate(t *testing.T) {
+	utils.InitUtils(100, func(s string) string { return s })
+
+	conn := db.GetSqliteDB().InitDB(map[string]config.Database{
+		"default": { Driver: db.DriverSqlite, File: filepath.Join(t.TempDir(), "admin.db") },
+	})
+	defer conn.Close()
+
+	// the tables are not changed at the start without the auto migrate.
+	config.Initialize(&config.Config{ Language: "en" })
+	assert.Equal(t, SavedView().SetConn(conn).Init(), nil)
+	_, err := conn.Query(`SELECT * FROM goadmin_saved_views`)
+	assert.Equal(t, err != nil, true)
+
+	config.Initialize(&config.Config{ Language: "en", AutoMigrate: true })
+	assert.Equal(t, SavedView().SetConn(conn).Init(), nil)
+	_, err = conn.Query(`SELECT * FROM goadmin_saved_views`)
+	assert.Equal(t, err, nil)
+
+	// the baseline migrations create the core tables before the others.
+	list, err := migration.Up(conn)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, list[0].Version, migrationUsers)
+	for _, table := range []string{ "goadmin_users", "goadmin_roles", "goadmin_permissions", "goadmin_menu",
+		"goadmin_role_menu", "goadmin_role_permissions", "goadmin_role_users", "goadmin_user_permissions",
+		"goadmin_operation_log", "goadmin_session", "goadmin_site" } {
+		_, err = conn.Query(`SELECT * FROM ` + table)
+		assert.Equal(t, err, nil)
+	}
+	menus, err := conn.Query(`SELECT * FROM goadmin_menu WHERE uri = '/info/export_jobs'`)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, len(menus), 1)
+
+	list, err = migration.Down(conn, len(migration.List()))
+	assert.Equal(t, err, nil)
+	assert.Equal(t, len(list), len(migration.List()))
+	_, err = conn.Query(`SELECT * FROM goadmin_users`)
+	assert.Equal(t, err != nil, true)
+}
+
+func TestTableMigrationDown(t *testing.T) {
+	m := tableMigration(migrationUsers, "goadmin_users", usersSchema)
+	assert.Equal(t, m.Down[db.DriverMssql], "IF OBJECT_ID(N'goadmin_users', N'U') IS NOT NULL DROP TABLE [goadmin_users]")
+	for driver, stmt := range m.Up {
+		assert.Equal(t, strings.Contains(stmt, "goadmin_users"), true, driver)
+	}
+}
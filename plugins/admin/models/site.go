@@ -117,6 +117,24 @@ var allowEmptyKeys = []string{
 	"custom_403_html", "custom_500_html", "footer_info", "asset_url", "extra", "domain",
 }
 
+// Diff return the changes of the items which the Update of the values would make.
+func (t SiteModel) Diff(v form.Values) AuditDiff {
+	var (
+		diff    = make(AuditDiff)
+		current = t.AllToMap()
+	)
+	for key, vv := range v {
+		if len(vv) > 0 && (vv[0] != "" || utils.InArray(allowEmptyKeys, key)) {
+			old, ok := current[key]
+			if !ok || old == vv[0] {
+				continue
+			}
+			diff[key] = AuditChange{ Before: old, After: vv[0] }
+		}
+	}
+	return diff
+}
+
 func (t SiteModel) Update(v form.Values) error {
 	for key, vv := range v {
 		if len(vv) > 0 && (vv[0] != "" || utils.InArray(allowEmptyKeys, key)) {
@@ -0,0 +1,178 @@
+package models
+
+import (
+	"github.com/GoAdminGroup/go-admin/modules/db"
+	"github.com/GoAdminGroup/go-admin/modules/db/dialect"
+)
+
+// TableDefTableName is the table of the table definitions saved by the designer.
+const TableDefTableName = "goadmin_table_defs"
+
+var tableDefSchema = tableSchema{
+	db.DriverMysql: "CREATE TABLE IF NOT EXISTS `goadmin_table_defs` (" +
+		"`id` int(10) unsigned NOT NULL AUTO_INCREMENT," +
+		"`prefix` varchar(100) NOT NULL DEFAULT ''," +
+		"`version` int(10) unsigned NOT NULL DEFAULT 1," +
+		"`title` varchar(255) NOT NULL DEFAULT ''," +
+		"`definition` text," +
+		"`user_id` int(10) unsigned NOT NULL DEFAULT 0," +
+		"`created_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP," +
+		"PRIMARY KEY (`id`)," +
+		"UNIQUE KEY `goadmin_table_defs_prefix_version` (`prefix`, `version`)" +
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4",
+	db.DriverPostgresql: `CREATE TABLE IF NOT EXISTS goadmin_table_defs (
+		id SERIAL PRIMARY KEY,
+		prefix character varying(100) NOT NULL DEFAULT '',
+		version integer NOT NULL DEFAULT 1,
+		title character varying(255) NOT NULL DEFAULT '',
+		definition text,
+		user_id integer NOT NULL DEFAULT 0,
+		created_at timestamp without time zone DEFAULT now()
+	);
+	CREATE UNIQUE INDEX IF NOT EXISTS goadmin_table_defs_prefix_version ON goadmin_table_defs (prefix, version)`,
+	db.DriverSqlite: `CREATE TABLE IF NOT EXISTS goadmin_table_defs (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		prefix TEXT NOT NULL DEFAULT '',
+		version INTEGER NOT NULL DEFAULT 1,
+		title TEXT NOT NULL DEFAULT '',
+		definition TEXT,
+		user_id INTEGER NOT NULL DEFAULT 0,
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+	);
+	CREATE UNIQUE INDEX IF NOT EXISTS goadmin_table_defs_prefix_version ON goadmin_table_defs (prefix, version)`,
+	db.DriverMssql: `IF OBJECT_ID(N'goadmin_table_defs', N'U') IS NULL
+	CREATE TABLE [goadmin_table_defs] (
+		[id] int IDENTITY(1,1) PRIMARY KEY,
+		[prefix] nvarchar(100) NOT NULL DEFAULT '',
+		[version] int NOT NULL DEFAULT 1,
+		[title] nvarchar(255) NOT NULL DEFAULT '',
+		[definition] nvarchar(max),
+		[user_id] int NOT NULL DEFAULT 0,
+		[created_at] datetime DEFAULT GETDATE(),
+		CONSTRAINT [goadmin_table_defs_prefix_version] UNIQUE ([prefix], [version])
+	)`,
+}
+
+// TableDefModel is a version of a table definition saved by the designer, the
+// definition is the json of the table.Definition. Every save of a table adds a
+// version, and the latest version of the prefix is the live one.
+type TableDefModel struct {
+	Base
+
+	Id         int64
+	Prefix     string
+	Version    int64
+	Title      string
+	Definition string
+	UserId     int64
+	CreatedAt  string
+}
+
+// TableDef return a default table definition model.
+func TableDef() TableDefModel {
+	return TableDefModel{Base: Base{TableName: TableDefTableName}}
+}
+
+func (t TableDefModel) SetConn(con db.Connection) TableDefModel {
+	t.Conn = con
+	return t
+}
+
+// Init applies the migration of the table of the table definitions if it is pending.
+func (t TableDefModel) Init() error {
+	return migrate(t.Conn, migrationTableDefs)
+}
+
+// Find return the version of the id.
+func (t TableDefModel) Find(id interface{}) TableDefModel {
+	item, _ := t.Table(t.TableName).Find(id)
+	return t.MapToModel(item)
+}
+
+// IsEmpty check the table definition model is empty or not.
+func (t TableDefModel) IsEmpty() bool {
+	return t.Id == int64(0)
+}
+
+// Latest return the live version of the prefix.
+func (t TableDefModel) Latest(prefix string) TableDefModel {
+	item, _ := t.Table(t.TableName).
+		Where("prefix", "=", prefix).
+		OrderBy("version", "desc").
+		First()
+	return t.MapToModel(item)
+}
+
+// AllLatest return the live versions of all the prefixes.
+func (t TableDefModel) AllLatest() []TableDefModel {
+	items, _ := t.Table(t.TableName).
+		OrderBy("version", "desc").
+		All()
+
+	var (
+		defs = make([]TableDefModel, 0)
+		seen = make(map[string]bool)
+	)
+	for _, item := range items {
+		def := t.MapToModel(item)
+		if seen[def.Prefix] {
+			continue
+		}
+		seen[def.Prefix] = true
+		defs = append(defs, def)
+	}
+	return defs
+}
+
+// Save adds the definition as the next version of the prefix.
+func (t TableDefModel) Save(prefix, title, definition string, userId int64) (TableDefModel, error) {
+	version := t.Latest(prefix).Version + 1
+
+	id, err := t.Table(t.TableName).Insert(dialect.H{
+		"prefix"    : prefix,
+		"version"   : version,
+		"title"     : title,
+		"definition": definition,
+		"user_id"   : userId,
+	})
+	if db.CheckError(err, db.INSERT) {
+		return t, err
+	}
+
+	t.Id         = id
+	t.Prefix     = prefix
+	t.Version    = version
+	t.Title      = title
+	t.Definition = definition
+	t.UserId     = userId
+	return t, nil
+}
+
+// Delete deletes the versions of the ids and return the prefixes of them.
+func (t TableDefModel) Delete(ids []interface{}) ([]string, error) {
+	items, _ := t.Table(t.TableName).WhereIn("id", ids).All()
+
+	prefixes := make([]string, 0, len(items))
+	for _, item := range items {
+		prefix, _ := item["prefix"].(string)
+		prefixes = append(prefixes, prefix)
+	}
+
+	err := t.Table(t.TableName).WhereIn("id", ids).Delete()
+	if db.CheckError(err, db.DELETE) {
+		return prefixes, err
+	}
+	return prefixes, nil
+}
+
+// MapToModel get the table definition model from given map.
+func (t TableDefModel) MapToModel(m map[string]interface{}) TableDefModel {
+	t.Id, _ = m["id"].(int64)
+	t.Prefix, _ = m["prefix"].(string)
+	t.Version, _ = m["version"].(int64)
+	t.Title, _ = m["title"].(string)
+	t.Definition, _ = m["definition"].(string)
+	t.UserId, _ = m["user_id"].(int64)
+	t.CreatedAt, _ = m["created_at"].(string)
+	return t
+}
@@ -0,0 +1,235 @@
+package models
+
+import (
+	"strconv"
+
+	"github.com/GoAdminGroup/go-admin/modules/db"
+	"github.com/GoAdminGroup/go-admin/modules/db/dialect"
+	"github.com/GoAdminGroup/go-admin/modules/utils"
+)
+
+var twoFactorSchema = tableSchema{
+	db.DriverMysql: "CREATE TABLE IF NOT EXISTS `goadmin_user_two_factor` (" +
+		"`id` int(10) unsigned NOT NULL AUTO_INCREMENT," +
+		"`user_id` int(10) unsigned NOT NULL," +
+		"`secret` varchar(64) NOT NULL DEFAULT ''," +
+		"`recovery_codes` text," +
+		"`last_step` bigint NOT NULL DEFAULT 0," +
+		"`created_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP," +
+		"`updated_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP," +
+		"PRIMARY KEY (`id`)," +
+		"UNIQUE KEY `goadmin_user_two_factor_user` (`user_id`)" +
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4",
+	db.DriverPostgresql: `CREATE TABLE IF NOT EXISTS goadmin_user_two_factor (
+		id SERIAL PRIMARY KEY,
+		user_id integer NOT NULL UNIQUE,
+		secret character varying(64) NOT NULL DEFAULT '',
+		recovery_codes text,
+		last_step bigint NOT NULL DEFAULT 0,
+		created_at timestamp without time zone DEFAULT now(),
+		updated_at timestamp without time zone DEFAULT now()
+	)`,
+	db.DriverSqlite: `CREATE TABLE IF NOT EXISTS goadmin_user_two_factor (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		user_id INTEGER NOT NULL UNIQUE,
+		secret TEXT NOT NULL DEFAULT '',
+		recovery_codes TEXT,
+		last_step INTEGER NOT NULL DEFAULT 0,
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+		updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+	)`,
+	db.DriverMssql: `IF OBJECT_ID(N'goadmin_user_two_factor', N'U') IS NULL
+	CREATE TABLE [goadmin_user_two_factor] (
+		[id] int IDENTITY(1,1) PRIMARY KEY,
+		[user_id] int NOT NULL UNIQUE,
+		[secret] nvarchar(64) NOT NULL DEFAULT '',
+		[recovery_codes] nvarchar(max),
+		[last_step] bigint NOT NULL DEFAULT 0,
+		[created_at] datetime DEFAULT GETDATE(),
+		[updated_at] datetime DEFAULT GETDATE()
+	)`,
+}
+
+var twoFactorRoleSchema = tableSchema{
+	db.DriverMysql: "CREATE TABLE IF NOT EXISTS `goadmin_role_two_factor` (" +
+		"`role_id` int(10) unsigned NOT NULL," +
+		"`created_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP," +
+		"PRIMARY KEY (`role_id`)" +
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4",
+	db.DriverPostgresql: `CREATE TABLE IF NOT EXISTS goadmin_role_two_factor (
+		role_id integer PRIMARY KEY,
+		created_at timestamp without time zone DEFAULT now()
+	)`,
+	db.DriverSqlite: `CREATE TABLE IF NOT EXISTS goadmin_role_two_factor (
+		role_id INTEGER PRIMARY KEY,
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+	)`,
+	db.DriverMssql: `IF OBJECT_ID(N'goadmin_role_two_factor', N'U') IS NULL
+	CREATE TABLE [goadmin_role_two_factor] (
+		[role_id] int PRIMARY KEY,
+		[created_at] datetime DEFAULT GETDATE()
+	)`,
+}
+
+// TwoFactorModel is the TOTP two-factor authentication of a user, the recovery
+// codes are stored hashed.
+type TwoFactorModel struct {
+	Base
+
+	Id            int64
+	UserId        int64
+	Secret        string
+	RecoveryCodes []string
+	LastStep      int64
+	CreatedAt     string
+	UpdatedAt     string
+}
+
+// TwoFactor return a default two-factor model.
+func TwoFactor() TwoFactorModel {
+	return TwoFactorModel{Base: Base{TableName: "goadmin_user_two_factor"}}
+}
+
+func (t TwoFactorModel) SetConn(con db.Connection) TwoFactorModel {
+	t.Conn = con
+	return t
+}
+
+// Init applies the migrations of the tables of the two-factor authentication if
+// they are pending.
+func (t TwoFactorModel) Init() error {
+	if err := migrate(t.Conn, migrationUserTwoFactor); err != nil {
+		return err
+	}
+	return migrate(t.Conn, migrationRoleTwoFactor)
+}
+
+// FindByUserId return the two-factor model of the user, it is empty when the
+// user has not enabled the two-factor authentication.
+func (t TwoFactorModel) FindByUserId(userId int64) TwoFactorModel {
+	item, _ := t.Table(t.TableName).Where("user_id", "=", userId).First()
+	return t.MapToModel(item)
+}
+
+// IsEmpty check the two-factor model is empty or not.
+func (t TwoFactorModel) IsEmpty() bool {
+	return t.Id == int64(0)
+}
+
+// Enable enables the two-factor authentication of the user with the verified
+// secret, the previous secret and recovery codes are replaced.
+func (t TwoFactorModel) Enable(userId int64, secret string, step int64, recoveryCodes []string) (TwoFactorModel, error) {
+	if err := t.Disable(userId); err != nil {
+		return t, err
+	}
+
+	id, err := t.Table(t.TableName).Insert(dialect.H{
+		"user_id"       : userId,
+		"secret"        : secret,
+		"recovery_codes": utils.JSON(recoveryCodes),
+		"last_step"     : step,
+	})
+	if db.CheckError(err, db.INSERT) {
+		return t, err
+	}
+
+	t.Id = id
+	t.UserId = userId
+	t.Secret = secret
+	t.RecoveryCodes = recoveryCodes
+	t.LastStep = step
+
+	return t, nil
+}
+
+// Disable disables the two-factor authentication of the user.
+func (t TwoFactorModel) Disable(userId int64) error {
+	err := t.Table(t.TableName).Where("user_id", "=", userId).Delete()
+	if db.CheckError(err, db.DELETE) {
+		return err
+	}
+	return nil
+}
+
+// UpdateLastStep records the time step of the last used code.
+func (t TwoFactorModel) UpdateLastStep(step int64) TwoFactorModel {
+	t.LastStep = step
+	t.update(dialect.H{ "last_step": step })
+	return t
+}
+
+// UpdateRecoveryCodes replaces the hashed recovery codes.
+func (t TwoFactorModel) UpdateRecoveryCodes(recoveryCodes []string) TwoFactorModel {
+	t.RecoveryCodes = recoveryCodes
+	t.update(dialect.H{ "recovery_codes": utils.JSON(recoveryCodes) })
+	return t
+}
+
+// UseRecoveryCode removes the hashed recovery code, it reports whether the code exists.
+func (t TwoFactorModel) UseRecoveryCode(hashed string) bool {
+	for i, code := range t.RecoveryCodes {
+		if code == hashed {
+			codes := make([]string, 0, len(t.RecoveryCodes) - 1)
+			codes = append(codes, t.RecoveryCodes[:i]...)
+			codes = append(codes, t.RecoveryCodes[i + 1:]...)
+			t.UpdateRecoveryCodes(codes)
+			return true
+		}
+	}
+	return false
+}
+
+// IsRequired reports whether any of the roles requires the two-factor authentication.
+func (t TwoFactorModel) IsRequired(roles []RoleModel) bool {
+	if len(roles) == 0 {
+		return false
+	}
+	ids := make([]interface{}, len(roles))
+	for i, role := range roles {
+		ids[i] = role.Id
+	}
+	item, _ := t.Table("goadmin_role_two_factor").WhereIn("role_id", ids).First()
+	return item != nil
+}
+
+// IsRoleRequired reports whether the role requires the two-factor authentication.
+func (t TwoFactorModel) IsRoleRequired(roleId string) bool {
+	item, _ := t.Table("goadmin_role_two_factor").Where("role_id", "=", roleId).First()
+	return item != nil
+}
+
+// SetRoleRequired set whether the role requires the two-factor authentication.
+func (t TwoFactorModel) SetRoleRequired(roleId string, required bool) error {
+	err := t.Table("goadmin_role_two_factor").Where("role_id", "=", roleId).Delete()
+	if db.CheckError(err, db.DELETE) {
+		return err
+	}
+	if !required {
+		return nil
+	}
+	id, _ := strconv.ParseInt(roleId, 10, 64)
+	_, err = t.Table("goadmin_role_two_factor").Insert(dialect.H{ "role_id": id })
+	if db.CheckError(err, db.INSERT) {
+		return err
+	}
+	return nil
+}
+
+func (t TwoFactorModel) update(values dialect.H) {
+	values["updated_at"] = utils.NowStr()
+	_, _ = t.Table(t.TableName).Where("id", "=", t.Id).Update(values)
+}
+
+// MapToModel get the two-factor model from given map.
+func (t TwoFactorModel) MapToModel(m map[string]interface{}) TwoFactorModel {
+	t.Id, _ = m["id"].(int64)
+	t.UserId, _ = m["user_id"].(int64)
+	t.Secret, _ = m["secret"].(string)
+	t.LastStep, _ = m["last_step"].(int64)
+	t.CreatedAt, _ = m["created_at"].(string)
+	t.UpdatedAt, _ = m["updated_at"].(string)
+	if codes, ok := m["recovery_codes"].(string); ok && codes != "" {
+		_ = utils.JsonUnmarshal([]byte(codes), &t.RecoveryCodes)
+	}
+	return t
+}
@@ -123,6 +123,7 @@ func (t UserModel) CheckPermissionByUrlMethod(path, method string, formParams ur
 	// path, _ = url.PathUnescape(path)
 	if path == "" { return false }
 	if utils.IsLogoutUrl(path) { return true }
+	if utils.IsTwoFactorUrl(path) { return true }
 
 	if path != "/" && path[len(path)-1] == '/' {
 		path = path[:len(path)-1]
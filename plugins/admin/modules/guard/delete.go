@@ -13,7 +13,10 @@ type DeleteParam struct {
 }
 
 func (g *Guard) Delete(ctx *context.Context) {
-	panel, prefix := g.table(ctx)
+	panel, prefix, ok := g.table(ctx)
+	if !ok {
+		return
+	}
 	if !panel.GetDeletable() {
 		alert(ctx, panel, errors.OperationNotAllow, g.conn, g.navBtns)
 		ctx.Abort()
@@ -25,7 +25,10 @@ type ShowFormParam struct {
 }
 
 func (g *Guard) ShowForm(ctx *context.Context) {
-	panel, prefix := g.table(ctx)
+	panel, prefix, ok := g.table(ctx)
+	if !ok {
+		return
+	}
 
 	if !panel.GetEditable() {
 		alert(ctx, panel, errors.OperationNotAllow, g.conn, g.navBtns)
@@ -89,7 +92,10 @@ func (e EditFormParam) Value() form.Values {
 }
 
 func (g *Guard) EditForm(ctx *context.Context) {
-	panel, prefix := g.table(ctx)
+	panel, prefix, ok := g.table(ctx)
+	if !ok {
+		return
+	}
 
 	if !panel.GetEditable() {
 		alert(ctx, panel, errors.OperationNotAllow, g.conn, g.navBtns)
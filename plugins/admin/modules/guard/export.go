@@ -5,18 +5,30 @@ import (
 
 	"github.com/GoAdminGroup/go-admin/context"
 	"github.com/GoAdminGroup/go-admin/modules/errors"
+	"github.com/GoAdminGroup/go-admin/plugins/admin/modules/response"
 	"github.com/GoAdminGroup/go-admin/plugins/admin/modules/table"
 )
 
+const (
+	ExportFormatExcel  = "xlsx"
+	ExportFormatCSV    = "csv"
+	ExportFormatNDJSON = "ndjson"
+)
+
 type ExportParam struct {
 	Panel  table.Table
 	Id     []string
 	Prefix string
 	IsAll  bool
+	Format string
+	Async  bool
 }
 
 func (g *Guard) Export(ctx *context.Context) {
-	panel, prefix := g.table(ctx)
+	panel, prefix, ok := g.table(ctx)
+	if !ok {
+		return
+	}
 	if !panel.GetExportable() {
 		alert(ctx, panel, errors.OperationNotAllow, g.conn, g.navBtns)
 		ctx.Abort()
@@ -28,11 +40,26 @@ func (g *Guard) Export(ctx *context.Context) {
 		ids = strings.Split(id, ",")
 	}
 
+	format := ctx.FormValue("format")
+	switch format {
+	case "":
+		format = ExportFormatExcel
+	case ExportFormatExcel, ExportFormatCSV, ExportFormatNDJSON:
+	case "jsonl":
+		format = ExportFormatNDJSON
+	default:
+		response.BadRequest(ctx, "wrong export format")
+		ctx.Abort()
+		return
+	}
+
 	ctx.SetUserValue(exportParamKey, &ExportParam{
 		Panel:  panel,
 		Id:     ids,
 		Prefix: prefix,
 		IsAll:  ctx.FormValue("is_all") == "true",
+		Format: format,
+		Async:  ctx.FormValue("async") == "true",
 	})
 	ctx.Next()
 }
@@ -1,10 +1,14 @@
 package guard
 
 import (
+	"net/http"
+
 	"github.com/GoAdminGroup/go-admin/context"
 	"github.com/GoAdminGroup/go-admin/modules/db"
 	"github.com/GoAdminGroup/go-admin/modules/errors"
 	"github.com/GoAdminGroup/go-admin/modules/service"
+	"github.com/GoAdminGroup/go-admin/modules/tenant"
+	"github.com/GoAdminGroup/go-admin/plugins/admin/models"
 	"github.com/GoAdminGroup/go-admin/plugins/admin/modules/constant"
 	"github.com/GoAdminGroup/go-admin/plugins/admin/modules/response"
 	"github.com/GoAdminGroup/go-admin/plugins/admin/modules/table"
@@ -28,32 +32,56 @@ func New(s service.List, c db.Connection, t table.GeneratorList, b *types.Button
 	}
 }
 
-func (g *Guard) table(ctx *context.Context) (table.Table, string) {
+// table return the table of the prefix, the ok is false and the 404 is responded
+// when the prefix is not found.
+func (g *Guard) table(ctx *context.Context) (table.Table, string, bool) {
 	prefix := ctx.Query(constant.PrefixKey)
-	return g.tableList[prefix](ctx), prefix
+	gen, ok := g.tableList.Get(prefix)
+	if !ok {
+		TableNotFound(ctx, g.conn, g.navBtns)
+		return nil, prefix, false
+	}
+	t := gen(ctx)
+	t.SetContext(ctx.Request.Context())
+	if tn, ok := tenant.FromContext(ctx); ok {
+		t.SetTenantConnection(tn.Connection)
+	}
+	if user, ok := ctx.User().(models.UserModel); ok {
+		t.SetOperator(user.Id)
+		t.SetUser(user)
+	}
+	return t, prefix, true
 }
 
 func (g *Guard) CheckPrefix(ctx *context.Context) {
 	prefix := ctx.Query(constant.PrefixKey)
 
-	if _, ok := g.tableList[prefix]; !ok {
-		if ctx.IsDataRequest() {
-			response.BadRequest(ctx, errors.Msg)
-		} else {
-			response.Alert(ctx, errors.Msg, errors.Msg, "table model not found", g.conn, g.navBtns,
-				template.Missing404Page)
-		}
-		ctx.Abort()
+	if _, ok := g.tableList.Get(prefix); !ok {
+		TableNotFound(ctx, g.conn, g.navBtns)
 		return
 	}
 
+	CheckTrash(ctx, prefix)
 	ctx.Next()
 }
 
+// TableNotFound responds the 404 of the table model and aborts the request.
+func TableNotFound(ctx *context.Context, conn db.Connection, btns *types.Buttons) {
+	if ctx.IsDataRequest() {
+		response.NotFound(ctx, "table model not found")
+	} else {
+		response.Alert(ctx, errors.Msg, errors.Msg, "table model not found", conn, btns,
+			template.Missing404Page)
+		ctx.SetStatusCode(http.StatusNotFound)
+	}
+	ctx.Abort()
+}
+
 const (
 	editFormParamKey    = "edit_form_param"
 	deleteParamKey      = "delete_param"
 	exportParamKey      = "export_param"
+	importParamKey      = "import_param"
 	serverLoginParamKey = "server_login_param"
 	deleteMenuParamKey  = "delete_menu_param"
 	editMenuParamKey    = "edit_menu_param"
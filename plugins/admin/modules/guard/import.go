@@ -0,0 +1,76 @@
+package guard
+
+import (
+	"github.com/GoAdminGroup/go-admin/context"
+	"github.com/GoAdminGroup/go-admin/modules/auth"
+	"github.com/GoAdminGroup/go-admin/modules/errors"
+	"github.com/GoAdminGroup/go-admin/plugins/admin/modules/form"
+	"github.com/GoAdminGroup/go-admin/plugins/admin/modules/table"
+)
+
+const (
+	ImportStepUpload = "upload"
+	ImportStepDryRun = "dry_run"
+	ImportStepRun    = "run"
+)
+
+type ImportParam struct {
+	Panel  table.Table
+	Prefix string
+	Step   string
+}
+
+func (g *Guard) ShowImport(ctx *context.Context) {
+	panel, prefix, ok := g.table(ctx)
+	if !ok {
+		return
+	}
+	if !panel.GetCanAdd() || panel.GetOnlyInfo() || panel.GetOnlyDetail() || panel.GetOnlyUpdateForm() {
+		alert(ctx, panel, errors.OperationNotAllow, g.conn, g.navBtns)
+		ctx.Abort()
+		return
+	}
+
+	ctx.SetUserValue(importParamKey, &ImportParam{
+		Panel:  panel,
+		Prefix: prefix,
+		Step:   ImportStepUpload,
+	})
+	ctx.Next()
+}
+
+func (g *Guard) Import(ctx *context.Context) {
+	panel, prefix, ok := g.table(ctx)
+	if !ok {
+		return
+	}
+	if !panel.GetCanAdd() || panel.GetOnlyInfo() || panel.GetOnlyDetail() || panel.GetOnlyUpdateForm() {
+		alert(ctx, panel, errors.OperationNotAllow, g.conn, g.navBtns)
+		ctx.Abort()
+		return
+	}
+
+	if !auth.GetTokenService(g.services.MustGet(auth.TokenServiceKey)).CheckToken(ctx.FormValue(form.TokenKey)) {
+		alert(ctx, panel, errors.CreateFailWrongToken, g.conn, g.navBtns)
+		ctx.Abort()
+		return
+	}
+
+	step := ctx.FormValue("__goadmin_import_step")
+	switch step {
+	case ImportStepDryRun, ImportStepRun:
+	default:
+		step = ImportStepUpload
+	}
+
+	ctx.SetUserValue(importParamKey, &ImportParam{
+		Panel:  panel,
+		Prefix: prefix,
+		Step:   step,
+	})
+	ctx.Next()
+}
+
+func GetImportParam(ctx *context.Context) *ImportParam {
+	return ctx.UserValue[importParamKey].(*ImportParam)
+}
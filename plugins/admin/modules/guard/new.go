@@ -22,7 +22,10 @@ type ShowNewFormParam struct {
 }
 
 func (g *Guard) ShowNewForm(ctx *context.Context) {
-	panel, prefix := g.table(ctx)
+	panel, prefix, ok := g.table(ctx)
+	if !ok {
+		return
+	}
 
 	if !panel.GetCanAdd() {
 		alert(ctx, panel, errors.OperationNotAllow, g.conn, g.navBtns)
@@ -81,11 +84,15 @@ func (e NewFormParam) Value() form.Values {
 }
 
 func (g *Guard) NewForm(ctx *context.Context) {
+	panel, prefix, ok := g.table(ctx)
+	if !ok {
+		return
+	}
+
 	var (
-		previous      = ctx.FormValue(form.PreviousKey)
-		panel, prefix = g.table(ctx)
-		conn          = db.GetConnection(g.services)
-		token         = ctx.FormValue(form.TokenKey)
+		previous = ctx.FormValue(form.PreviousKey)
+		conn     = db.GetConnection(g.services)
+		token    = ctx.FormValue(form.TokenKey)
 	)
 
 	if !auth.GetTokenService(g.services.MustGet(auth.TokenServiceKey)).CheckToken(token) {
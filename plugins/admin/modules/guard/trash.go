@@ -0,0 +1,60 @@
+package guard
+
+import (
+	"strings"
+
+	"github.com/GoAdminGroup/go-admin/context"
+	"github.com/GoAdminGroup/go-admin/modules/config"
+	"github.com/GoAdminGroup/go-admin/modules/errors"
+	"github.com/GoAdminGroup/go-admin/plugins/admin/models"
+	"github.com/GoAdminGroup/go-admin/plugins/admin/modules/parameter"
+)
+
+// TrashURL return the url of the trash of the table of the prefix.
+func TrashURL(prefix string) string {
+	return config.Url(strings.ReplaceAll(config.GetURLFormats().Trash, ":__prefix", prefix))
+}
+
+// CheckTrash removes the query of the soft deleted rows from the request when the
+// user has no permission of the trash route of the table, so the trash is not
+// readable by the info, the export or the api routes of the table. It is called
+// once by the CheckPrefix, which all the routes of the tables go through.
+func CheckTrash(ctx *context.Context, prefix string) {
+	if ctx.Query(parameter.Trashed) != parameter.True {
+		return
+	}
+	if user, ok := ctx.User().(models.UserModel); ok && user.CheckPermissionByUrlMethod(TrashURL(prefix), "GET", nil) {
+		return
+	}
+	query := ctx.Request.URL.Query()
+	query.Del(parameter.Trashed)
+	ctx.Request.URL.RawQuery = query.Encode()
+}
+
+// Trash checks the restore and purge requests of the soft deleted rows, they
+// are allowed only when the table is deletable and soft deleted.
+func (g *Guard) Trash(ctx *context.Context) {
+	panel, prefix, ok := g.table(ctx)
+	if !ok {
+		return
+	}
+	if !panel.GetDeletable() || panel.GetSoftDeleteField() == "" {
+		alert(ctx, panel, errors.OperationNotAllow, g.conn, g.navBtns)
+		ctx.Abort()
+		return
+	}
+
+	id := ctx.FormValue("id")
+	if id == "" {
+		alert(ctx, panel, errors.WrongID, g.conn, g.navBtns)
+		ctx.Abort()
+		return
+	}
+
+	ctx.SetUserValue(deleteParamKey, &DeleteParam{
+		Panel:  panel,
+		Id:     id,
+		Prefix: prefix,
+	})
+	ctx.Next()
+}
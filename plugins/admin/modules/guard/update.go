@@ -15,7 +15,10 @@ type UpdateParam struct {
 }
 
 func (g *Guard) Update(ctx *context.Context) {
-	panel, prefix := g.table(ctx)
+	panel, prefix, ok := g.table(ctx)
+	if !ok {
+		return
+	}
 
 	pname := panel.GetPrimaryKey().Name
 
@@ -34,6 +37,11 @@ func (g *Guard) Update(ctx *context.Context) {
 	f.Add(pname, id)
 	f.Add(ctx.FormValue("name"), ctx.FormValue("value"))
 
+	// the version of the row attached by the info page, see types.FormPanel.SetVersionField.
+	if version, ok := ctx.Request.PostForm[form.VersionKey]; ok {
+		f[form.VersionKey] = version
+	}
+
 	ctx.SetUserValue(updateParamKey, &UpdateParam{
 		Panel:  panel,
 		Prefix: prefix,
@@ -151,3 +151,71 @@ func addPageLink(arr []map[string]string, params parameter.Parameters, page int,
 		"url":     params.URLNoAnimation(pageStr),
 	})
 }
+
+// CursorConfig is the config of the paginator of the keyset pagination.
+type CursorConfig struct {
+	Param        parameter.Parameters
+	PageSizeList []string
+	// Count is the count of the rows of the page.
+	Count int
+	// Estimated is the estimated total count, it is not shown when zero.
+	Estimated int
+	// Previous and Next are the cursors of the previous and the next pages, the
+	// links are disabled when they are empty.
+	Previous string
+	Next     string
+	// First links the previous page to the first page, which is for an empty page.
+	First bool
+}
+
+// GetCursor return the paginator of the keyset pagination, which has the previous
+// and the next links instead of the page numbers since the total count is unknown.
+func GetCursor(cfg CursorConfig) types.PaginatorAttribute {
+
+	paginator := template2.Default().Paginator().(*components.PaginatorAttribute)
+
+	switch {
+	case cfg.Previous != "":
+		paginator.PreviousClass = ""
+		paginator.PreviousUrl = cfg.Param.URLPath + cfg.Param.GetCursorRouteParamStr(parameter.Before, cfg.Previous)
+	case cfg.First:
+		paginator.PreviousClass = ""
+		paginator.PreviousUrl = cfg.Param.URLPath + cfg.Param.SetPage("1").GetRouteParamStr()
+	default:
+		paginator.PreviousClass = "disabled"
+		paginator.PreviousUrl = cfg.Param.URLPath
+	}
+
+	if cfg.Next != "" {
+		paginator.NextClass = ""
+		paginator.NextUrl = cfg.Param.URLPath + cfg.Param.GetCursorRouteParamStr(parameter.After, cfg.Next)
+	} else {
+		paginator.NextClass = "disabled"
+		paginator.NextUrl = cfg.Param.URLPath
+	}
+
+	paginator.Url = cfg.Param.URLPath + cfg.Param.GetRouteParamStrWithoutPageSize("1") + "&" + form.NoAnimationKey + "=true"
+	paginator.Total = strconv.Itoa(cfg.Estimated)
+
+	if len(cfg.PageSizeList) == 0 {
+		cfg.PageSizeList = []string{"10", "20", "50", "100"}
+	}
+
+	paginator.Option = make(map[string]template.HTML, len(cfg.PageSizeList))
+	for _, p := range cfg.PageSizeList {
+		paginator.Option[p] = ""
+	}
+
+	paginator.Option[cfg.Param.PageSize] = "selected"
+
+	paginator.Pages = []map[string]string{}
+
+	entriesInfo := fmt.Sprintf(language.Get("showing <b>%s</b> entries"), strconv.Itoa(cfg.Count))
+	if cfg.Estimated > 0 {
+		entriesInfo += fmt.Sprintf(language.Get(", about <b>%s</b> entries in total"), strconv.Itoa(cfg.Estimated))
+	}
+
+	paginator.SetEntriesInfo(template.HTML(entriesInfo))
+
+	return paginator.SetPageSizeList(cfg.PageSizeList)
+}
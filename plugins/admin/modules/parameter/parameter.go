@@ -1,10 +1,14 @@
 package parameter
 
 import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
 	"net/url"
 	"strconv"
 	"strings"
 
+	"github.com/GoAdminGroup/go-admin/modules/db/dialect"
 	"github.com/GoAdminGroup/go-admin/modules/utils"
 	"github.com/GoAdminGroup/go-admin/plugins/admin/modules/constant"
 	"github.com/GoAdminGroup/go-admin/plugins/admin/modules/form"
@@ -18,11 +22,19 @@ type Parameters struct {
 	SortField     string
 	Columns       []string
 	SortType      string
+	// After and Before are the cursors of the keyset pagination, the page is the
+	// rows after or before the row of the cursor.
+	After         string
+	Before        string
+	// Filter is the filter of the filter builder, which is nil when there is no
+	// such a filter.
+	Filter        *dialect.Filter
 	Animation     bool
 	URLPath       string
 	Fields        map[string][]string
 	OrConditions  map[string]string
 	cacheFixedStr url.Values
+	ctx           context.Context
 }
 
 const (
@@ -33,12 +45,17 @@ const (
 	Columns  = "__columns"
 	Prefix   = "__prefix"
 	Pjax     = "_pjax"
+	After    = "__after"
+	Before   = "__before"
+	View     = "__view"
+	Filter   = "__filter"
 
 	sortTypeDesc = "desc"
 	sortTypeAsc  = "asc"
 
 	IsAll      = "__is_all"
 	PrimaryKey = "__pk"
+	Trashed    = "__trashed"
 
 	True  = "true"
 	False = "false"
@@ -72,7 +89,8 @@ var operators = map[string]string{
 }
 
 var globKeyMap = map[string]struct{}{
-	Page: {}, PageSize: {}, Sort: {}, Columns: {}, Prefix: {}, Pjax: {}, form.NoAnimationKey: {},
+	Page: {}, PageSize: {}, Sort: {}, Columns: {}, Prefix: {}, Pjax: {}, form.NoAnimationKey: {}, After: {}, Before: {},
+	View: {}, Filter: {},
 }
 
 func BaseParam() Parameters {
@@ -135,6 +153,9 @@ func GetParam(u *url.URL, defaultPageSize int, p ...string) Parameters {
 		URLPath:      u.Path,
 		SortField:    sortField,
 		SortType:     sortType,
+		After:        values.Get(After),
+		Before:       values.Get(Before),
+		Filter:       DecodeFilter(values.Get(Filter)),
 		Fields:       fields,
 		OrConditions: map[string]string{},
 		Animation:    animation,
@@ -174,11 +195,31 @@ func (param Parameters) IsAll() bool {
 	return param.GetFieldValue(IsAll) == True
 }
 
+// IsTrashed reports whether the soft deleted rows are queried.
+func (param Parameters) IsTrashed() bool {
+	return param.GetFieldValue(Trashed) == True
+}
+
 func (param Parameters) WithURLPath(path string) Parameters {
 	param.URLPath = path
 	return param
 }
 
+// WithContext set the context which the database queries of the parameters
+// are bound to, usually the context of the current request.
+func (param Parameters) WithContext(ctx context.Context) Parameters {
+	param.ctx = ctx
+	return param
+}
+
+// Context return the context of the parameters, context.Background is used when not set.
+func (param Parameters) Context() context.Context {
+	if param.ctx == nil {
+		return context.Background()
+	}
+	return param.ctx
+}
+
 func (param Parameters) WithIsAll(isAll bool) Parameters {
 	if isAll {
 		param.Fields[IsAll] = []string{ True }
@@ -248,6 +289,8 @@ func (param Parameters) GetFieldOperator(field, suffix string) string {
 func (param Parameters) Join() string {
 	p := param.GetFixedParamStr()
 	p.Add(Page, param.Page)
+	if param.After != "" { p.Add(After, param.After) }
+	if param.Before != "" { p.Add(Before, param.Before) }
 	return p.Encode()
 }
 
@@ -288,6 +331,7 @@ func (param Parameters) GetRouteParamStrWithoutPageSize(page string) string {
 	for key, value := range param.Fields {
 		p[key] = value
 	}
+	if param.Filter != nil { p.Add(Filter, EncodeFilter(*param.Filter)) }
 	return "?" + p.Encode()
 }
 
@@ -320,6 +364,47 @@ func (param Parameters) GetNextPageRouteParamStr(cache ...bool) string {
 	return "?" + p.Encode()
 }
 
+// GetCursorRouteParamStr return the route parameters of the page of the keyset
+// pagination after or before the cursor, the key of which is After or Before.
+func (param Parameters) GetCursorRouteParamStr(key, cursor string) string {
+	p := param.GetFixedParamStr()
+	p.Add(key, cursor)
+	return "?" + p.Encode()
+}
+
+// EncodeCursor return the cursor of the keyset pagination of the values, which
+// are the values of the sort field and the primary key of a row. A nil value is
+// the NULL of the database, and encoded as the null of the json.
+func EncodeCursor(values ...*string) string {
+	b, _ := json.Marshal(values)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// DecodeCursor return the values of the cursor, it fails when the cursor is not
+// returned by the EncodeCursor.
+func DecodeCursor(cursor string) ([]*string, error) {
+	b, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil { return nil, err }
+	var values []*string
+	err = json.Unmarshal(b, &values)
+	return values, err
+}
+
+// EncodeFilter return the value of the Filter key in the url of the filter.
+func EncodeFilter(filter dialect.Filter) string {
+	b, _ := json.Marshal(filter)
+	return string(b)
+}
+
+// DecodeFilter return the filter of the value of the Filter key in the url, it is
+// nil when the value is empty or wrong.
+func DecodeFilter(value string) *dialect.Filter {
+	if value == "" { return nil }
+	var filter dialect.Filter
+	if err := json.Unmarshal([]byte(value), &filter); err != nil { return nil }
+	return &filter
+}
+
 func (param Parameters) GetFixedParamStr() url.Values {
 	p := make(url.Values, 4 + len(param.Fields))
 	p.Add(Sort, param.SortField)
@@ -331,6 +416,7 @@ func (param Parameters) GetFixedParamStr() url.Values {
 	for key, value := range param.Fields {
 		p[key] = value
 	}
+	if param.Filter != nil { p.Add(Filter, EncodeFilter(*param.Filter)) }
 	return p
 }
 
@@ -351,6 +437,7 @@ func (param Parameters) GetFixedParamStrWithoutSort() string {
 	for key, value := range param.Fields {
 		p[key] = value
 	}
+	if param.Filter != nil { p.Add(Filter, EncodeFilter(*param.Filter)) }
 	p.Add(form.NoAnimationKey, "true")
 	if len(param.Columns) > 0 {
 		p.Add(Columns, strings.Join(param.Columns, ","))
@@ -10,6 +10,7 @@ import (
 	"github.com/GoAdminGroup/go-admin/modules/errors"
 	"github.com/GoAdminGroup/go-admin/modules/language"
 	"github.com/GoAdminGroup/go-admin/modules/menu"
+	"github.com/GoAdminGroup/go-admin/modules/tenant"
 	"github.com/GoAdminGroup/go-admin/template"
 	"github.com/GoAdminGroup/go-admin/template/types"
 )
@@ -43,6 +44,14 @@ func BadRequest(ctx *context.Context, msg string) {
 	})
 }
 
+// NotFound responds the json of the 404.
+func NotFound(ctx *context.Context, msg string) {
+	ctx.JSON(http.StatusNotFound, map[string]interface{}{
+		"code": http.StatusNotFound,
+		"msg":  language.Get(msg),
+	})
+}
+
 func Alert(ctx *context.Context, desc, title, msg string, conn db.Connection, btns *types.Buttons,
 	pageType ...template.PageType) {
 	user := auth.Auth(ctx)
@@ -64,8 +73,8 @@ func Alert(ctx *context.Context, desc, title, msg string, conn db.Connection, bt
 			Description: description,
 			Title:       pageTitle,
 		},
-		Config:    config.Get(),
-		Menu:      menu.GetGlobalMenu(user, conn, ctx.Lang()).SetActiveClass(config.URLRemovePrefix(ctx.Path())),
+		Config:    tenant.Config(ctx, config.Get()),
+		Menu:      menu.GetGlobalMenu(user, tenant.Conn(ctx, conn), ctx.Lang()).SetActiveClass(config.URLRemovePrefix(ctx.Path())),
 		Animation: true,
 		Buttons:   *btns,
 		IsPjax:    ctx.IsPjax(),
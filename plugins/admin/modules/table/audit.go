@@ -0,0 +1,180 @@
+package table
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/GoAdminGroup/go-admin/modules/db"
+	"github.com/GoAdminGroup/go-admin/modules/logger"
+	"github.com/GoAdminGroup/go-admin/modules/utils"
+	"github.com/GoAdminGroup/go-admin/plugins/admin/models"
+	"github.com/GoAdminGroup/go-admin/plugins/admin/modules/form"
+	"github.com/GoAdminGroup/go-admin/plugins/admin/modules/parameter"
+	"github.com/GoAdminGroup/go-admin/template/types"
+	form2 "github.com/GoAdminGroup/go-admin/template/types/form"
+)
+
+// SetOperator set the user who writes the table, the user is recorded in the audit trail.
+func (tb *DefaultTable) SetOperator(userId int64) {
+	tb.operatorId = userId
+}
+
+// auditable reports whether the writes of the table are recorded in the audit trail,
+// only the tables of the database and the remote sources are audited.
+func (tb *DefaultTable) auditable(table string) bool {
+	return table != "" && table != models.AuditTableName && services != nil &&
+		(tb.remote != nil || tb.connectionDriver != "" && tb.getDataFromDB())
+}
+
+// auditConn return the connection of the audit trail, which is the database of the
+// tenant of the table, or the default database.
+func (tb *DefaultTable) auditConn() db.Connection {
+	conn := db.GetConnection(services)
+	if tb.tenantConnection != "" {
+		return db.WithConnectionName(conn, tb.tenantConnection)
+	}
+	return conn
+}
+
+// auditRows return the rows of the table keyed by the primary key.
+func (tb *DefaultTable) auditRows(table string, ids []string) map[string]map[string]interface{} {
+	if !tb.auditable(table) || len(ids) == 0 {
+		return nil
+	}
+	var (
+		rows []map[string]interface{}
+		err  error
+	)
+	if tb.remote != nil {
+		param := parameter.BaseParam().WithPKs(ids...)
+		param.PageSize, param.PageSizeInt = strconv.Itoa(len(ids)), len(ids)
+		rows, _, err = tb.remote.List(tb.context(), param)
+	} else {
+		// the rows are read from the primary, a replica can lag behind the writes.
+		rows, err = tb.sql().WithPrimary().Table(table).WhereIn(tb.PrimaryKey.Name, interfaces(ids)).All()
+	}
+	if err != nil {
+		logger.Error("query audit rows error: ", err)
+		return nil
+	}
+	res := make(map[string]map[string]interface{}, len(rows))
+	for _, row := range rows {
+		res[fmt.Sprintf("%v", row[tb.PrimaryKey.Name])] = row
+	}
+	return res
+}
+
+func (tb *DefaultTable) auditRow(table, id string) map[string]interface{} {
+	if id == "" {
+		return nil
+	}
+	return tb.auditRows(table, []string{ id })[id]
+}
+
+// audit writes an audit record of the row, the errors are only logged since the
+// write of the row has been done.
+func (tb *DefaultTable) audit(table, action, id string, before, after map[string]interface{}) {
+	if !tb.auditable(table) {
+		return
+	}
+	diff := models.NewAuditDiff(tb.auditValues(before), tb.auditValues(after))
+	if len(diff) == 0 {
+		return
+	}
+	tb.auditRedact(diff)
+	err := models.Audit().SetConn(tb.auditConn()).Record(tb.operatorId, table, id, action, diff)
+	if err != nil {
+		logger.Error("record audit error: ", err)
+	}
+}
+
+// auditFields return the fields of the forms of the table, the columns out of the
+// forms, such as the tokens, are not recorded.
+func (tb *DefaultTable) auditFields() map[string]types.FormField {
+	fields := make(map[string]types.FormField)
+	for _, f := range []*types.FormPanel{ tb.Form, tb.NewForm } {
+		if f == nil {
+			continue
+		}
+		for _, field := range f.FieldList {
+			fields[field.Field] = field
+		}
+	}
+	return fields
+}
+
+// auditValues return the values of the row of the fields of the forms.
+func (tb *DefaultTable) auditValues(row map[string]interface{}) map[string]interface{} {
+	if row == nil {
+		return nil
+	}
+	var (
+		fields = tb.auditFields()
+		res    = make(map[string]interface{}, len(fields))
+	)
+	for field := range fields {
+		if v, ok := row[field]; ok {
+			res[field] = v
+		}
+	}
+	return res
+}
+
+// auditRedact redacts the values of the password fields of the diff, the changes
+// of them are still recorded.
+func (tb *DefaultTable) auditRedact(diff models.AuditDiff) {
+	for name, field := range tb.auditFields() {
+		change, ok := diff[name]
+		if !ok || field.FormType != form2.Password {
+			continue
+		}
+		if change.Before != nil {
+			change.Before = MaskedValue
+		}
+		if change.After != nil {
+			change.After = MaskedValue
+		}
+		diff[name] = change
+	}
+}
+
+// auditUpdate records the diff of the row between the before and the current values.
+func (tb *DefaultTable) auditUpdate(table, action, id string, before map[string]interface{}) {
+	if before == nil {
+		return
+	}
+	tb.audit(table, action, id, before, tb.auditRow(table, id))
+}
+
+// auditInsert records the inserted row, the posted values are recorded when the
+// row can not be found by the primary key.
+func (tb *DefaultTable) auditInsert(table string, id int64, dataList form.Values) {
+	if !tb.auditable(table) {
+		return
+	}
+	pk := dataList.Get(tb.PrimaryKey.Name)
+	if id > 0 {
+		pk = strconv.FormatInt(id, 10)
+	}
+	after := tb.auditRow(table, pk)
+	if after == nil {
+		after = make(map[string]interface{}, len(dataList))
+		for k, v := range dataList {
+			k = strings.ReplaceAll(k, "[]", "")
+			if _, ok := utils.DefaultExceptMap[k]; ok || k == form.PostTypeKey || k == form.PostResultKey ||
+				k == form.PostIsSingleUpdateKey {
+				continue
+			}
+			after[k] = strings.Join(v, ",")
+		}
+	}
+	tb.audit(table, models.AuditActionInsert, pk, nil, after)
+}
+
+// auditDelete records the deleted rows.
+func (tb *DefaultTable) auditDelete(table, action string, rows map[string]map[string]interface{}) {
+	for id, row := range rows {
+		tb.audit(table, action, id, row, nil)
+	}
+}
@@ -0,0 +1,25 @@
+package table
+
+import (
+	"testing"
+
+	"github.com/GoAdminGroup/go-admin/plugins/admin/models"
+	"github.com/GoAdminGroup/go-admin/template/types"
+	"github.com/GoAdminGroup/go-admin/template/types/form"
+	"github.com/magiconair/properties/assert"
+)
+
+func TestAuditValues(t *testing.T) {
+	tb := &DefaultTable{ BaseTable: &BaseTable{
+		Form:    &types.FormPanel{ FieldList: types.FormFields{ { Field: "name" }, { Field: "password", FormType: form.Password } } },
+		NewForm: &types.FormPanel{},
+	} }
+
+	before := tb.auditValues(map[string]interface{}{ "name": "foo", "password": "a", "remember_token": "x" })
+	after  := tb.auditValues(map[string]interface{}{ "name": "foo", "password": "b", "remember_token": "y" })
+	assert.Equal(t, before, map[string]interface{}{ "name": "foo", "password": "a" })
+
+	diff := models.NewAuditDiff(before, after)
+	tb.auditRedact(diff)
+	assert.Equal(t, diff, models.AuditDiff{ "password": { Before: MaskedValue, After: MaskedValue } })
+}
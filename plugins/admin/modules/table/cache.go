@@ -0,0 +1,129 @@
+package table
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/GoAdminGroup/go-admin/modules/db"
+	"github.com/GoAdminGroup/go-admin/modules/utils"
+)
+
+// defaultListCacheSize is the number of the list pages kept by the default cache.
+const defaultListCacheSize = 1024
+
+// ListCacheReplicaLag is the duration after a change of a table during which the
+// list pages of the table are read from the primary database, so that the pages
+// of a lagging read replica are not shown and cached.
+var ListCacheReplicaLag = 5 * time.Second
+
+var (
+	listCacheLock sync.RWMutex
+	listCache     utils.Cache
+	// listCacheGenerations is the generation of every table, which is a part of the
+	// cache keys, so that the invalidation of a table needs no removal from the cache.
+	listCacheGenerations = make(map[string]uint64)
+	// listCacheChanges is the time of the last change of every table.
+	listCacheChanges = make(map[string]time.Time)
+)
+
+// cachedList is a list page in the cache.
+type cachedList struct {
+	rows    []map[string]interface{}
+	size    int
+	expires time.Time
+}
+
+// SetListCache replaces the ARC cache of the list pages, such as a cache shared by
+// the instances. The values of the cache are kept in memory, they are not encoded.
+func SetListCache(cache utils.Cache) {
+	listCacheLock.Lock()
+	defer listCacheLock.Unlock()
+	listCache = cache
+}
+
+func getListCache() utils.Cache {
+	listCacheLock.RLock()
+	cache := listCache
+	listCacheLock.RUnlock()
+	if cache != nil {
+		return cache
+	}
+
+	listCacheLock.Lock()
+	defer listCacheLock.Unlock()
+	if listCache == nil {
+		listCache = utils.MustNewCache(defaultListCacheSize)
+	}
+	return listCache
+}
+
+// InvalidateListCache drops the cached list pages of the tables, for the tables
+// changed out of the DefaultTable.
+func InvalidateListCache(tables ...string) {
+	listCacheLock.Lock()
+	defer listCacheLock.Unlock()
+	for _, table := range tables {
+		if table != "" {
+			listCacheGenerations[table]++
+			listCacheChanges[table] = time.Now()
+		}
+	}
+}
+
+func listCacheGeneration(table string) uint64 {
+	listCacheLock.RLock()
+	defer listCacheLock.RUnlock()
+	return listCacheGenerations[table]
+}
+
+// isRecentlyChanged reports whether the table is changed within the ListCacheReplicaLag.
+func isRecentlyChanged(table string) bool {
+	listCacheLock.RLock()
+	defer listCacheLock.RUnlock()
+	changed, ok := listCacheChanges[table]
+	return ok && time.Since(changed) < ListCacheReplicaLag
+}
+
+// listCacheKey return the cache key of a list page. The statements are built from
+// the normalized parameters and the visible columns of the user, so that they are
+// the key together with the arguments and the database.
+func (tb *DefaultTable) listCacheKey(conn db.Connection, queryCmd, countCmd string, args []interface{}) string {
+	var sb strings.Builder
+	sb.Grow(len(queryCmd) + len(countCmd) + 64)
+	_, _ = fmt.Fprintf(&sb, "%s\x00%s\x00%s\x00%d\x00", conn.Name(), db.ConnectionName(conn, tb.connection),
+		tb.Info.Table, listCacheGeneration(tb.Info.Table))
+	sb.WriteString(queryCmd)
+	sb.WriteByte(0)
+	sb.WriteString(countCmd)
+	for _, arg := range args {
+		_, _ = fmt.Fprintf(&sb, "\x00%v", arg)
+	}
+	return sb.String()
+}
+
+func (tb *DefaultTable) getCachedList(key string) ([]map[string]interface{}, int, bool) {
+	v, ok := getListCache().Get(key)
+	if !ok {
+		return nil, 0, false
+	}
+	item, ok := v.(cachedList)
+	if !ok || time.Now().After(item.expires) {
+		return nil, 0, false
+	}
+	return item.rows, item.size, true
+}
+
+func (tb *DefaultTable) setCachedList(key string, rows []map[string]interface{}, size int) {
+	getListCache().Add(key, cachedList{ rows: rows, size: size, expires: time.Now().Add(tb.Info.CacheTTL) })
+}
+
+// invalidateListCache drops the cached list pages of the table after a change.
+func (tb *DefaultTable) invalidateListCache() {
+	if tb.Form.Table == tb.Info.Table {
+		InvalidateListCache(tb.Info.Table)
+		return
+	}
+	InvalidateListCache(tb.Info.Table, tb.Form.Table)
+}
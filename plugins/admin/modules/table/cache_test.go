@@ -0,0 +1,166 @@
+package table
+
+import (
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/GoAdminGroup/go-admin/modules/config"
+	"github.com/GoAdminGroup/go-admin/modules/db"
+	"github.com/GoAdminGroup/go-admin/modules/service"
+	"github.com/GoAdminGroup/go-admin/modules/utils"
+	"github.com/GoAdminGroup/go-admin/plugins/admin/models"
+	"github.com/GoAdminGroup/go-admin/plugins/admin/modules/form"
+	"github.com/GoAdminGroup/go-admin/plugins/admin/modules/parameter"
+	"github.com/GoAdminGroup/go-admin/template"
+	"github.com/GoAdminGroup/go-admin/template/components"
+	"github.com/GoAdminGroup/go-admin/template/types"
+	form2 "github.com/GoAdminGroup/go-admin/template/types/form"
+	"github.com/magiconair/properties/assert"
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// listTheme is the theme of the paginator of the list pages of the tests.
+type listTheme struct{ template.Template }
+
+func (listTheme) Name() string { return "list_test" }
+
+func (listTheme) Paginator() types.PaginatorAttribute { return components.Base{}.Paginator() }
+
+var listThemeOnce sync.Once
+
+func initListTest() {
+	listThemeOnce.Do(func() { template.Add("list_test", listTheme{}) })
+	utils.InitUtils(100, func(s string) string { return s })
+	config.Initialize(&config.Config{ Language: "en", Theme: "list_test" })
+}
+
+// newListTable return the table of the posts of the sqlite databases, the replica
+// is used when the file is not empty.
+func newListTable(t *testing.T, dir, replica string) (*DefaultTable, db.Connection) {
+	cfg := config.Database{ Driver: db.DriverSqlite, File: filepath.Join(dir, "primary.db") }
+	if replica != "" {
+		cfg.Replicas = []config.Database{ { File: filepath.Join(dir, replica) } }
+	}
+	conn := db.GetSqliteDB().InitDB(map[string]config.Database{ "default": cfg })
+	t.Cleanup(func() { conn.Close() })
+
+	tb := NewDefaultTable(DefaultConfigWithDriver(db.DriverSqlite)).(*DefaultTable)
+	tb.dbObj = conn
+	tb.Info.SetTable("posts")
+	tb.Info.AddField("ID", "id", db.Int)
+	tb.Info.AddField("Title", "title", db.Varchar)
+	tb.Info.AddField("Author", "author", db.Varchar).FieldFilterable()
+	tb.Form.Table = "posts"
+	return tb, conn
+}
+
+func createPosts(t *testing.T, file string, titles ...string) {
+	conn := db.GetSqliteDB().InitDB(map[string]config.Database{
+		"default": { Driver: db.DriverSqlite, File: file },
+	})
+	defer conn.Close()
+	_, err := conn.Exec(`CREATE TABLE posts (id INTEGER PRIMARY KEY, title TEXT, author TEXT)`)
+	assert.Equal(t, err, nil)
+	for _, title := range titles {
+		_, err = conn.Exec(`INSERT INTO posts (title, author) VALUES (?, 'sam')`, title)
+		assert.Equal(t, err, nil)
+	}
+}
+
+func listParams() parameter.Parameters {
+	return parameter.BaseParam().WithIsAll(false)
+}
+
+func TestReplicaReads(t *testing.T) {
+	initListTest()
+	defer func(srv service.List) { services = srv }(services)
+	services = service.List{}
+
+	dir := t.TempDir()
+	createPosts(t, filepath.Join(dir, "primary.db"), "primary")
+	createPosts(t, filepath.Join(dir, "replica.db"), "replica")
+
+	tb, _ := newListTable(t, dir, "replica.db")
+
+	info, err := tb.GetData(listParams())
+	assert.Equal(t, err, nil)
+	assert.Equal(t, info.InfoList[0]["title"].Value, "replica")
+
+	// the rows before a change are read from the primary.
+	assert.Equal(t, tb.auditRow("posts", "1")["title"], "primary")
+
+	// the pages are read from the primary just after a change.
+	tb.invalidateListCache()
+	info, err = tb.GetData(listParams())
+	assert.Equal(t, err, nil)
+	assert.Equal(t, info.InfoList[0]["title"].Value, "primary")
+
+	defer func(lag time.Duration) { ListCacheReplicaLag = lag }(ListCacheReplicaLag)
+	ListCacheReplicaLag = 0
+	info, err = tb.GetData(listParams())
+	assert.Equal(t, err, nil)
+	assert.Equal(t, info.InfoList[0]["title"].Value, "replica")
+}
+
+func TestListCache(t *testing.T) {
+	initListTest()
+	// the writes are not audited.
+	defer func(srv service.List) { services = srv }(services)
+	services = nil
+
+	dir := t.TempDir()
+	createPosts(t, filepath.Join(dir, "primary.db"), "hello")
+
+	tb, conn := newListTable(t, dir, "")
+	tb.Info.CacheTTL = time.Minute
+	tb.Form.AddField("ID", "id", db.Int, form2.Default)
+	tb.Form.AddField("Title", "title", db.Varchar, form2.Text)
+	tb.Form.AddField("Author", "author", db.Varchar, form2.Text)
+
+	titles := func(params parameter.Parameters) []string {
+		info, err := tb.GetData(params)
+		assert.Equal(t, err, nil)
+		var res []string
+		for _, row := range info.InfoList {
+			res = append(res, row["title"].Value)
+		}
+		return res
+	}
+
+	// the hit skips the query, the change out of the table is not seen.
+	assert.Equal(t, titles(listParams()), []string{ "hello" })
+	_, err := conn.Exec(`UPDATE posts SET title = 'outside' WHERE id = 1`)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, titles(listParams()), []string{ "hello" })
+
+	// the insert, the update and the delete of the table drop the cached pages.
+	assert.Equal(t, tb.InsertData(form.Values{ "title": { "bye" }, "author": { "tom" } }), nil)
+	assert.Equal(t, titles(listParams()), []string{ "outside", "bye" })
+	assert.Equal(t, tb.UpdateData(form.Values{ "id": { "1" }, "title": { "again" }, "author": { "sam" } }), nil)
+	assert.Equal(t, titles(listParams()), []string{ "again", "bye" })
+	assert.Equal(t, tb.DeleteData("2"), nil)
+	assert.Equal(t, titles(listParams()), []string{ "again" })
+	assert.Equal(t, tb.InsertData(form.Values{ "title": { "bye" }, "author": { "tom" } }), nil)
+
+	// the pages of the different filters and the policies of the different users
+	// are cached apart.
+	filter := func(author string) parameter.Parameters {
+		params := listParams()
+		params.Fields["author"] = []string{ author }
+		return params
+	}
+	assert.Equal(t, titles(filter("sam")), []string{ "again" })
+	assert.Equal(t, titles(filter("tom")), []string{ "bye" })
+
+	policies := []DataPolicy{ FilterRows([]string{ "author" }, "posts.author = {{.AuthUser}}") }
+	for _, name := range []string{ "sam", "tom", "sam" } {
+		tb.policy = newUserPolicy(policies, models.UserModel{ Id: 2, UserName: name, Roles: []models.RoleModel{ { Slug: "author" } } })
+		want := "again"
+		if name == "tom" {
+			want = "bye"
+		}
+		assert.Equal(t, titles(listParams()), []string{ want })
+	}
+}
@@ -5,20 +5,35 @@ import (
 )
 
 type Config struct {
-	Driver         string
-	DriverMode     string
-	Connection     string
-	CanAdd         bool
-	Editable       bool
-	Deletable      bool
-	Exportable     bool
-	PrimaryKey     PrimaryKey
-	SourceURL      string
-	GetDataFun     GetDataFun
-	OnlyInfo       bool
-	OnlyNewForm    bool
-	OnlyUpdateForm bool
-	OnlyDetail     bool
+	Driver          string
+	DriverMode      string
+	Connection      string
+	CanAdd          bool
+	Editable        bool
+	Deletable       bool
+	Exportable      bool
+	PrimaryKey      PrimaryKey
+	// SourceURL is the url of the remote source of the table, see the RemoteSource.
+	SourceURL       string
+	// Remote is the remote source of the table with the headers, the timeout and
+	// the retries, it takes the place of the SourceURL.
+	Remote          *RemoteSource
+	GetDataFun      GetDataFun
+	OnlyInfo        bool
+	OnlyNewForm     bool
+	OnlyUpdateForm  bool
+	OnlyDetail      bool
+	// SoftDeleteField is the column marking the row deleted, the rows
+	// are deleted physically when it is empty.
+	SoftDeleteField string
+	// KeysetPagination pages the list by the cursors instead of the offset, see
+	// the InfoPanel.SetKeysetPagination.
+	KeysetPagination bool
+	// EstimatedCount reads the total count from the statistics of the database,
+	// see the InfoPanel.SetEstimatedCount.
+	EstimatedCount bool
+	// Policies are the data permissions of the roles, see the DataPolicy.
+	Policies []DataPolicy
 }
 
 func DefaultConfig() Config {
@@ -62,6 +77,17 @@ func (config Config) SetSourceURL(url string) Config {
 	return config
 }
 
+func (config Config) SetRemoteSource(source *RemoteSource) Config {
+	config.Remote = source
+	return config
+}
+
+// SetPolicies set the data permissions of the roles on the table.
+func (config Config) SetPolicies(policies ...DataPolicy) Config {
+	config.Policies = policies
+	return config
+}
+
 func (config Config) SetGetDataFun(fun GetDataFun) Config {
 	config.GetDataFun = fun
 	return config
@@ -77,6 +103,30 @@ func (config Config) SetDeletable(deletable bool) Config {
 	return config
 }
 
+// SetSoftDelete marks the deleted rows by the given column instead of deleting
+// them, the column is DefaultSoftDeleteField when not given.
+func (config Config) SetSoftDelete(field ...string) Config {
+	config.SoftDeleteField = DefaultSoftDeleteField
+	if len(field) > 0 && field[0] != "" {
+		config.SoftDeleteField = field[0]
+	}
+	return config
+}
+
+// SetKeysetPagination pages the list by the cursors of the sort field and the
+// primary key instead of the offset.
+func (config Config) SetKeysetPagination() Config {
+	config.KeysetPagination = true
+	return config
+}
+
+// SetEstimatedCount reads the total count of the list from the statistics of the
+// database instead of the count query.
+func (config Config) SetEstimatedCount() Config {
+	config.EstimatedCount = true
+	return config
+}
+
 func (config Config) SetOnlyInfo() Config {
 	config.OnlyInfo = true
 	return config
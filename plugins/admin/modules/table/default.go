@@ -1,6 +1,8 @@
 package table
 
 import (
+	"context"
+	dbsql "database/sql"
 	"errors"
 	"fmt"
 	"github.com/GoAdminGroup/go-admin/modules/config"
@@ -10,6 +12,7 @@ import (
 	"github.com/GoAdminGroup/go-admin/modules/language"
 	"github.com/GoAdminGroup/go-admin/modules/logger"
 	"github.com/GoAdminGroup/go-admin/modules/utils"
+	"github.com/GoAdminGroup/go-admin/plugins/admin/models"
 	"github.com/GoAdminGroup/go-admin/plugins/admin/modules"
 	"github.com/GoAdminGroup/go-admin/plugins/admin/modules/constant"
 	"github.com/GoAdminGroup/go-admin/plugins/admin/modules/form"
@@ -17,8 +20,6 @@ import (
 	"github.com/GoAdminGroup/go-admin/plugins/admin/modules/parameter"
 	"github.com/GoAdminGroup/go-admin/template/types"
 	"html/template"
-	"io"
-	"net/http"
 	"runtime/debug"
 	"strconv"
 	"strings"
@@ -30,9 +31,14 @@ type DefaultTable struct {
 	connectionDriver     string
 	connectionDriverMode string
 	connection           string
-	sourceURL            string
+	remote               *RemoteSource
 	getDataFun           GetDataFun
 	dbObj                db.Connection
+	operatorId           int64
+	tenantConnection     string
+	reqCtx               context.Context
+	policies             []DataPolicy
+	policy               *userPolicy
 }
 
 type GetDataFun func(params parameter.Parameters) ([]map[string]interface{}, int)
@@ -46,32 +52,50 @@ func NewDefaultTable(cfgs ...Config) Table {
 		cfg = DefaultConfig()
 	}
 
+	info := types.NewInfoPanel(cfg.PrimaryKey.Name)
+	info.KeysetPagination = cfg.KeysetPagination
+	info.EstimatedCount   = cfg.EstimatedCount
+
+	remote := cfg.Remote
+	if remote == nil && cfg.SourceURL != "" {
+		remote = &RemoteSource{ URL: cfg.SourceURL }
+	}
+
 	return &DefaultTable{
 		BaseTable: &BaseTable{
-			Info:           types.NewInfoPanel(cfg.PrimaryKey.Name),
-			Form:           types.NewFormPanel(),
-			NewForm:        types.NewFormPanel(),
-			Detail:         types.NewInfoPanel(cfg.PrimaryKey.Name),
-			CanAdd:         cfg.CanAdd,
-			Editable:       cfg.Editable,
-			Deletable:      cfg.Deletable,
-			Exportable:     cfg.Exportable,
-			PrimaryKey:     cfg.PrimaryKey,
-			OnlyNewForm:    cfg.OnlyNewForm,
-			OnlyUpdateForm: cfg.OnlyUpdateForm,
-			OnlyDetail:     cfg.OnlyDetail,
-			OnlyInfo:       cfg.OnlyInfo,
+			Info:            info,
+			Form:            types.NewFormPanel(),
+			NewForm:         types.NewFormPanel(),
+			Detail:          types.NewInfoPanel(cfg.PrimaryKey.Name),
+			CanAdd:          cfg.CanAdd,
+			Editable:        cfg.Editable,
+			Deletable:       cfg.Deletable,
+			Exportable:      cfg.Exportable,
+			PrimaryKey:      cfg.PrimaryKey,
+			OnlyNewForm:     cfg.OnlyNewForm,
+			OnlyUpdateForm:  cfg.OnlyUpdateForm,
+			OnlyDetail:      cfg.OnlyDetail,
+			OnlyInfo:        cfg.OnlyInfo,
+			SoftDeleteField: cfg.SoftDeleteField,
 		},
 		connectionDriver:     cfg.Driver,
 		connectionDriverMode: cfg.DriverMode,
 		connection:           cfg.Connection,
-		sourceURL:            cfg.SourceURL,
+		remote:               remote,
 		getDataFun:           cfg.GetDataFun,
+		policies:             cfg.Policies,
 	}
 }
 
 // Copy copy a new table.Table from origin DefaultTable
 func (tb *DefaultTable) Copy() Table {
+	info := types.NewInfoPanel(tb.PrimaryKey.Name).SetTable(tb.Info.Table).
+		SetDescription(tb.Info.Description).
+		SetTitle(tb.Info.Title).
+		SetGetDataFn(tb.Info.GetDataFn)
+	info.KeysetPagination = tb.Info.KeysetPagination
+	info.EstimatedCount   = tb.Info.EstimatedCount
+
 	return &DefaultTable{
 		BaseTable: &BaseTable{
 			Form: types.NewFormPanel().SetTable(tb.Form.Table).
@@ -80,25 +104,28 @@ func (tb *DefaultTable) Copy() Table {
 			NewForm: types.NewFormPanel().SetTable(tb.Form.Table).
 				SetDescription(tb.Form.Description).
 				SetTitle(tb.Form.Title),
-			Info: types.NewInfoPanel(tb.PrimaryKey.Name).SetTable(tb.Info.Table).
-				SetDescription(tb.Info.Description).
-				SetTitle(tb.Info.Title).
-				SetGetDataFn(tb.Info.GetDataFn),
+			Info: info,
 			Detail: types.NewInfoPanel(tb.PrimaryKey.Name).SetTable(tb.Detail.Table).
 				SetDescription(tb.Detail.Description).
 				SetTitle(tb.Detail.Title).
 				SetGetDataFn(tb.Detail.GetDataFn),
-			CanAdd:     tb.CanAdd,
-			Editable:   tb.Editable,
-			Deletable:  tb.Deletable,
-			Exportable: tb.Exportable,
-			PrimaryKey: tb.PrimaryKey,
+			CanAdd:          tb.CanAdd,
+			Editable:        tb.Editable,
+			Deletable:       tb.Deletable,
+			Exportable:      tb.Exportable,
+			PrimaryKey:      tb.PrimaryKey,
+			SoftDeleteField: tb.SoftDeleteField,
 		},
 		connectionDriver:     tb.connectionDriver,
 		connectionDriverMode: tb.connectionDriverMode,
 		connection:           tb.connection,
-		sourceURL:            tb.sourceURL,
+		remote:               tb.remote,
 		getDataFun:           tb.getDataFun,
+		operatorId:           tb.operatorId,
+		tenantConnection:     tb.tenantConnection,
+		reqCtx:               tb.reqCtx,
+		policies:             tb.policies,
+		policy:               tb.policy,
 	}
 }
 
@@ -133,8 +160,9 @@ func (tb *DefaultTable) GetData(params parameter.Parameters) (PanelInfo, error)
 
 	if tb.getDataFun != nil {
 		data, size = tb.getDataFun(params)
-	} else if tb.sourceURL != "" {
-		data, size = tb.getDataFromURL(params)
+	} else if tb.remote != nil {
+		var err error
+		if data, size, err = tb.getDataFromURL(params); err != nil { return PanelInfo{}, err }
 	} else if tb.Info.GetDataFn != nil {
 		data, size = tb.Info.GetDataFn(params)
 	} else if params.IsAll() {
@@ -166,6 +194,7 @@ func (tb *DefaultTable) GetData(params parameter.Parameters) (PanelInfo, error)
 		Title:          tb.Info.Title,
 		FilterFormData: filterForm,
 		Description:    tb.Info.Description,
+		Size:           size,
 	}, nil
 }
 
@@ -174,34 +203,15 @@ type GetDataFromURLRes struct {
 	Size int
 }
 
-func (tb *DefaultTable) getDataFromURL(params parameter.Parameters) ([]map[string]interface{}, int) {
-	u := ""
-	if strings.ContainsRune(tb.sourceURL, '?') {
-		u = utils.StrConcat(tb.sourceURL, "&", params.Join())
-	} else {
-		u = utils.StrConcat(tb.sourceURL, "?", params.Join())
-	}
-
-	res, err := http.Get(utils.StrConcat(u, "&pk=", strings.Join(params.PKs(), ",")))
+// getDataFromURL return the rows of the remote source. The error is set to the
+// info panel for the list page, and returned for the exports.
+func (tb *DefaultTable) getDataFromURL(params parameter.Parameters) ([]map[string]interface{}, int, error) {
+	data, size, err := tb.remote.List(params.Context(), params)
 	if err != nil {
-		return []map[string]interface{}{}, 0
+		logger.Error("get data from remote source error: ", err)
+		tb.Info.SetError(err)
 	}
-
-	defer res.Body.Close()
-
-	body, err := io.ReadAll(res.Body)
-	if err != nil {
-		return []map[string]interface{}{}, 0
-	}
-
-	var data GetDataFromURLRes
-
-	err = utils.JsonUnmarshal(body, &data)
-	if err != nil {
-		return []map[string]interface{}{}, 0
-	}
-
-	return data.Data, data.Size
+	return data, size, err
 }
 
 // GetDataWithIds query the data set.
@@ -214,8 +224,9 @@ func (tb *DefaultTable) GetDataWithIds(params parameter.Parameters) (PanelInfo,
 
 	if tb.getDataFun != nil {
 		data, size = tb.getDataFun(params)
-	} else if tb.sourceURL != "" {
-		data, size = tb.getDataFromURL(params)
+	} else if tb.remote != nil {
+		var err error
+		if data, size, err = tb.getDataFromURL(params); err != nil { return PanelInfo{}, err }
 	} else if tb.Info.GetDataFn != nil {
 		data, size = tb.Info.GetDataFn(params)
 	} else {
@@ -240,10 +251,13 @@ func (tb *DefaultTable) GetDataWithIds(params parameter.Parameters) (PanelInfo,
 		Title:          tb.Info.Title,
 		FilterFormData: filterForm,
 		Description:    tb.Info.Description,
+		Size:           size,
 	}, nil
 }
 
 func (tb *DefaultTable) getTempModelData(res map[string]interface{}, params parameter.Parameters, columnMap map[string]struct{}) map[string]types.InfoItem {
+	res = tb.policyMaskRow(res)
+
 	tempModelData := map[string]types.InfoItem{
 		"__goadmin_edit_params"  : {},
 		"__goadmin_delete_params": {},
@@ -348,11 +362,40 @@ func (tb *DefaultTable) getTempModelData(res map[string]interface{}, params para
 }
 
 func (tb *DefaultTable) getAllDataFromDatabase(params parameter.Parameters) (PanelInfo, error) {
+	thead, queryCmd, whereArgs, columnMap, err := tb.getAllDataQuery(params)
+	if err != nil {
+		return PanelInfo{}, err
+	}
+
+	res, err := tb.db().QueryWithConnectionContext(params.Context(), tb.connection, queryCmd, whereArgs...)
+
+	if err != nil {
+		return PanelInfo{}, err
+	}
+
+	infoList := make([]map[string]types.InfoItem, len(res))
+	for i, e := range res {
+		infoList[i] = tb.getTempModelData(e, params, columnMap)
+	}
+
+	return PanelInfo{
+		InfoList:    infoList,
+		Thead:       thead,
+		Title:       tb.Info.Title,
+		Description: tb.Info.Description,
+		Size:        len(res),
+	}, nil
+}
+
+// getAllDataQuery return the thead and the statement querying all the data of the
+// given parameters without the pagination.
+func (tb *DefaultTable) getAllDataQuery(params parameter.Parameters) (types.Thead, string, []interface{}, map[string]struct{}, error) {
 	conn   := tb.db()
 	delim  := conn.GetDelimiter()
 	delim2 := conn.GetDelimiter2()
 	dl     := len(delim) + len(delim2)
 	pkl    := dl + len(tb.PrimaryKey.Name)
+	params  = tb.policyParameters(params)
 
 	const q1 = "SELECT %s FROM %s %s %s %s ORDER BY "
 	const q2 = "%s"
@@ -407,6 +450,12 @@ func (tb *DefaultTable) getAllDataFromDatabase(params parameter.Parameters) (Pan
 		nil, columnMap, nil, tb.Info.FieldList.GetFieldFilterProcessValue)
 	wheres, whereArgs = tb.Info.Wheres.Statement(wheres, delim, delim2, whereArgs, existKeys, columnMap)
 	wheres, whereArgs = tb.Info.WhereRaws.Statement(wheres, whereArgs)
+	wheres = tb.softDeleteStatement(wheres, params, delim, delim2)
+	wheres, whereArgs, err := tb.filterStatement(wheres, whereArgs, params, delim, delim2, columnMap)
+	if err != nil {
+		return thead, "", nil, columnMap, err
+	}
+	wheres, whereArgs = tb.policyStatement(wheres, whereArgs)
 
 	if wheres != "" {
 		wheres = "WHERE " + wheres
@@ -420,23 +469,48 @@ func (tb *DefaultTable) getAllDataFromDatabase(params parameter.Parameters) (Pan
 		wheres, groupBy.String(), tb.Info.Table, params.SortField, params.SortType)
 	logger.LogSQL(queryCmd, whereArgs)
 
-	res, err := conn.QueryWithConnection(tb.connection, queryCmd, whereArgs...)
+	return thead, queryCmd, whereArgs, columnMap, nil
+}
 
-	if err != nil {
-		return PanelInfo{}, err
+// EachData iterates over the data set of the given parameters row by row. When all
+// the data of the database is required, the rows are read from a cursor and never
+// loaded into the memory together, otherwise they are taken from GetData.
+func (tb *DefaultTable) EachData(params parameter.Parameters, theadFn TheadFn, rowFn InfoRowFn) error {
+	eachPanelInfo := func(info PanelInfo, err error) error {
+		if err != nil { return err }
+		if err := theadFn(info.Thead); err != nil { return err }
+		for _, row := range info.InfoList {
+			if err := rowFn(row); err != nil { return err }
+		}
+		return nil
 	}
 
-	infoList := make([]map[string]types.InfoItem, len(res))
-	for i, e := range res {
-		infoList[i] = tb.getTempModelData(e, params, columnMap)
+	if len(params.PKs()) > 0 {
+		return eachPanelInfo(tb.GetDataWithIds(params))
+	}
+	if !tb.getDataFromDB() || !params.IsAll() {
+		return eachPanelInfo(tb.GetData(params))
 	}
 
-	return PanelInfo{
-		InfoList:    infoList,
-		Thead:       thead,
-		Title:       tb.Info.Title,
-		Description: tb.Info.Description,
-	}, nil
+	if tb.Info.UpdateParametersFns != nil {
+		for _, fn := range tb.Info.UpdateParametersFns {
+			fn(&params)
+		}
+	}
+
+	if tb.Info.QueryFilterFn != nil {
+		if ids, stopQuery := tb.Info.QueryFilterFn(params, tb.db()); stopQuery {
+			return eachPanelInfo(tb.GetDataWithIds(params.WithPKs(ids...)))
+		}
+	}
+
+	thead, queryCmd, args, columnMap, err := tb.getAllDataQuery(params)
+	if err != nil { return err }
+	if err := theadFn(thead); err != nil { return err }
+
+	return tb.db().QueryEachWithConnectionContext(params.Context(), tb.connection, func(row map[string]interface{}) error {
+		return rowFn(tb.getTempModelData(row, params, columnMap))
+	}, queryCmd, args...)
 }
 
 // TODO: refactor
@@ -454,8 +528,16 @@ func (tb *DefaultTable) getDataFromDatabase(params parameter.Parameters) (PanelI
 		isMssql     = conn.Name() == db.DriverMssql
 	)
 
+	// the pages just after a change are read from the primary, so that neither the
+	// page nor the cache has the lag of the replicas.
+	if isRecentlyChanged(tb.Info.Table) {
+		params = params.WithContext(db.WithPrimary(params.Context()))
+	}
+
 	benchmark := utils.StartBenchmark()
 
+	params = tb.policyParameters(params)
+
 	if len(ids) > 0 {
 		countExtra := ""
 		if isMssql { countExtra = " AS [size]" }
@@ -481,6 +563,14 @@ func (tb *DefaultTable) getDataFromDatabase(params parameter.Parameters) (PanelI
 	columnMap, _ := tb.getColumnMap(tb.Info.Table)
 	thead, fields, joinFields, joins, joinTableMap, filterForm := tb.getTheadAndFilterForm(params, columnMap)
 
+	versionField := ""
+	if tb.Info.Table == tb.Form.Table {
+		versionField = tb.versionColumn(columnMap)
+	}
+	if versionField != "" {
+		fields = utils.StrConcat(fields, table, ".", modules.Delimiter(delim, delim2, versionField), ",")
+	}
+
 	fields      += pk
 	allFields   := fields
 	groupFields := fields
@@ -505,10 +595,28 @@ func (tb *DefaultTable) getDataFromDatabase(params parameter.Parameters) (PanelI
 	}
 
 	var (
-		wheres    = ""
-		whereArgs []interface{}
-		args      []interface{}
-		existKeys map[string]struct{}
+		keyset   *keysetPage
+		sortType = params.SortType
+	)
+
+	if tb.Info.KeysetPagination && len(ids) == 0 {
+		keyset    = tb.newKeysetPage(params)
+		sortType  = keyset.sortType
+		queryStmt = keyset.statement(isMssql, placeholder, pk)
+		// the cursors are made of the values of the sort field.
+		sortField := utils.StrConcat(table, ".", modules.Delimiter(delim, delim2, params.SortField))
+		if !strings.Contains(allFields + ",", sortField + ",") {
+			allFields   = utils.StrConcat(sortField, ",", allFields)
+			groupFields = utils.StrConcat(sortField, ",", groupFields)
+		}
+	}
+
+	var (
+		wheres      = ""
+		countWheres = ""
+		whereArgs   []interface{}
+		args        []interface{}
+		existKeys   map[string]struct{}
 	)
 
 	if len(ids) > 0 {
@@ -527,6 +635,14 @@ func (tb *DefaultTable) getDataFromDatabase(params parameter.Parameters) (PanelI
 		}
 		wheres = sb.String()
 		//wheres = wheres[:len(wheres)-1]
+		// the statement is "pk IN (%s)", which closes the parenthesis of the rows.
+		if tb.SoftDeleteField != "" {
+			wheres = utils.StrConcat(wheres, ") AND (", tb.softDeleteStatement("", params, conn.GetDelimiter(), conn.GetDelimiter2()))
+		}
+		if tb.policy != nil && tb.policy.rows != "" {
+			wheres = utils.StrConcat(wheres, ") AND (", tb.policy.rows)
+			args   = append(args, tb.policy.args...)
+		}
 	} else {
 		// parameter
 		wheres, whereArgs, existKeys = params.Statement(wheres, tb.Info.Table, conn.GetDelimiter(), conn.GetDelimiter2(),
@@ -534,12 +650,37 @@ func (tb *DefaultTable) getDataFromDatabase(params parameter.Parameters) (PanelI
 		// pre query
 		wheres, whereArgs = tb.Info.Wheres.Statement(wheres, conn.GetDelimiter(), conn.GetDelimiter2(), whereArgs, existKeys, columnMap)
 		wheres, whereArgs = tb.Info.WhereRaws.Statement(wheres, whereArgs)
+		wheres = tb.softDeleteStatement(wheres, params, conn.GetDelimiter(), conn.GetDelimiter2())
+		var err error
+		if wheres, whereArgs, err = tb.filterStatement(wheres, whereArgs, params, delim, delim2, columnMap); err != nil {
+			return PanelInfo{}, err
+		}
+		wheres, whereArgs = tb.policyStatement(wheres, whereArgs)
+
+		// the count leaves out the cursor of the keyset pagination.
+		if wheres != "" {
+			countWheres = "WHERE " + wheres
+		}
+
+		var cursorArgs []interface{}
+		if keyset != nil {
+			var cursorWhere string
+			cursorWhere, cursorArgs = keyset.where(table, pk, delim, delim2, conn.Name() == db.DriverPostgresql)
+			if cursorWhere != "" && wheres != "" {
+				wheres = utils.StrConcat("(", wheres, ") AND ", cursorWhere)
+			} else if cursorWhere != "" {
+				wheres = cursorWhere
+			}
+		}
 
 		if wheres != "" {
 			wheres = "WHERE " + wheres
 		}
 
-		if isMssql {
+		if keyset != nil {
+			// one more row tells whether there is the next page.
+			args = append(append(append(args, whereArgs...), cursorArgs...), params.PageSizeInt + 1)
+		} else if isMssql {
 			args = append(whereArgs, (params.PageInt - 1) * params.PageSizeInt, params.PageInt * params.PageSizeInt)
 		} else {
 			args = append(whereArgs, params.PageSizeInt, (params.PageInt - 1) * params.PageSizeInt)
@@ -563,32 +704,49 @@ func (tb *DefaultTable) getDataFromDatabase(params parameter.Parameters) (PanelI
 
 	queryCmd := ""
 	if isMssql && len(ids) == 0 {
-		queryCmd = fmt.Sprintf(queryStmt, tb.Info.Table, params.SortField, params.SortType,
+		queryCmd = fmt.Sprintf(queryStmt, tb.Info.Table, params.SortField, sortType,
 			allFields, tb.Info.Table, joins, wheres, groupBy)
 	} else {
 		queryCmd = fmt.Sprintf(queryStmt, allFields, tb.Info.Table, joins, wheres, groupBy,
-			tb.Info.Table, params.SortField, params.SortType)
+			tb.Info.Table, params.SortField, sortType)
 	}
 
-	logger.LogSQL(queryCmd, args)
-	res, err := conn.QueryWithConnection(tb.connection, queryCmd, args...)
+	// the statistics of the database count all the rows of the table, so the rows
+	// are counted when they are filtered by the wheres or the policy.
+	estimated := tb.Info.EstimatedCount && countWheres == ""
 
-	if err != nil {
-		return PanelInfo{}, err
+	countCmd := ""
+	if len(ids) == 0 && !estimated && (keyset == nil || tb.Info.EstimatedCount) {
+		countCmd = fmt.Sprintf(countStmt, tb.Info.Table, joins, countWheres, groupBy)
 	}
 
-	infoList := make([]map[string]types.InfoItem, len(res))
-	for i, e := range res {
-		infoList[i] = tb.getTempModelData(e, params, columnMap)
+	var (
+		res      []map[string]interface{}
+		size     int
+		cached   bool
+		cacheKey string
+	)
+
+	if tb.Info.CacheTTL > 0 {
+		cacheKey = tb.listCacheKey(conn, queryCmd, countCmd, args)
+		res, size, cached = tb.getCachedList(cacheKey)
 	}
 
-	// TODO: use the dialect
-	var size int
+	if !cached {
+		logger.LogSQL(queryCmd, args)
+		var err error
+		res, err = conn.QueryWithConnectionContext(params.Context(), tb.connection, queryCmd, args...)
 
-	if len(ids) == 0 {
-		countCmd := fmt.Sprintf(countStmt, tb.Info.Table, joins, wheres, groupBy)
+		if err != nil {
+			return PanelInfo{}, err
+		}
+	}
 
-		total, err := conn.QueryWithConnection(tb.connection, countCmd, whereArgs...)
+	// TODO: use the dialect
+	if len(ids) == 0 && !cached && estimated {
+		size = tb.estimatedCount(conn, params)
+	} else if countCmd != "" && !cached {
+		total, err := conn.QueryWithConnectionContext(params.Context(), tb.connection, countCmd, whereArgs...)
 		if err != nil { return PanelInfo{}, err }
 
 		logger.LogSQL(countCmd, nil)
@@ -620,13 +778,44 @@ func (tb *DefaultTable) getDataFromDatabase(params parameter.Parameters) (PanelI
 		}*/
 	}
 
+	if tb.Info.CacheTTL > 0 && !cached {
+		tb.setCachedList(cacheKey, res, size)
+	}
+
+	var more bool
+	if keyset != nil {
+		res, more = keyset.rows(res, params.PageSizeInt)
+	}
+
+	var versions map[string]string
+	if versionField != "" {
+		versions = make(map[string]string, len(res))
+	}
+
+	infoList := make([]map[string]types.InfoItem, len(res))
+	for i, e := range res {
+		infoList[i] = tb.getTempModelData(e, params, columnMap)
+		if versions != nil {
+			versions[fmt.Sprintf("%v", e[tb.PrimaryKey.Name])] = versionString(e[versionField])
+		}
+	}
+
+	var pg types.PaginatorAttribute
+	if keyset != nil {
+		pg = tb.keysetPaginator(keyset, params, res, more, size, template.HTML(elapsedQueryTime(benchmark)))
+	} else {
+		pg = tb.GetPaginator(size, params, template.HTML(elapsedQueryTime(benchmark)))
+	}
+
 	return PanelInfo{
 		Thead:          thead,
 		InfoList:       infoList,
-		Paginator:      tb.GetPaginator(size, params, template.HTML(elapsedQueryTime(benchmark))),
+		Paginator:      pg,
 		Title:          tb.Info.Title,
 		FilterFormData: filterForm,
 		Description:    tb.Info.Description,
+		Size:           size,
+		Versions:       versions,
 	}, nil
 }
 
@@ -656,8 +845,13 @@ func (tb *DefaultTable) GetDataWithId(param parameter.Parameters) (FormInfo, err
 
 	if tb.getDataFun != nil {
 		res = getDataRes(tb.getDataFun(param))
-	} else if tb.sourceURL != "" {
-		res = getDataRes(tb.getDataFromURL(param))
+	} else if tb.remote != nil {
+		list, _, err := tb.remote.List(param.Context(), param)
+		if err != nil { return FormInfo{ Title: tb.Form.Title, Description: tb.Form.Description }, err }
+		if len(list) == 0 {
+			return FormInfo{ Title: tb.Form.Title, Description: tb.Form.Description }, errors.New(errs.WrongID)
+		}
+		res = list[0]
 	} else if tb.Detail.GetDataFn != nil {
 		res = getDataRes(tb.Detail.GetDataFn(param))
 	} else if tb.Info.GetDataFn != nil {
@@ -689,7 +883,21 @@ func (tb *DefaultTable) GetDataWithId(param parameter.Parameters) (FormInfo, err
 		queryStmt.Grow(512)
 		queryStmt.WriteString("SELECT %s FROM %s %s WHERE ")
 		queryStmt.WriteString(pk)
-		queryStmt.WriteString(" = ? %s")
+		queryStmt.WriteString(" = ? ")
+		if tb.SoftDeleteField != "" && !param.IsTrashed() {
+			queryStmt.WriteString("AND ")
+			queryStmt.WriteString(tableName)
+			queryStmt.WriteByte('.')
+			queryStmt.WriteString(modules.Delimiter(delim, delim2, tb.SoftDeleteField))
+			queryStmt.WriteString(" IS NULL ")
+		}
+		if tb.policy != nil && tb.policy.rows != "" {
+			queryStmt.WriteString("AND (")
+			queryStmt.WriteString(strings.ReplaceAll(tb.policy.rows, "%", "%%"))
+			queryStmt.WriteString(") ")
+			args = append(args, tb.policy.args...)
+		}
+		queryStmt.WriteString("%s")
 		//queryStmt = "SELECT %s FROM %s %s WHERE " + pk + " = ? %s "
 
 		fields.Grow(256)
@@ -767,6 +975,14 @@ func (tb *DefaultTable) GetDataWithId(param parameter.Parameters) (FormInfo, err
 			}
 		}
 
+		if versionField := tb.versionColumn(columnMap); versionField != "" &&
+			tb.Form.FieldList.FindByFieldName(versionField) == nil {
+			if fields.Len() > 0 { fields.WriteByte(',') }
+			fields.WriteString(tableName)
+			fields.WriteByte('.')
+			fields.WriteString(modules.Delimiter(delim, delim2, versionField))
+		}
+
 		if fields.Len() > 0 { fields.WriteByte(',') }
 		fields.WriteString(pk)
 		//fields += pk
@@ -813,7 +1029,7 @@ func (tb *DefaultTable) GetDataWithId(param parameter.Parameters) (FormInfo, err
 
 		queryCmd := fmt.Sprintf(queryStmt.String(), fields.String(), tableName, joins.String(), groupBy.String())
 		logger.LogSQL(queryCmd, args)
-		result, err := conn.QueryWithConnection(tb.connection, queryCmd, args...)
+		result, err := conn.QueryWithConnectionContext(param.Context(), tb.connection, queryCmd, args...)
 		if err != nil {
 			return FormInfo{ Title: tb.Form.Title, Description: tb.Form.Description }, err
 		}
@@ -825,6 +1041,8 @@ func (tb *DefaultTable) GetDataWithId(param parameter.Parameters) (FormInfo, err
 		res = result[0]
 	}
 
+	res = tb.policyMaskRow(res)
+
 	var (
 		groupFormList []types.FormFields
 		groupHeaders  []string
@@ -838,6 +1056,7 @@ func (tb *DefaultTable) GetDataWithId(param parameter.Parameters) (FormInfo, err
 			GroupFieldHeaders: groupHeaders,
 			Title:             tb.Form.Title,
 			Description:       tb.Form.Description,
+			Version:           versionString(res[tb.Form.VersionField]),
 		}, nil
 	}
 
@@ -847,11 +1066,14 @@ func (tb *DefaultTable) GetDataWithId(param parameter.Parameters) (FormInfo, err
 		GroupFieldHeaders: groupHeaders,
 		Title:             tb.Form.Title,
 		Description:       tb.Form.Description,
+		Version:           versionString(res[tb.Form.VersionField]),
 	}, nil
 }
 
 // UpdateData update data.
 func (tb *DefaultTable) UpdateData(dataList form.Values) error {
+	defer tb.invalidateListCache()
+
 	dataList.Add(form.PostTypeKey, "0")
 
 	var (
@@ -889,22 +1111,71 @@ func (tb *DefaultTable) UpdateData(dataList form.Values) error {
 		dataList = tb.Form.PreProcessFn(dataList)
 	}
 
+	id := dataList.Get(tb.PrimaryKey.Name)
+
+	tb.policyValues(dataList)
+	if err = tb.policyCheckRows([]string{ id }); err != nil {
+		errMsg = "post error: " + err.Error()
+		return err
+	}
+
+	version, versioned, err := tb.checkVersion(dataList, id)
+	if err != nil {
+		errMsg = "post error: " + err.Error()
+		return err
+	}
+
+	before := tb.auditRow(tb.Form.Table, id)
+
 	if tb.Form.UpdateFn != nil {
 		dataList.Delete(form.PostTypeKey)
+		if versioned {
+			dataList.Add(tb.Form.VersionField, versionString(nextVersion(version)))
+		}
 		err = tb.Form.UpdateFn(tb.PreProcessValue(dataList, types.PostTypeUpdate))
 		if err != nil {
 			errMsg = "post error: " + err.Error()
+			return err
 		}
-		return err
+		if versioned {
+			dataList.Add(form.VersionKey, dataList.Get(tb.Form.VersionField))
+		}
+		tb.auditUpdate(tb.Form.Table, models.AuditActionUpdate, id, before)
+		return nil
+	}
+
+	if tb.remote != nil {
+		dataList.Delete(form.PostTypeKey)
+		err = tb.remote.Update(tb.context(), id, remoteValues(tb.PreProcessValue(dataList, types.PostTypeUpdate)))
+		if err != nil {
+			errMsg = "post error: " + err.Error()
+			return err
+		}
+		tb.auditUpdate(tb.Form.Table, models.AuditActionUpdate, id, before)
+		return nil
 	}
 
 	if len(dataList) == 0 {
 		return nil
 	}
 
-	_, err = tb.sql().Table(tb.Form.Table).
-		Where(tb.PrimaryKey.Name, "=", dataList.Get(tb.PrimaryKey.Name)).
-		Update(tb.getInjectValueFromFormValue(dataList, types.PostTypeUpdate))
+	var (
+		values = tb.getInjectValueFromFormValue(dataList, types.PostTypeUpdate)
+		stmt   = tb.sql().Table(tb.Form.Table).Where(tb.PrimaryKey.Name, "=", id)
+	)
+
+	if versioned {
+		stmt = tb.versionStatement(stmt, values, version)
+	}
+
+	_, err = stmt.Update(values)
+
+	// the row has been changed between the check and the update.
+	if versioned && err == db.ErrNoAffectedRows {
+		err    = tb.versionConflict(dataList, nil)
+		errMsg = "post error: " + err.Error()
+		return err
+	}
 
 	// NOTE: some errors should be ignored.
 	if db.CheckError(err, db.UPDATE) {
@@ -914,43 +1185,44 @@ func (tb *DefaultTable) UpdateData(dataList form.Values) error {
 		return err
 	}
 
+	if versioned {
+		dataList.Add(form.VersionKey, versionString(values[tb.Form.VersionField]))
+	}
+
+	tb.auditUpdate(tb.Form.Table, models.AuditActionUpdate, id, before)
+
 	return nil
 }
 
 // InsertData insert data.
 func (tb *DefaultTable) InsertData(dataList form.Values) error {
-	dataList.Add(form.PostTypeKey, "1")
+	defer tb.invalidateListCache()
 
-	var (
-		id     = int64(0)
-		err    error
-		errMsg = ""
-		f      = tb.GetActualNewForm()
-	)
+	f := tb.GetActualNewForm()
 
-	if f.PostHook != nil {
-		defer func() {
-			dataList.Add(form.PostTypeKey, "1")
-			dataList.Add(tb.GetPrimaryKey().Name, strconv.Itoa(int(id)))
-			dataList.Add(form.PostResultKey, errMsg)
-			go func() {
-				defer func() {
-					if r := recover(); r != nil {
-						logger.Error(r)
-						logger.Error(string(debug.Stack()))
-					}
-				}()
-				if err := f.PostHook(dataList); err != nil {
-					logger.Error(err)
-				}
-			}()
-		}()
+	tb.policyValues(dataList)
+
+	dataList, err := tb.prepareInsertData(f, dataList)
+	if err != nil {
+		tb.postInsert(f, dataList, 0, err)
+		return err
+	}
+
+	id, err := tb.insertData(f, dataList, nil)
+	if err == nil {
+		tb.auditInsert(f.Table, id, dataList)
 	}
+	tb.postInsert(f, dataList, id, err)
+	return err
+}
+
+// prepareInsertData validates the dataList and processes it with the PreProcessFn of the form.
+func (tb *DefaultTable) prepareInsertData(f *types.FormPanel, dataList form.Values) (form.Values, error) {
+	dataList.Add(form.PostTypeKey, "1")
 
 	if f.Validator != nil {
-		if err = f.Validator(dataList); err != nil {
-			errMsg = "post error: " + err.Error()
-			return err
+		if err := f.Validator(dataList); err != nil {
+			return dataList, err
 		}
 	}
 
@@ -958,28 +1230,64 @@ func (tb *DefaultTable) InsertData(dataList form.Values) error {
 		dataList = f.PreProcessFn(dataList)
 	}
 
+	return dataList, nil
+}
+
+// insertData inserts the prepared dataList, within the tx when it is not nil.
+func (tb *DefaultTable) insertData(f *types.FormPanel, dataList form.Values, tx *dbsql.Tx) (int64, error) {
 	if f.InsertFn != nil {
 		dataList.Delete(form.PostTypeKey)
-		err = f.InsertFn(tb.PreProcessValue(dataList, types.PostTypeCreate))
-		if err != nil {
-			errMsg = "post error: " + err.Error()
-		}
-		return err
+		return 0, f.InsertFn(tb.PreProcessValue(dataList, types.PostTypeCreate))
+	}
+
+	if tb.remote != nil {
+		dataList.Delete(form.PostTypeKey)
+		id, err := tb.remote.Create(tb.context(), remoteValues(tb.PreProcessValue(dataList, types.PostTypeCreate)))
+		if err != nil { return 0, err }
+		// the id is zero when the remote source has the ids other than the integers.
+		n, _ := strconv.ParseInt(id, 10, 64)
+		return n, nil
 	}
 
 	if len(dataList) == 0 {
-		return nil
+		return 0, nil
 	}
 
-	id, err = tb.sql().Table(f.Table).Insert(tb.getInjectValueFromFormValue(dataList, types.PostTypeCreate))
+	id, err := tb.sql().WithTx(tx).Table(f.Table).Insert(tb.getInjectValueFromFormValue(dataList, types.PostTypeCreate))
 
 	// NOTE: some errors should be ignored.
 	if db.CheckError(err, db.INSERT) {
+		return id, err
+	}
+
+	return id, nil
+}
+
+// postInsert calls the PostHook of the form with the result of the insertion.
+func (tb *DefaultTable) postInsert(f *types.FormPanel, dataList form.Values, id int64, err error) {
+	if f.PostHook == nil {
+		return
+	}
+
+	errMsg := ""
+	if err != nil {
 		errMsg = "post error: " + err.Error()
-		return err
 	}
 
-	return nil
+	dataList.Add(form.PostTypeKey, "1")
+	dataList.Add(tb.GetPrimaryKey().Name, strconv.Itoa(int(id)))
+	dataList.Add(form.PostResultKey, errMsg)
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				logger.Error(r)
+				logger.Error(string(debug.Stack()))
+			}
+		}()
+		if err := f.PostHook(dataList); err != nil {
+			logger.Error(err)
+		}
+	}()
 }
 
 func (tb *DefaultTable) getInjectValueFromFormValue(dataList form.Values, typ types.PostType) dialect.H {
@@ -1078,6 +1386,8 @@ func (tb *DefaultTable) PreProcessValue(dataList form.Values, typ types.PostType
 
 // DeleteData delete data.
 func (tb *DefaultTable) DeleteData(id string) error {
+	defer tb.invalidateListCache()
+
 	var err error
 	ids := strings.Split(id, ",")
 
@@ -1119,8 +1429,25 @@ func (tb *DefaultTable) DeleteData(id string) error {
 		}
 	}
 
+	if err = tb.policyCheckRows(ids); err != nil {
+		return err
+	}
+
+	rows := tb.auditRows(tb.Info.Table, ids)
+
 	if tb.Info.DeleteFn != nil {
 		err = tb.Info.DeleteFn(ids)
+		if err == nil {
+			tb.auditDelete(tb.Info.Table, models.AuditActionDelete, rows)
+		}
+		return err
+	}
+
+	if tb.remote != nil {
+		err = tb.remote.Delete(tb.context(), ids)
+		if err == nil {
+			tb.auditDelete(tb.Info.Table, models.AuditActionDelete, rows)
+		}
 		return err
 	}
 
@@ -1129,7 +1456,64 @@ func (tb *DefaultTable) DeleteData(id string) error {
 		return err
 	}
 
-	err = tb.delete(tb.Info.Table, tb.PrimaryKey.Name, ids)
+	if tb.SoftDeleteField != "" {
+		err = tb.softDelete(tb.Info.Table, tb.PrimaryKey.Name, ids)
+	} else {
+		err = tb.delete(tb.Info.Table, tb.PrimaryKey.Name, ids)
+	}
+	if err == nil {
+		tb.auditDelete(tb.Info.Table, models.AuditActionDelete, rows)
+	}
+	return err
+}
+
+// RestoreData restores the soft deleted rows.
+func (tb *DefaultTable) RestoreData(id string) error {
+	defer tb.invalidateListCache()
+
+	ids := strings.Split(id, ",")
+	if len(ids) == 0 || tb.Info.Table == "" || tb.SoftDeleteField == "" {
+		return errors.New("restore error: missing parameter")
+	}
+	if err := tb.policyCheckRows(ids); err != nil {
+		return err
+	}
+
+	rows := tb.auditRows(tb.Info.Table, ids)
+
+	_, err := tb.sql().Table(tb.Info.Table).WhereIn(tb.PrimaryKey.Name, interfaces(ids)).
+		WhereRaw(tb.softDeleteField() + " IS NOT NULL").
+		Update(dialect.H{ tb.SoftDeleteField: nil })
+	if db.CheckError(err, db.UPDATE) {
+		return err
+	}
+
+	for id, row := range rows {
+		tb.auditUpdate(tb.Info.Table, models.AuditActionRestore, id, row)
+	}
+	return nil
+}
+
+// PurgeData deletes the soft deleted rows physically.
+func (tb *DefaultTable) PurgeData(id string) error {
+	defer tb.invalidateListCache()
+
+	ids := strings.Split(id, ",")
+	if len(ids) == 0 || tb.Info.Table == "" || tb.SoftDeleteField == "" {
+		return errors.New("purge error: missing parameter")
+	}
+	if err := tb.policyCheckRows(ids); err != nil {
+		return err
+	}
+
+	rows := tb.auditRows(tb.Info.Table, ids)
+
+	err := tb.sql().Table(tb.Info.Table).WhereIn(tb.PrimaryKey.Name, interfaces(ids)).
+		WhereRaw(tb.softDeleteField() + " IS NOT NULL").
+		Delete()
+	if err == nil {
+		tb.auditDelete(tb.Info.Table, models.AuditActionPurge, rows)
+	}
 	return err
 }
 
@@ -1146,6 +1530,92 @@ func (tb *DefaultTable) GetNewFormInfo() FormInfo {
 // helper function for database operation
 // ***************************************
 
+func (tb *DefaultTable) softDelete(table, key string, values []string) error {
+	_, err := tb.sql().Table(table).WhereIn(key, interfaces(values)).
+		WhereRaw(tb.softDeleteField() + " IS NULL").
+		Update(dialect.H{ tb.SoftDeleteField: utils.NowStr() })
+	if db.CheckError(err, db.UPDATE) {
+		return err
+	}
+	return nil
+}
+
+// softDeleteField return the delimited soft delete column.
+func (tb *DefaultTable) softDeleteField() string {
+	conn := tb.db()
+	return modules.Delimiter(conn.GetDelimiter(), conn.GetDelimiter2(), tb.SoftDeleteField)
+}
+
+// softDeleteStatement adds the condition of the soft delete column to the wheres,
+// only the soft deleted rows are queried when the params is trashed.
+func (tb *DefaultTable) softDeleteStatement(wheres string, params parameter.Parameters, delimiter, delimiter2 string) string {
+	if tb.SoftDeleteField == "" {
+		return wheres
+	}
+
+	cond := " IS NULL"
+	if params.IsTrashed() {
+		cond = " IS NOT NULL"
+	}
+
+	stmt := utils.StrConcat(modules.Delimiter(delimiter, delimiter2, tb.Info.Table), ".",
+		modules.Delimiter(delimiter, delimiter2, tb.SoftDeleteField), cond)
+	if wheres == "" {
+		return stmt
+	}
+	return utils.StrConcat("(", wheres, ") AND ", stmt)
+}
+
+// filterStatement adds the condition of the filter of the filter builder to the
+// wheres, the fields of the filter are resolved by the filterColumn.
+func (tb *DefaultTable) filterStatement(wheres string, whereArgs []interface{}, params parameter.Parameters,
+	delimiter, delimiter2 string, columnMap map[string]struct{}) (string, []interface{}, error) {
+
+	if params.Filter == nil {
+		return wheres, whereArgs, nil
+	}
+
+	stmt, args, err := dialect.CompileFilter(tb.connectionDriver, *params.Filter, func(field string) (string, bool) {
+		return tb.filterColumn(field, delimiter, delimiter2, columnMap)
+	})
+	if err != nil || stmt == "" {
+		return wheres, whereArgs, err
+	}
+
+	if wheres == "" {
+		return stmt, append(whereArgs, args...), nil
+	}
+	return utils.StrConcat("(", wheres, ") AND ", stmt), append(whereArgs, args...), nil
+}
+
+// filterColumn return the column of the field of the filter builder. The fields
+// are the filterable fields of the info panel, which are the columns of the table
+// or the fields of the join tables, the others are refused, so the hidden columns
+// such as the passwords can not be guessed by the filters.
+func (tb *DefaultTable) filterColumn(field, delimiter, delimiter2 string, columnMap map[string]struct{}) (string, bool) {
+	// the values of the masked and the hidden columns can not be guessed by the filters.
+	if tb.policy.rule(policyFilterColumn(field)) >= ColumnMask {
+		return "", false
+	}
+	for _, f := range tb.Info.FieldList {
+		if !f.Filterable {
+			continue
+		}
+		if !f.Joins.Valid() {
+			if f.Field == field && utils.InMapT(columnMap, field) {
+				return utils.StrConcat(modules.Delimiter(delimiter, delimiter2, tb.Info.Table), ".",
+					modules.Delimiter(delimiter, delimiter2, field)), true
+			}
+			continue
+		}
+		if table := f.Joins.Last().GetTableName(); field == types.JoinField(table, f.Field) {
+			return utils.StrConcat(f.Joins.Last().GetTableName(delimiter, delimiter2), ".",
+				modules.Delimiter(delimiter, delimiter2, f.Field)), true
+		}
+	}
+	return "", false
+}
+
 func (tb *DefaultTable) delete(table, key string, values []string) error {
 	var vals = make([]interface{}, len(values))
 	for i, v := range values {
@@ -1164,6 +1634,33 @@ func (tb *DefaultTable) getTheadAndFilterForm(params parameter.Parameters, colum
 	}, params, columnMap, tb.sqlObjOrNil)
 }
 
+// SetTenantConnection replaces the default database of the table by the database
+// of the tenant, the tables of the other drivers or connections are kept. The
+// audit records of all the tables are written to the database of the tenant.
+func (tb *DefaultTable) SetTenantConnection(name string) {
+	tb.tenantConnection = name
+	if tb.connectionDriver == "" || tb.connection != DefaultConnectionName ||
+		tb.connectionDriver != config.GetDatabases().GetDefault().Driver {
+		return
+	}
+	tb.dbObj = db.WithConnectionName(tb.db(), name)
+}
+
+// SetContext set the context of the request which writes the table, the writes
+// to the remote source are canceled with the request.
+func (tb *DefaultTable) SetContext(ctx context.Context) {
+	tb.reqCtx = ctx
+}
+
+// context return the context of the request, or the background context when the
+// table is not used by a request.
+func (tb *DefaultTable) context() context.Context {
+	if tb.reqCtx != nil {
+		return tb.reqCtx
+	}
+	return context.Background()
+}
+
 // db is a helper function return raw db connection.
 func (tb *DefaultTable) db() db.Connection {
 	if tb.dbObj == nil {
@@ -1187,7 +1684,7 @@ func (tb *DefaultTable) delimiter2() string {
 }
 
 func (tb *DefaultTable) getDataFromDB() bool {
-	return tb.sourceURL == "" && tb.getDataFun == nil && tb.Info.GetDataFn == nil && tb.Detail.GetDataFn == nil
+	return tb.remote == nil && tb.getDataFun == nil && tb.Info.GetDataFn == nil && tb.Detail.GetDataFn == nil
 }
 
 // sql is a helper function return db sql.
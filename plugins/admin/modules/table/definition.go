@@ -0,0 +1,647 @@
+package table
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html/template"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/GoAdminGroup/go-admin/context"
+	"github.com/GoAdminGroup/go-admin/modules/config"
+	"github.com/GoAdminGroup/go-admin/modules/db"
+	errs "github.com/GoAdminGroup/go-admin/modules/errors"
+	"github.com/GoAdminGroup/go-admin/modules/logger"
+	"github.com/GoAdminGroup/go-admin/plugins/admin/models"
+	"github.com/GoAdminGroup/go-admin/plugins/admin/modules/parameter"
+	"github.com/GoAdminGroup/go-admin/template/types"
+	"github.com/GoAdminGroup/go-admin/template/types/form"
+	"gopkg.in/yaml.v3"
+)
+
+// Definition is a table defined by a yaml or a json file instead of the code,
+// such as:
+//
+//	prefix: posts
+//	table: posts
+//	title: Posts
+//	fields:
+//	  - { head: ID, field: id, type: int, sortable: true }
+//	  - { head: Title, field: title, type: varchar, filter: true, filter_operator: like }
+//	  - { head: Author, field: name, type: varchar, join: { table: users, field: user_id, join_field: id } }
+//	  - { head: Status, field: status, type: tinyint, display: label,
+//	      options: [{ text: draft, value: "0" }, { text: published, value: "1" }] }
+//	form:
+//	  - { head: Title, field: title, type: varchar, must: true, help: the title of the post }
+//	  - { head: Author, field: user_id, type: int, form_type: select_single,
+//	      options_from: { table: users, text: name, value: id } }
+//	permissions:
+//	  view: [editor]
+//	  delete: [administrator]
+//
+// The form is the fields of the info without the joins when it is empty.
+type Definition struct {
+	// Prefix is the prefix of the urls of the table, the name of the file by default.
+	Prefix         string                `yaml:"prefix" json:"prefix"`
+	Connection     string                `yaml:"connection" json:"connection"`
+	Driver         string                `yaml:"driver" json:"driver"`
+	Table          string                `yaml:"table" json:"table"`
+	Title          string                `yaml:"title" json:"title"`
+	Description    string                `yaml:"description" json:"description"`
+	PrimaryKey     string                `yaml:"primary_key" json:"primary_key"`
+	PrimaryKeyType string                `yaml:"primary_key_type" json:"primary_key_type"`
+	CanAdd         *bool                 `yaml:"can_add" json:"can_add"`
+	Editable       *bool                 `yaml:"editable" json:"editable"`
+	Deletable      *bool                 `yaml:"deletable" json:"deletable"`
+	Exportable     *bool                 `yaml:"exportable" json:"exportable"`
+	Fields         []DefinitionField     `yaml:"fields" json:"fields"`
+	Form           []DefinitionField     `yaml:"form" json:"form"`
+	Permissions    DefinitionPermissions `yaml:"permissions" json:"permissions"`
+}
+
+// DefinitionField is a field of the info or the form of a Definition, the keys
+// of the other panel are ignored.
+type DefinitionField struct {
+	Head  string `yaml:"head" json:"head"`
+	Field string `yaml:"field" json:"field"`
+	Type  string `yaml:"type" json:"type"`
+	Hide  bool   `yaml:"hide" json:"hide"`
+
+	// the options of the filter and the selections of the form.
+	Options     []DefinitionOption `yaml:"options" json:"options"`
+	OptionsFrom *DefinitionOptions `yaml:"options_from" json:"options_from"`
+
+	// the keys of the info.
+	Sortable       bool            `yaml:"sortable" json:"sortable"`
+	Filter         bool            `yaml:"filter" json:"filter"`
+	FilterOperator string          `yaml:"filter_operator" json:"filter_operator"`
+	FilterType     string          `yaml:"filter_type" json:"filter_type"`
+	Display        string          `yaml:"display" json:"display"`
+	DisplayArgs    []string        `yaml:"display_args" json:"display_args"`
+	Join           *DefinitionJoin `yaml:"join" json:"join"`
+
+	// the keys of the form.
+	FormType      string `yaml:"form_type" json:"form_type"`
+	Must          bool   `yaml:"must" json:"must"`
+	Default       string `yaml:"default" json:"default"`
+	Help          string `yaml:"help" json:"help"`
+	Placeholder   string `yaml:"placeholder" json:"placeholder"`
+	HideOnCreate  bool   `yaml:"hide_on_create" json:"hide_on_create"`
+	HideOnUpdate  bool   `yaml:"hide_on_update" json:"hide_on_update"`
+	DisableCreate bool   `yaml:"disable_create" json:"disable_create"`
+	DisableUpdate bool   `yaml:"disable_update" json:"disable_update"`
+}
+
+// DefinitionOption is an option of a field.
+type DefinitionOption struct {
+	Text  string `yaml:"text" json:"text"`
+	Value string `yaml:"value" json:"value"`
+}
+
+// DefinitionOptions are the options of a field from a table.
+type DefinitionOptions struct {
+	Table string `yaml:"table" json:"table"`
+	Text  string `yaml:"text" json:"text"`
+	Value string `yaml:"value" json:"value"`
+}
+
+// DefinitionJoin joins the field from the table, see the types.Join.
+type DefinitionJoin struct {
+	Table     string `yaml:"table" json:"table"`
+	Alias     string `yaml:"alias" json:"alias"`
+	Field     string `yaml:"field" json:"field"`
+	JoinField string `yaml:"join_field" json:"join_field"`
+}
+
+// DefinitionPermissions are the slugs of the roles allowed to do the operations
+// of the table, the operation is allowed to all the users when it is empty and
+// to the super administrators always.
+type DefinitionPermissions struct {
+	View   []string `yaml:"view" json:"view"`
+	Create []string `yaml:"create" json:"create"`
+	Edit   []string `yaml:"edit" json:"edit"`
+	Delete []string `yaml:"delete" json:"delete"`
+	Export []string `yaml:"export" json:"export"`
+}
+
+var definitionFilterOperators = map[string]types.FilterOperator{
+	""    : "",
+	"like": types.FilterOperatorLike,
+	"eq"  : types.FilterOperatorEqual,
+	"ne"  : types.FilterOperatorNotEqual,
+	"gt"  : types.FilterOperatorGreater,
+	"ge"  : types.FilterOperatorGreaterOrEqual,
+	"lt"  : types.FilterOperatorLess,
+	"le"  : types.FilterOperatorLessOrEqual,
+}
+
+var definitionDisplays = map[string]func(i *types.InfoPanel, args []string){
+	"label"   : func(i *types.InfoPanel, _ []string) { i.FieldLabel() },
+	"bool"    : func(i *types.InfoPanel, args []string) { i.FieldBool(args...) },
+	"copyable": func(i *types.InfoPanel, args []string) { i.FieldCopyable(args...) },
+	"qrcode"  : func(i *types.InfoPanel, _ []string) { i.FieldQrcode() },
+	"image": func(i *types.InfoPanel, args []string) {
+		i.FieldImage(definitionArg(args, 0, "50"), definitionArg(args, 1, "50"), definitionArg(args, 2, ""))
+	},
+	"link": func(i *types.InfoPanel, args []string) {
+		i.FieldLink(definitionArg(args, 0, "{{.Value}}"), definitionArg(args, 1, "") == "true")
+	},
+	"date": func(i *types.InfoPanel, args []string) { i.FieldDate(definitionArg(args, 0, "2006-01-02 15:04:05")) },
+}
+
+func definitionArg(args []string, i int, def string) string {
+	if i < len(args) {
+		return args[i]
+	}
+	return def
+}
+
+// Generator checks the definition and return the generator of the table.
+func (d Definition) Generator() (Generator, error) {
+	if d.Table == "" {
+		return nil, errors.New("definition: table is empty")
+	}
+	if len(d.Fields) == 0 {
+		return nil, errors.New("definition: no fields of table " + d.Table)
+	}
+
+	pk := PrimaryKey{ Name: DefaultPrimaryKeyName, Type: db.Int }
+	if d.PrimaryKey != "" {
+		pk.Name = d.PrimaryKey
+	}
+	if d.PrimaryKeyType != "" {
+		t, err := definitionType(d.PrimaryKeyType)
+		if err != nil { return nil, err }
+		pk.Type = t
+	}
+
+	formFields := d.Form
+	if len(formFields) == 0 {
+		for _, field := range d.Fields {
+			if field.Join == nil {
+				formFields = append(formFields, field)
+			}
+		}
+	}
+
+	// the fields are checked once here, so that the generator never fails.
+	for _, field := range append(append([]DefinitionField{}, d.Fields...), formFields...) {
+		if err := field.check(); err != nil {
+			return nil, err
+		}
+	}
+
+	connection := d.Connection
+	if connection == "" {
+		connection = DefaultConnectionName
+	}
+
+	return func(ctx *context.Context) Table {
+		var user models.UserModel
+		if ctx != nil {
+			user, _ = ctx.User().(models.UserModel)
+		}
+
+		driver := d.Driver
+		if driver == "" {
+			driver = config.GetDatabases()[connection].Driver
+		}
+
+		cfg := DefaultConfigWithDriverAndConnection(driver, connection)
+		cfg.PrimaryKey = pk
+		cfg.CanAdd     = definitionFlag(d.CanAdd) && definitionAllowed(user, d.Permissions.Create)
+		cfg.Editable   = definitionFlag(d.Editable) && definitionAllowed(user, d.Permissions.Edit)
+		cfg.Deletable  = definitionFlag(d.Deletable) && definitionAllowed(user, d.Permissions.Delete)
+		cfg.Exportable = definitionFlag(d.Exportable) && definitionAllowed(user, d.Permissions.Export)
+
+		viewable := definitionAllowed(user, d.Permissions.View)
+		if !viewable {
+			cfg.CanAdd, cfg.Editable, cfg.Deletable, cfg.Exportable = false, false, false, false
+			cfg.GetDataFun = func(params parameter.Parameters) ([]map[string]interface{}, int) {
+				return []map[string]interface{}{}, 0
+			}
+		}
+
+		tb := NewDefaultTable(cfg)
+
+		info := tb.GetInfo()
+		for _, field := range d.Fields {
+			field.addToInfo(info)
+		}
+		info.SetTable(d.Table).SetTitle(d.Title).SetDescription(d.Description)
+
+		formList := tb.GetForm()
+		for _, field := range formFields {
+			field.addToForm(formList)
+		}
+		formList.SetTable(d.Table).SetTitle(d.Title).SetDescription(d.Description)
+
+		if !viewable {
+			info.SetError(errs.PageError403)
+			formList.SetError(errs.PageError403)
+		}
+
+		return tb
+	}, nil
+}
+
+func (f DefinitionField) check() error {
+	if f.Field == "" {
+		return errors.New("definition: field is empty")
+	}
+	if _, err := definitionType(f.Type); err != nil {
+		return err
+	}
+	if _, ok := definitionFilterOperators[strings.ToLower(f.FilterOperator)]; !ok {
+		return fmt.Errorf("definition: wrong filter operator %s of field %s", f.FilterOperator, f.Field)
+	}
+	if _, ok := definitionDisplays[strings.ToLower(f.Display)]; !ok && f.Display != "" {
+		return fmt.Errorf("definition: wrong display %s of field %s", f.Display, f.Field)
+	}
+	for _, t := range []string{ f.FormType, f.FilterType } {
+		if _, err := definitionFormType(t, form.Text); err != nil {
+			return err
+		}
+	}
+	if f.Join != nil && (f.Join.Table == "" || f.Join.Field == "" || f.Join.JoinField == "") {
+		return errors.New("definition: wrong join of field " + f.Field)
+	}
+	if f.OptionsFrom != nil && (f.OptionsFrom.Table == "" || f.OptionsFrom.Text == "" || f.OptionsFrom.Value == "") {
+		return errors.New("definition: wrong options_from of field " + f.Field)
+	}
+	return nil
+}
+
+func (f DefinitionField) head() string {
+	if f.Head != "" {
+		return f.Head
+	}
+	return strings.Title(strings.ReplaceAll(f.Field, "_", " "))
+}
+
+func (f DefinitionField) options() types.FieldOptions {
+	options := make(types.FieldOptions, len(f.Options))
+	for i, o := range f.Options {
+		options[i] = types.FieldOption{ Text: o.Text, Value: o.Value }
+	}
+	return options
+}
+
+func (f DefinitionField) addToInfo(info *types.InfoPanel) {
+	typ, _ := definitionType(f.Type)
+	info.AddField(f.head(), f.Field, typ)
+
+	if f.Join != nil {
+		info.FieldJoin(types.Join{
+			Table:      f.Join.Table,
+			TableAlias: f.Join.Alias,
+			Field:      f.Join.Field,
+			JoinField:  f.Join.JoinField,
+		})
+	}
+	if f.Sortable {
+		info.FieldSortable()
+	}
+	if f.Hide {
+		info.FieldHide()
+	}
+	if f.Filter {
+		formType, _ := definitionFormType(f.FilterType, form.Text)
+		info.FieldFilterable(types.FilterType{
+			FormType: formType,
+			Operator: definitionFilterOperators[strings.ToLower(f.FilterOperator)],
+			Options:  f.options(),
+		})
+	}
+	if display, ok := definitionDisplays[strings.ToLower(f.Display)]; ok {
+		display(info, f.DisplayArgs)
+	} else if len(f.Options) > 0 {
+		// the values are displayed as the texts of the options.
+		options := f.options()
+		info.FieldDisplay(func(value types.FieldModel) interface{} {
+			for _, o := range options {
+				if o.Value == value.Value {
+					return o.Text
+				}
+			}
+			return value.Value
+		})
+	}
+}
+
+func (f DefinitionField) addToForm(formList *types.FormPanel) {
+	typ, _ := definitionType(f.Type)
+	formType, _ := definitionFormType(f.FormType, form.Text)
+	if f.FormType == "" {
+		formType, _ = definitionFormType(form.GetFormTypeFromFieldType(typ, f.Field), form.Text)
+		if len(f.Options) > 0 || f.OptionsFrom != nil {
+			formType = form.SelectSingle
+		}
+	}
+	formList.AddField(f.head(), f.Field, typ, formType)
+
+	if len(f.Options) > 0 {
+		formList.FieldOptions(f.options())
+	}
+	if f.OptionsFrom != nil {
+		formList.FieldOptionsFromTable(f.OptionsFrom.Table, f.OptionsFrom.Text, f.OptionsFrom.Value)
+	}
+	if f.Must {
+		formList.FieldMust()
+	}
+	if f.Default != "" {
+		formList.FieldDefault(f.Default)
+	}
+	if f.Help != "" {
+		formList.FieldHelpMsg(template.HTML(template.HTMLEscapeString(f.Help)))
+	}
+	if f.Placeholder != "" {
+		formList.FieldPlaceholder(f.Placeholder)
+	}
+	if f.Hide {
+		formList.FieldHide()
+	}
+	if f.HideOnCreate {
+		formList.FieldHideWhenCreate()
+	}
+	if f.HideOnUpdate {
+		formList.FieldHideWhenUpdate()
+	}
+	if f.DisableCreate {
+		formList.FieldDisableWhenCreate()
+	}
+	if f.DisableUpdate {
+		formList.FieldDisableWhenUpdate()
+	}
+}
+
+// definitionType return the database type of the name, such as varchar or int,
+// varchar when the name is empty.
+func definitionType(name string) (t db.DatabaseType, err error) {
+	if name == "" {
+		return db.Varchar, nil
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("definition: wrong type %s", name)
+		}
+	}()
+	return db.GetDTAndCheck(strings.ToUpper(name)), nil
+}
+
+// definitionFormType return the form type of the name, which is the name of the
+// type in the code such as SelectSingle or in the templates such as select_single.
+func definitionFormType(name string, def form.Type) (form.Type, error) {
+	if name == "" {
+		return def, nil
+	}
+	for _, t := range form.AllType {
+		if strings.EqualFold(t.Name(), name) || strings.EqualFold(t.String(), name) {
+			return t, nil
+		}
+	}
+	return def, fmt.Errorf("definition: wrong form type %s", name)
+}
+
+func definitionFlag(flag *bool) bool {
+	return flag == nil || *flag
+}
+
+func definitionAllowed(user models.UserModel, roles []string) bool {
+	if len(roles) == 0 || user.IsSuperAdmin() {
+		return true
+	}
+	for _, slug := range roles {
+		if user.CheckRole(slug) {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseDefinition parses the definition of the file of yaml or json by the extension
+// of the name, the prefix is the name of the file without the extension by default.
+func ParseDefinition(name string, content []byte) (Definition, error) {
+	var (
+		d   Definition
+		err error
+		ext = strings.ToLower(filepath.Ext(name))
+	)
+	switch ext {
+	case ".yaml", ".yml":
+		dec := yaml.NewDecoder(bytes.NewReader(content))
+		dec.KnownFields(true)
+		err = dec.Decode(&d)
+		if err == io.EOF {
+			err = nil
+		}
+	case ".json":
+		dec := json.NewDecoder(bytes.NewReader(content))
+		dec.DisallowUnknownFields()
+		err = dec.Decode(&d)
+	default:
+		err = errors.New("definition: unsupported file " + name)
+	}
+	if err != nil {
+		return d, fmt.Errorf("definition: parse %s: %v", name, err)
+	}
+	if d.Prefix == "" {
+		d.Prefix = strings.TrimSuffix(filepath.Base(name), filepath.Ext(name))
+	}
+	return d, nil
+}
+
+func isDefinitionFile(name string) bool {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".yaml", ".yml", ".json":
+		return true
+	}
+	return false
+}
+
+func loadDefinition(path string) (string, Generator, error) {
+	content, err := os.ReadFile(path)
+	if err != nil { return "", nil, err }
+	d, err := ParseDefinition(path, content)
+	if err != nil { return "", nil, err }
+	g, err := d.Generator()
+	if err != nil { return "", nil, fmt.Errorf("%s: %v", path, err) }
+	return d.Prefix, g, nil
+}
+
+// LoadDefinitions loads the definitions of the yaml and json files of the directory,
+// the wrong files are skipped and the errors of them are returned together.
+func LoadDefinitions(dir string) (GeneratorList, error) {
+	var (
+		list = make(GeneratorList)
+		msgs []string
+	)
+	files, err := os.ReadDir(dir)
+	if err != nil { return list, err }
+	for _, file := range files {
+		if file.IsDir() || !isDefinitionFile(file.Name()) {
+			continue
+		}
+		path := filepath.Join(dir, file.Name())
+		key, g, err := loadDefinition(path)
+		if err != nil {
+			msgs = append(msgs, err.Error())
+			continue
+		}
+		if _, ok := list[key]; ok {
+			msgs = append(msgs, fmt.Sprintf("definition: duplicate prefix %s of %s", key, path))
+			continue
+		}
+		list[key] = g
+	}
+	if len(msgs) > 0 {
+		return list, errors.New(strings.Join(msgs, "; "))
+	}
+	return list, nil
+}
+
+// DefinitionWatcher reloads the definitions of a directory when the files are
+// changed, see the WatchDefinitions.
+type DefinitionWatcher struct {
+	dir      string
+	interval time.Duration
+	fn       func(changed GeneratorList, removed []string)
+	files    map[string]definitionFile
+	stop     chan struct{}
+	once     sync.Once
+}
+
+type definitionFile struct {
+	key     string
+	modTime time.Time
+	size    int64
+}
+
+// DefaultDefinitionInterval is the default interval of checking the files of the definitions.
+const DefaultDefinitionInterval = 2 * time.Second
+
+// WatchDefinitions loads the definitions of the directory and checks the files every
+// interval, the fn is called with the generators of the changed files and the prefixes
+// of the removed files. A file which becomes wrong keeps the generator loaded before,
+// and the error of it is logged. Call Stop of the watcher to stop it.
+func WatchDefinitions(dir string, interval time.Duration, fn func(changed GeneratorList, removed []string)) (GeneratorList, *DefinitionWatcher, error) {
+	if interval <= 0 {
+		interval = DefaultDefinitionInterval
+	}
+	w := &DefinitionWatcher{
+		dir:      dir,
+		interval: interval,
+		fn:       fn,
+		files:    make(map[string]definitionFile),
+		stop:     make(chan struct{}),
+	}
+	list, err := w.scan()
+	go w.run()
+	return list, w, err
+}
+
+// Stop stops the watcher.
+func (w *DefinitionWatcher) Stop() {
+	w.once.Do(func() { close(w.stop) })
+}
+
+func (w *DefinitionWatcher) run() {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-w.stop:
+			return
+		case <-ticker.C:
+			changed, removed, err := w.check()
+			if err != nil {
+				logger.Error("definition: ", err)
+			}
+			if (len(changed) > 0 || len(removed) > 0) && w.fn != nil {
+				w.fn(changed, removed)
+			}
+		}
+	}
+}
+
+// scan loads all the files at the start.
+func (w *DefinitionWatcher) scan() (GeneratorList, error) {
+	list, _, err := w.check()
+	return list, err
+}
+
+// check loads the files changed since the last check.
+func (w *DefinitionWatcher) check() (GeneratorList, []string, error) {
+	var (
+		changed = make(GeneratorList)
+		removed []string
+		msgs    []string
+		seen    = make(map[string]bool)
+	)
+	files, err := os.ReadDir(w.dir)
+	if err != nil { return changed, removed, err }
+
+	keys := make(map[string]string)
+	for path, f := range w.files {
+		keys[f.key] = path
+	}
+
+	for _, file := range files {
+		if file.IsDir() || !isDefinitionFile(file.Name()) {
+			continue
+		}
+		info, err := file.Info()
+		if err != nil { continue }
+		path := filepath.Join(w.dir, file.Name())
+		seen[path] = true
+
+		old, ok := w.files[path]
+		if ok && old.modTime.Equal(info.ModTime()) && old.size == info.Size() {
+			continue
+		}
+		key, g, err := loadDefinition(path)
+		if err != nil {
+			msgs = append(msgs, err.Error())
+			// the file is not loaded again until it is changed.
+			old.modTime, old.size = info.ModTime(), info.Size()
+			w.files[path] = old
+			continue
+		}
+		if other, ok := keys[key]; ok && other != path {
+			msgs = append(msgs, fmt.Sprintf("duplicate prefix %s of %s and %s", key, path, other))
+			old.modTime, old.size = info.ModTime(), info.Size()
+			w.files[path] = old
+			continue
+		}
+		// the prefix of the file is changed.
+		if ok && old.key != "" && old.key != key {
+			removed = append(removed, old.key)
+			delete(keys, old.key)
+		}
+		keys[key] = path
+		w.files[path] = definitionFile{ key: key, modTime: info.ModTime(), size: info.Size() }
+		changed[key] = g
+	}
+
+	for path, f := range w.files {
+		if !seen[path] {
+			if f.key != "" {
+				removed = append(removed, f.key)
+			}
+			delete(w.files, path)
+		}
+	}
+	sort.Strings(removed)
+
+	if len(msgs) > 0 {
+		return changed, removed, errors.New(strings.Join(msgs, "; "))
+	}
+	return changed, removed, nil
+}
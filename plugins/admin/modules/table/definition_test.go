@@ -0,0 +1,128 @@
+package table
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/GoAdminGroup/go-admin/modules/config"
+	"github.com/GoAdminGroup/go-admin/modules/db"
+	form2 "github.com/GoAdminGroup/go-admin/plugins/admin/modules/form"
+	"github.com/GoAdminGroup/go-admin/template/types/form"
+	"github.com/magiconair/properties/assert"
+)
+
+const definitionYaml = `
+table: posts
+title: Posts
+driver: sqlite
+fields:
+  - { head: ID, field: id, type: int, sortable: true }
+  - { head: Title, field: title, type: varchar, filter: true, filter_operator: like }
+  - { head: Author, field: name, type: varchar, join: { table: users, field: user_id, join_field: id } }
+form:
+  - { head: Title, field: title, must: true }
+  - { head: Author, field: user_id, type: int, options: [{ text: foo, value: "1" }] }
+permissions:
+  delete: [administrator]
+`
+
+func TestParseDefinition(t *testing.T) {
+	d, err := ParseDefinition("dir/posts.yaml", []byte(definitionYaml))
+	assert.Equal(t, err, nil)
+	assert.Equal(t, d.Prefix, "posts")
+	assert.Equal(t, len(d.Fields), 3)
+	assert.Equal(t, d.Fields[2].Join.JoinField, "id")
+	assert.Equal(t, d.Permissions.Delete, []string{ "administrator" })
+
+	gen, err := d.Generator()
+	assert.Equal(t, err, nil)
+	config.Initialize(&config.Config{ Language: "en" })
+	tb := gen(nil)
+	assert.Equal(t, tb.GetInfo().Table, "posts")
+	assert.Equal(t, len(tb.GetInfo().FieldList), 3)
+	assert.Equal(t, tb.GetForm().FieldList[1].FormType, form.SelectSingle)
+	assert.Equal(t, tb.GetForm().FieldList[1].TypeName, db.Int)
+	// the user without the roles can not delete.
+	assert.Equal(t, tb.GetDeletable(), false)
+	assert.Equal(t, tb.GetEditable(), true)
+
+	_, err = ParseDefinition("posts.json", []byte(`{"table": "posts", "unknown": 1}`))
+	assert.Equal(t, err != nil, true)
+
+	for _, content := range []string{
+		`{"table": "posts"}`,
+		`{"table": "posts", "fields": [{"field": "id", "type": "wrong"}]}`,
+		`{"table": "posts", "fields": [{"field": "id", "form_type": "wrong"}]}`,
+		`{"table": "posts", "fields": [{"field": "id", "display": "wrong"}]}`,
+	} {
+		d, err := ParseDefinition("posts.json", []byte(content))
+		assert.Equal(t, err, nil)
+		_, err = d.Generator()
+		assert.Equal(t, err != nil, true)
+	}
+}
+
+func TestDefinitionWatcher(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) {
+		assert.Equal(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0644), nil)
+	}
+	write("posts.yaml", definitionYaml)
+	write("readme.md", "not a definition")
+
+	w := &DefinitionWatcher{ dir: dir, files: make(map[string]definitionFile) }
+	list, err := w.scan()
+	assert.Equal(t, err, nil)
+	assert.Equal(t, len(list), 1)
+
+	changed, removed, err := w.check()
+	assert.Equal(t, err, nil)
+	assert.Equal(t, len(changed)+len(removed), 0)
+
+	// a wrong file keeps the generator loaded before.
+	write("posts.yaml", definitionYaml + "\nwrong: 1\n")
+	changed, removed, err = w.check()
+	assert.Equal(t, err != nil, true)
+	assert.Equal(t, len(changed)+len(removed), 0)
+
+	write("users.json", `{"table": "users", "fields": [{"field": "id", "type": "int"}]}`)
+	assert.Equal(t, os.Remove(filepath.Join(dir, "posts.yaml")), nil)
+	changed, removed, err = w.check()
+	assert.Equal(t, err, nil)
+	_, ok := changed["users"]
+	assert.Equal(t, ok, true)
+	assert.Equal(t, removed, []string{ "posts" })
+
+	list, err = LoadDefinitions(dir)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, len(list), 1)
+}
+
+func TestTableDefFromValues(t *testing.T) {
+	d := tableDefFromValues(form2.Values{
+		"table":            { "posts" },
+		"title":            { "Posts" },
+		"read_only":        { "y" },
+		"view_roles":       { "editor, analyst" },
+		"field_name":       { "id", "title", "" },
+		"field_head":       { "ID", "" },
+		"field_db_type":    { "Int", "Varchar" },
+		"field_filterable": { "n", "y" },
+		"field_sortable":   { "y", "n" },
+		"field_display":    { "", "label" },
+	})
+	assert.Equal(t, d.Connection, DefaultConnectionName)
+	assert.Equal(t, *d.Editable, false)
+	assert.Equal(t, d.Permissions.View, []string{ "editor", "analyst" })
+	assert.Equal(t, len(d.Fields), 2)
+	assert.Equal(t, d.Fields[1].Filter, true)
+	assert.Equal(t, d.Fields[1].Display, "label")
+
+	d.Driver = "sqlite"
+	code, err := d.GoCode("tables")
+	assert.Equal(t, err, nil)
+	assert.Equal(t, strings.Contains(string(code), `info.AddField("Title", "title", db.Varchar).`), true)
+	assert.Equal(t, strings.Contains(string(code), "info.HideEditButton()"), true)
+}
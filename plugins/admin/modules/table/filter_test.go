@@ -0,0 +1,33 @@
+package table
+
+import (
+	"testing"
+
+	"github.com/GoAdminGroup/go-admin/template/types"
+	"github.com/magiconair/properties/assert"
+)
+
+func TestFilterColumn(t *testing.T) {
+	tb := &DefaultTable{ BaseTable: &BaseTable{ Info: &types.InfoPanel{
+		Table:     "users",
+		FieldList: types.FieldList{
+			{ Field: "name", Filterable: true },
+			{ Field: "email" },
+			{ Field: "title", Filterable: true, Joins: types.Joins{ { Table: "roles", Field: "role_id", JoinField: "id" } } },
+		},
+	} } }
+	columnMap := map[string]struct{}{ "name": {}, "email": {}, "password": {}, "role_id": {} }
+
+	column, ok := tb.filterColumn("name", "`", "`", columnMap)
+	assert.Equal(t, ok, true)
+	assert.Equal(t, column, "`users`.`name`")
+
+	column, ok = tb.filterColumn(types.JoinField("roles", "title"), "`", "`", columnMap)
+	assert.Equal(t, ok, true)
+	assert.Equal(t, column, "`roles`.`title`")
+
+	for _, field := range []string{ "email", "password", "role_id", "title" } {
+		_, ok = tb.filterColumn(field, "`", "`", columnMap)
+		assert.Equal(t, ok, false)
+	}
+}
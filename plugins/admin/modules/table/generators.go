@@ -4,6 +4,7 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	html2 "html"
 	"github.com/GoAdminGroup/go-admin/context"
 	"github.com/GoAdminGroup/go-admin/modules/auth"
 	"github.com/GoAdminGroup/go-admin/modules/collection"
@@ -13,6 +14,7 @@ import (
 	errs "github.com/GoAdminGroup/go-admin/modules/errors"
 	"github.com/GoAdminGroup/go-admin/modules/language"
 	"github.com/GoAdminGroup/go-admin/modules/logger"
+	"github.com/GoAdminGroup/go-admin/modules/tenant"
 	"github.com/GoAdminGroup/go-admin/modules/ui"
 	"github.com/GoAdminGroup/go-admin/modules/utils"
 	"github.com/GoAdminGroup/go-admin/plugins/admin/models"
@@ -24,24 +26,45 @@ import (
 	"github.com/GoAdminGroup/go-admin/template/types/form"
 	"github.com/GoAdminGroup/html"
 	"net/url"
+	"sort"
 	"strconv"
 	"strings"
+	"time"
 )
 
 type SystemTable struct {
 	conn db.Connection
 	cfg  *config.Config
+	// generators are the tables served by the admin, to which the tables of
+	// the designer are added.
+	generators GeneratorList
 }
 
 func NewSystemTable(conn db.Connection, cfg *config.Config) *SystemTable {
 	return &SystemTable{ conn: conn, cfg: cfg }
 }
 
+// SetGenerators set the tables served by the admin.
+func (s *SystemTable) SetGenerators(list GeneratorList) *SystemTable {
+	s.generators = list
+	return s
+}
+
+// forContext return the system table of the tenant of the request, which reads and
+// writes the database and the config of the tenant.
+func (s *SystemTable) forContext(ctx *context.Context) *SystemTable {
+	if _, ok := tenant.FromContext(ctx); !ok {
+		return s
+	}
+	return &SystemTable{ conn: tenant.Conn(ctx, s.conn), cfg: tenant.Config(ctx, s.cfg), generators: s.generators }
+}
+
 func (s *SystemTable) link(url, content string) template.HTML {
 	return link(s.cfg.Url(url), content)
 }
 
 func (s *SystemTable) GetManagerTable(ctx *context.Context) Table {
+	s = s.forContext(ctx)
 	isRootAuth := auth.Auth(ctx).IsRootAdmin()
 
 	managerTable := NewDefaultTable(DefaultConfigWithDriver(config.GetDatabases().GetDefault().Driver))
@@ -80,9 +103,32 @@ func (s *SystemTable) GetManagerTable(ctx *context.Context) Table {
 			return res.String()
 		}).FieldFilterable()
 
+	info.AddField(lg("Locked"), "locked", db.Varchar).
+		FieldDisplay(func(model types.FieldModel) interface{} {
+			if username, ok := model.Row["username"].(string); ok && auth.IsLoginLocked(username, s.conn) {
+				return label().SetType("danger").SetContent(template.HTML(lg("locked"))).GetContent()
+			}
+			return ""
+		})
+
 	info.AddField(lg("Created At"), "created_at", db.Timestamp)
 	info.AddField(lg("Updated At"), "updated_at", db.Timestamp).FieldSortable()
 
+	info.AddActionButton(template.HTML(lg("Unlock")), action.Ajax("manager_unlock",
+		func(ctx *context.Context) (success bool, msg string, data interface{}) {
+			user := models.User().SetConn(s.conn).Find(ctx.FormValue("id"))
+			if user.IsEmpty() {
+				return false, lg("user not found"), ""
+			}
+			if user.IsRootAdmin() && !auth.Auth(ctx).IsRootAdmin() {
+				return false, lg("You are not allowed to unlock a Root user"), ""
+			}
+			if err := auth.UnlockLogin(user.UserName, s.conn); err != nil {
+				return false, err.Error(), ""
+			}
+			return true, lg("unlocked"), ""
+		}))
+
 	info.SetTable("goadmin_users").SetTitle(lg("Users")).//SetDescription(lg("Users")).
 		SetDeleteFn(func(idArr []string) error {
 			ids := interfaces(idArr)
@@ -371,6 +417,7 @@ func (s *SystemTable) GetManagerTable(ctx *context.Context) Table {
 }
 
 func (s *SystemTable) GetNormalManagerTable(ctx *context.Context) (managerTable Table) {
+	s = s.forContext(ctx)
 	isRootAuth := auth.Auth(ctx).IsRootAdmin()
 
 	managerTable = NewDefaultTable(DefaultConfigWithDriver(config.GetDatabases().GetDefault().Driver))
@@ -533,6 +580,7 @@ func (s *SystemTable) GetNormalManagerTable(ctx *context.Context) (managerTable
 }
 
 func (s *SystemTable) GetPermissionTable(ctx *context.Context) (permissionTable Table) {
+	s = s.forContext(ctx)
 	permissionTable = NewDefaultTable(DefaultConfigWithDriver(config.GetDatabases().GetDefault().Driver))
 
 	info := permissionTable.GetInfo().AddXssJsFilter().HideFilterArea()
@@ -633,8 +681,12 @@ func (s *SystemTable) GetPermissionTable(ctx *context.Context) (permissionTable
 }
 
 func (s *SystemTable) GetRolesTable(ctx *context.Context) (roleTable Table) {
+	s = s.forContext(ctx)
 	roleTable = NewDefaultTable(DefaultConfigWithDriver(config.GetDatabases().GetDefault().Driver))
 
+	isRootAuth := auth.Auth(ctx).IsRootAdmin()
+	twoFactor := models.TwoFactor().SetConn(s.conn)
+
 	info := roleTable.GetInfo().AddXssJsFilter().HideFilterArea()
 
 	info.AddField("ID", "id", db.Int).FieldSortable()
@@ -674,6 +726,14 @@ func (s *SystemTable) GetRolesTable(ctx *context.Context) (roleTable Table) {
 					return nil, deleteRolePermissionErr
 				}
 
+				deleteRoleTwoFactorErr := s.connection().WithTx(tx).
+					Table("goadmin_role_two_factor").
+					WhereIn("role_id", ids).
+					Delete()
+				if db.CheckError(deleteRoleTwoFactorErr, db.DELETE) {
+					return nil, deleteRoleTwoFactorErr
+				}
+
 				deleteRolesErr := s.connection().WithTx(tx).
 					Table("goadmin_roles").
 					WhereIn("id", ids).
@@ -709,6 +769,18 @@ func (s *SystemTable) GetRolesTable(ctx *context.Context) (roleTable Table) {
 		}).
 		FieldHelpMsg(template.HTML(lg("no corresponding options?")) + " " + s.link("/info/permission/new", "Create here"))
 
+	if isRootAuth {
+		formList.AddField(lg("Two-factor authentication"), "two_factor", db.Varchar, form.Switch).
+			FieldOptions(types.BoolFieldOptions()).
+			FieldDisplay(func(model types.FieldModel) interface{} {
+				if model.ID != "" && twoFactor.IsRoleRequired(model.ID) {
+					return models.StrTrue
+				}
+				return models.StrFalse
+			}).
+			FieldHelpMsg(template.HTML(lg("Require the users of the role to login with the two-factor authentication")))
+	}
+
 	formList.AddField(lg("Updated At"), "updated_at", db.Timestamp, form.Default).FieldDisableWhenCreate()
 	formList.AddField(lg("Created At"), "created_at", db.Timestamp, form.Default).FieldDisableWhenCreate()
 
@@ -741,7 +813,11 @@ func (s *SystemTable) GetRolesTable(ctx *context.Context) (roleTable Table) {
 			return nil, nil
 		})
 
-		return txErr
+		if txErr != nil || !isRootAuth {
+			return txErr
+		}
+
+		return twoFactor.SetRoleRequired(values.Get("id"), values.Get("two_factor") == models.StrTrue)
 	})
 
 	formList.SetInsertFn(func(values form2.Values) error {
@@ -749,6 +825,8 @@ func (s *SystemTable) GetRolesTable(ctx *context.Context) (roleTable Table) {
 			return errors.New("slug exists")
 		}
 
+		var roleId int64
+
 		_, txErr := s.connection().WithTransaction(func(tx *sql.Tx) (map[string]interface{}, error) {
 			role, createRoleErr := models.Role().WithTx(tx).SetConn(s.conn).New(values.Get("name"), values.Get("slug"))
 			if db.CheckError(createRoleErr, db.INSERT) {
@@ -760,16 +838,22 @@ func (s *SystemTable) GetRolesTable(ctx *context.Context) (roleTable Table) {
 					return nil, addPermissionErr
 				}
 			}
+			roleId = role.Id
 			return nil, nil
 		})
 
-		return txErr
+		if txErr != nil || !isRootAuth || values.Get("two_factor") != models.StrTrue {
+			return txErr
+		}
+
+		return twoFactor.SetRoleRequired(strconv.FormatInt(roleId, 10), true)
 	})
 
 	return
 }
 
 func (s *SystemTable) GetOpTable(ctx *context.Context) (opTable Table) {
+	s = s.forContext(ctx)
 	allowDelete := auth.Auth(ctx).IsRootAdmin()
 
 	opTable = NewDefaultTable(Config{
@@ -844,7 +928,139 @@ func (s *SystemTable) GetOpTable(ctx *context.Context) (opTable Table) {
 	return
 }
 
+func (s *SystemTable) GetExportJobTable(ctx *context.Context) (exportJobTable Table) {
+	s = s.forContext(ctx)
+	exportJobTable = NewDefaultTable(Config{
+		Driver:     config.GetDatabases().GetDefault().Driver,
+		CanAdd:     false,
+		Editable:   false,
+		Deletable:  false,
+		Exportable: false,
+		Connection: "default",
+		PrimaryKey: PrimaryKey{ Type: db.Int, Name: DefaultPrimaryKeyName },
+	})
+
+	info := exportJobTable.GetInfo().AddXssJsFilter().
+		HideFilterArea().HideDetailButton().HideEditButton().HideNewButton().HideDeleteButton().HideExportButton().
+		Where("user_id", "=", auth.Auth(ctx).Id)
+
+	info.AddField("ID", "id", db.Int).FieldSortable()
+	info.AddField(lg("Table"), "prefix", db.Varchar)
+	info.AddField(lg("Format"), "format", db.Varchar)
+	info.AddField(lg("Status"), "status", db.Varchar).FieldDisplay(func(value types.FieldModel) interface{} {
+		return lg(value.Value)
+	})
+	info.AddField(lg("Rows"), "processed", db.Int)
+	info.AddField(lg("Message"), "message", db.Varchar)
+	info.AddField(lg("Expired At"), "expired_at", db.Bigint).FieldDisplay(func(value types.FieldModel) interface{} {
+		expiredAt, _ := strconv.ParseInt(value.Value, 10, 64)
+		if expiredAt == 0 { return "" }
+		return time.Unix(expiredAt, 0).Format("2006-01-02 15:04:05")
+	})
+	info.AddField(lg("Created At"), "created_at", db.Timestamp).FieldSortable()
+	info.AddField(lg("Download"), "file_path", db.Varchar).FieldDisplay(func(value types.FieldModel) interface{} {
+		expiredAt, _ := strconv.ParseInt(fmt.Sprintf("%v", value.Row["expired_at"]), 10, 64)
+		if value.Row["status"] != models.ExportJobStatusDone || time.Now().Unix() > expiredAt {
+			return ""
+		}
+		return template.Default().
+			Link().
+			SetURL(config.Url("/export_job/download?id=") + value.ID).
+			SetContent(template.HTML(lg("Download"))).
+			GetContent()
+	})
+
+	info.SetTable("goadmin_export_jobs").SetTitle(lg("Export Jobs"))
+
+	formList := exportJobTable.GetForm().AddXssJsFilter()
+	formList.AddField("ID", "id", db.Int, form.Default).FieldNotAllowEdit()
+	formList.SetTable("goadmin_export_jobs").SetTitle(lg("Export Jobs"))
+
+	return
+}
+
+func (s *SystemTable) GetAuditTrailTable(ctx *context.Context) (auditTable Table) {
+	s = s.forContext(ctx)
+	// the table is of the default connection, which is replaced by the database of
+	// the tenant the records are written to.
+	auditTable = NewDefaultTable(Config{
+		Driver:     config.GetDatabases().GetDefault().Driver,
+		CanAdd:     false,
+		Editable:   false,
+		Deletable:  false,
+		Exportable: true,
+		Connection: "default",
+		PrimaryKey: PrimaryKey{ Type: db.Int, Name: DefaultPrimaryKeyName },
+	})
+
+	info := auditTable.GetInfo().AddXssJsFilter().
+		HideDetailButton().HideEditButton().HideNewButton().HideDeleteButton()
+
+	info.AddField("ID", "id", db.Int).FieldSortable()
+	info.AddField("User ID", "user_id", db.Int).FieldHide()
+	info.AddField(lg("User"), "name", db.Varchar).FieldJoin(types.Join{
+		Table:     config.GetAuthUserTable(),
+		JoinField: "id",
+		Field:     "user_id",
+	}).FieldDisplay(func(value types.FieldModel) interface{} {
+		return template.Default().
+			Link().
+			SetURL(config.Url("/info/manager/detail?__goadmin_detail_pk=") + strconv.Itoa(int(value.Row["user_id"].(int64)))).
+			SetContent(template.HTML(value.Value)).
+			OpenInNewTab().
+			SetTabTitle("User Detail").
+			GetContent()
+	}).FieldFilterable()
+	info.AddField(lg("Table"), "table_name", db.Varchar).FieldFilterable()
+	info.AddField(lg("Primary Key"), "pk", db.Varchar).FieldFilterable()
+	info.AddField(lg("Action"), "action", db.Varchar).FieldFilterable(types.FilterType{ FormType: form.SelectSingle }).
+		FieldFilterOptions(types.FieldOptions{
+			{ Value: models.AuditActionInsert , Text: lg(models.AuditActionInsert)  },
+			{ Value: models.AuditActionUpdate , Text: lg(models.AuditActionUpdate)  },
+			{ Value: models.AuditActionDelete , Text: lg(models.AuditActionDelete)  },
+			{ Value: models.AuditActionRestore, Text: lg(models.AuditActionRestore) },
+			{ Value: models.AuditActionPurge  , Text: lg(models.AuditActionPurge)   },
+		}).
+		FieldDisplay(func(value types.FieldModel) interface{} {
+			return lg(value.Value)
+		})
+	// the masked and the hidden columns of the data policies of the user, which are
+	// read when the changes are displayed.
+	var policyRules map[string]map[string]ColumnRule
+	info.AddField(lg("Changes"), "diff", db.Text).FieldDisplay(func(value types.FieldModel) interface{} {
+		if policyRules == nil {
+			policyRules = policyAuditRules(ctx, s.generators)
+		}
+		var (
+			diff   = models.AuditModel{ Diff: value.Value }.GetDiff()
+			masked = policyRules[fmt.Sprintf("%v", value.Row["table_name"])]
+			res    = ""
+		)
+		for _, field := range diff.Fields() {
+			change := diff[field]
+			before, after := models.FormatAuditValue(change.Before), models.FormatAuditValue(change.After)
+			if masked[field] >= ColumnMask {
+				before, after = MaskedValue, MaskedValue
+			}
+			res += fmt.Sprintf("<p><b>%s</b>: %s &rarr; %s</p>", html2.EscapeString(field),
+				html2.EscapeString(before), html2.EscapeString(after))
+		}
+		return template.HTML(res)
+	}).FieldWidth(400)
+	info.AddField(lg("Created At"), "created_at", db.Timestamp).FieldSortable().
+		FieldFilterable(types.FilterType{ FormType: form.DatetimeRange })
+
+	info.SetTable(models.AuditTableName).SetTitle(lg("Audit Trail"))
+
+	formList := auditTable.GetForm().AddXssJsFilter()
+	formList.AddField("ID", "id", db.Int, form.Default).FieldNotAllowEdit()
+	formList.SetTable(models.AuditTableName).SetTitle(lg("Audit Trail"))
+
+	return
+}
+
 func (s *SystemTable) GetMenuTable(ctx *context.Context) (menuTable Table) {
+	s = s.forContext(ctx)
 	user        := auth.Auth(ctx)
 	allowEdit   := user.IsSuperAdmin()
 	allowDelete := user.IsRootAdmin()
@@ -979,6 +1195,7 @@ func (s *SystemTable) GetMenuTable(ctx *context.Context) (menuTable Table) {
 }
 
 func (s *SystemTable) GetSiteTable(ctx *context.Context) (siteTable Table) {
+	s = s.forContext(ctx)
 	siteTable = NewDefaultTable(DefaultConfigWithDriver(config.GetDatabases().GetDefault().Driver).
 		SetOnlyUpdateForm().
 		SetGetDataFun(func(params parameter.Parameters) (i []map[string]interface{}, i2 int) {
@@ -1046,6 +1263,14 @@ func (s *SystemTable) GetSiteTable(ctx *context.Context) (siteTable Table) {
 	formList.AddField(lgWithConfigScore("Login logo"), "login_logo", db.Varchar, form.Code)
 	formList.AddField(lgWithConfigScore("No limit login IP"), "no_limit_login_ip", db.Varchar, form.Switch).
 		FieldOptions(types.BoolFieldOptions())
+	formList.AddField(lgWithConfigScore("Login max attempts"), "login_max_attempts", db.Varchar, form.Number).
+		FieldHelpMsg(template.HTML(lgWithConfigScore("failed attempts of a username before it is locked, negative to turn off")))
+	formList.AddField(lgWithConfigScore("Login IP max attempts"), "login_ip_max_attempts", db.Varchar, form.Number).
+		FieldHelpMsg(template.HTML(lgWithConfigScore("failed attempts of an IP before it is locked, negative to turn off")))
+	formList.AddField(lgWithConfigScore("Login lockout time"), "login_lockout_time", db.Varchar, form.Number).
+		FieldHelpMsg(template.HTML(lgWithConfigScore("seconds")))
+	formList.AddField(lgWithConfigScore("Login backoff time"), "login_backoff_time", db.Varchar, form.Number).
+		FieldHelpMsg(template.HTML(lgWithConfigScore("initial delay after a failed attempt in seconds, doubled for each failure")))
 	formList.AddField(lgWithConfigScore("Access log off"), "operation_log_off", db.Varchar, form.Switch).
 		FieldOptions(types.BoolFieldOptions())
 	formList.AddField(lgWithConfigScore("Allow delete operation log"), "allow_del_operation_log", db.Varchar, form.Switch).
@@ -1173,6 +1398,7 @@ func (s *SystemTable) GetSiteTable(ctx *context.Context) (siteTable Table) {
 	formList.HideBackButton().HideContinueEditCheckBox().HideContinueNewCheckBox()
 	formList.SetTabGroups(types.NewTabGroups("id", "debug", "env", "language", "theme", "color_scheme",
 		"asset_url", "title", "login_title", "session_life_time", "no_limit_login_ip",
+		"login_max_attempts", "login_ip_max_attempts", "login_lockout_time", "login_backoff_time",
 		"operation_log_off", "allow_del_operation_log", "hide_config_center_entrance", "hide_app_info_entrance", "hide_tool_entrance",
 		"hide_plugin_entrance", "animation_type",
 		"animation_duration", "animation_delay", "file_upload_engine", "extra").
@@ -1195,6 +1421,11 @@ func (s *SystemTable) GetSiteTable(ctx *context.Context) (siteTable Table) {
 		if sesInt < 900 {
 			return errors.New("wrong session life time, must bigger than 900 seconds")
 		}
+		for _, key := range []string{"login_lockout_time", "login_backoff_time"} {
+			if v, err := strconv.Atoi(values.Get(key)); err != nil || v <= 0 {
+				return errors.New("wrong " + strings.ReplaceAll(key, "_", " ") + ", must bigger than 0 seconds")
+			}
+		}
 		if err := checkJSON(values, "file_upload_engine"); err != nil {
 			return err
 		}
@@ -1209,27 +1440,12 @@ func (s *SystemTable) GetSiteTable(ctx *context.Context) (siteTable Table) {
 		values["footer_info"][0] = escape(values.Get("footer_info"))
 		values["login_logo"][0] = escape(values.Get("login_logo"))
 
-		var err error
-		if s.cfg.UpdateProcessFn != nil {
-			values, err = s.cfg.UpdateProcessFn(values)
-			if err != nil {
-				return err
-			}
-		}
-
-		ui.GetService(services).RemoveOrShowSiteNavButton(values["hide_config_center_entrance"][0] == "true")
-		ui.GetService(services).RemoveOrShowInfoNavButton(values["hide_app_info_entrance"][0] == "true")
-		ui.GetService(services).RemoveOrShowToolNavButton(values["hide_tool_entrance"][0] == "true")
-		ui.GetService(services).RemoveOrShowPlugNavButton(values["hide_plugin_entrance"][0] == "true")
-
-		// TODO: add transaction
-		err = models.Site().SetConn(s.conn).Update(values.RemoveSysRemark())
-		if err != nil {
-			return err
-		}
-		return s.cfg.Update(values.ToMap())
+		return s.updateSite(values, auth.Auth(ctx).Id, models.ConfigRevisionActionUpdate, 0)
 	})
 
+	formList.SetHeaderHtml(template.HTML(`<div class="pull-right" style="margin-bottom: 10px;">`) +
+		s.link("/info/config_revisions", lgWithConfigScore("revisions")) + `</div>`)
+
 	formList.EnableAjax(
 		lgWithConfigScore("Modify site config"),
 		lgWithConfigScore("modify site config"),
@@ -1240,6 +1456,314 @@ func (s *SystemTable) GetSiteTable(ctx *context.Context) (siteTable Table) {
 	return
 }
 
+// updateSite saves the site config and records the revision of the changed items,
+// then updates the config in memory with the side effects.
+func (s *SystemTable) updateSite(values form2.Values, userId int64, action string, targetId int64) error {
+	var err error
+	if fn := s.cfg.Snapshot().UpdateProcessFn; fn != nil {
+		values, err = fn(values)
+		if err != nil {
+			return err
+		}
+	}
+
+	ui.GetService(services).RemoveOrShowSiteNavButton(values.Get("hide_config_center_entrance") == "true")
+	ui.GetService(services).RemoveOrShowInfoNavButton(values.Get("hide_app_info_entrance") == "true")
+	ui.GetService(services).RemoveOrShowToolNavButton(values.Get("hide_tool_entrance") == "true")
+	ui.GetService(services).RemoveOrShowPlugNavButton(values.Get("hide_plugin_entrance") == "true")
+
+	values = values.RemoveSysRemark()
+	site  := models.Site().SetConn(s.conn)
+	diff  := site.Diff(values)
+
+	// TODO: add transaction
+	err = site.Update(values)
+	if err != nil {
+		return err
+	}
+	if len(diff) > 0 {
+		if err := models.ConfigRevision().SetConn(s.conn).Record(userId, action, targetId, diff); err != nil {
+			logger.Error("record config revision error: ", err)
+		}
+	}
+	return s.cfg.Update(values.ToMap())
+}
+
+// rollbackSite restores the site config to the state right after the revision,
+// by reverting the changes of the later revisions from the latest one.
+func (s *SystemTable) rollbackSite(id string, userId int64) error {
+	target := models.ConfigRevision().SetConn(s.conn).Find(id)
+	if target.IsEmpty() {
+		return errors.New("revision not found")
+	}
+
+	values := make(form2.Values)
+	for key, value := range models.Site().SetConn(s.conn).AllToMap() {
+		values[key] = []string{value}
+	}
+	for _, revision := range models.ConfigRevision().SetConn(s.conn).GetAfter(target.Id) {
+		for key, change := range revision.GetDiff() {
+			if before, ok := change.Before.(string); ok {
+				values[key] = []string{before}
+			}
+		}
+	}
+
+	return s.updateSite(values, userId, models.ConfigRevisionActionRollback, target.Id)
+}
+
+func (s *SystemTable) GetConfigRevisionTable(ctx *context.Context) (revisionTable Table) {
+	s = s.forContext(ctx)
+	revisionTable = NewDefaultTable(Config{
+		Driver:     config.GetDatabases().GetDefault().Driver,
+		CanAdd:     false,
+		Editable:   false,
+		Deletable:  false,
+		Exportable: false,
+		Connection: "default",
+		PrimaryKey: PrimaryKey{ Type: db.Int, Name: DefaultPrimaryKeyName },
+	})
+
+	info := revisionTable.GetInfo().AddXssJsFilter().HideFilterArea().
+		HideDetailButton().HideEditButton().HideNewButton().HideDeleteButton()
+
+	info.AddField("ID", "id", db.Int).FieldSortable()
+	info.AddField("User ID", "user_id", db.Int).FieldHide()
+	info.AddField(lg("User"), "name", db.Varchar).FieldJoin(types.Join{
+		Table:     config.GetAuthUserTable(),
+		JoinField: "id",
+		Field:     "user_id",
+	})
+	info.AddField(lg("Action"), "action", db.Varchar).FieldDisplay(func(value types.FieldModel) interface{} {
+		if value.Value == models.ConfigRevisionActionRollback {
+			return fmt.Sprintf("%s #%v", lg(value.Value), value.Row["target_id"])
+		}
+		return lg(value.Value)
+	})
+	info.AddField("Target ID", "target_id", db.Int).FieldHide()
+	info.AddField(lg("Changes"), "diff", db.Text).FieldDisplay(func(value types.FieldModel) interface{} {
+		diff := models.ConfigRevisionModel{ Diff: value.Value }.GetDiff()
+		res  := ""
+		for _, key := range diff.Fields() {
+			change := diff[key]
+			res += fmt.Sprintf("<p><b>%s</b>: <del>%s</del> &rarr; %s</p>", html2.EscapeString(key),
+				html2.EscapeString(models.FormatAuditValue(change.Before)), html2.EscapeString(models.FormatAuditValue(change.After)))
+		}
+		return template.HTML(res)
+	}).FieldWidth(500)
+	info.AddField(lg("Created At"), "created_at", db.Timestamp).FieldSortable()
+
+	info.AddActionButton(template.HTML(lg("Rollback")), action.Ajax("config_revision_rollback",
+		func(ctx *context.Context) (success bool, msg string, data interface{}) {
+			if err := s.rollbackSite(ctx.FormValue("id"), auth.Auth(ctx).Id); err != nil {
+				return false, err.Error(), ""
+			}
+			return true, lg("rollback success"), ""
+		}).WithAlert())
+
+	info.SetTable(models.ConfigRevisionTableName).SetTitle(lg("Config Revisions")).
+		SetDescription(lg("rollback restores the site config right after the revision"))
+
+	formList := revisionTable.GetForm().AddXssJsFilter()
+	formList.AddField("ID", "id", db.Int, form.Default).FieldNotAllowEdit()
+	formList.SetTable(models.ConfigRevisionTableName).SetTitle(lg("Config Revisions"))
+
+	return
+}
+
+// GetTableDefTable return the designer of the tables, which saves the definitions
+// of the tables into the database and serves them at once. Every save adds a
+// version of the table, and the latest one is live. The definitions are shared
+// by the tenants, so that they are designed out of the tenants.
+func (s *SystemTable) GetTableDefTable(ctx *context.Context) (defTable Table) {
+	defTable = NewDefaultTable(DefaultConfigWithDriver(config.GetDatabases().GetDefault().Driver))
+
+	var (
+		live   = make(map[string]int64)
+		userId = int64(0)
+	)
+	if user, ok := ctx.User().(models.UserModel); ok {
+		userId = user.Id
+	}
+	_, isTenant := tenant.FromContext(ctx)
+	if !isTenant {
+		for _, def := range models.TableDef().SetConn(s.conn).AllLatest() {
+			live[def.Prefix] = def.Version
+		}
+	}
+
+	definition := func(row map[string]interface{}) Definition {
+		content, _ := row["definition"].(string)
+		d, _ := tableDefDefinition(content)
+		return d
+	}
+	fieldValues := func(def string, fn func(f DefinitionField) string) types.FieldFilterFn {
+		return func(value types.FieldModel) interface{} {
+			fields := definition(value.Row).Fields
+			if len(fields) == 0 {
+				return []string{ def }
+			}
+			res := make([]string, len(fields))
+			for i, f := range fields {
+				res[i] = fn(f)
+			}
+			return res
+		}
+	}
+	check := func(b bool) string {
+		if b { return "y" }
+		return "n"
+	}
+
+	info := defTable.GetInfo().AddXssJsFilter().HideFilterArea().HideDetailButton()
+
+	info.AddField("ID", "id", db.Int).FieldSortable()
+	info.AddField(lg("Prefix"), "prefix", db.Varchar).FieldFilterable().
+		FieldDisplay(func(value types.FieldModel) interface{} {
+			if live[value.Value] == 0 {
+				return value.Value
+			}
+			return s.link("/info/"+value.Value, value.Value)
+		})
+	info.AddField(lg("Version"), "version", db.Int).FieldSortable().
+		FieldDisplay(func(value types.FieldModel) interface{} {
+			if version, _ := strconv.ParseInt(value.Value, 10, 64); live[value.Row["prefix"].(string)] == version {
+				return template.HTML(value.Value + " " + string(label().SetType("success").SetContent(template.HTML(lg("live"))).GetContent()))
+			}
+			return value.Value
+		})
+	info.AddField(lg("Title"), "title", db.Varchar)
+	info.AddField("User ID", "user_id", db.Int).FieldHide()
+	info.AddField(lg("User"), "name", db.Varchar).FieldJoin(types.Join{
+		Table:     config.GetAuthUserTable(),
+		JoinField: "id",
+		Field:     "user_id",
+	})
+	info.AddField(lg("Created At"), "created_at", db.Timestamp).FieldSortable()
+
+	info.AddActionButton(template.HTML(lg("Export code")), action.PopUp("table_def_export", lg("Export code"),
+		func(ctx *context.Context) (success bool, msg string, data interface{}) {
+			def := models.TableDef().SetConn(s.conn).Find(ctx.FormValue("id"))
+			if def.IsEmpty() {
+				return false, "wrong id", ""
+			}
+			d, err := tableDefDefinition(def.Definition)
+			if err != nil { return false, err.Error(), "" }
+			code, err := d.GoCode("tables")
+			if err != nil { return false, err.Error(), "" }
+			return true, "ok", `<pre style="max-height:600px;overflow:auto;">` + html2.EscapeString(string(code)) + `</pre>`
+		}))
+
+	info.SetTable(models.TableDefTableName).SetTitle(lg("Table Designer")).
+		SetDescription(lg("the latest version of a table is live, deleting it brings the version before back")).
+		SetDeleteFn(func(idArr []string) error {
+			prefixes, err := models.TableDef().SetConn(s.conn).Delete(interfaces(idArr))
+			s.syncTableDefs(prefixes)
+			return err
+		})
+
+	formList := defTable.GetForm().AddXssJsFilter()
+
+	formList.AddField("ID", "id", db.Int, form.Default).FieldDisplayButCanNotEditWhenUpdate().FieldDisableWhenCreate()
+	formList.AddField(lg("Prefix"), "prefix", db.Varchar, form.Text).FieldMust().FieldDisplayButCanNotEditWhenUpdate().
+		FieldHelpMsg(template.HTML(lg("the table is served at /info/{prefix}")))
+	formList.AddField(lg("Connection"), "connection", db.Varchar, form.SelectSingle).
+		FieldOptionInitFn(func(value types.FieldModel) types.FieldOptions {
+			databases := s.cfg.Snapshot().Databases
+			options   := make(types.FieldOptions, 0, len(databases))
+			for name := range databases {
+				options = append(options, types.FieldOption{ Text: name, Value: name })
+			}
+			sort.Slice(options, func(i, j int) bool { return options[i].Value < options[j].Value })
+			connection := utils.SetDefault(definition(value.Row).Connection, "", DefaultConnectionName)
+			return options.SetSelected(connection, form.SelectSingle.SelectedLabel())
+		})
+	formList.AddField(lg("Table"), "table", db.Varchar, form.Text).FieldMust().
+		FieldDisplay(func(value types.FieldModel) interface{} { return definition(value.Row).Table })
+	formList.AddField(lg("Primary Key"), "pk", db.Varchar, form.Text).FieldDefault(DefaultPrimaryKeyName).
+		FieldDisplay(func(value types.FieldModel) interface{} {
+			return utils.SetDefault(definition(value.Row).PrimaryKey, "", DefaultPrimaryKeyName)
+		})
+	formList.AddField(lg("Title"), "title", db.Varchar, form.Text)
+	formList.AddField(lg("Description"), "description", db.Varchar, form.Text).
+		FieldDisplay(func(value types.FieldModel) interface{} { return definition(value.Row).Description })
+	formList.AddField(lg("Read only"), "read_only", db.Varchar, form.Switch).
+		FieldOptions(types.FieldOptions{
+			{ Text: lg("yes"), Value: "y" },
+			{ Text: lg("no"), Value: "n" },
+		}).FieldDefault("y").
+		FieldDisplay(func(value types.FieldModel) interface{} {
+			if len(value.Row) == 0 {
+				return "y"
+			}
+			return check(!definitionFlag(definition(value.Row).Editable))
+		})
+	formList.AddField(lg("View roles"), "view_roles", db.Varchar, form.Text).
+		FieldHelpMsg(template.HTML(lg("the slugs of the roles which can view the table, separated by commas"))).
+		FieldDisplay(func(value types.FieldModel) interface{} {
+			return strings.Join(definition(value.Row).Permissions.View, ",")
+		})
+
+	formList.AddTable(lgWithScore("Field", "tool"), "fields", func(pa *types.FormPanel) {
+		pa.AddField(lgWithScore("Title", "tool"), "field_head", db.Varchar, form.Text).FieldHideLabel().
+			FieldDisplay(fieldValues("", func(f DefinitionField) string { return f.Head }))
+		pa.AddField(lgWithScore("Field name", "tool"), "field_name", db.Varchar, form.Text).FieldHideLabel().
+			FieldDisplay(fieldValues("", func(f DefinitionField) string { return f.Field }))
+		pa.AddField(lgWithScore("Field filterable", "tool"), "field_filterable", db.Varchar, form.CheckboxSingle).
+			FieldOptions(types.FieldOptions{
+				{ Text: "", Value: "y" },
+				{ Text: "", Value: "n" },
+			}).
+			FieldDefault("n").
+			FieldDisplay(fieldValues("n", func(f DefinitionField) string { return check(f.Filter) }))
+		pa.AddField(lgWithScore("Field sortable", "tool"), "field_sortable", db.Varchar, form.CheckboxSingle).
+			FieldOptions(types.FieldOptions{
+				{ Text: "", Value: "y" },
+				{ Text: "", Value: "n" },
+			}).
+			FieldDefault("n").
+			FieldDisplay(fieldValues("n", func(f DefinitionField) string { return check(f.Sortable) }))
+		pa.AddField(lgWithScore("Field hide", "tool"), "field_hide", db.Varchar, form.CheckboxSingle).
+			FieldOptions(types.FieldOptions{
+				{ Text: "", Value: "y" },
+				{ Text: "", Value: "n" },
+			}).
+			FieldDefault("n").
+			FieldDisplay(fieldValues("n", func(f DefinitionField) string { return check(f.Hide) }))
+		pa.AddField(lgWithScore("DB type", "tool"), "field_db_type", db.Varchar, form.SelectSingle).
+			FieldOptions(databaseTypeOptions()).
+			FieldDisplay(fieldValues("Int", func(f DefinitionField) string {
+				return strings.Title(strings.ToLower(utils.SetDefault(f.Type, "", "varchar")))
+			}))
+		pa.AddField(lg("Display"), "field_display", db.Varchar, form.SelectSingle).
+			FieldOptions(tableDefDisplayOptions()).
+			FieldDisplay(fieldValues("", func(f DefinitionField) string { return strings.ToLower(f.Display) }))
+	}).FieldInputWidth(11)
+
+	formList.SetTable(models.TableDefTableName).SetTitle(lg("Table Designer"))
+
+	formList.SetInsertFn(func(values form2.Values) error {
+		return s.saveTableDef(strings.TrimSpace(values.Get("prefix")), values, userId)
+	})
+
+	// an update saves the next version, so that an old version is brought
+	// back by saving it again.
+	formList.SetUpdateFn(func(values form2.Values) error {
+		def := models.TableDef().SetConn(s.conn).Find(values.Get("id"))
+		if def.IsEmpty() {
+			return errors.New("wrong id")
+		}
+		return s.saveTableDef(def.Prefix, values, userId)
+	})
+
+	if isTenant {
+		info.SetError(errs.PageError403)
+		formList.SetError(errs.PageError403)
+	}
+
+	return
+}
+
 /*func (s *SystemTable) GetGenerateForm(ctx *context.Context) (generateTool Table) {
 	generateTool = NewDefaultTable(DefaultConfigWithDriver(config.GetDatabases().GetDefault().Driver).
 		SetOnlyNewForm())
@@ -1269,7 +1793,7 @@ func (s *SystemTable) GetSiteTable(ctx *context.Context) (siteTable Table) {
 				if connName == "" {
 					return false, "wrong parameter", nil
 				}
-				cfg := s.cfg.Databases[connName]
+				cfg := s.cfg.Snapshot().Databases[connName]
 				conn := db.GetConnectionFromService(services.MustGet(cfg.Driver))
 				tables, err := db.WithDriverAndConnection(connName, conn).Table(cfg.Name).ShowTables()
 				if err != nil {
@@ -1288,7 +1812,7 @@ func (s *SystemTable) GetSiteTable(ctx *context.Context) (siteTable Table) {
 				var (
 					tableName       = ctx.FormValue("value")
 					connName        = ctx.FormValue("conn")
-					driver          = s.cfg.Databases[connName].Driver
+					driver          = s.cfg.Snapshot().Databases[connName].Driver
 					conn            = db.GetConnectionFromService(services.MustGet(driver))
 					columnsModel, _ = db.WithDriverAndConnection(connName, conn).Table(tableName).ShowColumns()
 
@@ -1652,7 +2176,7 @@ func (s *SystemTable) GetSiteTable(ctx *context.Context) (siteTable Table) {
 
 		err := tools.Generate(tools.NewParamWithFields(tools.Config{
 			Connection:               connName,
-			Driver:                   s.cfg.Databases[connName].Driver,
+			Driver:                   s.cfg.Snapshot().Databases[connName].Driver,
 			Package:                  values.MustGet("package"),
 			Table:                    table,
 			HideFilterArea:           values.MustGet("hide_filter_area") == "y",
@@ -1871,5 +2395,5 @@ func passwordFromValues(values form2.Values) (string, error) {
 	if password == "" {
 		return "", nil
 	}
-	return auth.EncryptPass(auth.EncryptPassAlgo, password), nil
+	return auth.EncodePassword(password), nil
 }
@@ -0,0 +1,140 @@
+package table
+
+import (
+	dbsql "database/sql"
+	"fmt"
+
+	"github.com/GoAdminGroup/go-admin/plugins/admin/modules/form"
+	"github.com/GoAdminGroup/go-admin/template/types"
+)
+
+// ImportBatchSize is the number of the rows inserted within one transaction by ImportData.
+var ImportBatchSize = 100
+
+// ImportRowError is the error of an imported row, the Row starts from 1.
+type ImportRowError struct {
+	Row   int    `json:"row"`
+	Error string `json:"error"`
+}
+
+// ImportResult is the report of ImportData.
+type ImportResult struct {
+	DryRun   bool             `json:"dry_run"`
+	Total    int              `json:"total"`
+	Inserted int              `json:"inserted"`
+	Errors   []ImportRowError `json:"errors"`
+}
+
+func (r *ImportResult) addError(row int, err error) {
+	r.Errors = append(r.Errors, ImportRowError{ Row: row + 1, Error: err.Error() })
+}
+
+// ImportData inserts the rows like InsertData does. The values of the columns the
+// data policies of the user forbid are dropped, every row is validated by the
+// Validator, PreProcessFn and PostFieldFilterFn of the new form, and nothing is
+// inserted when dryRun is true. Otherwise the valid rows are inserted in the
+// transactions of ImportBatchSize rows, a failed insertion rolls back its batch.
+func (tb *DefaultTable) ImportData(rows []form.Values, dryRun bool) ImportResult {
+	var (
+		f      = tb.GetActualNewForm()
+		result = ImportResult{ DryRun: dryRun, Total: len(rows), Errors: make([]ImportRowError, 0) }
+	)
+
+	if dryRun {
+		for i, row := range rows {
+			tb.policyValues(row)
+			dataList, err := tb.prepareInsertData(f, row)
+			if err != nil {
+				result.addError(i, err)
+				continue
+			}
+			// the PostFieldFilterFn of the fields are called here.
+			if f.InsertFn != nil {
+				tb.PreProcessValue(dataList, types.PostTypeCreate)
+			} else {
+				tb.getInjectValueFromFormValue(dataList, types.PostTypeCreate)
+			}
+		}
+		return result
+	}
+
+	defer tb.invalidateListCache()
+
+	// the InsertFn and the remote source can not join a transaction, so the rows are
+	// inserted one by one.
+	if f.InsertFn != nil || tb.remote != nil || tb.connectionDriver == "" {
+		for i, row := range rows {
+			if err := tb.InsertData(row); err != nil {
+				result.addError(i, err)
+				continue
+			}
+			result.Inserted++
+		}
+		return result
+	}
+
+	size := ImportBatchSize
+	if size <= 0 {
+		size = len(rows)
+	}
+
+	for start := 0; start < len(rows); start += size {
+		end := start + size
+		if end > len(rows) {
+			end = len(rows)
+		}
+		result.Inserted += tb.importBatch(f, rows[start:end], start, &result)
+	}
+
+	return result
+}
+
+type importedRow struct {
+	index    int
+	dataList form.Values
+	id       int64
+}
+
+// importBatch inserts the rows within a transaction and return the number of the inserted rows.
+func (tb *DefaultTable) importBatch(f *types.FormPanel, rows []form.Values, offset int, result *ImportResult) int {
+	var (
+		inserted = make([]importedRow, 0, len(rows))
+		failed   = -1
+	)
+
+	_, err := tb.sql().WithTransaction(func(tx *dbsql.Tx) (map[string]interface{}, error) {
+		for i, row := range rows {
+			tb.policyValues(row)
+			dataList, err := tb.prepareInsertData(f, row)
+			if err != nil {
+				result.addError(offset + i, err)
+				continue
+			}
+			id, err := tb.insertData(f, dataList, tx)
+			if err != nil {
+				failed = offset + i
+				return nil, err
+			}
+			inserted = append(inserted, importedRow{ index: offset + i, dataList: dataList, id: id })
+		}
+		return nil, nil
+	})
+
+	if err != nil {
+		if failed != -1 {
+			result.addError(failed, err)
+			err = fmt.Errorf("rolled back because row %d failed", failed + 1)
+		}
+		for _, row := range inserted {
+			result.addError(row.index, err)
+		}
+		return 0
+	}
+
+	for _, row := range inserted {
+		tb.auditInsert(f.Table, row.id, row.dataList)
+		tb.postInsert(f, row.dataList, row.id, nil)
+	}
+
+	return len(inserted)
+}
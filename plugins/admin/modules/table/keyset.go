@@ -0,0 +1,223 @@
+package table
+
+import (
+	"fmt"
+	"html/template"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/GoAdminGroup/go-admin/modules/db"
+	"github.com/GoAdminGroup/go-admin/modules/logger"
+	"github.com/GoAdminGroup/go-admin/modules/utils"
+	"github.com/GoAdminGroup/go-admin/plugins/admin/modules"
+	"github.com/GoAdminGroup/go-admin/plugins/admin/modules/paginator"
+	"github.com/GoAdminGroup/go-admin/plugins/admin/modules/parameter"
+	"github.com/GoAdminGroup/go-admin/template/types"
+)
+
+// keysetPage is a page of the keyset pagination. The rows of the page are the ones
+// after the row of the After cursor in the order of the sort field and the primary
+// key, or the ones before the row of the Before cursor, which are queried in the
+// reversed order and reversed back.
+type keysetPage struct {
+	pk        string
+	sortField string
+	sortType  string
+	reversed  bool
+	cursor    []*string
+}
+
+func (tb *DefaultTable) newKeysetPage(params parameter.Parameters) *keysetPage {
+	page := &keysetPage{ pk: tb.PrimaryKey.Name, sortField: params.SortField, sortType: params.SortType }
+
+	cursor := params.After
+	if params.Before != "" {
+		cursor        = params.Before
+		page.reversed = true
+		if page.sortType == "asc" {
+			page.sortType = "desc"
+		} else {
+			page.sortType = "asc"
+		}
+	}
+	if cursor == "" {
+		page.reversed = false
+		return page
+	}
+
+	values, err := parameter.DecodeCursor(cursor)
+	if err != nil || len(values) != 2 || values[1] == nil {
+		logger.Error("keyset pagination: wrong cursor ", cursor)
+		page.reversed = false
+		page.sortType = params.SortType
+		return page
+	}
+	page.cursor = values
+	return page
+}
+
+// statement return the query of the page, the format verbs are the same as the
+// query of the offset pagination and the last argument is the limit.
+func (page *keysetPage) statement(isMssql bool, placeholder, pk string) string {
+	order := ""
+	if page.sortField != page.pk {
+		order = utils.StrConcat(", ", strings.ReplaceAll(pk, "%", "%%"), " ", page.sortType)
+	}
+	if isMssql {
+		// %s means: order by field, order by type, fields, table, join table, wheres, group by
+		return utils.StrConcat("SELECT * FROM (SELECT ROW_NUMBER() OVER (ORDER BY %s.", placeholder, " %s", order,
+			") AS ROWNUMBER_, %s FROM ", placeholder, "%s %s %s ) as TMP_ WHERE TMP_.ROWNUMBER_ <= ? ORDER BY TMP_.ROWNUMBER_")
+	}
+	// %s means: fields, table, join table, wheres, group by, order by field, order by type
+	return utils.StrConcat("SELECT %s FROM ", placeholder, "%s %s %s ORDER BY ", placeholder, ".", placeholder, " %s", order, " LIMIT ?")
+}
+
+// where return the condition of the rows after the cursor in the order of the query.
+// The NULL values of the sort field are compared by the IS NULL, they are first in
+// the ascending order and last in the descending order, except the postgresql
+// which puts them the other way round.
+func (page *keysetPage) where(table, pk, delim, delim2 string, isPostgresql bool) (string, []interface{}) {
+	if page.cursor == nil {
+		return "", nil
+	}
+	op := ">"
+	if page.sortType == "desc" {
+		op = "<"
+	}
+	id := *page.cursor[1]
+	if page.sortField == page.pk {
+		return utils.StrConcat(pk, " ", op, " ?"), []interface{}{ id }
+	}
+
+	sortField  := utils.StrConcat(table, ".", modules.Delimiter(delim, delim2, page.sortField))
+	nullsFirst := (page.sortType == "asc") != isPostgresql
+
+	if page.cursor[0] == nil {
+		// after a NULL are the NULLs of the greater keys, and all the values when
+		// the NULLs are first.
+		stmt := utils.StrConcat("(", sortField, " IS NULL AND ", pk, " ", op, " ?)")
+		if nullsFirst {
+			stmt = utils.StrConcat("(", stmt, " OR ", sortField, " IS NOT NULL)")
+		}
+		return stmt, []interface{}{ id }
+	}
+
+	value := *page.cursor[0]
+	stmt  := utils.StrConcat(sortField, " ", op, " ? OR (", sortField, " = ? AND ", pk, " ", op, " ?)")
+	if !nullsFirst {
+		stmt = utils.StrConcat(stmt, " OR ", sortField, " IS NULL")
+	}
+	return utils.StrConcat("(", stmt, ")"), []interface{}{ value, value, id }
+}
+
+// rows return the rows of the page in the order of the sort type of the parameters
+// from the rows queried with the limit of the page size plus one, and whether
+// there are more rows in the direction of the query.
+func (page *keysetPage) rows(res []map[string]interface{}, pageSize int) ([]map[string]interface{}, bool) {
+	more := len(res) > pageSize
+	if more {
+		res = res[:pageSize]
+	}
+	if !page.reversed {
+		return res, more
+	}
+	// the rows may be the ones in the cache, they are reversed in a new slice.
+	rows := make([]map[string]interface{}, len(res))
+	for i, row := range res {
+		rows[len(res) - 1 - i] = row
+	}
+	return rows, more
+}
+
+// cursorOf return the cursor of the row.
+func (page *keysetPage) cursorOf(row map[string]interface{}) string {
+	return parameter.EncodeCursor(cursorValue(row[page.sortField]), cursorValue(row[page.pk]))
+}
+
+// cursorValue return the value of the cursor, which is nil for the NULL.
+func cursorValue(value interface{}) *string {
+	var v string
+	switch value := value.(type) {
+	case nil:
+		return nil
+	case time.Time:
+		v = value.Format("2006-01-02 15:04:05.999999")
+	case []byte:
+		v = string(value)
+	default:
+		v = fmt.Sprintf("%v", value)
+	}
+	return &v
+}
+
+// keysetPaginator return the paginator of the page with the previous and the next
+// links and no page numbers.
+func (tb *DefaultTable) keysetPaginator(page *keysetPage, params parameter.Parameters, rows []map[string]interface{},
+	more bool, size int, extraHtml template.HTML) types.PaginatorAttribute {
+
+	cfg := paginator.CursorConfig{
+		Param:        params,
+		PageSizeList: tb.Info.GetPageSizeList(),
+		Count:        len(rows),
+		Estimated:    size,
+	}
+
+	if len(rows) > 0 {
+		first, last := rows[0], rows[len(rows) - 1]
+		hasPrevious  := page.cursor != nil && !page.reversed || page.reversed && more
+		hasNext      := !page.reversed && more || page.reversed
+		if hasPrevious { cfg.Previous = page.cursorOf(first) }
+		if hasNext { cfg.Next = page.cursorOf(last) }
+	} else if page.cursor != nil {
+		// an empty page after the last row or before the first row, links back to
+		// the first page.
+		cfg.First = true
+	}
+
+	return paginator.GetCursor(cfg).SetExtraInfo(extraHtml)
+}
+
+// estimatedCount return the count of the rows of the table from the statistics
+// of the database, it is zero when the statistics are not available.
+func (tb *DefaultTable) estimatedCount(conn db.Connection, params parameter.Parameters) int {
+	var (
+		stmt string
+		args = []interface{}{ tb.Info.Table }
+	)
+	switch conn.Name() {
+	case db.DriverPostgresql:
+		stmt = "SELECT CAST(reltuples AS bigint) AS estimate FROM pg_class WHERE relname = ?"
+	case db.DriverMysql:
+		stmt = "SELECT table_rows AS estimate FROM information_schema.tables WHERE table_schema = DATABASE() AND table_name = ?"
+	case db.DriverMssql:
+		stmt = "SELECT SUM(rows) AS estimate FROM sys.partitions WHERE object_id = OBJECT_ID(?) AND index_id IN (0, 1)"
+	default:
+		return 0
+	}
+
+	res, err := conn.QueryWithConnectionContext(params.Context(), tb.connection, stmt, args...)
+	if err != nil || len(res) == 0 {
+		if err != nil { logger.Error("estimated count error: ", err) }
+		return 0
+	}
+
+	var n int
+	switch v := res[0]["estimate"].(type) {
+	case int64:
+		n = int(v)
+	case uint64:
+		n = int(v)
+	case float64:
+		n = int(v)
+	case []byte:
+		n, _ = strconv.Atoi(string(v))
+	case string:
+		n, _ = strconv.Atoi(v)
+	}
+	// the reltuples of postgresql is -1 before the table is analyzed.
+	if n < 0 {
+		return 0
+	}
+	return n
+}
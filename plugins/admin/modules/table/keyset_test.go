@@ -0,0 +1,102 @@
+package table
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/GoAdminGroup/go-admin/modules/service"
+	"github.com/GoAdminGroup/go-admin/plugins/admin/modules/parameter"
+	"github.com/magiconair/properties/assert"
+)
+
+func TestKeysetPagination(t *testing.T) {
+	initListTest()
+	defer func(srv service.List) { services = srv }(services)
+	services = service.List{}
+
+	tb, conn := newListTable(t, t.TempDir(), "")
+	tb.Info.KeysetPagination = true
+
+	_, err := conn.Exec(`CREATE TABLE posts (id INTEGER PRIMARY KEY AUTOINCREMENT, title TEXT, author TEXT)`)
+	assert.Equal(t, err, nil)
+	_, err = conn.Exec(`INSERT INTO posts (id, title, author) VALUES (1, 'b', 'sam'), (2, NULL, 'sam'),
+		(3, 'a', 'tom'), (4, '', 'sam'), (5, NULL, 'tom'), (6, 'b', 'sam'), (7, NULL, 'sam')`)
+	assert.Equal(t, err, nil)
+
+	titles := map[string]*string{}
+	for _, row := range []struct{ id, title string }{ { "1", "b" }, { "3", "a" }, { "4", "" }, { "6", "b" } } {
+		title := row.title
+		titles[row.id] = &title
+	}
+
+	page := func(sortType, key, cursor string) []string {
+		params := listParams()
+		params.PageSizeInt, params.SortField, params.SortType = 2, "title", sortType
+		if key == parameter.After {
+			params.After = cursor
+		} else {
+			params.Before = cursor
+		}
+		info, err := tb.GetData(params)
+		assert.Equal(t, err, nil)
+		var ids []string
+		for _, row := range info.InfoList {
+			ids = append(ids, row["id"].Value)
+		}
+		return ids
+	}
+	cursorOf := func(id string) string {
+		return parameter.EncodeCursor(titles[id], &id)
+	}
+
+	// the NULLs of the sqlite are first in the ascending order and last in the
+	// descending order, and the empty string is not a NULL.
+	for sortType, want := range map[string][]string{
+		"asc":  { "2", "5", "7", "4", "3", "1", "6" },
+		"desc": { "6", "1", "3", "4", "7", "5", "2" },
+	} {
+		var ids []string
+		for cursor := ""; ; {
+			next := page(sortType, parameter.After, cursor)
+			if len(next) == 0 {
+				break
+			}
+			ids    = append(ids, next...)
+			cursor = cursorOf(next[len(next) - 1])
+		}
+		assert.Equal(t, ids, want)
+
+		// the pages before the cursors of the NULL and the value.
+		assert.Equal(t, page(sortType, parameter.Before, cursorOf(want[3])), want[1:3])
+		assert.Equal(t, page(sortType, parameter.Before, cursorOf(want[2])), want[0:2])
+	}
+}
+
+func TestEstimatedCount(t *testing.T) {
+	initListTest()
+	defer func(srv service.List) { services = srv }(services)
+	services = service.List{}
+
+	dir := t.TempDir()
+	createPosts(t, filepath.Join(dir, "primary.db"), "hello", "bye", "again")
+
+	tb, _ := newListTable(t, dir, "")
+	tb.Info.EstimatedCount = true
+
+	// the statistics of the sqlite are not available.
+	info, err := tb.GetData(listParams())
+	assert.Equal(t, err, nil)
+	assert.Equal(t, info.Size, 0)
+
+	// the filtered rows are counted.
+	params := listParams()
+	params.Fields["author"] = []string{ "sam" }
+	info, err = tb.GetData(params)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, info.Size, 3)
+
+	tb.Info.KeysetPagination = true
+	info, err = tb.GetData(params)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, info.Size, 3)
+}
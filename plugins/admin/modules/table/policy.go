@@ -0,0 +1,435 @@
+package table
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/GoAdminGroup/go-admin/context"
+	"github.com/GoAdminGroup/go-admin/modules/config"
+	"github.com/GoAdminGroup/go-admin/modules/db"
+	errs "github.com/GoAdminGroup/go-admin/modules/errors"
+	"github.com/GoAdminGroup/go-admin/modules/logger"
+	"github.com/GoAdminGroup/go-admin/modules/utils"
+	"github.com/GoAdminGroup/go-admin/plugins/admin/models"
+	"github.com/GoAdminGroup/go-admin/plugins/admin/modules/form"
+	"github.com/GoAdminGroup/go-admin/plugins/admin/modules/parameter"
+	"github.com/GoAdminGroup/go-admin/template/types"
+)
+
+// ColumnRule is the rule of a column of a DataPolicy.
+type ColumnRule uint8
+
+const (
+	// ColumnShow shows the column as usual.
+	ColumnShow ColumnRule = iota
+	// ColumnReadOnly shows the column, which can not be changed by the forms.
+	ColumnReadOnly
+	// ColumnMask shows the values of the column as the MaskedValue, which can
+	// not be changed by the forms, filtered or sorted.
+	ColumnMask
+	// ColumnHide removes the column from the lists, the details, the exports
+	// and the forms.
+	ColumnHide
+)
+
+// MaskedValue is the value of the masked columns.
+const MaskedValue = "******"
+
+// DataPolicy is a data permission of the users of the roles on a table, which
+// limits the rows and the columns of the table they can see or change. The
+// Roles are the slugs of the roles, the policy is of all the users when it is
+// empty.
+//
+// The Rows is a sql predicate of the rows the users can see, update and delete,
+// such as:
+//
+//	posts.author_id = {{.AuthId}} OR posts.region = {{.User.region}}
+//
+// {{.AuthId}} and {{.AuthUser}} are the id and the username of the user, as the
+// AuthTemplate of the user model, and {{.User.xxx}} is the column xxx of the user
+// in the table of the users. The values are bound as the arguments, not spliced
+// into the statement. The columns are better qualified by the table, which can be
+// joined with the other tables.
+//
+// The policies of the roles of a user are combined, the user can see the rows
+// of any policy having the Rows, and the rule of a column is the loosest rule
+// of the policies having the column, a ColumnShow of a role lifts the rules of
+// the other roles. The super administrators are not limited. The rows only
+// limit the tables of the database.
+type DataPolicy struct {
+	Roles   []string
+	Rows    string
+	Columns map[string]ColumnRule
+}
+
+// HideColumns return the policy hiding the columns from the roles.
+func HideColumns(roles []string, columns ...string) DataPolicy {
+	return columnPolicy(roles, ColumnHide, columns)
+}
+
+// MaskColumns return the policy masking the columns from the roles.
+func MaskColumns(roles []string, columns ...string) DataPolicy {
+	return columnPolicy(roles, ColumnMask, columns)
+}
+
+// ReadOnlyColumns return the policy making the columns read only to the roles.
+func ReadOnlyColumns(roles []string, columns ...string) DataPolicy {
+	return columnPolicy(roles, ColumnReadOnly, columns)
+}
+
+// FilterRows return the policy limiting the roles to the rows of the predicate.
+func FilterRows(roles []string, rows string) DataPolicy {
+	return DataPolicy{ Roles: roles, Rows: rows }
+}
+
+func columnPolicy(roles []string, rule ColumnRule, columns []string) DataPolicy {
+	p := DataPolicy{ Roles: roles, Columns: make(map[string]ColumnRule, len(columns)) }
+	for _, col := range columns {
+		p.Columns[col] = rule
+	}
+	return p
+}
+
+func (p DataPolicy) match(user models.UserModel) bool {
+	if len(p.Roles) == 0 {
+		return true
+	}
+	for _, slug := range p.Roles {
+		if user.CheckRole(slug) {
+			return true
+		}
+	}
+	return false
+}
+
+// userPolicy is the combined policy of a user.
+type userPolicy struct {
+	rows    string
+	args    []interface{}
+	columns map[string]ColumnRule
+}
+
+var policyPlaceholderReg = regexp.MustCompile(`\{\{\s*\.(AuthId|AuthUser|User\.([a-zA-Z0-9_]+))\s*\}\}`)
+
+// newUserPolicy combines the policies of the user, it return nil when the user
+// is not limited.
+func newUserPolicy(policies []DataPolicy, user models.UserModel) *userPolicy {
+	if len(policies) == 0 || user.IsSuperAdmin() {
+		return nil
+	}
+
+	var matched []DataPolicy
+	for _, p := range policies {
+		if p.match(user) {
+			matched = append(matched, p)
+		}
+	}
+	if len(matched) == 0 {
+		return nil
+	}
+
+	up := &userPolicy{ columns: make(map[string]ColumnRule) }
+
+	// a column is limited only when all the policies of the column limit it.
+	for _, p := range matched {
+		for col := range p.Columns {
+			rule := p.Columns[col]
+			for _, other := range matched {
+				if r, ok := other.Columns[col]; ok && r < rule {
+					rule = r
+				}
+			}
+			if rule != ColumnShow {
+				up.columns[col] = rule
+			}
+		}
+	}
+
+	var (
+		rows    []string
+		userRow map[string]interface{}
+	)
+	for _, p := range matched {
+		if strings.TrimSpace(p.Rows) == "" {
+			continue
+		}
+		var (
+			argsLen = len(up.args)
+			missing = false
+		)
+		stmt := policyPlaceholderReg.ReplaceAllStringFunc(p.Rows, func(s string) string {
+			m := policyPlaceholderReg.FindStringSubmatch(s)
+			switch {
+			case m[1] == "AuthId":
+				up.args = append(up.args, user.Id)
+			case m[1] == "AuthUser":
+				up.args = append(up.args, user.UserName)
+			default:
+				if userRow == nil {
+					userRow = policyUserRow(user)
+				}
+				v, ok := userRow[m[2]]
+				if !ok {
+					logger.Error("data policy: no column ", m[2], " of the users")
+					missing = true
+					return "?"
+				}
+				up.args = append(up.args, v)
+			}
+			return "?"
+		})
+		// the rows of the policy are not visible when a column of the user is missing,
+		// the whole predicate is replaced since a part of it could match any row.
+		if missing {
+			up.args = up.args[:argsLen]
+			stmt    = "1 = 0"
+		}
+		rows = append(rows, utils.StrConcat("(", stmt, ")"))
+	}
+	if len(rows) > 0 {
+		up.rows = strings.Join(rows, " OR ")
+	}
+
+	if up.rows == "" && len(up.columns) == 0 {
+		return nil
+	}
+	return up
+}
+
+func policyUserRow(user models.UserModel) map[string]interface{} {
+	conn := user.Conn
+	if conn == nil && services != nil {
+		conn = db.GetConnection(services)
+	}
+	if conn == nil {
+		return map[string]interface{}{}
+	}
+	row, err := db.WithDriver(conn).Table(config.GetAuthUserTable()).Where("id", "=", user.Id).First()
+	if err != nil || row == nil {
+		return map[string]interface{}{}
+	}
+	return row
+}
+
+func (up *userPolicy) rule(column string) ColumnRule {
+	if up == nil {
+		return ColumnShow
+	}
+	return up.columns[column]
+}
+
+// SetUser set the user reading and writing the table, the data policies of the
+// roles of the user are applied to the table.
+func (tb *DefaultTable) SetUser(user models.UserModel) {
+	tb.policy = newUserPolicy(tb.policies, user)
+	if tb.policy == nil {
+		return
+	}
+
+	tb.Info.FieldList = tb.policyInfoFields(tb.Info.FieldList)
+	tb.Detail.FieldList = tb.policyInfoFields(tb.Detail.FieldList)
+	tb.Form.FieldList = tb.policyFormFields(tb.Form.FieldList)
+	tb.NewForm.FieldList = tb.policyFormFields(tb.NewForm.FieldList)
+}
+
+// ColumnRule return the rule of the column to the user of the table.
+func (tb *DefaultTable) ColumnRule(column string) ColumnRule {
+	return tb.policy.rule(column)
+}
+
+// policyAuditRules return the masked and the hidden columns of the tables of the
+// list to the user of the request by the names of the tables, the strictest rule
+// of the tables of the same name is taken.
+func policyAuditRules(ctx *context.Context, list GeneratorList) map[string]map[string]ColumnRule {
+	rules := make(map[string]map[string]ColumnRule)
+	user, ok := ctx.User().(models.UserModel)
+	if !ok || user.IsSuperAdmin() {
+		return rules
+	}
+	for _, gen := range list.Copy() {
+		tb, ok := gen(ctx).(*DefaultTable)
+		if !ok || len(tb.policies) == 0 {
+			continue
+		}
+		tb.SetUser(user)
+		if tb.policy == nil {
+			continue
+		}
+		for _, table := range []string{ tb.Info.Table, tb.Form.Table } {
+			if table == "" {
+				continue
+			}
+			for col, rule := range tb.policy.columns {
+				if rule < ColumnMask {
+					continue
+				}
+				if rules[table] == nil {
+					rules[table] = make(map[string]ColumnRule)
+				}
+				if rule > rules[table][col] {
+					rules[table][col] = rule
+				}
+			}
+		}
+	}
+	return rules
+}
+
+func (tb *DefaultTable) policyInfoFields(fields types.FieldList) types.FieldList {
+	res := make(types.FieldList, 0, len(fields))
+	for _, field := range fields {
+		switch tb.policy.rule(field.Field) {
+		case ColumnHide:
+			continue
+		case ColumnMask:
+			field.Sortable   = false
+			field.Filterable = false
+			field.EditAble   = false
+		case ColumnReadOnly:
+			field.EditAble = false
+		}
+		res = append(res, field)
+	}
+	return res
+}
+
+func (tb *DefaultTable) policyFormFields(fields types.FormFields) types.FormFields {
+	res := make(types.FormFields, 0, len(fields))
+	for _, field := range fields {
+		switch tb.policy.rule(field.Field) {
+		case ColumnHide:
+			continue
+		case ColumnMask, ColumnReadOnly:
+			field.Editable         = false
+			field.DisplayButNotAdd = true
+		}
+		res = append(res, field)
+	}
+	return res
+}
+
+// policyStatement adds the predicate of the rows of the user to the wheres.
+func (tb *DefaultTable) policyStatement(wheres string, whereArgs []interface{}) (string, []interface{}) {
+	if tb.policy == nil || tb.policy.rows == "" {
+		return wheres, whereArgs
+	}
+	if wheres == "" {
+		return tb.policy.rows, append(whereArgs, tb.policy.args...)
+	}
+	return utils.StrConcat("(", wheres, ") AND (", tb.policy.rows, ")"), append(whereArgs, tb.policy.args...)
+}
+
+// policyParameters removes the filters and the sort of the masked and the hidden
+// columns, which tell the values of them.
+func (tb *DefaultTable) policyParameters(params parameter.Parameters) parameter.Parameters {
+	if tb.policy == nil || len(tb.policy.columns) == 0 {
+		return params
+	}
+	fields := make(map[string][]string, len(params.Fields))
+	for key, value := range params.Fields {
+		if tb.policy.rule(policyFilterColumn(key)) < ColumnMask {
+			fields[key] = value
+		}
+	}
+	params.Fields = fields
+	if tb.policy.rule(params.SortField) >= ColumnMask {
+		params.SortField = tb.PrimaryKey.Name
+	}
+	return params
+}
+
+// policyFilterColumn return the column of the key of a filter, such as price of
+// price_start__goadmin.
+func policyFilterColumn(key string) string {
+	for _, suffix := range []string{ parameter.FilterRangeParamStartSuffix, parameter.FilterRangeParamEndSuffix } {
+		if p := strings.Index(key, suffix); p >= 0 {
+			return key[:p]
+		}
+	}
+	if p := strings.Index(key, "__goadmin"); p >= 0 {
+		key = key[:p]
+	}
+	if p := strings.Index(key, parameter.FilterParamJoinInfix); p >= 0 {
+		key = key[p + len(parameter.FilterParamJoinInfix):]
+	}
+	return key
+}
+
+// policyMaskRow return the row with the values of the masked columns masked, the
+// fields of the join tables are masked by the names of the fields. The row is
+// copied when it has the masked columns, which may be shared by the cache.
+func (tb *DefaultTable) policyMaskRow(row map[string]interface{}) map[string]interface{} {
+	if tb.policy == nil || len(tb.policy.columns) == 0 {
+		return row
+	}
+	var masked map[string]interface{}
+	for key := range row {
+		col := key
+		if p := strings.Index(key, parameter.FilterParamJoinInfix); p >= 0 {
+			col = key[p + len(parameter.FilterParamJoinInfix):]
+		}
+		if tb.policy.rule(col) < ColumnMask {
+			continue
+		}
+		if masked == nil {
+			masked = make(map[string]interface{}, len(row))
+			for k, v := range row {
+				masked[k] = v
+			}
+		}
+		masked[key] = MaskedValue
+	}
+	if masked == nil {
+		return row
+	}
+	return masked
+}
+
+// policyValues removes the posted values of the columns which can not be changed.
+func (tb *DefaultTable) policyValues(dataList form.Values) {
+	if tb.policy == nil {
+		return
+	}
+	for col, rule := range tb.policy.columns {
+		if rule >= ColumnReadOnly {
+			dataList.Delete(col)
+			dataList.Delete(col + "[]")
+		}
+	}
+}
+
+// policyCheckRows checks the rows of the ids are all visible to the user.
+func (tb *DefaultTable) policyCheckRows(ids []string) error {
+	if tb.policy == nil || tb.policy.rows == "" || !tb.getDataFromDB() || len(ids) == 0 {
+		return nil
+	}
+	var (
+		conn   = tb.db()
+		delim  = conn.GetDelimiter()
+		delim2 = conn.GetDelimiter2()
+		table  = tb.Form.Table
+	)
+	if table == "" {
+		table = tb.Info.Table
+	}
+	uniq := make([]string, 0, len(ids))
+	for _, id := range ids {
+		if id != "" && !utils.InArray(uniq, id) {
+			uniq = append(uniq, id)
+		}
+	}
+	marks := strings.TrimSuffix(strings.Repeat("?,", len(uniq)), ",")
+	stmt := utils.StrConcat("SELECT count(*) AS ", delim, "size", delim2, " FROM ", delim, table, delim2,
+		" WHERE ", delim, table, delim2, ".", delim, tb.PrimaryKey.Name, delim2, " IN (", marks, ") AND (",
+		tb.policy.rows, ")")
+	logger.LogSQL(stmt, nil)
+	res, err := conn.QueryWithConnection(tb.connection, stmt, append(interfaces(uniq), tb.policy.args...)...)
+	if err != nil { return err }
+	if len(res) == 0 || fmt.Sprintf("%v", res[0]["size"]) != strconv.Itoa(len(uniq)) {
+		return errors.New(errs.NoPermission)
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+package table
+
+import (
+	"testing"
+
+	"github.com/GoAdminGroup/go-admin/plugins/admin/models"
+	"github.com/GoAdminGroup/go-admin/plugins/admin/modules/form"
+	"github.com/GoAdminGroup/go-admin/plugins/admin/modules/parameter"
+	"github.com/GoAdminGroup/go-admin/template/types"
+	"github.com/magiconair/properties/assert"
+)
+
+func TestDataPolicy(t *testing.T) {
+	policies := []DataPolicy{
+		FilterRows([]string{ "sales" }, "orders.owner_id = {{.AuthId}}"),
+		FilterRows([]string{ "auditor" }, "orders.checker = {{ .AuthUser }}"),
+		MaskColumns([]string{ "sales", "auditor" }, "phone", "cost"),
+		HideColumns([]string{ "sales" }, "cost"),
+		ReadOnlyColumns(nil, "amount"),
+	}
+
+	assert.Equal(t, newUserPolicy(policies, models.UserModel{ Id: 1, Root: models.StrTrue }) == nil, true)
+	assert.Equal(t, newUserPolicy(policies[:2], models.UserModel{ Id: 1 }) == nil, true)
+
+	var (
+		sales   = models.UserModel{ Id: 3, UserName: "sam", Roles: []models.RoleModel{ { Slug: "sales" } } }
+		both    = models.UserModel{ Id: 4, UserName: "ann", Roles: []models.RoleModel{ { Slug: "sales" }, { Slug: "auditor" } } }
+		salesUp = newUserPolicy(policies, sales)
+		bothUp  = newUserPolicy(policies, both)
+	)
+
+	assert.Equal(t, salesUp.rows, "(orders.owner_id = ?)")
+	assert.Equal(t, salesUp.args, []interface{}{ int64(3) })
+	assert.Equal(t, salesUp.columns, map[string]ColumnRule{ "phone": ColumnMask, "cost": ColumnMask, "amount": ColumnReadOnly })
+
+	assert.Equal(t, bothUp.rows, "(orders.owner_id = ?) OR (orders.checker = ?)")
+	assert.Equal(t, bothUp.args, []interface{}{ int64(4), "ann" })
+
+	// a missing column of the users hides all the rows of the policy, the other
+	// policies still apply.
+	missingUp := newUserPolicy([]DataPolicy{
+		FilterRows([]string{ "sales" }, "orders.owner_id = {{.AuthId}} AND orders.region = {{.User.region}}"),
+		FilterRows([]string{ "sales" }, "orders.checker = {{.AuthUser}}"),
+	}, sales)
+	assert.Equal(t, missingUp.rows, "(1 = 0) OR (orders.checker = ?)")
+	assert.Equal(t, missingUp.args, []interface{}{ "sam" })
+
+	tb := &DefaultTable{
+		BaseTable: &BaseTable{ PrimaryKey: PrimaryKey{ Name: "id" } },
+		policy:    newUserPolicy([]DataPolicy{
+			policies[0],
+			HideColumns([]string{ "sales" }, "phone"),
+			MaskColumns([]string{ "sales" }, "cost"),
+			policies[4],
+		}, sales),
+	}
+
+	wheres, args := tb.policyStatement("orders.state = ?", []interface{}{ 1 })
+	assert.Equal(t, wheres, "(orders.state = ?) AND ((orders.owner_id = ?))")
+	assert.Equal(t, args, []interface{}{ 1, int64(3) })
+
+	assert.Equal(t, policyFilterColumn("cost_start__goadmin"), "cost")
+	assert.Equal(t, policyFilterColumn("name__goadmin_operator__"), "name")
+	assert.Equal(t, policyFilterColumn("users" + parameter.FilterParamJoinInfix + "phone"), "phone")
+
+	params := tb.policyParameters(parameter.Parameters{
+		SortField: "cost",
+		Fields:    map[string][]string{ "cost_start__goadmin": { "1" }, "state": { "1" } },
+	})
+	assert.Equal(t, params.SortField, "id")
+	assert.Equal(t, params.Fields, map[string][]string{ "state": { "1" } })
+
+	row := map[string]interface{}{ "id": 1, "cost": 9, "users" + parameter.FilterParamJoinInfix + "phone": "123" }
+	assert.Equal(t, tb.policyMaskRow(row), map[string]interface{}{ "id": 1, "cost": MaskedValue, "users" + parameter.FilterParamJoinInfix + "phone": MaskedValue })
+	assert.Equal(t, row["cost"], 9)
+
+	fields := tb.policyInfoFields(types.FieldList{ { Field: "phone" }, { Field: "cost", Sortable: true }, { Field: "amount", EditAble: true } })
+	assert.Equal(t, len(fields), 2)
+	assert.Equal(t, fields[0].Sortable, false)
+	assert.Equal(t, fields[1].EditAble, false)
+
+	values := form.Values{ "amount": { "1" }, "cost": { "2" }, "state": { "1" } }
+	tb.policyValues(values)
+	assert.Equal(t, values, form.Values{ "state": { "1" } })
+}
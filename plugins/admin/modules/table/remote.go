@@ -0,0 +1,257 @@
+package table
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/GoAdminGroup/go-admin/modules/utils"
+	"github.com/GoAdminGroup/go-admin/plugins/admin/modules/constant"
+	"github.com/GoAdminGroup/go-admin/plugins/admin/modules/form"
+	"github.com/GoAdminGroup/go-admin/plugins/admin/modules/parameter"
+)
+
+const (
+	// DefaultRemoteTimeout is the timeout of a request to a remote source.
+	DefaultRemoteTimeout = 10 * time.Second
+
+	remoteRetryDelay = 200 * time.Millisecond
+)
+
+// RemoteSource is a remote REST data source of a table, which is an endpoint of
+// json the table reads and writes the rows by:
+//
+//	list    GET    {url}?{params}&pk={ids}  -> {"code": 0, "data": [{"id": 1, ...}], "size": 1}
+//	create  POST   {url}                     -> {"code": 0, "id": 2}
+//	update  PUT    {url}/{id}                -> {"code": 0}
+//	delete  DELETE {url}?pk={ids}            -> {"code": 0}
+//
+// The params of the list are the parameters of the url of the list, such as the
+// page, the page size, the sort and the filters, and the ids are separated by
+// commas. The row of the forms and the detail is the first row of the list with
+// the pk of the row. The body of the create and the update is a json object of
+// the fields of the form, the values of the multiple selections are arrays:
+//
+//	{"name": "foo", "tags": ["a", "b"]}
+//
+// A response with a status other than 2xx or a code other than 0 is an error, and
+// the msg of the response is the message of the error:
+//
+//	{"code": 403, "msg": "permission denied"}
+//
+// The requests of the writes are canceled with the request of the admin, and the
+// writes are recorded in the audit trail when the table has a name, with the rows
+// listed by the pks before and after the writes.
+type RemoteSource struct {
+	// URL is the url of the rows.
+	URL string
+	// Headers are added to each request, such as the api keys.
+	Headers map[string]string
+	// Token is sent as the bearer token of the Authorization header when it is not empty.
+	Token string
+	// Timeout is the timeout of each try of a request, DefaultRemoteTimeout when it is zero.
+	Timeout time.Duration
+	// Retries is the number of the retries of the reads, the updates and the deletes
+	// after a network error or a 5xx response. The creates are not retried as they
+	// are not idempotent.
+	Retries int
+	// Client sends the requests, http.DefaultClient when it is nil.
+	Client *http.Client
+}
+
+// RemoteError is an error response of a remote source.
+type RemoteError struct {
+	Status int
+	Code   int
+	Msg    string
+}
+
+func (e *RemoteError) Error() string {
+	if e.Msg != "" {
+		return "remote source: " + e.Msg
+	}
+	return fmt.Sprintf("remote source: status %d, code %d", e.Status, e.Code)
+}
+
+type remoteResponse struct {
+	Code int    `json:"code"`
+	Msg  string `json:"msg"`
+}
+
+type remoteListResponse struct {
+	Data []map[string]interface{} `json:"data"`
+	Size int                      `json:"size"`
+}
+
+type remoteCreateResponse struct {
+	Id interface{} `json:"id"`
+}
+
+// List return the rows and the total size of the list of the params.
+func (s *RemoteSource) List(ctx context.Context, params parameter.Parameters) ([]map[string]interface{}, int, error) {
+	query := utils.StrConcat(params.Join(), "&pk=", strings.Join(params.PKs(), ","))
+
+	var res remoteListResponse
+	if err := s.do(ctx, http.MethodGet, "", query, nil, &res); err != nil {
+		return []map[string]interface{}{}, 0, err
+	}
+	if res.Data == nil {
+		res.Data = []map[string]interface{}{}
+	}
+	return res.Data, res.Size, nil
+}
+
+// Create creates a row of the values, and return the id of the new row.
+func (s *RemoteSource) Create(ctx context.Context, values map[string]interface{}) (string, error) {
+	var res remoteCreateResponse
+	if err := s.do(ctx, http.MethodPost, "", "", values, &res); err != nil {
+		return "", err
+	}
+	if res.Id == nil {
+		return "", nil
+	}
+	return fmt.Sprintf("%v", res.Id), nil
+}
+
+// Update updates the row of the id with the values.
+func (s *RemoteSource) Update(ctx context.Context, id string, values map[string]interface{}) error {
+	return s.do(ctx, http.MethodPut, url.PathEscape(id), "", values, nil)
+}
+
+// Delete deletes the rows of the ids.
+func (s *RemoteSource) Delete(ctx context.Context, ids []string) error {
+	return s.do(ctx, http.MethodDelete, "", "pk=" + url.QueryEscape(strings.Join(ids, ",")), nil, nil)
+}
+
+func (s *RemoteSource) do(ctx context.Context, method, path, query string, body, out interface{}) error {
+	u := s.URL
+	if path != "" {
+		if i := strings.IndexByte(u, '?'); i >= 0 {
+			u = utils.StrConcat(strings.TrimRight(u[:i], "/"), "/", path, u[i:])
+		} else {
+			u = utils.StrConcat(strings.TrimRight(u, "/"), "/", path)
+		}
+	}
+	if query != "" {
+		if strings.ContainsRune(u, '?') {
+			u = utils.StrConcat(u, "&", query)
+		} else {
+			u = utils.StrConcat(u, "?", query)
+		}
+	}
+
+	var payload []byte
+	if body != nil {
+		var err error
+		if payload, err = utils.JsonMarshal(body); err != nil { return err }
+	}
+
+	retries := s.Retries
+	if method == http.MethodPost {
+		retries = 0
+	}
+
+	var err error
+	for try := 0; ; try++ {
+		var retry bool
+		retry, err = s.try(ctx, method, u, payload, out)
+		if err == nil || !retry || try >= retries {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(remoteRetryDelay << uint(try)):
+		}
+	}
+}
+
+// try sends the request once, and reports whether the request can be retried
+// when it fails.
+func (s *RemoteSource) try(ctx context.Context, method, u string, payload []byte, out interface{}) (bool, error) {
+	timeout := s.Timeout
+	if timeout <= 0 {
+		timeout = DefaultRemoteTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	var body io.Reader
+	if payload != nil {
+		body = bytes.NewReader(payload)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, u, body)
+	if err != nil { return false, err }
+
+	req.Header.Set("Accept", "application/json")
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	for k, v := range s.Headers {
+		req.Header.Set(k, v)
+	}
+	if s.Token != "" {
+		req.Header.Set("Authorization", "Bearer " + s.Token)
+	}
+
+	client := s.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	res, err := client.Do(req)
+	if err != nil { return true, err }
+	defer func() { _ = res.Body.Close() }()
+
+	data, err := io.ReadAll(res.Body)
+	if err != nil { return true, err }
+
+	var r remoteResponse
+	if len(bytes.TrimSpace(data)) > 0 {
+		if err := utils.JsonUnmarshal(data, &r); err != nil && res.StatusCode < 300 {
+			return false, fmt.Errorf("remote source: wrong response: %v", err)
+		}
+	}
+	if res.StatusCode < 200 || res.StatusCode >= 300 || r.Code != 0 {
+		return res.StatusCode >= 500, &RemoteError{ Status: res.StatusCode, Code: r.Code, Msg: r.Msg }
+	}
+
+	if out != nil && len(data) > 0 {
+		if err := utils.JsonUnmarshal(data, out); err != nil {
+			return false, fmt.Errorf("remote source: wrong response: %v", err)
+		}
+	}
+	return false, nil
+}
+
+// remoteValues return the body of the create and the update of the dataList,
+// without the keys of the framework.
+func remoteValues(dataList form.Values) map[string]interface{} {
+	values := make(map[string]interface{}, len(dataList))
+	for k, v := range dataList {
+		switch k {
+		case form.PostTypeKey, form.PostResultKey, form.PostIsSingleUpdateKey, form.PreviousKey, form.TokenKey,
+			form.MethodKey, form.VersionKey, form.NoAnimationKey, constant.IframeKey, constant.IframeIDKey:
+			continue
+		}
+		if strings.HasSuffix(k, "[]") {
+			list := make([]string, 0, len(v))
+			for _, e := range v {
+				if e != "" {
+					list = append(list, e)
+				}
+			}
+			values[strings.TrimSuffix(k, "[]")] = list
+		} else if len(v) > 0 {
+			values[k] = v[0]
+		} else {
+			values[k] = ""
+		}
+	}
+	return values
+}
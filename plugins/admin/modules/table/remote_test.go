@@ -0,0 +1,103 @@
+package table
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/GoAdminGroup/go-admin/modules/config"
+	"github.com/GoAdminGroup/go-admin/plugins/admin/modules/form"
+	"github.com/GoAdminGroup/go-admin/plugins/admin/modules/parameter"
+	"github.com/magiconair/properties/assert"
+)
+
+func TestRemoteSource(t *testing.T) {
+	var (
+		tries int
+		body  map[string]interface{}
+	)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token" || r.Header.Get("X-Api-Key") != "key" {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"code": 401, "msg": "unauthorized"}`))
+			return
+		}
+		switch {
+		case r.Method == http.MethodGet && r.URL.Query().Get("pk") == "9":
+			_, _ = w.Write([]byte(`{"code": 1, "msg": "no such row"}`))
+		case r.Method == http.MethodGet:
+			if tries++; tries < 3 {
+				w.WriteHeader(http.StatusBadGateway)
+				return
+			}
+			_, _ = w.Write([]byte(`{"code": 0, "data": [{"id": 1, "name": "foo"}], "size": 10}`))
+		case r.Method == http.MethodPost:
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			_, _ = w.Write([]byte(`{"code": 0, "id": 2}`))
+		case r.Method == http.MethodPut && r.URL.Path == "/users/2":
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			_, _ = w.Write([]byte(`{"code": 0}`))
+		case r.Method == http.MethodDelete && r.URL.Query().Get("pk") == "1,2":
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	var (
+		ctx    = context.Background()
+		source = &RemoteSource{
+			URL:     server.URL + "/users",
+			Headers: map[string]string{ "X-Api-Key": "key" },
+			Token:   "token",
+			Retries: 2,
+		}
+	)
+
+	data, size, err := source.List(ctx, parameter.BaseParam())
+	assert.Equal(t, err, nil)
+	assert.Equal(t, tries, 3)
+	assert.Equal(t, size, 10)
+	assert.Equal(t, data[0]["name"], "foo")
+
+	_, _, err = source.List(ctx, parameter.BaseParam().WithPKs("9"))
+	assert.Equal(t, err.Error(), "remote source: no such row")
+
+	id, err := source.Create(ctx, remoteValues(form.Values{
+		"name":          { "bar" },
+		"tags[]":        { "a", "", "b" },
+		form.TokenKey:    { "token" },
+		form.PostTypeKey: { "1" },
+	}))
+	assert.Equal(t, err, nil)
+	assert.Equal(t, id, "2")
+	assert.Equal(t, body, map[string]interface{}{ "name": "bar", "tags": []interface{}{ "a", "b" } })
+
+	assert.Equal(t, source.Update(ctx, "2", map[string]interface{}{ "name": "baz" }), nil)
+	assert.Equal(t, body["name"], "baz")
+
+	assert.Equal(t, source.Delete(ctx, []string{ "1", "2" }), nil)
+
+	err = (&RemoteSource{ URL: server.URL + "/users" }).Delete(ctx, []string{ "1" })
+	assert.Equal(t, err.(*RemoteError).Status, http.StatusUnauthorized)
+
+	// the writes of the table are canceled with the request.
+	config.Initialize(&config.Config{ Language: "en" })
+
+	cfg := DefaultConfig()
+	cfg.Remote = source
+	tb := NewDefaultTable(cfg)
+
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	tb.SetContext(canceled)
+	assert.Equal(t, errors.Is(tb.DeleteData("1,2"), context.Canceled), true)
+
+	tb.SetContext(ctx)
+	assert.Equal(t, tb.DeleteData("1,2"), nil)
+}
@@ -1,6 +1,7 @@
 package table
 
 import (
+	ctx2 "context"
 	"html/template"
 	"sync"
 	"sync/atomic"
@@ -8,6 +9,7 @@ import (
 	"github.com/GoAdminGroup/go-admin/context"
 	"github.com/GoAdminGroup/go-admin/modules/db"
 	"github.com/GoAdminGroup/go-admin/modules/service"
+	"github.com/GoAdminGroup/go-admin/plugins/admin/models"
 	"github.com/GoAdminGroup/go-admin/plugins/admin/modules/form"
 	"github.com/GoAdminGroup/go-admin/plugins/admin/modules/paginator"
 	"github.com/GoAdminGroup/go-admin/plugins/admin/modules/parameter"
@@ -18,11 +20,45 @@ type Generator func(ctx *context.Context) Table
 
 type GeneratorList map[string]Generator
 
+// generatorsLock guards the generator lists, which are changed at runtime by the
+// watch of the table definitions.
+var generatorsLock sync.RWMutex
+
 func (g GeneratorList) Add(key string, gen Generator) {
+	generatorsLock.Lock()
+	defer generatorsLock.Unlock()
 	g[key] = gen
 }
 
+// Get return the generator of the key.
+func (g GeneratorList) Get(key string) (Generator, bool) {
+	generatorsLock.RLock()
+	defer generatorsLock.RUnlock()
+	gen, ok := g[key]
+	return gen, ok
+}
+
+// Remove removes the generator of the key.
+func (g GeneratorList) Remove(key string) {
+	generatorsLock.Lock()
+	defer generatorsLock.Unlock()
+	delete(g, key)
+}
+
+// Copy return a copy of the list, which is safe to range over.
+func (g GeneratorList) Copy() GeneratorList {
+	generatorsLock.RLock()
+	defer generatorsLock.RUnlock()
+	list := make(GeneratorList, len(g))
+	for key, gen := range g {
+		list[key] = gen
+	}
+	return list
+}
+
 func (g GeneratorList) Combine(list GeneratorList) GeneratorList {
+	generatorsLock.Lock()
+	defer generatorsLock.Unlock()
 	for key, gen := range list {
 		if _, ok := g[key]; !ok {
 			g[key] = gen
@@ -32,6 +68,8 @@ func (g GeneratorList) Combine(list GeneratorList) GeneratorList {
 }
 
 func (g GeneratorList) CombineAll(gens []GeneratorList) GeneratorList {
+	generatorsLock.Lock()
+	defer generatorsLock.Unlock()
 	for _, list := range gens {
 		for key, gen := range list {
 			if _, ok := g[key]; !ok {
@@ -54,18 +92,29 @@ type Table interface {
 	GetEditable() bool
 	GetDeletable() bool
 	GetExportable() bool
+	GetSoftDeleteField() string
 
 	GetPrimaryKey() PrimaryKey
 
 	GetData(params parameter.Parameters) (PanelInfo, error)
 	GetDataWithIds(params parameter.Parameters) (PanelInfo, error)
 	GetDataWithId(params parameter.Parameters) (FormInfo, error)
+	EachData(params parameter.Parameters, theadFn TheadFn, rowFn InfoRowFn) error
 	UpdateData(dataList form.Values) error
 	InsertData(dataList form.Values) error
+	ImportData(rows []form.Values, dryRun bool) ImportResult
 	DeleteData(pk string) error
+	RestoreData(pk string) error
+	PurgeData(pk string) error
 
 	GetNewFormInfo() FormInfo
 
+	SetOperator(userId int64)
+	SetUser(user models.UserModel)
+	ColumnRule(column string) ColumnRule
+	SetTenantConnection(name string)
+	SetContext(ctx ctx2.Context)
+
 	GetOnlyInfo() bool
 	GetOnlyDetail() bool
 	GetOnlyNewForm() bool
@@ -75,19 +124,20 @@ type Table interface {
 }
 
 type BaseTable struct {
-	Info           *types.InfoPanel
-	Form           *types.FormPanel
-	NewForm        *types.FormPanel
-	Detail         *types.InfoPanel
-	CanAdd         bool
-	Editable       bool
-	Deletable      bool
-	Exportable     bool
-	OnlyInfo       bool
-	OnlyDetail     bool
-	OnlyNewForm    bool
-	OnlyUpdateForm bool
-	PrimaryKey     PrimaryKey
+	Info            *types.InfoPanel
+	Form            *types.FormPanel
+	NewForm         *types.FormPanel
+	Detail          *types.InfoPanel
+	CanAdd          bool
+	Editable        bool
+	Deletable       bool
+	Exportable      bool
+	OnlyInfo        bool
+	OnlyDetail      bool
+	OnlyNewForm     bool
+	OnlyUpdateForm  bool
+	PrimaryKey      PrimaryKey
+	SoftDeleteField string
 }
 
 func (base *BaseTable) GetInfo() *types.InfoPanel {
@@ -124,14 +174,15 @@ func (base *BaseTable) GetCanAdd() bool {
 	return base.CanAdd
 }
 
-func (base *BaseTable) GetPrimaryKey() PrimaryKey { return base.PrimaryKey }
-func (base *BaseTable) GetEditable() bool         { return base.Editable }
-func (base *BaseTable) GetDeletable() bool        { return base.Deletable }
-func (base *BaseTable) GetExportable() bool       { return base.Exportable }
-func (base *BaseTable) GetOnlyInfo() bool         { return base.OnlyInfo }
-func (base *BaseTable) GetOnlyDetail() bool       { return base.OnlyDetail }
-func (base *BaseTable) GetOnlyNewForm() bool      { return base.OnlyNewForm }
-func (base *BaseTable) GetOnlyUpdateForm() bool   { return base.OnlyUpdateForm }
+func (base *BaseTable) GetPrimaryKey() PrimaryKey  { return base.PrimaryKey }
+func (base *BaseTable) GetEditable() bool          { return base.Editable }
+func (base *BaseTable) GetDeletable() bool         { return base.Deletable }
+func (base *BaseTable) GetExportable() bool        { return base.Exportable }
+func (base *BaseTable) GetSoftDeleteField() string { return base.SoftDeleteField }
+func (base *BaseTable) GetOnlyInfo() bool          { return base.OnlyInfo }
+func (base *BaseTable) GetOnlyDetail() bool        { return base.OnlyDetail }
+func (base *BaseTable) GetOnlyNewForm() bool       { return base.OnlyNewForm }
+func (base *BaseTable) GetOnlyUpdateForm() bool    { return base.OnlyUpdateForm }
 
 func (base *BaseTable) GetPaginator(size int, params parameter.Parameters, extraHtml ...template.HTML) types.PaginatorAttribute {
 
@@ -155,14 +206,26 @@ type PanelInfo struct {
 	Paginator      types.PaginatorAttribute `json:"-"`
 	Title          string                   `json:"title"`
 	Description    string                   `json:"description"`
+	// Size is the number of the rows of all the pages.
+	Size           int                      `json:"-"`
+	// Versions is the values of the version field of the form keyed by the primary key.
+	Versions       map[string]string        `json:"versions,omitempty"`
 }
 
+// TheadFn is called with the thead before the rows of EachData.
+type TheadFn func(thead types.Thead) error
+
+// InfoRowFn is called for every row of EachData, a non-nil error stops the iteration.
+type InfoRowFn func(row map[string]types.InfoItem) error
+
 type FormInfo struct {
 	FieldList         types.FormFields        `json:"field_list"`
 	GroupFieldList    types.GroupFormFields   `json:"group_field_list"`
 	GroupFieldHeaders types.GroupFieldHeaders `json:"group_field_headers"`
 	Title             string                  `json:"title"`
 	Description       string                  `json:"description"`
+	// Version is the value of the version field of the form, see types.FormPanel.SetVersionField.
+	Version           string                  `json:"version,omitempty"`
 }
 
 type PrimaryKey struct {
@@ -171,8 +234,9 @@ type PrimaryKey struct {
 }
 
 const (
-	DefaultPrimaryKeyName = "id"
-	DefaultConnectionName = "default"
+	DefaultPrimaryKeyName  = "id"
+	DefaultConnectionName  = "default"
+	DefaultSoftDeleteField = "deleted_at"
 )
 
 var (
@@ -0,0 +1,247 @@
+package table
+
+import (
+	"encoding/json"
+	"errors"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/GoAdminGroup/go-admin/modules/config"
+	"github.com/GoAdminGroup/go-admin/modules/db"
+	"github.com/GoAdminGroup/go-admin/modules/logger"
+	"github.com/GoAdminGroup/go-admin/modules/utils"
+	"github.com/GoAdminGroup/go-admin/plugins/admin/models"
+	form2 "github.com/GoAdminGroup/go-admin/plugins/admin/modules/form"
+	"github.com/GoAdminGroup/go-admin/plugins/admin/modules/tools"
+	"github.com/GoAdminGroup/go-admin/template/types"
+	"github.com/GoAdminGroup/go-admin/template/types/form"
+)
+
+var tableDefPrefixReg = regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
+
+// LoadTableDefs adds the live versions of the table definitions saved by the
+// designer to the list, the generators of the code are kept when the prefixes
+// are the same.
+func LoadTableDefs(conn db.Connection, list GeneratorList) {
+	for _, def := range models.TableDef().SetConn(conn).AllLatest() {
+		g, err := tableDefGenerator(def)
+		if err != nil {
+			logger.Error("load table definition ", def.Prefix, " error: ", err)
+			continue
+		}
+		list.Combine(GeneratorList{ def.Prefix: g })
+	}
+}
+
+func tableDefGenerator(def models.TableDefModel) (Generator, error) {
+	d, err := tableDefDefinition(def.Definition)
+	if err != nil { return nil, err }
+	d.Prefix = def.Prefix
+	return d.Generator()
+}
+
+func tableDefDefinition(content string) (Definition, error) {
+	var d Definition
+	if content == "" {
+		return d, nil
+	}
+	err := json.Unmarshal([]byte(content), &d)
+	return d, err
+}
+
+// tableDefFromValues return the definition of the values posted by the designer.
+// The tables are read only by default, and the form of them is the fields of the
+// info when they are not.
+func tableDefFromValues(values form2.Values) Definition {
+	readOnly := values.Get("read_only") != "n"
+	d := Definition{
+		Connection:  utils.SetDefault(values.Get("connection"), "", DefaultConnectionName),
+		Table:       strings.TrimSpace(values.Get("table")),
+		Title:       values.Get("title"),
+		Description: values.Get("description"),
+		PrimaryKey:  strings.TrimSpace(values.Get("pk")),
+	}
+	if readOnly {
+		no := false
+		d.CanAdd, d.Editable, d.Deletable = &no, &no, &no
+	}
+	for _, role := range strings.Split(values.Get("view_roles"), ",") {
+		if role = strings.TrimSpace(role); role != "" {
+			d.Permissions.View = append(d.Permissions.View, role)
+		}
+	}
+
+	var (
+		heads    = values["field_head"]
+		names    = values["field_name"]
+		dbTypes  = values["field_db_type"]
+		filters  = values["field_filterable"]
+		sorts    = values["field_sortable"]
+		hides    = values["field_hide"]
+		displays = values["field_display"]
+		at       = func(arr []string, i int) string {
+			if i < len(arr) { return arr[i] }
+			return ""
+		}
+	)
+	for i, name := range names {
+		if name = strings.TrimSpace(name); name == "" {
+			continue
+		}
+		d.Fields = append(d.Fields, DefinitionField{
+			Head:     at(heads, i),
+			Field:    name,
+			Type:     at(dbTypes, i),
+			Filter:   at(filters, i) == "y",
+			Sortable: at(sorts, i) == "y",
+			Hide:     at(hides, i) == "y",
+			Display:  at(displays, i),
+		})
+	}
+	return d
+}
+
+// GoCode return the go code of the table model of the definition, which is the
+// same as the code generated by the tool of the package.
+func (d Definition) GoCode(pkg string) ([]byte, error) {
+	connection := utils.SetDefault(d.Connection, "", DefaultConnectionName)
+	driver := d.Driver
+	if driver == "" {
+		driver = config.GetDatabases()[connection].Driver
+	}
+
+	toolField := func(f DefinitionField) tools.Field {
+		typ, _ := definitionType(f.Type)
+		field := tools.Field{
+			Head:       f.head(),
+			Name:       f.Field,
+			DBType:     strings.Title(strings.ToLower(string(typ))),
+			Filterable: f.Filter,
+			Sortable:   f.Sortable,
+			Hide:       f.Hide,
+			CanAdd:     !f.DisableCreate,
+			Editable:   !f.DisableUpdate,
+			CreateHide: f.HideOnCreate,
+			EditHide:   f.HideOnUpdate,
+			Must:       f.Must,
+			HelpMsg:    f.Help,
+		}
+		if f.Join != nil {
+			field.JoinTable      = f.Join.Table
+			field.JoinTableAlias = f.Join.Alias
+			field.JoinForeignKey = f.Join.Field
+			field.JoinField      = f.Join.JoinField
+		}
+		if f.OptionsFrom != nil {
+			field.OptionTable = f.OptionsFrom.Table
+			field.OptionText  = f.OptionsFrom.Text
+			field.OptionValue = f.OptionsFrom.Value
+		}
+		if f.Default != "" {
+			field.Default = strconv.Quote(f.Default)
+		}
+		formType, _ := definitionFormType(f.FormType, form.Text)
+		if f.FormType == "" {
+			formType, _ = definitionFormType(form.GetFormTypeFromFieldType(typ, f.Field), form.Text)
+			if f.OptionsFrom != nil {
+				formType = form.SelectSingle
+			}
+		}
+		field.FormType = formType.Name()
+		return field
+	}
+
+	fields := make(tools.Fields, len(d.Fields))
+	for i, f := range d.Fields {
+		fields[i] = toolField(f)
+	}
+	formFields := make(tools.Fields, 0, len(d.Fields))
+	if len(d.Form) > 0 {
+		for _, f := range d.Form {
+			formFields = append(formFields, toolField(f))
+		}
+	} else {
+		for _, f := range d.Fields {
+			if f.Join == nil {
+				formFields = append(formFields, toolField(f))
+			}
+		}
+	}
+
+	return tools.GenerateCode(tools.NewParamWithFields(tools.Config{
+		Connection:       connection,
+		Driver:           driver,
+		Package:          pkg,
+		Table:            d.Table,
+		HideNewButton:    !definitionFlag(d.CanAdd),
+		HideEditButton:   !definitionFlag(d.Editable),
+		HideDeleteButton: !definitionFlag(d.Deletable),
+		HideExportButton: !definitionFlag(d.Exportable),
+		TableTitle:       d.Title,
+		TableDescription: d.Description,
+		FormTitle:        d.Title,
+		FormDescription:  d.Description,
+	}, fields, formFields))
+}
+
+// saveTableDef saves the definition of the designer as the next version of the
+// prefix and serves it at once.
+func (s *SystemTable) saveTableDef(prefix string, values form2.Values, userId int64) error {
+	if !tableDefPrefixReg.MatchString(prefix) {
+		return errors.New("wrong prefix, only letters, numbers, _ and - are allowed")
+	}
+
+	model := models.TableDef().SetConn(s.conn)
+	if _, ok := s.generators.Get(prefix); ok && model.Latest(prefix).IsEmpty() {
+		return errors.New("prefix " + prefix + " is used by another table")
+	}
+
+	d := tableDefFromValues(values)
+	d.Prefix = prefix
+	g, err := d.Generator()
+	if err != nil { return err }
+
+	content, err := json.Marshal(d)
+	if err != nil { return err }
+
+	if _, err := model.Save(prefix, d.Title, string(content), userId); err != nil {
+		return err
+	}
+	if s.generators != nil {
+		s.generators.Add(prefix, g)
+	}
+	return nil
+}
+
+// syncTableDefs serves the live versions of the prefixes after the versions are
+// deleted, the tables without versions are removed.
+func (s *SystemTable) syncTableDefs(prefixes []string) {
+	if s.generators == nil {
+		return
+	}
+	model := models.TableDef().SetConn(s.conn)
+	for _, prefix := range prefixes {
+		def := model.Latest(prefix)
+		if def.IsEmpty() {
+			s.generators.Remove(prefix)
+			continue
+		}
+		g, err := tableDefGenerator(def)
+		if err != nil {
+			logger.Error("load table definition ", prefix, " error: ", err)
+			continue
+		}
+		s.generators.Add(prefix, g)
+	}
+}
+
+func tableDefDisplayOptions() types.FieldOptions {
+	options := types.FieldOptions{ { Text: "-", Value: "" } }
+	for name := range definitionDisplays {
+		options = append(options, types.FieldOption{ Text: name, Value: name })
+	}
+	sort.Slice(options[1:], func(i, j int) bool { return options[i + 1].Value < options[j + 1].Value })
+	return options
+}
@@ -0,0 +1,75 @@
+package table
+
+import (
+	"testing"
+
+	"github.com/GoAdminGroup/go-admin/modules/service"
+	"github.com/GoAdminGroup/go-admin/plugins/admin/modules/parameter"
+	"github.com/magiconair/properties/assert"
+)
+
+func TestTrash(t *testing.T) {
+	initListTest()
+	defer func(srv service.List) { services = srv }(services)
+	services = service.List{}
+
+	tb, conn := newListTable(t, t.TempDir(), "")
+	tb.SoftDeleteField = DefaultSoftDeleteField
+
+	_, err := conn.Exec(`CREATE TABLE posts (id INTEGER PRIMARY KEY AUTOINCREMENT, title TEXT, author TEXT, deleted_at TIMESTAMP)`)
+	assert.Equal(t, err, nil)
+	_, err = conn.Exec(`INSERT INTO posts (id, title, author) VALUES (1, 'hello', 'sam'), (2, 'bye', 'tom'), (3, 'again', 'sam')`)
+	assert.Equal(t, err, nil)
+
+	titles := func(info PanelInfo) []string {
+		var res []string
+		for _, row := range info.InfoList {
+			res = append(res, row["title"].Value)
+		}
+		return res
+	}
+	list := func(trashed bool, ids ...string) []string {
+		params := parameter.BaseParam().WithIsAll(false)
+		if trashed {
+			params.Fields[parameter.Trashed] = []string{ parameter.True }
+		}
+		var (
+			info PanelInfo
+			err  error
+		)
+		if len(ids) > 0 {
+			info, err = tb.GetDataWithIds(params.WithPKs(ids...))
+		} else {
+			info, err = tb.GetData(params)
+		}
+		assert.Equal(t, err, nil)
+		return titles(info)
+	}
+	deletedAt := func(id string) interface{} {
+		rows, err := conn.Query(`SELECT deleted_at FROM posts WHERE id = ?`, id)
+		assert.Equal(t, err, nil)
+		if len(rows) == 0 {
+			return "purged"
+		}
+		return rows[0]["deleted_at"]
+	}
+
+	assert.Equal(t, tb.DeleteData("1,2"), nil)
+	assert.Equal(t, deletedAt("1") != nil, true)
+	assert.Equal(t, list(false), []string{ "again" })
+	assert.Equal(t, len(list(true)), 2)
+
+	// the rows selected by the ids are filtered as well.
+	assert.Equal(t, list(false, "1", "3"), []string{ "again" })
+	assert.Equal(t, list(true, "1", "3"), []string{ "hello" })
+
+	// only the soft deleted rows are restored or purged.
+	assert.Equal(t, tb.RestoreData("1"), nil)
+	assert.Equal(t, deletedAt("1"), nil)
+	assert.Equal(t, tb.PurgeData("1,2,3"), nil)
+	assert.Equal(t, deletedAt("1"), nil)
+	assert.Equal(t, deletedAt("2"), "purged")
+	assert.Equal(t, deletedAt("3"), nil)
+	assert.Equal(t, len(list(true)), 0)
+	assert.Equal(t, len(list(false)), 2)
+}
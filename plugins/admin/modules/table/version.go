@@ -0,0 +1,158 @@
+package table
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/GoAdminGroup/go-admin/modules/db"
+	"github.com/GoAdminGroup/go-admin/modules/db/dialect"
+	errs "github.com/GoAdminGroup/go-admin/modules/errors"
+	"github.com/GoAdminGroup/go-admin/modules/logger"
+	"github.com/GoAdminGroup/go-admin/modules/utils"
+	"github.com/GoAdminGroup/go-admin/plugins/admin/modules"
+	"github.com/GoAdminGroup/go-admin/plugins/admin/modules/form"
+)
+
+// VersionConflictField is a field whose submitted value differs from the current value.
+type VersionConflictField struct {
+	Field     string `json:"field"`
+	Head      string `json:"head"`
+	Current   string `json:"current"`
+	Submitted string `json:"submitted"`
+}
+
+// VersionConflictError is returned by UpdateData when the row has been changed
+// since the form was loaded, see types.FormPanel.SetVersionField.
+type VersionConflictError struct {
+	Fields []VersionConflictField `json:"fields"`
+}
+
+func (e *VersionConflictError) Error() string {
+	return errs.VersionConflict
+}
+
+// versionColumn return the version field of the form when it is a column of the table.
+func (tb *DefaultTable) versionColumn(columnMap map[string]struct{}) string {
+	field := tb.Form.VersionField
+	if field == "" || field == tb.PrimaryKey.Name {
+		return ""
+	}
+	if _, ok := columnMap[field]; !ok {
+		return ""
+	}
+	return field
+}
+
+// checkVersion compares the submitted version with the version of the row, it
+// return the current version which the update should be conditioned on and
+// whether the version is checked. An update of a versioned table without the
+// version is rejected, otherwise it would overwrite the changes of others.
+func (tb *DefaultTable) checkVersion(dataList form.Values, id string) (interface{}, bool, error) {
+	submitted, ok := dataList[form.VersionKey]
+	dataList.Delete(form.VersionKey)
+
+	if tb.Form.VersionField == "" || tb.connectionDriver == "" || !tb.getDataFromDB() {
+		return nil, false, nil
+	}
+
+	columnMap, _ := tb.getColumnMap(tb.Form.Table)
+	if tb.versionColumn(columnMap) == "" {
+		return nil, false, nil
+	}
+
+	if !ok {
+		return nil, false, errors.New(errs.MissingVersion)
+	}
+
+	row, err := tb.sql().Table(tb.Form.Table).Where(tb.PrimaryKey.Name, "=", id).First()
+	if err != nil || row == nil {
+		if err != nil { logger.Error("query version error: ", err) }
+		return nil, false, nil
+	}
+
+	version := row[tb.Form.VersionField]
+	if versionString(version) != strings.Join(submitted, "") {
+		return nil, false, tb.versionConflict(dataList, row)
+	}
+
+	return version, true, nil
+}
+
+// versionConflict return the conflict error with the fields of the submitted
+// values which differ from the current row.
+func (tb *DefaultTable) versionConflict(dataList form.Values, row map[string]interface{}) *VersionConflictError {
+	if row == nil {
+		row, _ = tb.sql().Table(tb.Form.Table).
+			Where(tb.PrimaryKey.Name, "=", dataList.Get(tb.PrimaryKey.Name)).
+			First()
+	}
+
+	conflict := &VersionConflictError{ Fields: make([]VersionConflictField, 0) }
+	for _, field := range tb.Form.FieldList {
+		if field.Field == tb.PrimaryKey.Name || field.Field == tb.Form.VersionField {
+			continue
+		}
+		value, ok := dataList[field.Field]
+		if !ok {
+			if value, ok = dataList[field.Field + "[]"]; !ok { continue }
+		}
+		current, ok := row[field.Field]
+		if !ok { continue }
+		var (
+			delim     = ","
+			submitted = ""
+		)
+		if field.DefaultOptionDelimiter != "" {
+			delim = field.DefaultOptionDelimiter
+		}
+		submitted = strings.Join(value, delim)
+		if submitted == versionString(current) {
+			continue
+		}
+		conflict.Fields = append(conflict.Fields, VersionConflictField{
+			Field    : field.Field,
+			Head     : field.Head,
+			Current  : versionString(current),
+			Submitted: submitted,
+		})
+	}
+	return conflict
+}
+
+func versionString(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case []byte:
+		return string(v)
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
+
+// nextVersion increases an integer version, the other versions are seen as the
+// updated_at and replaced with the current time.
+func nextVersion(value interface{}) interface{} {
+	switch v := value.(type) {
+	case int64:
+		return v + 1
+	case int:
+		return v + 1
+	}
+	if v, err := strconv.ParseInt(versionString(value), 10, 64); err == nil {
+		return v + 1
+	}
+	return utils.NowStr()
+}
+
+// versionStatement conditions the update on the current version and increases the version.
+func (tb *DefaultTable) versionStatement(stmt *db.SQL, values dialect.H, version interface{}) *db.SQL {
+	values[tb.Form.VersionField] = nextVersion(version)
+	if version == nil {
+		conn := tb.db()
+		return stmt.WhereRaw(modules.Delimiter(conn.GetDelimiter(), conn.GetDelimiter2(), tb.Form.VersionField) + " IS NULL")
+	}
+	return stmt.Where(tb.Form.VersionField, "=", version)
+}
@@ -0,0 +1,52 @@
+package table
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/GoAdminGroup/go-admin/modules/config"
+	"github.com/GoAdminGroup/go-admin/modules/db"
+	errs "github.com/GoAdminGroup/go-admin/modules/errors"
+	"github.com/GoAdminGroup/go-admin/modules/utils"
+	"github.com/GoAdminGroup/go-admin/plugins/admin/modules/form"
+	"github.com/magiconair/properties/assert"
+	_ "github.com/mattn/go-sqlite3"
+)
+
+func TestCheckVersion(t *testing.T) {
+	utils.InitUtils(100, func(s string) string { return s })
+	config.Initialize(&config.Config{ Language: "en" })
+
+	conn := db.GetSqliteDB().InitDB(map[string]config.Database{
+		"default": { Driver: db.DriverSqlite, File: filepath.Join(t.TempDir(), "admin.db") },
+	})
+	defer conn.Close()
+
+	_, err := conn.Exec(`CREATE TABLE posts (id INTEGER PRIMARY KEY, title TEXT, version INTEGER)`)
+	assert.Equal(t, err, nil)
+	_, err = conn.Exec(`INSERT INTO posts (id, title, version) VALUES (1, 'foo', 2)`)
+	assert.Equal(t, err, nil)
+
+	tb := NewDefaultTable(DefaultConfigWithDriver(db.DriverSqlite)).(*DefaultTable)
+	tb.dbObj = conn
+	tb.Form.Table = "posts"
+	tb.Form.VersionField = "version"
+
+	_, versioned, err := tb.checkVersion(form.Values{ "id": { "1" } }, "1")
+	assert.Equal(t, versioned, false)
+	assert.Equal(t, err.Error(), errs.MissingVersion)
+
+	version, versioned, err := tb.checkVersion(form.Values{ "id": { "1" }, form.VersionKey: { "2" } }, "1")
+	assert.Equal(t, err, nil)
+	assert.Equal(t, versioned, true)
+	assert.Equal(t, versionString(version), "2")
+
+	_, _, err = tb.checkVersion(form.Values{ "id": { "1" }, form.VersionKey: { "1" } }, "1")
+	assert.Equal(t, err.Error(), errs.VersionConflict)
+
+	// the tables without the version field are not checked.
+	tb.Form.VersionField = ""
+	_, versioned, err = tb.checkVersion(form.Values{ "id": { "1" } }, "1")
+	assert.Equal(t, versioned, false)
+	assert.Equal(t, err, nil)
+}
@@ -3,8 +3,10 @@ package tools
 import (
 	"fmt"
 	"go/format"
+	"html"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"text/template"
 
@@ -118,7 +120,7 @@ func NewParam(cfg Config) *Param {
 		dbTable = cfg.Schema + "." + cfg.Table
 	}
 
-	fields := getFieldsFromConn(cfg.Conn, dbTable, cfg.Driver)
+	fields, formFields := getFieldsFromConn(cfg.Conn, dbTable, cfg.Schema, cfg.Driver)
 	tt := strings.Title(ta)
 
 	return &Param{
@@ -145,7 +147,7 @@ func NewParam(cfg Config) *Param {
 		HideBackButton:           cfg.HideBackButton,
 		RowTable:                 cfg.Table,
 		Fields:                   fields,
-		FormFields:               fields,
+		FormFields:               formFields,
 		DetailDisplay:            cfg.DetailDisplay,
 		Output:                   cfg.Output,
 		ExtraImport:              cfg.ExtraImport,
@@ -233,23 +235,54 @@ type Field struct {
 	Default      string `json:"default"`
 	CanAdd       bool   `json:"can_add"`
 	ExtraFun     string `json:"extra_fun"`
+
+	Must    bool   `json:"must"`
+	HelpMsg string `json:"help_msg"`
+
+	// JoinTable, JoinForeignKey and JoinField join the field of the info from
+	// the table by the foreign key, see the types.Join.
+	JoinTable      string `json:"join_table"`
+	JoinTableAlias string `json:"join_table_alias"`
+	JoinForeignKey string `json:"join_foreign_key"`
+	JoinField      string `json:"join_field"`
+
+	// OptionTable, OptionText and OptionValue are the options of the form field
+	// from the table, see the FormPanel.FieldOptionsFromTable.
+	OptionTable string `json:"option_table"`
+	OptionText  string `json:"option_text"`
+	OptionValue string `json:"option_value"`
+}
+
+// HasJoin reports whether the info fields have the joins.
+func (param *Param) HasJoin() bool {
+	for _, field := range param.Fields {
+		if field.JoinTable != "" {
+			return true
+		}
+	}
+	return false
 }
 
 func Generate(param *Param) error {
-	t, err := template.New("table_model").Parse(tableModelTmpl)
+	c, err := GenerateCode(param)
 	if err != nil {
 		return err
 	}
-	var sb strings.Builder
-	err = t.Execute(&sb, param)
+	return os.WriteFile(filepath.FromSlash(param.Output)+"/"+param.RowTable+".go", c, 0644)
+}
+
+// GenerateCode return the formatted go code of the table model of the param.
+func GenerateCode(param *Param) ([]byte, error) {
+	t, err := template.New("table_model").Parse(tableModelTmpl)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	c, err := format.Source([]byte(sb.String()))
+	var sb strings.Builder
+	err = t.Execute(&sb, param)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	return os.WriteFile(filepath.FromSlash(param.Output)+"/"+param.RowTable+".go", c, 0644)
+	return format.Source([]byte(sb.String()))
 }
 
 const (
@@ -412,7 +445,106 @@ func camelcase(s string) string {
 	return res
 }
 
-func getFieldsFromConn(conn db.Connection, table, driver string) Fields {
+// getFieldsFromConn return the fields of the info and the form of the table. The
+// fields of the foreign keys are joined in the info and selected from the
+// referenced tables in the form, the not null columns are required, the
+// defaults and the comments of the columns are the defaults and the help
+// messages of the form fields. It falls back to the columns of the table when
+// the schema can not be read.
+func getFieldsFromConn(conn db.Connection, table, schema, driver string) (Fields, Fields) {
+	s, err := db.WithDriver(conn).Table(table).Schema()
+	if err != nil {
+		fields := getFieldsFromColumns(conn, table, driver)
+		return fields, fields
+	}
+
+	var (
+		fields     = make(Fields, 0, len(s.Columns))
+		formFields = make(Fields, 0, len(s.Columns))
+		joined     = make(map[string]bool)
+	)
+
+	for _, col := range s.Columns {
+		typeName := utils.GetTypeName(col.Type)
+		field := Field{
+			Head:     strings.Title(col.Name),
+			Name:     col.Name,
+			DBType:   typeName,
+			CanAdd:   true,
+			Editable: true,
+			FormType: form.GetFormTypeFromFieldType(db.DT(strings.ToUpper(typeName)), col.Name),
+		}
+		if col.Name == "id" {
+			field.Filterable = true
+		}
+		fields = append(fields, field)
+
+		field.Must    = !col.Nullable && !col.HasDefault && !col.AutoIncrement
+		field.HelpMsg = html.EscapeString(col.Comment)
+		if col.HasDefault && !col.DefaultExpr {
+			field.Default = strconv.Quote(col.Default)
+		}
+
+		if fk, ok := s.ForeignKey(col.Name); ok {
+			refTable := fk.RefTable
+			if schema != "" {
+				refTable = schema + "." + refTable
+			}
+			display, displayType := getDisplayColumn(conn, refTable, fk.RefColumns[0])
+			if display != "" {
+				// the table joined by more than one foreign key is aliased.
+				alias := ""
+				if joined[refTable] {
+					alias = fk.RefTable + "_" + col.Name
+				}
+				joined[refTable] = true
+				fields = append(fields, Field{
+					Head:           strings.Title(strings.TrimSuffix(col.Name, "_id")) + " " + strings.Title(display),
+					Name:           display,
+					DBType:         displayType,
+					JoinTable:      refTable,
+					JoinTableAlias: alias,
+					JoinForeignKey: col.Name,
+					JoinField:      fk.RefColumns[0],
+				})
+			} else {
+				display = fk.RefColumns[0]
+			}
+			field.FormType    = "SelectSingle"
+			field.OptionTable = refTable
+			field.OptionText  = display
+			field.OptionValue = fk.RefColumns[0]
+		}
+		formFields = append(formFields, field)
+	}
+
+	return fields, formFields
+}
+
+var displayColumnNames = []string{ "name", "title", "username", "nickname", "label", "email", "code" }
+
+// getDisplayColumn return the column displaying the rows of the referenced table
+// and its type, which is the first column of the common names or of the strings.
+func getDisplayColumn(conn db.Connection, table, key string) (string, string) {
+	s, err := db.WithDriver(conn).Table(table).Schema()
+	if err != nil {
+		return "", ""
+	}
+	for _, name := range displayColumnNames {
+		if col, ok := s.Column(name); ok {
+			return col.Name, utils.GetTypeName(col.Type)
+		}
+	}
+	for _, col := range s.Columns {
+		t := strings.ToLower(col.Type)
+		if col.Name != key && (strings.Contains(t, "char") || strings.Contains(t, "text")) {
+			return col.Name, utils.GetTypeName(col.Type)
+		}
+	}
+	return "", ""
+}
+
+func getFieldsFromColumns(conn db.Connection, table, driver string) Fields {
 	columnsModel, _ := db.WithDriver(conn).Table(table).ShowColumns()
 
 	fields := make(Fields, len(columnsModel))
@@ -8,6 +8,7 @@ import (
 	"github.com/GoAdminGroup/go-admin/context"
 	"github.com/GoAdminGroup/go-admin/modules/db"
 	"github.com/GoAdminGroup/go-admin/plugins/admin/modules/table"
+	{{if .HasJoin}}"github.com/GoAdminGroup/go-admin/template/types"{{end}}
 	"github.com/GoAdminGroup/go-admin/template/types/form"
 )
 
@@ -37,7 +38,13 @@ func MustGet{{.TableTitle}}Table(ctx *context.Context) table.Table {
 		FieldFilterable(){{end -}}{{if $field.Sortable}}.
 		FieldSortable(){{end -}}{{if $field.InfoEditable}}.
 		FieldEditAble(){{end -}}{{if $field.Hide}}.
-		FieldHide(){{end -}}
+		FieldHide(){{end -}}{{if ne $field.JoinTable ""}}.
+		FieldJoin(types.Join{
+			Table:     "{{$field.JoinTable}}",{{if ne $field.JoinTableAlias ""}}
+			TableAlias: "{{$field.JoinTableAlias}}",{{end}}
+			Field:     "{{$field.JoinForeignKey}}",
+			JoinField: "{{$field.JoinField}}",
+		}){{end -}}
 	{{- end}}
 
 	info.SetTable("{{.TableName}}").SetTitle("{{.TablePageTitle}}").SetDescription("{{.TableDescription}}")
@@ -51,7 +58,10 @@ func MustGet{{.TableTitle}}Table(ctx *context.Context) table.Table {
 		FieldDisableWhenUpdate(){{end -}}{{if $field.FormHide}}.
 		FieldHide(){{end -}}{{if $field.EditHide}}.
 		FieldHideWhenUpdate(){{end -}}{{if $field.CreateHide}}.
-		FieldHideWhenCreate(){{end -}}{{$field.ExtraFun}}
+		FieldHideWhenCreate(){{end -}}{{if $field.Must}}.
+		FieldMust(){{end -}}{{if ne $field.HelpMsg ""}}.
+		FieldHelpMsg({{printf "%q" $field.HelpMsg}}){{end -}}{{if ne $field.OptionTable ""}}.
+		FieldOptionsFromTable("{{$field.OptionTable}}", "{{$field.OptionText}}", "{{$field.OptionValue}}"){{end -}}{{$field.ExtraFun}}
 	{{- end}}
 
 	{{if .HideContinueEditCheckBox}}formList.HideContinueEditCheckBox(){{end}}
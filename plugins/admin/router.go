@@ -4,6 +4,7 @@ import (
 	"github.com/GoAdminGroup/go-admin/context"
 	"github.com/GoAdminGroup/go-admin/modules/auth"
 	"github.com/GoAdminGroup/go-admin/modules/config"
+	"github.com/GoAdminGroup/go-admin/modules/tenant"
 	"github.com/GoAdminGroup/go-admin/plugins/admin/modules/response"
 	"github.com/GoAdminGroup/go-admin/template"
 )
@@ -12,11 +13,13 @@ import (
 func (admin *Admin) initRouter() *Admin {
 	app := context.NewApp()
 
-	route := app.Group(config.Prefix(), admin.GlobalErrorHandler)
+	route := app.Group(config.Prefix(), tenant.Middleware, admin.GlobalErrorHandler)
 
 	// auth
 	route.GET(config.GetLoginUrl(), admin.handler.ShowLogin)
 	route.POST("/signin", admin.handler.Auth)
+	route.GET("/login/two_factor", admin.handler.ShowTwoFactorLogin)
+	route.POST("/login/two_factor", admin.handler.TwoFactorLogin)
 
 	checkRepeatedPath := make(map[string]struct{}, 32)
 	for _, themeName := range template.Themes() {
@@ -36,6 +39,8 @@ func (admin *Admin) initRouter() *Admin {
 
 	// auth
 	authRoute.GET("/logout", admin.handler.Logout)
+	authRoute.GET("/two_factor", admin.handler.ShowTwoFactor).Name("two_factor")
+	authRoute.POST("/two_factor", admin.handler.TwoFactor)
 
 	authPrefixRoute := route.Group("/", auth.Middleware(admin.Conn), admin.guardian.CheckPrefix)
 
@@ -67,12 +72,21 @@ func (admin *Admin) initRouter() *Admin {
 	authPrefixRoute.POST(formats.Edit, admin.guardian.EditForm, admin.handler.EditForm).Name("edit")
 	authPrefixRoute.POST(formats.Create, admin.guardian.NewForm, admin.handler.NewForm).Name("new")
 	authPrefixRoute.POST(formats.Delete, admin.guardian.Delete, admin.handler.Delete).Name("delete")
+	authPrefixRoute.POST(formats.Restore, admin.guardian.Trash, admin.handler.Restore).Name("restore")
+	authPrefixRoute.POST(formats.Purge, admin.guardian.Trash, admin.handler.Purge).Name("purge")
+	authPrefixRoute.GET(formats.Trash, admin.handler.ShowTrash).Name("trash")
 	authPrefixRoute.POST(formats.Export, admin.guardian.Export, admin.handler.Export).Name("export")
+	authPrefixRoute.GET(formats.ShowImport, admin.guardian.ShowImport, admin.handler.ShowImport).Name("show_import")
+	authPrefixRoute.POST(formats.Import, admin.guardian.Import, admin.handler.Import).Name("import")
 	authPrefixRoute.GET(formats.Info, admin.handler.ShowInfo).Name("info")
 
 	authPrefixRoute.POST(formats.Update, admin.guardian.Update, admin.handler.Update).Name("update")
 
+	authPrefixRoute.POST("/saved_view/new/:__prefix", admin.handler.SaveView).Name("save_view")
+	authPrefixRoute.POST("/saved_view/delete/:__prefix", admin.handler.DeleteView).Name("delete_view")
+
 	authRoute.GET("/application/info", admin.handler.SystemInfo)
+	authRoute.GET("/export_job/download", admin.handler.DownloadExportJob).Name("export_job_download")
 
 	route.ANY("/operation/:__goadmin_op_id", auth.Middleware(admin.Conn), admin.handler.Operation)
 
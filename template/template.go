@@ -441,6 +441,7 @@ func Execute(param *ExecuteParam) *bytes.Buffer {
 			TmplHeadHTML: Default().GetHeadHTML(),
 			TmplFootJS:   Default().GetFootJS(),
 			Logo:         param.Logo,
+			Config:       param.Config,
 		}))
 	if err != nil {
 		logger.Error("template execute error", err)
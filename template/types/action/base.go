@@ -59,6 +59,7 @@ var _ types.Action = (*AjaxAction)(nil)
 var _ types.Action = (*PopUpAction)(nil)
 var _ types.Action = (*JumpAction)(nil)
 var _ types.Action = (*JumpSelectBoxAction)(nil)
+var _ types.Action = (*FilterBuilderAction)(nil)
 
 func URL(id string) string {
 	return config.Url("/operation/" + utils.WrapURL(id))
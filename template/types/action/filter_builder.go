@@ -0,0 +1,204 @@
+package action
+
+import (
+	"html/template"
+
+	"github.com/GoAdminGroup/go-admin/modules/db/dialect"
+	"github.com/GoAdminGroup/go-admin/modules/language"
+	"github.com/GoAdminGroup/go-admin/modules/utils"
+	template2 "github.com/GoAdminGroup/go-admin/template"
+	"github.com/GoAdminGroup/go-admin/template/types"
+)
+
+var filterOperatorLabels = map[dialect.FilterOperator]string{
+	dialect.FilterEqual         : "equal",
+	dialect.FilterNotEqual      : "not equal",
+	dialect.FilterGreater       : "greater than",
+	dialect.FilterGreaterOrEqual: "greater than or equal",
+	dialect.FilterLess          : "less than",
+	dialect.FilterLessOrEqual   : "less than or equal",
+	dialect.FilterContains      : "contains",
+	dialect.FilterStartsWith    : "starts with",
+	dialect.FilterEndsWith      : "ends with",
+	dialect.FilterIn            : "in",
+	dialect.FilterNotIn         : "not in",
+	dialect.FilterIsNull        : "is null",
+	dialect.FilterIsNotNull     : "is not null",
+	dialect.FilterBetween       : "between",
+}
+
+// FilterBuilderAction is the button of the filter builder, which opens a modal
+// editing the groups of the conditions and jumps to the list with the filter in
+// the url.
+type FilterBuilderAction struct {
+	BaseAction
+	Id     string
+	Url    string
+	Key    string
+	Fields types.FieldOptions
+	Filter *dialect.Filter
+}
+
+// FilterBuilder return the action of the filter builder of the fields, the values
+// of the options are the fields and the texts are the heads. The url is the url of
+// the list without the filter, the key is the key of the filter in the url.
+func FilterBuilder(url, key string, fields types.FieldOptions, filter *dialect.Filter) *FilterBuilderAction {
+	return &FilterBuilderAction{
+		Id:     "filter-builder-" + utils.Uuid(10),
+		Url:    url,
+		Key:    key,
+		Fields: fields,
+		Filter: filter,
+	}
+}
+
+func (f *FilterBuilderAction) BtnAttribute() template.HTML {
+	return template.HTML(utils.StrConcat(`data-toggle="modal" data-target="#`, f.Id, `" style="cursor: pointer;"`))
+}
+
+func (f *FilterBuilderAction) FooterContent() template.HTML {
+	body := template.HTML(utils.StrConcat(`<div class="filter-builder-tree"></div>
+<div style="margin-top: 15px;">
+	<button type="button" class="btn btn-sm btn-primary pull-right filter-builder-apply">`, language.Get("filter"), `</button>
+	<button type="button" class="btn btn-sm btn-default filter-builder-clear">`, language.Get("reset"), `</button>
+</div>`))
+	return template2.Default().Popup().SetID(f.Id).
+		SetTitle(language.GetFromHtml("filter builder")).
+		SetWidth("900px").
+		SetHideFooter().
+		SetBody(body).
+		GetContent()
+}
+
+func (f *FilterBuilderAction) Js() template.JS {
+	fields := make([]map[string]string, len(f.Fields))
+	for i, field := range f.Fields {
+		fields[i] = map[string]string{ "value": field.Value, "text": field.Text }
+	}
+	operators := make([]map[string]string, len(dialect.FilterOperators))
+	for i, op := range dialect.FilterOperators {
+		operators[i] = map[string]string{ "value": string(op), "text": language.Get(filterOperatorLabels[op]) }
+	}
+	fieldsJSON, _ := utils.JsonMarshal(fields)
+	operatorsJSON, _ := utils.JsonMarshal(operators)
+	filterJSON := []byte("null")
+	if f.Filter != nil {
+		filterJSON, _ = utils.JsonMarshal(f.Filter)
+	}
+	texts, _ := utils.JsonMarshal(map[string]string{
+		"and"      : language.Get("and"),
+		"or"       : language.Get("or"),
+		"condition": language.Get("add condition"),
+		"group"    : language.Get("add group"),
+		"path"     : language.Get("json path"),
+		"values"   : language.Get("values, separated by commas"),
+	})
+
+	return template.JS(utils.StrConcat(`(function() {
+	let modal = $('#`, f.Id, `');
+	let fields = `, string(fieldsJSON), `;
+	let operators = `, string(operatorsJSON), `;
+	let texts = `, string(texts), `;
+	let noValue = { "is_null": true, "is_not_null": true };
+	let multiValue = { "in": true, "not_in": true, "between": true };
+	let state = `, string(filterJSON), ` || { logic: "and", children: [] };
+	if (state.field) { state = { logic: "and", children: [state] }; }
+
+	function esc(s) {
+		return $('<div>').text(s === undefined || s === null ? '' : s).html().replace(/"/g, '&quot;');
+	}
+	function options(list, selected) {
+		return list.map(function(o) {
+			return '<option value="' + esc(o.value) + '"' + (o.value === selected ? ' selected' : '') + '>' + esc(o.text) + '</option>';
+		}).join('');
+	}
+	function removeButton(list, i) {
+		return $('<button type="button" class="btn btn-sm btn-default"><i class="fa fa-trash"></i></button>').on('click', function() {
+			list.splice(i, 1);
+			render();
+		});
+	}
+	function renderCondition(cond, list, i) {
+		let row = $('<div class="form-inline" style="margin: 5px 0;"></div>');
+		cond.op = cond.op || "eq";
+		cond.values = cond.values || [];
+		let field = $('<select class="form-control input-sm">' + options(fields, cond.field) + '</select>');
+		cond.field = cond.field || (fields.length > 0 ? fields[0].value : "");
+		field.on('change', function() { cond.field = $(this).val(); });
+		let path = $('<input class="form-control input-sm" style="width: 120px;">').attr('placeholder', texts.path).val(cond.path || '');
+		path.on('change', function() { cond.path = $(this).val().trim(); });
+		let op = $('<select class="form-control input-sm">' + options(operators, cond.op) + '</select>');
+		let value = $('<input class="form-control input-sm" style="width: 220px;">').val(cond.values.join(','));
+		let setValue = function() {
+			let v = value.val();
+			if (noValue[cond.op]) {
+				cond.values = [];
+			} else if (multiValue[cond.op]) {
+				cond.values = v.split(',').map(function(e) { return e.trim(); });
+			} else {
+				cond.values = [v];
+			}
+		};
+		let toggle = function() {
+			value.toggle(!noValue[cond.op]);
+			value.attr('placeholder', multiValue[cond.op] ? texts.values : '');
+		};
+		op.on('change', function() { cond.op = $(this).val(); toggle(); setValue(); });
+		value.on('change', setValue);
+		toggle();
+		if (cond.values.length === 0) { setValue(); }
+		return row.append(field, ' ', path, ' ', op, ' ', value, ' ', removeButton(list, i));
+	}
+	function renderGroup(group, list, i) {
+		group.logic = group.logic || "and";
+		group.children = group.children || [];
+		let box = $('<div style="border-left: 3px solid #3c8dbc; padding: 5px 0 5px 10px; margin: 5px 0;"></div>');
+		let logic = $('<select class="form-control input-sm">' +
+			options([{ value: "and", text: texts.and }, { value: "or", text: texts.or }], group.logic) + '</select>');
+		logic.on('change', function() { group.logic = $(this).val(); });
+		let addCondition = $('<button type="button" class="btn btn-sm btn-default"></button>').text(texts.condition).on('click', function() {
+			group.children.push({ field: fields.length > 0 ? fields[0].value : "", op: "eq", values: [] });
+			render();
+		});
+		let addGroup = $('<button type="button" class="btn btn-sm btn-default"></button>').text(texts.group).on('click', function() {
+			group.children.push({ logic: "and", children: [] });
+			render();
+		});
+		let head = $('<div class="form-inline"></div>').append(logic, ' ', addCondition, ' ', addGroup);
+		if (list) { head.append(' ', removeButton(list, i)); }
+		box.append(head);
+		group.children.forEach(function(child, j) {
+			box.append(child.op || child.field ?
+				renderCondition(child, group.children, j) : renderGroup(child, group.children, j));
+		});
+		return box;
+	}
+	function render() {
+		modal.find('.filter-builder-tree').empty().append(renderGroup(state));
+	}
+	function clean(f) {
+		if (f.children) {
+			f.children = f.children.filter(clean);
+			return f.children.length > 0;
+		}
+		return !!f.field;
+	}
+	function jump(url) {
+		modal.modal('hide');
+		$('.modal-backdrop').remove();
+		$.pjax({ url: url, container: '#pjax-container' });
+	}
+
+	render();
+	modal.find('.filter-builder-apply').on('click', function() {
+		let url = "`, f.Url, `";
+		if (clean(state)) {
+			url += (url.indexOf('?') >= 0 ? '&' : '?') + "`, f.Key, `=" + encodeURIComponent(JSON.stringify(state));
+		}
+		jump(url);
+	});
+	modal.find('.filter-builder-clear').on('click', function() {
+		jump("`, f.Url, `");
+	});
+})();`))
+}
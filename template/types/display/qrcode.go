@@ -3,6 +3,7 @@ package display
 import (
 	"html/template"
 
+	"github.com/GoAdminGroup/go-admin/modules/qrcode"
 	"github.com/GoAdminGroup/go-admin/template/types"
 )
 
@@ -17,7 +18,12 @@ func init() {
 func (q *Qrcode) Get(args ...interface{}) types.FieldFilterFn {
 	return func(value types.FieldModel) interface{} {
 
-		src := `https://api.qrserver.com/v1/create-qr-code/?size=150x150&amp;data=` + value.Value
+		// the image is generated locally, the online service is only used for the
+		// long text which the local encoder does not support.
+		src, err := qrcode.DataURL(value.Value, 4)
+		if err != nil {
+			src = `https://api.qrserver.com/v1/create-qr-code/?size=150x150&amp;data=` + value.Value
+		}
 
 		return template.HTML(`
 <a href="javascript:void(0);" class="grid-column-qrcode text-muted" 
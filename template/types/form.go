@@ -330,6 +330,9 @@ type FormPanel struct {
 
 	primaryKey primaryKey
 
+	// VersionField is the version or updated_at column checked by the update.
+	VersionField string `json:"version_field"`
+
 	UpdateFn FormPostFn `json:"update_fn"`
 	InsertFn FormPostFn `json:"insert_fn"`
 
@@ -432,6 +435,16 @@ func (f *FormPanel) SetPrimaryKey(name string, typ db.DatabaseType) *FormPanel {
 	return f
 }
 
+// SetVersionField set the version or updated_at column of the table. The edit form
+// carries the value loaded with the row, and the update fails with a conflict when
+// the row has been changed since then, the updates without the version are rejected.
+// An integer version is increased by the update, otherwise the column is set to the
+// current time.
+func (f *FormPanel) SetVersionField(field string) *FormPanel {
+	f.VersionField = field
+	return f
+}
+
 func (f *FormPanel) HideContinueEditCheckBox() *FormPanel {
 	f.IsHideContinueEditCheckBox = true
 	return f
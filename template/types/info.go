@@ -7,6 +7,7 @@ import (
 	"runtime/debug"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/GoAdminGroup/go-admin/context"
 	"github.com/GoAdminGroup/go-admin/modules/config"
@@ -671,6 +672,23 @@ type InfoPanel struct {
 	HideSideBar bool
 
 	AutoRefresh uint
+
+	// CacheTTL is how long the list pages queried from the database are cached,
+	// the cache is off when it is zero.
+	CacheTTL time.Duration
+
+	// KeysetPagination pages the rows queried from the database by the cursors of
+	// the sort field and the primary key instead of the offset, and no total count
+	// is queried unless the EstimatedCount falls back to it.
+	KeysetPagination bool
+
+	// EstimatedCount reads the total count from the statistics of the database
+	// instead of the count query, the rows are still counted when they are filtered.
+	EstimatedCount bool
+
+	// FilterBuilder shows the builder of the nested filters with the groups of the
+	// AND and the OR conditions.
+	FilterBuilder bool
 }
 
 type Where struct {
@@ -1631,6 +1649,40 @@ func (i *InfoPanel) SetAutoRefresh(interval uint) *InfoPanel {
 	return i
 }
 
+// SetCache caches the list pages queried from the database for the ttl, which
+// saves the queries of the dashboards refreshed by the SetAutoRefresh. The cache
+// of a table is invalidated by the updates, the inserts and the deletes of it.
+func (i *InfoPanel) SetCache(ttl time.Duration) *InfoPanel {
+	i.CacheTTL = ttl
+	return i
+}
+
+// SetKeysetPagination pages the rows by the cursors instead of the offset, which
+// keeps the pages of the huge tables fast. The pages are ordered by the sort field
+// and then the primary key, the NULL values of the sort field are kept in the
+// order of the database.
+func (i *InfoPanel) SetKeysetPagination() *InfoPanel {
+	i.KeysetPagination = true
+	return i
+}
+
+// SetEstimatedCount shows the estimated total count read from the statistics of
+// the database instead of the count query, which is the pg_class of postgresql,
+// the information_schema.tables of mysql and the sys.partitions of mssql. The
+// rows filtered by the wheres, the filters or the data policy are still counted.
+func (i *InfoPanel) SetEstimatedCount() *InfoPanel {
+	i.EstimatedCount = true
+	return i
+}
+
+// EnableFilterBuilder shows the button of the filter builder, which filters the
+// rows by the groups of the conditions of the filterable columns and join fields
+// of the info panel, and the values of the json columns by the paths.
+func (i *InfoPanel) EnableFilterBuilder() *InfoPanel {
+	i.FilterBuilder = true
+	return i
+}
+
 func (i *InfoPanel) Set404Error(content ...template.HTML) *InfoPanel {
 	i.SetError(errors.PageError404, content...)
 	return i
@@ -100,6 +100,9 @@ type NewPageParam struct {
 	TmplFootJS     template.HTML
 	NavButtonsHTML template.HTML
 	NavButtonsJS   template.HTML
+	// Config is the config of the page, such as the config of a tenant, the global
+	// config is used when it is nil.
+	Config *config.Config
 }
 
 func (param *NewPageParam) NavButtonsAndJS() (template.HTML, template.HTML) {
@@ -139,9 +142,22 @@ func NewPage(param *NewPageParam) *Page {
 		param.NavButtonsHTML, param.NavButtonsJS = param.NavButtonsAndJS()
 	}
 
-	logo := param.Logo
+	var (
+		logo        = param.Logo
+		title       = config.GetTitle()
+		miniLogo    = config.GetMiniLogo()
+		colorScheme = config.GetColorScheme()
+		headHtml    = config.GetCustomHeadHtml()
+		footHtml    = config.GetCustomFootHtml()
+		footerInfo  = config.GetFooterInfo()
+		cfgLogo     = config.GetLogo()
+	)
+	if cfg := param.Config; cfg != nil {
+		title, miniLogo, colorScheme, cfgLogo = cfg.Title, cfg.MiniLogo, cfg.ColorScheme, cfg.Logo
+		headHtml, footHtml, footerInfo = cfg.CustomHeadHtml, cfg.CustomFootHtml, cfg.FooterInfo
+	}
 	if logo == "" {
-		logo = config.GetLogo()
+		logo = cfgLogo
 	}
 
 	return &Page{
@@ -154,15 +170,15 @@ func NewPage(param *NewPageParam) *Page {
 			Theme:   config.GetTheme(),
 		},
 		UrlPrefix:      config.AssertPrefix(),
-		Title:          config.GetTitle(),
+		Title:          title,
 		Logo:           logo,
-		MiniLogo:       config.GetMiniLogo(),
-		ColorScheme:    config.GetColorScheme(),
+		MiniLogo:       miniLogo,
+		ColorScheme:    colorScheme,
 		IndexUrl:       config.GetIndexURL(),
 		CdnUrl:         config.GetAssetUrl(),
-		CustomHeadHtml: config.GetCustomHeadHtml(),
-		CustomFootHtml: config.GetCustomFootHtml() + param.NavButtonsJS,
-		FooterInfo:     config.GetFooterInfo(),
+		CustomHeadHtml: headHtml,
+		CustomFootHtml: footHtml + param.NavButtonsJS,
+		FooterInfo:     footerInfo,
 		AssetsList:     param.Assets,
 		navButtons:     param.Buttons,
 		Iframe:         param.Iframe,